@@ -45,6 +45,49 @@ func (q *Queries) GetBodyWeightLogs(ctx context.Context, userID int32) ([]Bodywe
 	return items, nil
 }
 
+const getBodyWeightLogsBetween = `-- name: GetBodyWeightLogsBetween :many
+
+SELECT bodyweight, log_date
+FROM bodyweight_logs
+WHERE user_id = $1 AND log_date >= $2 AND log_date < $3
+ORDER BY log_date ASC
+`
+
+type GetBodyWeightLogsBetweenParams struct {
+	UserID   int32              `json:"user_id"`
+	FromDate pgtype.Timestamptz `json:"from_date"`
+	ToDate   pgtype.Timestamptz `json:"to_date"`
+}
+
+type GetBodyWeightLogsBetweenRow struct {
+	Bodyweight pgtype.Numeric     `json:"bodyweight"`
+	LogDate    pgtype.Timestamptz `json:"log_date"`
+}
+
+// Weigh-ins from a date until another, oldest first
+func (q *Queries) GetBodyWeightLogsBetween(ctx context.Context, arg GetBodyWeightLogsBetweenParams) ([]GetBodyWeightLogsBetweenRow, error) {
+	rows, err := q.db.Query(ctx, getBodyWeightLogsBetween, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBodyWeightLogsBetweenRow
+	for rows.Next() {
+		var i GetBodyWeightLogsBetweenRow
+		if err := rows.Scan(
+			&i.Bodyweight,
+			&i.LogDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBodyweightLogByUserIDAndDate = `-- name: GetBodyweightLogByUserIDAndDate :one
 SELECT id
 FROM bodyweight_logs
@@ -63,6 +106,26 @@ func (q *Queries) GetBodyweightLogByUserIDAndDate(ctx context.Context, arg GetBo
 	return id, err
 }
 
+const getBodyweightLogOnOrBefore = `-- name: GetBodyweightLogOnOrBefore :one
+SELECT id
+FROM bodyweight_logs
+WHERE user_id = $1 AND log_date <= $2
+ORDER BY log_date DESC
+LIMIT 1
+`
+
+type GetBodyweightLogOnOrBeforeParams struct {
+	UserID  int32              `json:"user_id"`
+	LogDate pgtype.Timestamptz `json:"log_date"`
+}
+
+func (q *Queries) GetBodyweightLogOnOrBefore(ctx context.Context, arg GetBodyweightLogOnOrBeforeParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getBodyweightLogOnOrBefore, arg.UserID, arg.LogDate)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getLatestBodyWeight = `-- name: GetLatestBodyWeight :one
 SELECT bodyweight
 FROM bodyweight_logs
@@ -11,6 +11,19 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countExerciseLogsByExercise = `-- name: CountExerciseLogsByExercise :one
+SELECT COUNT(*)
+FROM exercise_logs
+WHERE exercise_id = $1
+`
+
+func (q *Queries) CountExerciseLogsByExercise(ctx context.Context, exerciseID int32) (int64, error) {
+	row := q.db.QueryRow(ctx, countExerciseLogsByExercise, exerciseID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getExerciseLogByDetails = `-- name: GetExerciseLogByDetails :one
 SELECT id
 FROM exercise_logs
@@ -42,8 +55,61 @@ func (q *Queries) GetExerciseLogByDetails(ctx context.Context, arg GetExerciseLo
 	return id, err
 }
 
+const getExerciseLogID = `-- name: GetExerciseLogID :one
+
+SELECT id
+FROM exercise_logs
+WHERE user_id = $1 AND exercise_id = $2 AND log_date = $3
+`
+
+type GetExerciseLogIDParams struct {
+	UserID     int32              `json:"user_id"`
+	ExerciseID int32              `json:"exercise_id"`
+	LogDate    pgtype.Timestamptz `json:"log_date"`
+}
+
+// Exercise logs are unique per exercise and time
+func (q *Queries) GetExerciseLogID(ctx context.Context, arg GetExerciseLogIDParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getExerciseLogID, arg.UserID, arg.ExerciseID, arg.LogDate)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getExerciseLogWithExercise = `-- name: GetExerciseLogWithExercise :one
+
+SELECT el.id, el.user_id, el.exercise_id, e.name AS exercise_name, e.measurement_kind, el.log_date
+FROM exercise_logs el
+JOIN exercises e ON el.exercise_id = e.id
+WHERE el.id = $1
+`
+
+type GetExerciseLogWithExerciseRow struct {
+	ID              int32              `json:"id"`
+	UserID          int32              `json:"user_id"`
+	ExerciseID      int32              `json:"exercise_id"`
+	ExerciseName    string             `json:"exercise_name"`
+	MeasurementKind MeasurementKind    `json:"measurement_kind"`
+	LogDate         pgtype.Timestamptz `json:"log_date"`
+}
+
+// A logged set with its exercise, to check whether it was a personal record
+func (q *Queries) GetExerciseLogWithExercise(ctx context.Context, id int32) (GetExerciseLogWithExerciseRow, error) {
+	row := q.db.QueryRow(ctx, getExerciseLogWithExercise, id)
+	var i GetExerciseLogWithExerciseRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ExerciseID,
+		&i.ExerciseName,
+		&i.MeasurementKind,
+		&i.LogDate,
+	)
+	return i, err
+}
+
 const getExerciseLogs = `-- name: GetExerciseLogs :many
-SELECT el.id, el.exercise_id, e.name AS exercise_name, el.reps, el.weight, el.log_date
+SELECT el.id, el.exercise_id, e.name AS exercise_name, el.reps, el.weight, el.rpe, el.rir, el.tempo, el.rest_seconds, el.notes, el.log_date
 FROM exercise_logs el
 JOIN exercises e ON el.exercise_id = e.id
 WHERE el.user_id = $1
@@ -56,6 +122,11 @@ type GetExerciseLogsRow struct {
 	ExerciseName string             `json:"exercise_name"`
 	Reps         int32              `json:"reps"`
 	Weight       pgtype.Numeric     `json:"weight"`
+	Rpe          pgtype.Numeric     `json:"rpe"`
+	Rir          pgtype.Int4        `json:"rir"`
+	Tempo        pgtype.Text        `json:"tempo"`
+	RestSeconds  pgtype.Int4        `json:"rest_seconds"`
+	Notes        pgtype.Text        `json:"notes"`
 	LogDate      pgtype.Timestamptz `json:"log_date"`
 }
 
@@ -74,6 +145,226 @@ func (q *Queries) GetExerciseLogs(ctx context.Context, userID int32) ([]GetExerc
 			&i.ExerciseName,
 			&i.Reps,
 			&i.Weight,
+			&i.Rpe,
+			&i.Rir,
+			&i.Tempo,
+			&i.RestSeconds,
+			&i.Notes,
+			&i.LogDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExerciseLogsForExport = `-- name: GetExerciseLogsForExport :many
+SELECT
+    el.id,
+    el.exercise_id,
+    e.name AS exercise_name,
+    el.reps,
+    el.weight,
+    el.additional_weight,
+    el.exercise_type,
+    el.duration_seconds,
+    el.distance_meters,
+    el.rpe,
+    el.rir,
+    el.tempo,
+    el.rest_seconds,
+    el.notes,
+    bw.bodyweight,
+    el.log_date,
+    el.created_at,
+    el.updated_at
+FROM exercise_logs el
+JOIN exercises e ON el.exercise_id = e.id
+JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+WHERE el.user_id = $1
+ORDER BY el.log_date
+`
+
+type GetExerciseLogsForExportRow struct {
+	ID               int32              `json:"id"`
+	ExerciseID       int32              `json:"exercise_id"`
+	ExerciseName     string             `json:"exercise_name"`
+	Reps             int32              `json:"reps"`
+	Weight           pgtype.Numeric     `json:"weight"`
+	AdditionalWeight pgtype.Numeric     `json:"additional_weight"`
+	ExerciseType     NullExerciseType   `json:"exercise_type"`
+	DurationSeconds  pgtype.Int4        `json:"duration_seconds"`
+	DistanceMeters   pgtype.Numeric     `json:"distance_meters"`
+	Rpe              pgtype.Numeric     `json:"rpe"`
+	Rir              pgtype.Int4        `json:"rir"`
+	Tempo            pgtype.Text        `json:"tempo"`
+	RestSeconds      pgtype.Int4        `json:"rest_seconds"`
+	Notes            pgtype.Text        `json:"notes"`
+	Bodyweight       pgtype.Numeric     `json:"bodyweight"`
+	LogDate          pgtype.Timestamptz `json:"log_date"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetExerciseLogsForExport(ctx context.Context, userID int32) ([]GetExerciseLogsForExportRow, error) {
+	rows, err := q.db.Query(ctx, getExerciseLogsForExport, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExerciseLogsForExportRow
+	for rows.Next() {
+		var i GetExerciseLogsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExerciseID,
+			&i.ExerciseName,
+			&i.Reps,
+			&i.Weight,
+			&i.AdditionalWeight,
+			&i.ExerciseType,
+			&i.DurationSeconds,
+			&i.DistanceMeters,
+			&i.Rpe,
+			&i.Rir,
+			&i.Tempo,
+			&i.RestSeconds,
+			&i.Notes,
+			&i.Bodyweight,
+			&i.LogDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExerciseLogsForProgram = `-- name: GetExerciseLogsForProgram :many
+SELECT el.exercise_id, el.reps, el.weight, el.log_date
+FROM exercise_logs el
+WHERE el.user_id = $1
+  AND el.exercise_id = ANY($2::integer[])
+  AND el.log_date >= $3
+  AND el.log_date < $4
+ORDER BY el.log_date
+`
+
+type GetExerciseLogsForProgramParams struct {
+	UserID      int32              `json:"user_id"`
+	ExerciseIds []int32            `json:"exercise_ids"`
+	FromDate    pgtype.Timestamptz `json:"from_date"`
+	ToDate      pgtype.Timestamptz `json:"to_date"`
+}
+
+type GetExerciseLogsForProgramRow struct {
+	ExerciseID int32              `json:"exercise_id"`
+	Reps       int32              `json:"reps"`
+	Weight     pgtype.Numeric     `json:"weight"`
+	LogDate    pgtype.Timestamptz `json:"log_date"`
+}
+
+func (q *Queries) GetExerciseLogsForProgram(ctx context.Context, arg GetExerciseLogsForProgramParams) ([]GetExerciseLogsForProgramRow, error) {
+	rows, err := q.db.Query(ctx, getExerciseLogsForProgram,
+		arg.UserID,
+		arg.ExerciseIds,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExerciseLogsForProgramRow
+	for rows.Next() {
+		var i GetExerciseLogsForProgramRow
+		if err := rows.Scan(
+			&i.ExerciseID,
+			&i.Reps,
+			&i.Weight,
+			&i.LogDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExerciseLogsForStats = `-- name: GetExerciseLogsForStats :many
+SELECT
+    el.reps,
+    el.weight,
+    el.additional_weight,
+    el.exercise_type,
+    el.duration_seconds,
+    el.distance_meters,
+    el.rpe,
+    bw.bodyweight,
+    el.log_date
+FROM exercise_logs el
+JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+WHERE el.user_id = $1
+  AND el.exercise_id = $2
+  AND ($3::timestamptz IS NULL OR el.log_date >= $3)
+  AND ($4::timestamptz IS NULL OR el.log_date < $4)
+ORDER BY el.log_date
+`
+
+type GetExerciseLogsForStatsParams struct {
+	UserID     int32              `json:"user_id"`
+	ExerciseID int32              `json:"exercise_id"`
+	FromDate   pgtype.Timestamptz `json:"from_date"`
+	ToDate     pgtype.Timestamptz `json:"to_date"`
+}
+
+type GetExerciseLogsForStatsRow struct {
+	Reps             int32              `json:"reps"`
+	Weight           pgtype.Numeric     `json:"weight"`
+	AdditionalWeight pgtype.Numeric     `json:"additional_weight"`
+	ExerciseType     NullExerciseType   `json:"exercise_type"`
+	DurationSeconds  pgtype.Int4        `json:"duration_seconds"`
+	DistanceMeters   pgtype.Numeric     `json:"distance_meters"`
+	Rpe              pgtype.Numeric     `json:"rpe"`
+	Bodyweight       pgtype.Numeric     `json:"bodyweight"`
+	LogDate          pgtype.Timestamptz `json:"log_date"`
+}
+
+func (q *Queries) GetExerciseLogsForStats(ctx context.Context, arg GetExerciseLogsForStatsParams) ([]GetExerciseLogsForStatsRow, error) {
+	rows, err := q.db.Query(ctx, getExerciseLogsForStats,
+		arg.UserID,
+		arg.ExerciseID,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExerciseLogsForStatsRow
+	for rows.Next() {
+		var i GetExerciseLogsForStatsRow
+		if err := rows.Scan(
+			&i.Reps,
+			&i.Weight,
+			&i.AdditionalWeight,
+			&i.ExerciseType,
+			&i.DurationSeconds,
+			&i.DistanceMeters,
+			&i.Rpe,
+			&i.Bodyweight,
 			&i.LogDate,
 		); err != nil {
 			return nil, err
@@ -104,6 +395,11 @@ SELECT
     el.log_date,
     el.reps,
     el.weight,
+    el.rpe,
+    el.rir,
+    el.tempo,
+    el.rest_seconds,
+    el.notes,
     el.bodyweight_id
 FROM
     exercises e
@@ -125,6 +421,11 @@ type GetExercisesWithLatestLogDateRow struct {
 	LogDate      pgtype.Timestamptz `json:"log_date"`
 	Reps         int32              `json:"reps"`
 	Weight       pgtype.Numeric     `json:"weight"`
+	Rpe          pgtype.Numeric     `json:"rpe"`
+	Rir          pgtype.Int4        `json:"rir"`
+	Tempo        pgtype.Text        `json:"tempo"`
+	RestSeconds  pgtype.Int4        `json:"rest_seconds"`
+	Notes        pgtype.Text        `json:"notes"`
 	BodyweightID int32              `json:"bodyweight_id"`
 }
 
@@ -143,6 +444,11 @@ func (q *Queries) GetExercisesWithLatestLogDate(ctx context.Context, userID int3
 			&i.LogDate,
 			&i.Reps,
 			&i.Weight,
+			&i.Rpe,
+			&i.Rir,
+			&i.Tempo,
+			&i.RestSeconds,
+			&i.Notes,
 			&i.BodyweightID,
 		); err != nil {
 			return nil, err
@@ -157,8 +463,8 @@ func (q *Queries) GetExercisesWithLatestLogDate(ctx context.Context, userID int3
 
 const logExercise = `-- name: LogExercise :exec
 
-INSERT INTO exercise_logs (user_id, exercise_id, reps, weight, additional_weight, exercise_type, bodyweight_id, log_date)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO exercise_logs (user_id, exercise_id, reps, weight, additional_weight, exercise_type, bodyweight_id, log_date, duration_seconds, distance_meters, rpe, rir, tempo, rest_seconds, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 `
 
 type LogExerciseParams struct {
@@ -170,6 +476,13 @@ type LogExerciseParams struct {
 	ExerciseType     NullExerciseType   `json:"exercise_type"`
 	BodyweightID     int32              `json:"bodyweight_id"`
 	LogDate          pgtype.Timestamptz `json:"log_date"`
+	DurationSeconds  pgtype.Int4        `json:"duration_seconds"`
+	DistanceMeters   pgtype.Numeric     `json:"distance_meters"`
+	Rpe              pgtype.Numeric     `json:"rpe"`
+	Rir              pgtype.Int4        `json:"rir"`
+	Tempo            pgtype.Text        `json:"tempo"`
+	RestSeconds      pgtype.Int4        `json:"rest_seconds"`
+	Notes            pgtype.Text        `json:"notes"`
 }
 
 // Exercise log queries
@@ -183,10 +496,54 @@ func (q *Queries) LogExercise(ctx context.Context, arg LogExerciseParams) error
 		arg.ExerciseType,
 		arg.BodyweightID,
 		arg.LogDate,
+		arg.DurationSeconds,
+		arg.DistanceMeters,
+		arg.Rpe,
+		arg.Rir,
+		arg.Tempo,
+		arg.RestSeconds,
+		arg.Notes,
 	)
 	return err
 }
 
+const logWorkoutSet = `-- name: LogWorkoutSet :one
+INSERT INTO exercise_logs (user_id, exercise_id, reps, weight, additional_weight, exercise_type, bodyweight_id, log_date, rpe, rest_seconds)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id
+`
+
+type LogWorkoutSetParams struct {
+	UserID           int32              `json:"user_id"`
+	ExerciseID       int32              `json:"exercise_id"`
+	Reps             int32              `json:"reps"`
+	Weight           pgtype.Numeric     `json:"weight"`
+	AdditionalWeight pgtype.Numeric     `json:"additional_weight"`
+	ExerciseType     NullExerciseType   `json:"exercise_type"`
+	BodyweightID     int32              `json:"bodyweight_id"`
+	LogDate          pgtype.Timestamptz `json:"log_date"`
+	Rpe              pgtype.Numeric     `json:"rpe"`
+	RestSeconds      pgtype.Int4        `json:"rest_seconds"`
+}
+
+func (q *Queries) LogWorkoutSet(ctx context.Context, arg LogWorkoutSetParams) (int32, error) {
+	row := q.db.QueryRow(ctx, logWorkoutSet,
+		arg.UserID,
+		arg.ExerciseID,
+		arg.Reps,
+		arg.Weight,
+		arg.AdditionalWeight,
+		arg.ExerciseType,
+		arg.BodyweightID,
+		arg.LogDate,
+		arg.Rpe,
+		arg.RestSeconds,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const updateExerciseLog = `-- name: UpdateExerciseLog :exec
 UPDATE exercise_logs
 SET
@@ -195,6 +552,13 @@ SET
     additional_weight = $5,
     exercise_type = $6,
     bodyweight_id = $7,
+    duration_seconds = $9,
+    distance_meters = $10,
+    rpe = $11,
+    rir = $12,
+    tempo = $13,
+    rest_seconds = $14,
+    notes = $15,
     updated_at = CURRENT_TIMESTAMP
 WHERE
     user_id = $1 AND exercise_id = $2 AND log_date = $8
@@ -209,6 +573,13 @@ type UpdateExerciseLogParams struct {
 	ExerciseType     NullExerciseType   `json:"exercise_type"`
 	BodyweightID     int32              `json:"bodyweight_id"`
 	LogDate          pgtype.Timestamptz `json:"log_date"`
+	DurationSeconds  pgtype.Int4        `json:"duration_seconds"`
+	DistanceMeters   pgtype.Numeric     `json:"distance_meters"`
+	Rpe              pgtype.Numeric     `json:"rpe"`
+	Rir              pgtype.Int4        `json:"rir"`
+	Tempo            pgtype.Text        `json:"tempo"`
+	RestSeconds      pgtype.Int4        `json:"rest_seconds"`
+	Notes            pgtype.Text        `json:"notes"`
 }
 
 func (q *Queries) UpdateExerciseLog(ctx context.Context, arg UpdateExerciseLogParams) error {
@@ -221,6 +592,13 @@ func (q *Queries) UpdateExerciseLog(ctx context.Context, arg UpdateExerciseLogPa
 		arg.ExerciseType,
 		arg.BodyweightID,
 		arg.LogDate,
+		arg.DurationSeconds,
+		arg.DistanceMeters,
+		arg.Rpe,
+		arg.Rir,
+		arg.Tempo,
+		arg.RestSeconds,
+		arg.Notes,
 	)
 	return err
 }
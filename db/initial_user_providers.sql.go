@@ -11,6 +11,44 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getInitialUserProvidersByUserID = `-- name: GetInitialUserProvidersByUserID :many
+SELECT id, user_id, provider, provider_user_id, first_name, last_name, nickname, avatar_url, location, created_at
+FROM initial_user_providers
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetInitialUserProvidersByUserID(ctx context.Context, userID int32) ([]InitialUserProvider, error) {
+	rows, err := q.db.Query(ctx, getInitialUserProvidersByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InitialUserProvider
+	for rows.Next() {
+		var i InitialUserProvider
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.ProviderUserID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Nickname,
+			&i.AvatarUrl,
+			&i.Location,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertInitialUserProvider = `-- name: InsertInitialUserProvider :exec
 
 INSERT INTO initial_user_providers (user_id, provider, provider_user_id, first_name, last_name, nickname, avatar_url, location, created_at)
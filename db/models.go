@@ -11,47 +11,891 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AttemptResult string
+
+const (
+	AttemptResultPending AttemptResult = "pending"
+	AttemptResultGood    AttemptResult = "good"
+	AttemptResultNoLift  AttemptResult = "no_lift"
+)
+
+func (e *AttemptResult) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AttemptResult(s)
+	case string:
+		*e = AttemptResult(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AttemptResult: %T", src)
+	}
+	return nil
+}
+
+type NullAttemptResult struct {
+	AttemptResult AttemptResult `json:"attempt_result"`
+	Valid         bool          `json:"valid"` // Valid is true if AttemptResult is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAttemptResult) Scan(value interface{}) error {
+	if value == nil {
+		ns.AttemptResult, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AttemptResult.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAttemptResult) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AttemptResult), nil
+}
+
+type BodyMeasurementKind string
+
+const (
+	BodyMeasurementKindNeck         BodyMeasurementKind = "neck"
+	BodyMeasurementKindShoulders    BodyMeasurementKind = "shoulders"
+	BodyMeasurementKindChest        BodyMeasurementKind = "chest"
+	BodyMeasurementKindWaist        BodyMeasurementKind = "waist"
+	BodyMeasurementKindHips         BodyMeasurementKind = "hips"
+	BodyMeasurementKindLeftArm      BodyMeasurementKind = "left_arm"
+	BodyMeasurementKindRightArm     BodyMeasurementKind = "right_arm"
+	BodyMeasurementKindLeftForearm  BodyMeasurementKind = "left_forearm"
+	BodyMeasurementKindRightForearm BodyMeasurementKind = "right_forearm"
+	BodyMeasurementKindLeftThigh    BodyMeasurementKind = "left_thigh"
+	BodyMeasurementKindRightThigh   BodyMeasurementKind = "right_thigh"
+	BodyMeasurementKindLeftCalf     BodyMeasurementKind = "left_calf"
+	BodyMeasurementKindRightCalf    BodyMeasurementKind = "right_calf"
+	BodyMeasurementKindBodyFat      BodyMeasurementKind = "body_fat"
+)
+
+func (e *BodyMeasurementKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BodyMeasurementKind(s)
+	case string:
+		*e = BodyMeasurementKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BodyMeasurementKind: %T", src)
+	}
+	return nil
+}
+
+type NullBodyMeasurementKind struct {
+	BodyMeasurementKind BodyMeasurementKind `json:"body_measurement_kind"`
+	Valid               bool                `json:"valid"` // Valid is true if BodyMeasurementKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBodyMeasurementKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.BodyMeasurementKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BodyMeasurementKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBodyMeasurementKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BodyMeasurementKind), nil
+}
+
+type ChallengeMetric string
+
+const (
+	ChallengeMetricTotalReps ChallengeMetric = "total_reps"
+	ChallengeMetricTonnage   ChallengeMetric = "tonnage"
+	ChallengeMetricSessions  ChallengeMetric = "sessions"
+	ChallengeMetricBestE1rm  ChallengeMetric = "best_e1rm"
+)
+
+func (e *ChallengeMetric) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ChallengeMetric(s)
+	case string:
+		*e = ChallengeMetric(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ChallengeMetric: %T", src)
+	}
+	return nil
+}
+
+type NullChallengeMetric struct {
+	ChallengeMetric ChallengeMetric `json:"challenge_metric"`
+	Valid           bool            `json:"valid"` // Valid is true if ChallengeMetric is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullChallengeMetric) Scan(value interface{}) error {
+	if value == nil {
+		ns.ChallengeMetric, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ChallengeMetric.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullChallengeMetric) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ChallengeMetric), nil
+}
+
+type ChallengeParticipantStatus string
+
+const (
+	ChallengeParticipantStatusInvited ChallengeParticipantStatus = "invited"
+	ChallengeParticipantStatusJoined  ChallengeParticipantStatus = "joined"
+)
+
+func (e *ChallengeParticipantStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ChallengeParticipantStatus(s)
+	case string:
+		*e = ChallengeParticipantStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ChallengeParticipantStatus: %T", src)
+	}
+	return nil
+}
+
+type NullChallengeParticipantStatus struct {
+	ChallengeParticipantStatus ChallengeParticipantStatus `json:"challenge_participant_status"`
+	Valid                      bool                       `json:"valid"` // Valid is true if ChallengeParticipantStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullChallengeParticipantStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ChallengeParticipantStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ChallengeParticipantStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullChallengeParticipantStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ChallengeParticipantStatus), nil
+}
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"
+	DataExportStatusProcessing DataExportStatus = "processing"
+	DataExportStatusCompleted  DataExportStatus = "completed"
+	DataExportStatusFailed     DataExportStatus = "failed"
+)
+
+func (e *DataExportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DataExportStatus(s)
+	case string:
+		*e = DataExportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DataExportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDataExportStatus struct {
+	DataExportStatus DataExportStatus `json:"data_export_status"`
+	Valid            bool             `json:"valid"` // Valid is true if DataExportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDataExportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DataExportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DataExportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDataExportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DataExportStatus), nil
+}
+
+type EquipmentType string
+
+const (
+	EquipmentTypeBarbell    EquipmentType = "barbell"
+	EquipmentTypeDumbbell   EquipmentType = "dumbbell"
+	EquipmentTypeKettlebell EquipmentType = "kettlebell"
+	EquipmentTypeMachine    EquipmentType = "machine"
+	EquipmentTypeCable      EquipmentType = "cable"
+	EquipmentTypeBodyweight EquipmentType = "bodyweight"
+	EquipmentTypeBand       EquipmentType = "band"
+	EquipmentTypeOther      EquipmentType = "other"
+)
+
+func (e *EquipmentType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EquipmentType(s)
+	case string:
+		*e = EquipmentType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EquipmentType: %T", src)
+	}
+	return nil
+}
+
+type NullEquipmentType struct {
+	EquipmentType EquipmentType `json:"equipment_type"`
+	Valid         bool          `json:"valid"` // Valid is true if EquipmentType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEquipmentType) Scan(value interface{}) error {
+	if value == nil {
+		ns.EquipmentType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EquipmentType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEquipmentType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EquipmentType), nil
+}
+
 type ExerciseType string
 
 const (
-	ExerciseTypeBodyweight ExerciseType = "Bodyweight"
-	ExerciseTypeWeighted   ExerciseType = "Weighted"
-	ExerciseTypeAssisted   ExerciseType = "Assisted"
+	ExerciseTypeBodyweight ExerciseType = "Bodyweight"
+	ExerciseTypeWeighted   ExerciseType = "Weighted"
+	ExerciseTypeAssisted   ExerciseType = "Assisted"
+)
+
+func (e *ExerciseType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ExerciseType(s)
+	case string:
+		*e = ExerciseType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ExerciseType: %T", src)
+	}
+	return nil
+}
+
+type NullExerciseType struct {
+	ExerciseType ExerciseType `json:"exercise_type"`
+	Valid        bool         `json:"valid"` // Valid is true if ExerciseType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullExerciseType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ExerciseType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ExerciseType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullExerciseType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ExerciseType), nil
+}
+
+type GoalMetric string
+
+const (
+	GoalMetricEstimated1rm   GoalMetric = "estimated_1rm"
+	GoalMetricHeaviestWeight GoalMetric = "heaviest_weight"
+	GoalMetricMostReps       GoalMetric = "most_reps"
+)
+
+func (e *GoalMetric) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = GoalMetric(s)
+	case string:
+		*e = GoalMetric(s)
+	default:
+		return fmt.Errorf("unsupported scan type for GoalMetric: %T", src)
+	}
+	return nil
+}
+
+type NullGoalMetric struct {
+	GoalMetric GoalMetric `json:"goal_metric"`
+	Valid      bool       `json:"valid"` // Valid is true if GoalMetric is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullGoalMetric) Scan(value interface{}) error {
+	if value == nil {
+		ns.GoalMetric, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.GoalMetric.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullGoalMetric) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.GoalMetric), nil
+}
+
+type ImportSource string
+
+const (
+	ImportSourceStrong  ImportSource = "strong"
+	ImportSourceHevy    ImportSource = "hevy"
+	ImportSourceGeneric ImportSource = "generic"
+)
+
+func (e *ImportSource) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImportSource(s)
+	case string:
+		*e = ImportSource(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImportSource: %T", src)
+	}
+	return nil
+}
+
+type NullImportSource struct {
+	ImportSource ImportSource `json:"import_source"`
+	Valid        bool         `json:"valid"` // Valid is true if ImportSource is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImportSource) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImportSource, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImportSource.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImportSource) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImportSource), nil
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusPreview   ImportStatus = "preview"
+	ImportStatusCommitted ImportStatus = "committed"
+)
+
+func (e *ImportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImportStatus(s)
+	case string:
+		*e = ImportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullImportStatus struct {
+	ImportStatus ImportStatus `json:"import_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ImportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImportStatus), nil
+}
+
+type InsightKind string
+
+const (
+	InsightKindPlateau         InsightKind = "plateau"
+	InsightKindRegression      InsightKind = "regression"
+	InsightKindFastGain        InsightKind = "fast_gain"
+	InsightKindSuspiciousEntry InsightKind = "suspicious_entry"
+)
+
+func (e *InsightKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InsightKind(s)
+	case string:
+		*e = InsightKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InsightKind: %T", src)
+	}
+	return nil
+}
+
+type NullInsightKind struct {
+	InsightKind InsightKind `json:"insight_kind"`
+	Valid       bool        `json:"valid"` // Valid is true if InsightKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInsightKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.InsightKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InsightKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInsightKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InsightKind), nil
+}
+
+type MeasurementKind string
+
+const (
+	MeasurementKindRepsWeight     MeasurementKind = "reps_weight"
+	MeasurementKindTime           MeasurementKind = "time"
+	MeasurementKindDistance       MeasurementKind = "distance"
+	MeasurementKindDistanceWeight MeasurementKind = "distance_weight"
+	MeasurementKindTimeWeight     MeasurementKind = "time_weight"
+)
+
+func (e *MeasurementKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeasurementKind(s)
+	case string:
+		*e = MeasurementKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeasurementKind: %T", src)
+	}
+	return nil
+}
+
+type NullMeasurementKind struct {
+	MeasurementKind MeasurementKind `json:"measurement_kind"`
+	Valid           bool            `json:"valid"` // Valid is true if MeasurementKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeasurementKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeasurementKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeasurementKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeasurementKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeasurementKind), nil
+}
+
+type MeasurementSource string
+
+const (
+	MeasurementSourceTape       MeasurementSource = "tape"
+	MeasurementSourceCalipers   MeasurementSource = "calipers"
+	MeasurementSourceSmartScale MeasurementSource = "smart_scale"
+	MeasurementSourceDexa       MeasurementSource = "dexa"
+	MeasurementSourceOther      MeasurementSource = "other"
+)
+
+func (e *MeasurementSource) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeasurementSource(s)
+	case string:
+		*e = MeasurementSource(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeasurementSource: %T", src)
+	}
+	return nil
+}
+
+type NullMeasurementSource struct {
+	MeasurementSource MeasurementSource `json:"measurement_source"`
+	Valid             bool              `json:"valid"` // Valid is true if MeasurementSource is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeasurementSource) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeasurementSource, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeasurementSource.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeasurementSource) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeasurementSource), nil
+}
+
+type MeetLift string
+
+const (
+	MeetLiftSquat    MeetLift = "squat"
+	MeetLiftBench    MeetLift = "bench"
+	MeetLiftDeadlift MeetLift = "deadlift"
+)
+
+func (e *MeetLift) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeetLift(s)
+	case string:
+		*e = MeetLift(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeetLift: %T", src)
+	}
+	return nil
+}
+
+type NullMeetLift struct {
+	MeetLift MeetLift `json:"meet_lift"`
+	Valid    bool     `json:"valid"` // Valid is true if MeetLift is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeetLift) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeetLift, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeetLift.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeetLift) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeetLift), nil
+}
+
+type MeetScoring string
+
+const (
+	MeetScoringDots  MeetScoring = "dots"
+	MeetScoringIpfGl MeetScoring = "ipf_gl"
+)
+
+func (e *MeetScoring) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeetScoring(s)
+	case string:
+		*e = MeetScoring(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeetScoring: %T", src)
+	}
+	return nil
+}
+
+type NullMeetScoring struct {
+	MeetScoring MeetScoring `json:"meet_scoring"`
+	Valid       bool        `json:"valid"` // Valid is true if MeetScoring is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeetScoring) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeetScoring, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeetScoring.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeetScoring) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeetScoring), nil
+}
+
+type MeetStatus string
+
+const (
+	MeetStatusOpen   MeetStatus = "open"
+	MeetStatusClosed MeetStatus = "closed"
+)
+
+func (e *MeetStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeetStatus(s)
+	case string:
+		*e = MeetStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeetStatus: %T", src)
+	}
+	return nil
+}
+
+type NullMeetStatus struct {
+	MeetStatus MeetStatus `json:"meet_status"`
+	Valid      bool       `json:"valid"` // Valid is true if MeetStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeetStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeetStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeetStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeetStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeetStatus), nil
+}
+
+type MovementPattern string
+
+const (
+	MovementPatternSquat          MovementPattern = "squat"
+	MovementPatternHinge          MovementPattern = "hinge"
+	MovementPatternLunge          MovementPattern = "lunge"
+	MovementPatternHorizontalPush MovementPattern = "horizontal_push"
+	MovementPatternVerticalPush   MovementPattern = "vertical_push"
+	MovementPatternHorizontalPull MovementPattern = "horizontal_pull"
+	MovementPatternVerticalPull   MovementPattern = "vertical_pull"
+	MovementPatternCarry          MovementPattern = "carry"
+	MovementPatternCore           MovementPattern = "core"
+	MovementPatternIsolation      MovementPattern = "isolation"
+	MovementPatternOlympic        MovementPattern = "olympic"
+)
+
+func (e *MovementPattern) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MovementPattern(s)
+	case string:
+		*e = MovementPattern(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MovementPattern: %T", src)
+	}
+	return nil
+}
+
+type NullMovementPattern struct {
+	MovementPattern MovementPattern `json:"movement_pattern"`
+	Valid           bool            `json:"valid"` // Valid is true if MovementPattern is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMovementPattern) Scan(value interface{}) error {
+	if value == nil {
+		ns.MovementPattern, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MovementPattern.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMovementPattern) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MovementPattern), nil
+}
+
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner   OrganizationRole = "owner"
+	OrganizationRoleCoach   OrganizationRole = "coach"
+	OrganizationRoleAthlete OrganizationRole = "athlete"
+)
+
+func (e *OrganizationRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrganizationRole(s)
+	case string:
+		*e = OrganizationRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrganizationRole: %T", src)
+	}
+	return nil
+}
+
+type NullOrganizationRole struct {
+	OrganizationRole OrganizationRole `json:"organization_role"`
+	Valid            bool             `json:"valid"` // Valid is true if OrganizationRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrganizationRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrganizationRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrganizationRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrganizationRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrganizationRole), nil
+}
+
+type ProgramEnrollmentStatus string
+
+const (
+	ProgramEnrollmentStatusActive  ProgramEnrollmentStatus = "active"
+	ProgramEnrollmentStatusStopped ProgramEnrollmentStatus = "stopped"
 )
 
-func (e *ExerciseType) Scan(src interface{}) error {
+func (e *ProgramEnrollmentStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = ExerciseType(s)
+		*e = ProgramEnrollmentStatus(s)
 	case string:
-		*e = ExerciseType(s)
+		*e = ProgramEnrollmentStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for ExerciseType: %T", src)
+		return fmt.Errorf("unsupported scan type for ProgramEnrollmentStatus: %T", src)
 	}
 	return nil
 }
 
-type NullExerciseType struct {
-	ExerciseType ExerciseType `json:"exercise_type"`
-	Valid        bool         `json:"valid"` // Valid is true if ExerciseType is not NULL
+type NullProgramEnrollmentStatus struct {
+	ProgramEnrollmentStatus ProgramEnrollmentStatus `json:"program_enrollment_status"`
+	Valid                   bool                    `json:"valid"` // Valid is true if ProgramEnrollmentStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullExerciseType) Scan(value interface{}) error {
+func (ns *NullProgramEnrollmentStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.ExerciseType, ns.Valid = "", false
+		ns.ProgramEnrollmentStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.ExerciseType.Scan(value)
+	return ns.ProgramEnrollmentStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullExerciseType) Value() (driver.Value, error) {
+func (ns NullProgramEnrollmentStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.ExerciseType), nil
+	return string(ns.ProgramEnrollmentStatus), nil
+}
+
+type ReactionKind string
+
+const (
+	ReactionKindLike   ReactionKind = "like"
+	ReactionKindFire   ReactionKind = "fire"
+	ReactionKindStrong ReactionKind = "strong"
+	ReactionKindClap   ReactionKind = "clap"
+)
+
+func (e *ReactionKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReactionKind(s)
+	case string:
+		*e = ReactionKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReactionKind: %T", src)
+	}
+	return nil
+}
+
+type NullReactionKind struct {
+	ReactionKind ReactionKind `json:"reaction_kind"`
+	Valid        bool         `json:"valid"` // Valid is true if ReactionKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReactionKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReactionKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReactionKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReactionKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReactionKind), nil
 }
 
 type UnitSystem string
@@ -96,6 +940,123 @@ func (ns NullUnitSystem) Value() (driver.Value, error) {
 	return string(ns.UnitSystem), nil
 }
 
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+type WorkoutSessionStatus string
+
+const (
+	WorkoutSessionStatusInProgress WorkoutSessionStatus = "in_progress"
+	WorkoutSessionStatusCompleted  WorkoutSessionStatus = "completed"
+)
+
+func (e *WorkoutSessionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkoutSessionStatus(s)
+	case string:
+		*e = WorkoutSessionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkoutSessionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkoutSessionStatus struct {
+	WorkoutSessionStatus WorkoutSessionStatus `json:"workout_session_status"`
+	Valid                bool                 `json:"valid"` // Valid is true if WorkoutSessionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkoutSessionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkoutSessionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkoutSessionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkoutSessionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkoutSessionStatus), nil
+}
+
+type AnalyticsCache struct {
+	UserID     int32              `json:"user_id"`
+	CacheKey   string             `json:"cache_key"`
+	Payload    []byte             `json:"payload"`
+	ComputedAt pgtype.Timestamptz `json:"computed_at"`
+}
+
+type AnalyticsVersion struct {
+	UserID  int32 `json:"user_id"`
+	Version int64 `json:"version"`
+}
+
+type BodyMeasurement struct {
+	ID         int32                 `json:"id"`
+	UserID     int32                 `json:"user_id"`
+	Kind       BodyMeasurementKind   `json:"kind"`
+	Value      pgtype.Numeric        `json:"value"`
+	Source     NullMeasurementSource `json:"source"`
+	MeasuredAt pgtype.Timestamptz    `json:"measured_at"`
+	CreatedAt  pgtype.Timestamptz    `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz    `json:"updated_at"`
+}
+
+type BodyweightGoal struct {
+	UserID       int32              `json:"user_id"`
+	TargetWeight pgtype.Numeric     `json:"target_weight"`
+	TargetDate   pgtype.Date        `json:"target_date"`
+	StartWeight  pgtype.Numeric     `json:"start_weight"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
 type BodyweightLog struct {
 	ID         int32              `json:"id"`
 	UserID     int32              `json:"user_id"`
@@ -105,9 +1066,103 @@ type BodyweightLog struct {
 	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
 }
 
+type Challenge struct {
+	ID          int32              `json:"id"`
+	OwnerUserID int32              `json:"owner_user_id"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Metric      ChallengeMetric    `json:"metric"`
+	ExerciseID  pgtype.Int4        `json:"exercise_id"`
+	StartsOn    pgtype.Date        `json:"starts_on"`
+	EndsOn      pgtype.Date        `json:"ends_on"`
+	OpenJoin    bool               `json:"open_join"`
+	FinalizedAt pgtype.Timestamptz `json:"finalized_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ChallengeParticipant struct {
+	ChallengeID int32                      `json:"challenge_id"`
+	UserID      int32                      `json:"user_id"`
+	Status      ChallengeParticipantStatus `json:"status"`
+	InvitedBy   pgtype.Int4                `json:"invited_by"`
+	JoinedAt    pgtype.Timestamptz         `json:"joined_at"`
+	FinalRank   pgtype.Int4                `json:"final_rank"`
+	FinalValue  pgtype.Numeric             `json:"final_value"`
+	CreatedAt   pgtype.Timestamptz         `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz         `json:"updated_at"`
+}
+
+type CoachAssignment struct {
+	ID             int32              `json:"id"`
+	OrganizationID int32              `json:"organization_id"`
+	CoachUserID    pgtype.Int4        `json:"coach_user_id"`
+	AthleteUserID  int32              `json:"athlete_user_id"`
+	TemplateID     pgtype.Int4        `json:"template_id"`
+	ProgramID      pgtype.Int4        `json:"program_id"`
+	EnrollmentID   pgtype.Int4        `json:"enrollment_id"`
+	Note           pgtype.Text        `json:"note"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type Comment struct {
+	ID            int32              `json:"id"`
+	UserID        int32              `json:"user_id"`
+	WorkoutID     pgtype.Int4        `json:"workout_id"`
+	ExerciseLogID pgtype.Int4        `json:"exercise_log_id"`
+	ParentID      pgtype.Int4        `json:"parent_id"`
+	Body          string             `json:"body"`
+	EditedAt      pgtype.Timestamptz `json:"edited_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CommentMention struct {
+	CommentID int32 `json:"comment_id"`
+	UserID    int32 `json:"user_id"`
+}
+
+type DataExport struct {
+	ID          int32              `json:"id"`
+	UserID      int32              `json:"user_id"`
+	Status      DataExportStatus   `json:"status"`
+	StorageKey  pgtype.Text        `json:"storage_key"`
+	Error       pgtype.Text        `json:"error"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Event struct {
+	ID         int64              `json:"id"`
+	UserID     int32              `json:"user_id"`
+	Type       string             `json:"type"`
+	Payload    []byte             `json:"payload"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	NotifiedAt pgtype.Timestamptz `json:"notified_at"`
+}
+
 type Exercise struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
+	ID                    int32               `json:"id"`
+	Name                  string              `json:"name"`
+	OwnerUserID           pgtype.Int4         `json:"owner_user_id"`
+	PrimaryMuscleGroups   []string            `json:"primary_muscle_groups"`
+	SecondaryMuscleGroups []string            `json:"secondary_muscle_groups"`
+	Equipment             NullEquipmentType   `json:"equipment"`
+	MovementPattern       NullMovementPattern `json:"movement_pattern"`
+	IsUnilateral          bool                `json:"is_unilateral"`
+	DefaultExerciseType   NullExerciseType    `json:"default_exercise_type"`
+	MeasurementKind       MeasurementKind     `json:"measurement_kind"`
+	CreatedAt             pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz  `json:"updated_at"`
+}
+
+type ExerciseAlias struct {
+	ID         int32              `json:"id"`
+	ExerciseID int32              `json:"exercise_id"`
+	Alias      string             `json:"alias"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
 type ExerciseLog struct {
@@ -118,6 +1173,13 @@ type ExerciseLog struct {
 	Weight           pgtype.Numeric     `json:"weight"`
 	AdditionalWeight pgtype.Numeric     `json:"additional_weight"`
 	ExerciseType     NullExerciseType   `json:"exercise_type"`
+	DurationSeconds  pgtype.Int4        `json:"duration_seconds"`
+	DistanceMeters   pgtype.Numeric     `json:"distance_meters"`
+	Rpe              pgtype.Numeric     `json:"rpe"`
+	Rir              pgtype.Int4        `json:"rir"`
+	Tempo            pgtype.Text        `json:"tempo"`
+	RestSeconds      pgtype.Int4        `json:"rest_seconds"`
+	Notes            pgtype.Text        `json:"notes"`
 	BodyweightID     int32              `json:"bodyweight_id"`
 	LogDate          pgtype.Timestamptz `json:"log_date"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
@@ -137,6 +1199,167 @@ type InitialUserProvider struct {
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 }
 
+type Insight struct {
+	ID            int32              `json:"id"`
+	UserID        int32              `json:"user_id"`
+	Kind          InsightKind        `json:"kind"`
+	DedupeKey     string             `json:"dedupe_key"`
+	ExerciseID    int32              `json:"exercise_id"`
+	ExerciseLogID pgtype.Int4        `json:"exercise_log_id"`
+	Details       []byte             `json:"details"`
+	DetectedAt    pgtype.Timestamptz `json:"detected_at"`
+	DismissedAt   pgtype.Timestamptz `json:"dismissed_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type InsightScan struct {
+	UserID    int32              `json:"user_id"`
+	ScannedAt pgtype.Timestamptz `json:"scanned_at"`
+}
+
+type Meet struct {
+	ID          int32              `json:"id"`
+	OwnerUserID int32              `json:"owner_user_id"`
+	Name        string             `json:"name"`
+	MeetDate    pgtype.Date        `json:"meet_date"`
+	Scoring     MeetScoring        `json:"scoring"`
+	Status      MeetStatus         `json:"status"`
+	ClosedAt    pgtype.Timestamptz `json:"closed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type MeetAttempt struct {
+	ID            int32              `json:"id"`
+	EntryID       int32              `json:"entry_id"`
+	Lift          MeetLift           `json:"lift"`
+	Attempt       int16              `json:"attempt"`
+	Weight        pgtype.Numeric     `json:"weight"`
+	Result        AttemptResult      `json:"result"`
+	ExerciseLogID pgtype.Int4        `json:"exercise_log_id"`
+	JudgedAt      pgtype.Timestamptz `json:"judged_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type MeetDivision struct {
+	ID          int32          `json:"id"`
+	MeetID      int32          `json:"meet_id"`
+	Name        string         `json:"name"`
+	Sex         string         `json:"sex"`
+	WeightClass pgtype.Numeric `json:"weight_class"`
+	MinAge      pgtype.Int2    `json:"min_age"`
+	MaxAge      pgtype.Int2    `json:"max_age"`
+}
+
+type MeetEntry struct {
+	ID          int32              `json:"id"`
+	MeetID      int32              `json:"meet_id"`
+	DivisionID  int32              `json:"division_id"`
+	UserID      int32              `json:"user_id"`
+	Age         pgtype.Int2        `json:"age"`
+	Bodyweight  pgtype.Numeric     `json:"bodyweight"`
+	ConfirmedAt pgtype.Timestamptz `json:"confirmed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Notification struct {
+	ID        int64              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	Type      string             `json:"type"`
+	Title     string             `json:"title"`
+	Body      string             `json:"body"`
+	Data      []byte             `json:"data"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserID    int32              `json:"user_id"`
+	Type      string             `json:"type"`
+	InApp     bool               `json:"in_app"`
+	Email     bool               `json:"email"`
+	Push      bool               `json:"push"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Organization struct {
+	ID          int32              `json:"id"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type OrganizationInvite struct {
+	ID             int32              `json:"id"`
+	OrganizationID int32              `json:"organization_id"`
+	Code           string             `json:"code"`
+	Role           OrganizationRole   `json:"role"`
+	CreatedBy      pgtype.Int4        `json:"created_by"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+	MaxUses        pgtype.Int4        `json:"max_uses"`
+	Uses           int32              `json:"uses"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrganizationMember struct {
+	OrganizationID int32              `json:"organization_id"`
+	UserID         int32              `json:"user_id"`
+	Role           OrganizationRole   `json:"role"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type PlateInventory struct {
+	UserID       int32              `json:"user_id"`
+	Unit         UnitSystem         `json:"unit"`
+	BarWeight    pgtype.Numeric     `json:"bar_weight"`
+	CollarWeight pgtype.Numeric     `json:"collar_weight"`
+	Plates       []byte             `json:"plates"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type PrivacySetting struct {
+	UserID                   int32              `json:"user_id"`
+	ShareTrainingWithCoaches bool               `json:"share_training_with_coaches"`
+	ShareBodyWithCoaches     bool               `json:"share_body_with_coaches"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ProgramEnrollment struct {
+	ID           int32                   `json:"id"`
+	UserID       int32                   `json:"user_id"`
+	ProgramID    int32                   `json:"program_id"`
+	Status       ProgramEnrollmentStatus `json:"status"`
+	State        []byte                  `json:"state"`
+	DayStartedAt pgtype.Timestamptz      `json:"day_started_at"`
+	CreatedAt    pgtype.Timestamptz      `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz      `json:"updated_at"`
+}
+
+type PushSubscription struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	Endpoint  string             `json:"endpoint"`
+	P256dh    string             `json:"p256dh"`
+	Auth      string             `json:"auth"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Reaction struct {
+	ID            int32              `json:"id"`
+	UserID        int32              `json:"user_id"`
+	WorkoutID     pgtype.Int4        `json:"workout_id"`
+	ExerciseLogID pgtype.Int4        `json:"exercise_log_id"`
+	Kind          ReactionKind       `json:"kind"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
 type RefreshToken struct {
 	ID        int32              `json:"id"`
 	UserID    int32              `json:"user_id"`
@@ -145,6 +1368,66 @@ type RefreshToken struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type StreakSetting struct {
+	UserID            int32              `json:"user_id"`
+	TargetDaysPerWeek int16              `json:"target_days_per_week"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StrengthGoal struct {
+	ID         int32              `json:"id"`
+	UserID     int32              `json:"user_id"`
+	ExerciseID int32              `json:"exercise_id"`
+	Metric     GoalMetric         `json:"metric"`
+	Target     pgtype.Numeric     `json:"target"`
+	Deadline   pgtype.Date        `json:"deadline"`
+	StartValue pgtype.Numeric     `json:"start_value"`
+	AchievedAt pgtype.Timestamptz `json:"achieved_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StrengthStandard struct {
+	ID              int32          `json:"id"`
+	VersionID       int32          `json:"version_id"`
+	ExerciseID      int32          `json:"exercise_id"`
+	Sex             string         `json:"sex"`
+	BodyweightClass pgtype.Numeric `json:"bodyweight_class"`
+	Beginner        pgtype.Numeric `json:"beginner"`
+	Novice          pgtype.Numeric `json:"novice"`
+	Intermediate    pgtype.Numeric `json:"intermediate"`
+	Advanced        pgtype.Numeric `json:"advanced"`
+	Elite           pgtype.Numeric `json:"elite"`
+}
+
+type StrengthStandardVersion struct {
+	ID          int32              `json:"id"`
+	Name        string             `json:"name"`
+	PublishedAt pgtype.Date        `json:"published_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type StrengthStanding struct {
+	UserID     int32              `json:"user_id"`
+	ExerciseID int32              `json:"exercise_id"`
+	OneRepMax  pgtype.Numeric     `json:"one_rep_max"`
+	Bodyweight pgtype.Numeric     `json:"bodyweight"`
+	LogDate    pgtype.Timestamptz `json:"log_date"`
+	Percentile float64            `json:"percentile"`
+	Population int64              `json:"population"`
+}
+
+type TrainingProgram struct {
+	ID          int32              `json:"id"`
+	OwnerUserID pgtype.Int4        `json:"owner_user_id"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Definition  []byte             `json:"definition"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Trophy struct {
 	ID          int32              `json:"id"`
 	Name        string             `json:"name"`
@@ -165,6 +1448,8 @@ type User struct {
 	Bio            pgtype.Text        `json:"bio"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+	PurgeAfter     pgtype.Timestamptz `json:"purge_after"`
 }
 
 type UserProvider struct {
@@ -189,3 +1474,99 @@ type UserTrophy struct {
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 }
+
+type Webhook struct {
+	ID             int32              `json:"id"`
+	UserID         pgtype.Int4        `json:"user_id"`
+	OrganizationID pgtype.Int4        `json:"organization_id"`
+	Url            string             `json:"url"`
+	Secret         string             `json:"secret"`
+	EventTypes     []string           `json:"event_types"`
+	Description    pgtype.Text        `json:"description"`
+	Active         bool               `json:"active"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	WebhookID      int32                 `json:"webhook_id"`
+	EventID        pgtype.Int8           `json:"event_id"`
+	UserID         int32                 `json:"user_id"`
+	EventType      string                `json:"event_type"`
+	Payload        []byte                `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                 `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamptz    `json:"next_attempt_at"`
+	LastAttemptAt  pgtype.Timestamptz    `json:"last_attempt_at"`
+	ResponseStatus pgtype.Int4           `json:"response_status"`
+	LastError      pgtype.Text           `json:"last_error"`
+	DeliveredAt    pgtype.Timestamptz    `json:"delivered_at"`
+	CreatedAt      pgtype.Timestamptz    `json:"created_at"`
+}
+
+type WorkoutImport struct {
+	ID               int32              `json:"id"`
+	UserID           int32              `json:"user_id"`
+	Source           ImportSource       `json:"source"`
+	Status           ImportStatus       `json:"status"`
+	ParsedRows       []byte             `json:"parsed_rows"`
+	ParseErrors      []byte             `json:"parse_errors"`
+	ExerciseMappings []byte             `json:"exercise_mappings"`
+	Report           []byte             `json:"report"`
+	CommittedAt      pgtype.Timestamptz `json:"committed_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WorkoutSession struct {
+	ID          int32                `json:"id"`
+	UserID      int32                `json:"user_id"`
+	TemplateID  pgtype.Int4          `json:"template_id"`
+	Name        string               `json:"name"`
+	Status      WorkoutSessionStatus `json:"status"`
+	StartedAt   pgtype.Timestamptz   `json:"started_at"`
+	CompletedAt pgtype.Timestamptz   `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz   `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz   `json:"updated_at"`
+}
+
+type WorkoutSessionSet struct {
+	ID            int32              `json:"id"`
+	SessionID     int32              `json:"session_id"`
+	ExerciseID    int32              `json:"exercise_id"`
+	Position      int32              `json:"position"`
+	SetNumber     int32              `json:"set_number"`
+	TargetReps    pgtype.Int4        `json:"target_reps"`
+	TargetWeight  pgtype.Numeric     `json:"target_weight"`
+	RestSeconds   pgtype.Int4        `json:"rest_seconds"`
+	Reps          pgtype.Int4        `json:"reps"`
+	Weight        pgtype.Numeric     `json:"weight"`
+	Rpe           pgtype.Numeric     `json:"rpe"`
+	CompletedAt   pgtype.Timestamptz `json:"completed_at"`
+	ExerciseLogID pgtype.Int4        `json:"exercise_log_id"`
+}
+
+type WorkoutTemplate struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	Name         string             `json:"name"`
+	Description  pgtype.Text        `json:"description"`
+	ShareCode    pgtype.Text        `json:"share_code"`
+	ClonedFromID pgtype.Int4        `json:"cloned_from_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WorkoutTemplateExercise struct {
+	ID               int32          `json:"id"`
+	TemplateID       int32          `json:"template_id"`
+	Position         int32          `json:"position"`
+	ExerciseID       int32          `json:"exercise_id"`
+	TargetSets       int32          `json:"target_sets"`
+	TargetReps       pgtype.Int4    `json:"target_reps"`
+	TargetWeight     pgtype.Numeric `json:"target_weight"`
+	TargetPercentage pgtype.Numeric `json:"target_percentage"`
+	RestSeconds      pgtype.Int4    `json:"rest_seconds"`
+	Notes            pgtype.Text    `json:"notes"`
+}
@@ -36,6 +36,43 @@ func (q *Queries) DeleteRefreshToken(ctx context.Context, arg DeleteRefreshToken
 	return err
 }
 
+const getRefreshTokensByUserID = `-- name: GetRefreshTokensByUserID :many
+SELECT id, expires_at, created_at
+FROM refresh_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+type GetRefreshTokensByUserIDRow struct {
+	ID        int32              `json:"id"`
+	ExpiresAt pgtype.Timestamp   `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetRefreshTokensByUserID(ctx context.Context, userID int32) ([]GetRefreshTokensByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getRefreshTokensByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRefreshTokensByUserIDRow
+	for rows.Next() {
+		var i GetRefreshTokensByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserByRefreshToken = `-- name: GetUserByRefreshToken :one
 SELECT u.id, u.username, u.email, u.name
 FROM users u
@@ -81,18 +118,19 @@ func (q *Queries) InsertOrUpdateRefreshToken(ctx context.Context, arg InsertOrUp
 }
 
 const validateRefreshToken = `-- name: ValidateRefreshToken :one
-SELECT u.id, u.username, u.email, u.avatar_url, u.name
+SELECT u.id, u.username, u.email, u.avatar_url, u.name, u.deleted_at
 FROM users u
 JOIN refresh_tokens rt ON u.id = rt.user_id
 WHERE rt.token = $1 AND rt.expires_at > CURRENT_TIMESTAMP
 `
 
 type ValidateRefreshTokenRow struct {
-	ID        int32       `json:"id"`
-	Username  string      `json:"username"`
-	Email     string      `json:"email"`
-	AvatarUrl pgtype.Text `json:"avatar_url"`
-	Name      pgtype.Text `json:"name"`
+	ID        int32              `json:"id"`
+	Username  string             `json:"username"`
+	Email     string             `json:"email"`
+	AvatarUrl pgtype.Text        `json:"avatar_url"`
+	Name      pgtype.Text        `json:"name"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
 func (q *Queries) ValidateRefreshToken(ctx context.Context, token string) (ValidateRefreshTokenRow, error) {
@@ -104,6 +142,7 @@ func (q *Queries) ValidateRefreshToken(ctx context.Context, token string) (Valid
 		&i.Email,
 		&i.AvatarUrl,
 		&i.Name,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -131,3 +131,39 @@ func (q *Queries) InsertUserTrophy(ctx context.Context, arg InsertUserTrophyPara
 	_, err := q.db.Exec(ctx, insertUserTrophy, arg.UserID, arg.TrophyID, arg.DisplayOrder)
 	return err
 }
+
+const listTrophies = `-- name: ListTrophies :many
+SELECT id, name, description
+FROM trophies
+ORDER BY id
+`
+
+type ListTrophiesRow struct {
+	ID          int32       `json:"id"`
+	Name        string      `json:"name"`
+	Description pgtype.Text `json:"description"`
+}
+
+func (q *Queries) ListTrophies(ctx context.Context) ([]ListTrophiesRow, error) {
+	rows, err := q.db.Query(ctx, listTrophies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrophiesRow
+	for rows.Next() {
+		var i ListTrophiesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
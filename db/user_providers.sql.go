@@ -11,6 +11,45 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getUserProvidersByUserID = `-- name: GetUserProvidersByUserID :many
+SELECT id, user_id, provider, provider_user_id, first_name, last_name, nickname, avatar_url, location, created_at, updated_at
+FROM user_providers
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetUserProvidersByUserID(ctx context.Context, userID int32) ([]UserProvider, error) {
+	rows, err := q.db.Query(ctx, getUserProvidersByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserProvider
+	for rows.Next() {
+		var i UserProvider
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.ProviderUserID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Nickname,
+			&i.AvatarUrl,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upsertUserProvider = `-- name: UpsertUserProvider :exec
 
 INSERT INTO user_providers (
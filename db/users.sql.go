@@ -22,8 +22,58 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 	return err
 }
 
+const getActiveUserByUsername = `-- name: GetActiveUserByUsername :one
+
+SELECT id, username
+FROM users
+WHERE username = $1 AND deleted_at IS NULL
+`
+
+type GetActiveUserByUsernameRow struct {
+	ID       int32  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Accounts pending deletion are left out
+func (q *Queries) GetActiveUserByUsername(ctx context.Context, username string) (GetActiveUserByUsernameRow, error) {
+	row := q.db.QueryRow(ctx, getActiveUserByUsername, username)
+	var i GetActiveUserByUsernameRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+	)
+	return i, err
+}
+
+const getUserAccount = `-- name: GetUserAccount :one
+SELECT id, username, email, name, sex, preferred_units, country_code, avatar_url, bio, created_at, updated_at, deleted_at, purge_after
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserAccount(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRow(ctx, getUserAccount, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Name,
+		&i.Sex,
+		&i.PreferredUnits,
+		&i.CountryCode,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PurgeAfter,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, name, email, avatar_url, sex, preferred_units, country_code, created_at, updated_at
+SELECT id, username, name, email, avatar_url, sex, preferred_units, country_code, created_at, updated_at, deleted_at, purge_after
 FROM users
 WHERE email = $1
 `
@@ -39,6 +89,8 @@ type GetUserByEmailRow struct {
 	CountryCode    pgtype.Text        `json:"country_code"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+	PurgeAfter     pgtype.Timestamptz `json:"purge_after"`
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
@@ -55,6 +107,8 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 		&i.CountryCode,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PurgeAfter,
 	)
 	return i, err
 }
@@ -133,6 +187,27 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (GetUs
 	return i, err
 }
 
+const getUserDeletionStatus = `-- name: GetUserDeletionStatus :one
+SELECT deleted_at, purge_after
+FROM users
+WHERE id = $1
+`
+
+type GetUserDeletionStatusRow struct {
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+	PurgeAfter pgtype.Timestamptz `json:"purge_after"`
+}
+
+func (q *Queries) GetUserDeletionStatus(ctx context.Context, id int32) (GetUserDeletionStatusRow, error) {
+	row := q.db.QueryRow(ctx, getUserDeletionStatus, id)
+	var i GetUserDeletionStatusRow
+	err := row.Scan(
+		&i.DeletedAt,
+		&i.PurgeAfter,
+	)
+	return i, err
+}
+
 const getUserDetails = `-- name: GetUserDetails :one
 SELECT
     u.id as user_id,
@@ -149,12 +224,19 @@ SELECT
         'exercise_name', e.name,
         'reps', el.reps,
         'weight', el.weight,
+        'rpe', el.rpe,
+        'rir', el.rir,
+        'tempo', el.tempo,
+        'rest_seconds', el.rest_seconds,
+        'notes', el.notes,
+        'duration_seconds', el.duration_seconds,
+        'distance_meters', el.distance_meters,
         'log_date', el.log_date
     )) FILTER (WHERE el.id IS NOT NULL), '[]') as exercise_logs
 FROM
     users u
 LEFT JOIN
-    (SELECT DISTINCT ON (user_id) id, user_id, bodyweight, log_date, created_at, updated_at FROM bodyweight_logs ORDER BY user_id, log_date DESC) bw
+    (SELECT DISTINCT ON (user_id) * FROM bodyweight_logs ORDER BY user_id, log_date DESC) bw
     ON u.id = bw.user_id
 LEFT JOIN
     exercise_logs el
@@ -249,6 +331,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -296,6 +385,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -394,6 +490,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -441,6 +544,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -478,7 +588,7 @@ SELECT
         ) bw
     ) AS latest_bodyweight
 FROM users u
-WHERE u.username = $1
+WHERE u.username = $1 AND u.deleted_at IS NULL
 `
 
 type GetUserProfileByUsernameRow struct {
@@ -571,12 +681,71 @@ func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (InsertU
 	return i, err
 }
 
+const listUsersDueForPurge = `-- name: ListUsersDueForPurge :many
+SELECT id
+FROM users
+WHERE deleted_at IS NOT NULL AND purge_after <= NOW()
+ORDER BY purge_after
+LIMIT $1
+`
+
+func (q *Queries) ListUsersDueForPurge(ctx context.Context, limit int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listUsersDueForPurge, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markUserForDeletion = `-- name: MarkUserForDeletion :exec
+UPDATE users
+SET deleted_at = NOW(), purge_after = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type MarkUserForDeletionParams struct {
+	ID         int32              `json:"id"`
+	PurgeAfter pgtype.Timestamptz `json:"purge_after"`
+}
+
+func (q *Queries) MarkUserForDeletion(ctx context.Context, arg MarkUserForDeletionParams) error {
+	_, err := q.db.Exec(ctx, markUserForDeletion, arg.ID, arg.PurgeAfter)
+	return err
+}
+
+const restoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = NULL, purge_after = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const searchUsers = `-- name: SearchUsers :many
 SELECT username, name, avatar_url, sex, country_code
 FROM users
 WHERE
-    username ILIKE '%' || $1 || '%'
-   OR name ILIKE '%' || $1 || '%'
+    deleted_at IS NULL
+    AND (username ILIKE '%' || $1 || '%'
+        OR name ILIKE '%' || $1 || '%')
 ORDER BY username
 LIMIT $2 OFFSET $3
 `
@@ -0,0 +1,81 @@
+package bodyweight
+
+import (
+	"math"
+	"time"
+)
+
+// maxProjectionDays is how far ahead a goal is projected. A rate too slow to get there in time
+// gives no projected date.
+const maxProjectionDays = 5 * 365
+
+// Goal is a target weight to reach by a date, starting from the weight when it was set.
+type Goal struct {
+	TargetKg   float64
+	TargetDate time.Time
+	StartKg    float64
+}
+
+// Projection is the progress towards a goal.
+type Projection struct {
+	RemainingKg      float64    // Signed change still needed, negative to lose weight
+	Progress         float64    // Percent of the way from the start weight to the target
+	Achieved         bool       // The trend reached the target in the direction of the goal
+	RequiredWeeklyKg *float64   // Weekly change needed to reach the target on time, nil once due
+	ProjectedDate    *time.Time // When the current rate reaches the target, nil if it never does
+	OnTrack          bool
+}
+
+// Project compares the current trend weight and weekly rate of change with a goal.
+func Project(goal Goal, currentKg float64, weeklyChangeKg *float64, today time.Time) Projection {
+	today = today.UTC().Truncate(24 * time.Hour)
+	remaining := goal.TargetKg - currentKg
+	direction := sign(goal.TargetKg - goal.StartKg)
+
+	projection := Projection{RemainingKg: round(remaining)}
+	if total := goal.TargetKg - goal.StartKg; total != 0 {
+		projection.Progress = round(math.Max(0, math.Min(100, (currentKg-goal.StartKg)/total*100)))
+	}
+
+	// A goal to maintain a weight is achieved within half a kilogram of it
+	if direction == 0 {
+		projection.Achieved = math.Abs(remaining) <= 0.5
+	} else {
+		projection.Achieved = sign(remaining) != direction
+	}
+	if projection.Achieved {
+		projection.Progress = 100
+		projection.OnTrack = true
+		return projection
+	}
+
+	if days := goal.TargetDate.Sub(today).Hours() / 24; days > 0 {
+		required := round(remaining / days * 7)
+		projection.RequiredWeeklyKg = &required
+	}
+
+	if weeklyChangeKg != nil && *weeklyChangeKg != 0 && sign(*weeklyChangeKg) == sign(remaining) {
+		days := math.Ceil(remaining / *weeklyChangeKg * 7)
+		if days > maxProjectionDays {
+			return projection
+		}
+		projected := today.AddDate(0, 0, int(days))
+		projection.ProjectedDate = &projected
+		projection.OnTrack = !projected.After(goal.TargetDate)
+	}
+	return projection
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,139 @@
+package bodyweight
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// Smoothing is the weight of a new day in the exponentially smoothed trend.
+	Smoothing = 0.1
+	// MaxInterpolatedGap is the longest run of days without a weigh-in that is interpolated.
+	// After a longer gap the trend restarts at the next weigh-in.
+	MaxInterpolatedGap = 14
+	// rateWindowDays is how far back the weekly rate of change looks.
+	rateWindowDays = 28
+	// minRateDays is the fewest days of trend a weekly rate is estimated from.
+	minRateDays = 7
+)
+
+// Point is a weigh-in.
+type Point struct {
+	Date     time.Time
+	WeightKg float64
+}
+
+// Day is a day of the trend. Days without a weigh-in between two weigh-ins are interpolated.
+type Day struct {
+	Date         time.Time
+	WeightKg     float64 // Average of the day's weigh-ins, or the interpolated weight
+	TrendKg      float64
+	Interpolated bool
+	Restarted    bool // The trend restarted on this day after a long gap
+}
+
+// Trend is a smoothed bodyweight series.
+type Trend struct {
+	Days           []Day
+	WeeklyChangeKg *float64 // Rate of change of the trend, nil without enough recent days
+}
+
+// Current returns the latest trend weight.
+func (t Trend) Current() (float64, bool) {
+	if len(t.Days) == 0 {
+		return 0, false
+	}
+	return t.Days[len(t.Days)-1].TrendKg, true
+}
+
+// Smooth turns weigh-ins into a daily exponentially smoothed trend. Weigh-ins on the same UTC
+// day are averaged.
+func Smooth(points []Point) Trend {
+	daily := averageByDay(points)
+	trend := Trend{Days: []Day{}}
+
+	var previous Day
+	for i, point := range daily {
+		day := Day{Date: point.Date, WeightKg: point.WeightKg}
+		if i == 0 {
+			day.TrendKg = point.WeightKg
+			trend.Days = append(trend.Days, day)
+			previous = day
+			continue
+		}
+
+		gap := int(point.Date.Sub(previous.Date).Hours()/24) - 1
+		if gap > MaxInterpolatedGap {
+			day.TrendKg = point.WeightKg
+			day.Restarted = true
+			trend.Days = append(trend.Days, day)
+			previous = day
+			continue
+		}
+		for k := 1; k <= gap; k++ {
+			weight := previous.WeightKg + (point.WeightKg-previous.WeightKg)*float64(k)/float64(gap+1)
+			last := trend.Days[len(trend.Days)-1]
+			trend.Days = append(trend.Days, Day{
+				Date:         previous.Date.AddDate(0, 0, k),
+				WeightKg:     weight,
+				TrendKg:      last.TrendKg + Smoothing*(weight-last.TrendKg),
+				Interpolated: true,
+			})
+		}
+		last := trend.Days[len(trend.Days)-1]
+		day.TrendKg = last.TrendKg + Smoothing*(point.WeightKg-last.TrendKg)
+		trend.Days = append(trend.Days, day)
+		previous = day
+	}
+
+	trend.WeeklyChangeKg = weeklyChange(trend.Days)
+	return trend
+}
+
+// weeklyChange fits a line through the trend of the last weeks since the latest restart and
+// returns its slope per week.
+func weeklyChange(days []Day) *float64 {
+	start := len(days) - rateWindowDays
+	for i := len(days) - 1; i >= 0 && i >= start; i-- {
+		if days[i].Restarted {
+			start = i
+			break
+		}
+	}
+	start = max(start, 0)
+	window := days[start:]
+	if len(window) < minRateDays {
+		return nil
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, day := range window {
+		x := float64(i)
+		sumX += x
+		sumY += day.TrendKg
+		sumXY += x * day.TrendKg
+		sumXX += x * x
+	}
+	n := float64(len(window))
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	change := math.Round(slope*7*1000) / 1000
+	return &change
+}
+
+func averageByDay(points []Point) []Point {
+	totals := make(map[time.Time]float64)
+	counts := make(map[time.Time]int)
+	for _, point := range points {
+		day := point.Date.UTC().Truncate(24 * time.Hour)
+		totals[day] += point.WeightKg
+		counts[day]++
+	}
+
+	daily := make([]Point, 0, len(totals))
+	for day, total := range totals {
+		daily = append(daily, Point{Date: day, WeightKg: total / float64(counts[day])})
+	}
+	sort.Slice(daily, func(i, j int) bool { return daily[i].Date.Before(daily[j].Date) })
+	return daily
+}
@@ -0,0 +1,9 @@
+package catalog
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+// Editable reports whether a user can update or delete an exercise. Only the owner edits a
+// custom exercise, catalog exercises are not editable.
+func Editable(userID int32, ownerUserID pgtype.Int4) bool {
+	return ownerUserID.Valid && ownerUserID.Int32 == userID
+}
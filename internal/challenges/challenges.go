@@ -0,0 +1,110 @@
+package challenges
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"new-chainsaw/db"
+)
+
+// ChampionTrophy is the name of the trophy unlocked by winning a challenge.
+const ChampionTrophy = "challenge-champion"
+
+// Status is where a challenge is in its lifecycle.
+type Status string
+
+const (
+	StatusUpcoming  Status = "upcoming"
+	StatusActive    Status = "active"
+	StatusEnded     Status = "ended"     // Waiting for the final ranks to be saved
+	StatusFinalized Status = "finalized" // Final ranks saved and winners awarded
+)
+
+// Standing is the value a participant reached in a challenge so far.
+type Standing struct {
+	UserID   int32
+	Value    float64 // Kilograms for tonnage and best_e1rm
+	JoinedAt time.Time
+}
+
+// Ranked is a standing with its place on the leaderboard.
+type Ranked struct {
+	Standing
+	Rank int
+}
+
+// StatusOn returns the status of a challenge running between two dates, both inclusive.
+func StatusOn(startsOn time.Time, endsOn time.Time, finalized bool, today time.Time) Status {
+	switch {
+	case finalized:
+		return StatusFinalized
+	case today.Before(startsOn):
+		return StatusUpcoming
+	case today.After(endsOn):
+		return StatusEnded
+	}
+	return StatusActive
+}
+
+// IsWeight reports whether the values of a metric are weights.
+func IsWeight(metric db.ChallengeMetric) bool {
+	return metric == db.ChallengeMetricTonnage || metric == db.ChallengeMetricBestE1rm
+}
+
+// Standings picks the value of the challenge's metric from the totals of each participant.
+func Standings(metric db.ChallengeMetric, rows []db.GetChallengeStandingsRow) []Standing {
+	standings := make([]Standing, len(rows))
+	for i, row := range rows {
+		standings[i] = Standing{UserID: row.UserID, JoinedAt: row.JoinedAt.Time}
+		switch metric {
+		case db.ChallengeMetricTotalReps:
+			standings[i].Value = float64(row.TotalReps)
+		case db.ChallengeMetricTonnage:
+			standings[i].Value = numericToFloat(row.Tonnage)
+		case db.ChallengeMetricSessions:
+			standings[i].Value = float64(row.Sessions)
+		case db.ChallengeMetricBestE1rm:
+			standings[i].Value = numericToFloat(row.BestE1rm)
+		}
+	}
+	return standings
+}
+
+// Rank orders standings from the highest value. Equal values share a rank and the next rank
+// skips the places taken, listed by who joined first.
+func Rank(standings []Standing) []Ranked {
+	ranked := make([]Ranked, len(standings))
+	for i, standing := range standings {
+		ranked[i] = Ranked{Standing: standing}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Value != ranked[j].Value {
+			return ranked[i].Value > ranked[j].Value
+		}
+		return ranked[i].JoinedAt.Before(ranked[j].JoinedAt)
+	})
+	for i := range ranked {
+		if i > 0 && ranked[i].Value == ranked[i-1].Value {
+			ranked[i].Rank = ranked[i-1].Rank
+		} else {
+			ranked[i].Rank = i + 1
+		}
+	}
+	return ranked
+}
+
+// Won reports whether a ranked participant won. Nobody wins without logging anything that
+// counts, even when everybody shares the first rank.
+func (r Ranked) Won() bool {
+	return r.Rank == 1 && r.Value > 0
+}
+
+func numericToFloat(n pgtype.Numeric) float64 {
+	f, err := n.Float64Value()
+	if err != nil || !f.Valid {
+		return 0
+	}
+	return math.Round(f.Float64*100) / 100
+}
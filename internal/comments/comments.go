@@ -0,0 +1,41 @@
+package comments
+
+import "regexp"
+
+// MaxMentions is how many users a comment can mention, later mentions are ignored.
+const MaxMentions = 10
+
+// Subject is what a comment or reaction is on.
+type Subject string
+
+const (
+	SubjectWorkout        Subject = "workout"
+	SubjectPersonalRecord Subject = "personal_record" // A logged set that was a personal record
+)
+
+// mentionRegex matches @username where usernames are valid, not within a word or an email address.
+var mentionRegex = regexp.MustCompile(`(?:^|[^A-Za-z0-9._@])@([A-Za-z0-9](?:[A-Za-z0-9._]{0,22}[A-Za-z0-9])?)`)
+
+// Mentions returns the usernames mentioned in a comment in the order they first appear.
+func Mentions(body string) []string {
+	usernames := []string{}
+	seen := make(map[string]bool)
+	for _, match := range mentionRegex.FindAllStringSubmatch(body, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == MaxMentions {
+			break
+		}
+	}
+	return usernames
+}
+
+// CanDelete reports whether a user can delete a comment. Authors delete their comments and
+// owners moderate the comments on their workouts and records.
+func CanDelete(userID int32, authorID int32, ownerID int32) bool {
+	return userID == authorID || userID == ownerID
+}
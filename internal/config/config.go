@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -12,6 +13,21 @@ const (
 	RefreshTokenCookie     = "refresh_token"
 	JwtExpiration          = 1 * time.Hour
 	RefreshTokenExpiration = 7 * 24 * time.Hour
+	DataExportExpiration   = 7 * 24 * time.Hour
+	DataExportLinkLifetime = 1 * time.Hour
+	// DataExportTimeout bounds building an archive, within the 30 minute lease of GetActiveDataExport
+	DataExportTimeout     = 20 * time.Minute
+	LogExportWriteTimeout = 10 * time.Minute
+
+	DefaultAccountDeletionGracePeriod = 30 * 24 * time.Hour
+	AccountPurgeInterval              = 1 * time.Hour
+	DataExportMaintenanceInterval     = 15 * time.Minute
+	InsightScanInterval               = 15 * time.Minute
+	StrengthStandingsRefreshInterval  = 1 * time.Hour
+	ChallengeFinalizeInterval         = 1 * time.Hour
+	NotificationDeliveryInterval      = 15 * time.Second
+	EventStreamHeartbeat              = 25 * time.Second
+	WebhookDeliveryInterval           = 10 * time.Second
 )
 
 var EnvVars = make(map[string]string)
@@ -19,7 +35,7 @@ var EnvVars = make(map[string]string)
 func LoadConfig() {
 	criticalVars := []string{
 		"DB_HOST", "DB_PORT", "DB_DATABASE", "DB_USERNAME", "DB_PASSWORD",
-		"JWT_SECRET", "REFRESH_SECRET",
+		"JWT_SECRET", "REFRESH_SECRET", "URL_SIGNING_SECRET",
 		"FRONTEND_URL", "BACKEND_URL",
 	}
 	var missingVars []string
@@ -41,3 +57,13 @@ func LoadConfig() {
 		log.Fatalf("Critical environment variables missing: %v", missingVars)
 	}
 }
+
+// AccountDeletionGracePeriod returns how long a deleted account can still be restored,
+// configurable in days through ACCOUNT_DELETION_GRACE_DAYS.
+func AccountDeletionGracePeriod() time.Duration {
+	days, err := strconv.Atoi(EnvVars["ACCOUNT_DELETION_GRACE_DAYS"])
+	if err != nil || days < 0 {
+		return DefaultAccountDeletionGracePeriod
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
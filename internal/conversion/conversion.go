@@ -1,5 +1,65 @@
 package conversion
 
+import (
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ToNumeric converts a float to a NUMERIC parameter without dropping its fractional part. The
+// column's scale rounds it on insert.
+func ToNumeric(value float64) pgtype.Numeric {
+	var num pgtype.Numeric
+	if err := num.Scan(strconv.FormatFloat(value, 'f', -1, 64)); err != nil {
+		return pgtype.Numeric{}
+	}
+	return num
+}
+
 func LbsToKg(lbs float64) float64 {
 	return lbs * 0.453592
 }
+
+func KgToLbs(kg float64) float64 {
+	return kg / 0.453592
+}
+
+const (
+	metersPerKilometer = 1000
+	metersPerMile      = 1609.344
+	metersPerYard      = 0.9144
+)
+
+func KmToMeters(km float64) float64 {
+	return km * metersPerKilometer
+}
+
+func MilesToMeters(miles float64) float64 {
+	return miles * metersPerMile
+}
+
+func YardsToMeters(yards float64) float64 {
+	return yards * metersPerYard
+}
+
+func MetersToKm(meters float64) float64 {
+	return meters / metersPerKilometer
+}
+
+func MetersToMiles(meters float64) float64 {
+	return meters / metersPerMile
+}
+
+func MetersToYards(meters float64) float64 {
+	return meters / metersPerYard
+}
+
+const centimetersPerInch = 2.54
+
+func CmToInches(cm float64) float64 {
+	return cm / centimetersPerInch
+}
+
+func InchesToCm(inches float64) float64 {
+	return inches * centimetersPerInch
+}
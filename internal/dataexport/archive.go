@@ -0,0 +1,231 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"new-chainsaw/db"
+)
+
+// dataset is a single table of user data written to the archive as both JSON and CSV.
+type dataset struct {
+	name   string
+	value  interface{}
+	header []string
+	rows   [][]string
+}
+
+// WriteArchive writes a ZIP archive with everything stored about the user to w.
+func WriteArchive(ctx context.Context, q *db.Queries, userID int32, w io.Writer) error {
+	datasets, err := collect(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, ds := range datasets {
+		if err := writeJSON(zw, ds); err != nil {
+			return fmt.Errorf("failed to write %s.json: %w", ds.name, err)
+		}
+		if err := writeCSV(zw, ds); err != nil {
+			return fmt.Errorf("failed to write %s.csv: %w", ds.name, err)
+		}
+	}
+	return zw.Close()
+}
+
+func collect(ctx context.Context, q *db.Queries, userID int32) ([]dataset, error) {
+	user, err := q.GetUserAccount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	providers, err := q.GetUserProvidersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user providers: %w", err)
+	}
+	initialProviders, err := q.GetInitialUserProvidersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch initial user providers: %w", err)
+	}
+	exerciseLogs, err := q.GetExerciseLogsForExport(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exercise logs: %w", err)
+	}
+	bodyweightLogs, err := q.GetBodyWeightLogs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bodyweight logs: %w", err)
+	}
+	trophies, err := q.GetUserTrophies(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trophies: %w", err)
+	}
+	sessions, err := q.GetRefreshTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sessions: %w", err)
+	}
+
+	profile := dataset{
+		name:   "profile",
+		value:  user,
+		header: []string{"id", "username", "email", "name", "sex", "preferred_units", "country_code", "avatar_url", "bio", "created_at", "updated_at"},
+		rows: [][]string{{
+			strconv.Itoa(int(user.ID)), user.Username, user.Email, text(user.Name), text(user.Sex),
+			string(user.PreferredUnits), text(user.CountryCode), text(user.AvatarUrl), text(user.Bio),
+			timestamp(user.CreatedAt), timestamp(user.UpdatedAt),
+		}},
+	}
+
+	providerSet := dataset{
+		name:   "user_providers",
+		value:  nonNil(providers),
+		header: []string{"id", "provider", "provider_user_id", "first_name", "last_name", "nickname", "avatar_url", "location", "created_at", "updated_at"},
+	}
+	for _, p := range providers {
+		providerSet.rows = append(providerSet.rows, []string{
+			strconv.Itoa(int(p.ID)), p.Provider, p.ProviderUserID, text(p.FirstName), text(p.LastName),
+			text(p.Nickname), text(p.AvatarUrl), text(p.Location), timestamp(p.CreatedAt), timestamp(p.UpdatedAt),
+		})
+	}
+
+	initialProviderSet := dataset{
+		name:   "initial_user_providers",
+		value:  nonNil(initialProviders),
+		header: []string{"id", "provider", "provider_user_id", "first_name", "last_name", "nickname", "avatar_url", "location", "created_at"},
+	}
+	for _, p := range initialProviders {
+		initialProviderSet.rows = append(initialProviderSet.rows, []string{
+			strconv.Itoa(int(p.ID)), p.Provider, p.ProviderUserID, text(p.FirstName), text(p.LastName),
+			text(p.Nickname), text(p.AvatarUrl), text(p.Location), timestamp(p.CreatedAt),
+		})
+	}
+
+	exerciseSet := dataset{
+		name:   "exercise_logs",
+		value:  nonNil(exerciseLogs),
+		header: []string{"id", "exercise_id", "exercise_name", "reps", "weight_kg", "additional_weight_kg", "exercise_type", "duration_seconds", "distance_meters", "rpe", "rir", "tempo", "rest_seconds", "notes", "bodyweight_kg", "log_date", "created_at", "updated_at"},
+	}
+	for _, l := range exerciseLogs {
+		exerciseType := ""
+		if l.ExerciseType.Valid {
+			exerciseType = string(l.ExerciseType.ExerciseType)
+		}
+		exerciseSet.rows = append(exerciseSet.rows, []string{
+			strconv.Itoa(int(l.ID)), strconv.Itoa(int(l.ExerciseID)), l.ExerciseName, strconv.Itoa(int(l.Reps)),
+			numeric(l.Weight), numeric(l.AdditionalWeight), exerciseType, integer(l.DurationSeconds), numeric(l.DistanceMeters),
+			numeric(l.Rpe), integer(l.Rir), text(l.Tempo), integer(l.RestSeconds), text(l.Notes), numeric(l.Bodyweight),
+			timestamp(l.LogDate), timestamp(l.CreatedAt), timestamp(l.UpdatedAt),
+		})
+	}
+
+	bodyweightSet := dataset{
+		name:   "bodyweight_logs",
+		value:  nonNil(bodyweightLogs),
+		header: []string{"id", "bodyweight_kg", "log_date", "created_at", "updated_at"},
+	}
+	for _, l := range bodyweightLogs {
+		bodyweightSet.rows = append(bodyweightSet.rows, []string{
+			strconv.Itoa(int(l.ID)), numeric(l.Bodyweight), timestamp(l.LogDate), timestamp(l.CreatedAt), timestamp(l.UpdatedAt),
+		})
+	}
+
+	trophySet := dataset{
+		name:   "trophies",
+		value:  nonNil(trophies),
+		header: []string{"id", "name", "description", "display_order"},
+	}
+	for _, t := range trophies {
+		displayOrder := ""
+		if t.DisplayOrder.Valid {
+			displayOrder = strconv.Itoa(int(t.DisplayOrder.Int32))
+		}
+		trophySet.rows = append(trophySet.rows, []string{strconv.Itoa(int(t.ID)), t.Name, text(t.Description), displayOrder})
+	}
+
+	// Sessions are exported without the refresh token itself
+	sessionSet := dataset{
+		name:   "sessions",
+		value:  nonNil(sessions),
+		header: []string{"id", "created_at", "expires_at"},
+	}
+	for _, s := range sessions {
+		expiresAt := ""
+		if s.ExpiresAt.Valid {
+			expiresAt = s.ExpiresAt.Time.UTC().Format(time.RFC3339)
+		}
+		sessionSet.rows = append(sessionSet.rows, []string{strconv.Itoa(int(s.ID)), timestamp(s.CreatedAt), expiresAt})
+	}
+
+	return []dataset{profile, providerSet, initialProviderSet, exerciseSet, bodyweightSet, trophySet, sessionSet}, nil
+}
+
+func writeJSON(zw *zip.Writer, ds dataset) error {
+	f, err := zw.Create(ds.name + ".json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ds.value)
+}
+
+func writeCSV(zw *zip.Writer, ds dataset) error {
+	f, err := zw.Create(ds.name + ".csv")
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write(ds.header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(ds.rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// nonNil makes empty result sets encode as [] instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func text(t pgtype.Text) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.String
+}
+
+func integer(i pgtype.Int4) string {
+	if !i.Valid {
+		return ""
+	}
+	return strconv.Itoa(int(i.Int32))
+}
+
+func numeric(n pgtype.Numeric) string {
+	if !n.Valid {
+		return ""
+	}
+	f, err := n.Float64Value()
+	if err != nil || !f.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(f.Float64, 'f', -1, 64)
+}
+
+func timestamp(t pgtype.Timestamptz) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
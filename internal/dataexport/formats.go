@@ -0,0 +1,299 @@
+package dataexport
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"new-chainsaw/db"
+)
+
+// Formats supported by NewLogWriter.
+const (
+	FormatCSV              = "csv"
+	FormatJSON             = "json"
+	FormatXLSX             = "xlsx"
+	FormatOpenPowerlifting = "openpowerlifting"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+var logColumns = []string{"date", "type", "exercise", "reps", "weight", "additional_weight", "duration_seconds", "distance", "rpe", "rir", "tempo", "rest_seconds", "notes", "bodyweight", "unit", "distance_unit"}
+
+// LogWriter writes a stream of logs in one export format. Close must be called to finish the output.
+type LogWriter interface {
+	Write(row LogRow) error
+	Close() error
+}
+
+// Lifter identifies the user in formats that include who performed the lifts.
+type Lifter struct {
+	Name  string
+	Sex   string           // male, female or empty
+	Lifts map[int32]string // Event letters of the exercises performed as a competition lift, see ResolveLifts
+}
+
+// NewLogWriter returns a writer for format.
+func NewLogWriter(format string, w io.Writer, lifter Lifter) (LogWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVLogWriter(w)
+	case FormatJSON:
+		return newJSONLogWriter(w)
+	case FormatXLSX:
+		return newXLSXLogWriter(w, logColumns)
+	case FormatOpenPowerlifting:
+		return newOpenPowerliftingWriter(w, lifter)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// IsFormat reports whether format is supported by NewLogWriter.
+func IsFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatJSON, FormatXLSX, FormatOpenPowerlifting:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type and file extension of format.
+func ContentType(format string) (string, string) {
+	switch format {
+	case FormatJSON:
+		return "application/json", "json"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"
+	}
+	return "text/csv", "csv"
+}
+
+func logRecord(row LogRow) []string {
+	return []string{
+		row.Date.UTC().Format(time.RFC3339),
+		row.Type,
+		optionalString(row.Exercise),
+		optionalInt(row.Reps),
+		optionalFloat(row.Weight),
+		optionalFloat(row.AdditionalWeight),
+		optionalInt(row.DurationSeconds),
+		optionalFloat(row.Distance),
+		optionalFloat(row.RPE),
+		optionalInt(row.RIR),
+		optionalString(row.Tempo),
+		optionalInt(row.RestSeconds),
+		optionalString(row.Notes),
+		formatFloat(row.Bodyweight),
+		row.Unit,
+		row.DistanceUnit,
+	}
+}
+
+type csvLogWriter struct {
+	w *csv.Writer
+}
+
+func newCSVLogWriter(w io.Writer) (*csvLogWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(logColumns); err != nil {
+		return nil, err
+	}
+	return &csvLogWriter{w: cw}, nil
+}
+
+func (lw *csvLogWriter) Write(row LogRow) error {
+	return lw.w.Write(logRecord(row))
+}
+
+func (lw *csvLogWriter) Close() error {
+	lw.w.Flush()
+	return lw.w.Error()
+}
+
+// jsonLogWriter writes a JSON array one element at a time.
+type jsonLogWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+func newJSONLogWriter(w io.Writer) (*jsonLogWriter, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return nil, err
+	}
+	return &jsonLogWriter{w: w, enc: json.NewEncoder(w)}, nil
+}
+
+func (lw *jsonLogWriter) Write(row LogRow) error {
+	if lw.count > 0 {
+		if _, err := io.WriteString(lw.w, ","); err != nil {
+			return err
+		}
+	}
+	lw.count++
+	return lw.enc.Encode(row)
+}
+
+func (lw *jsonLogWriter) Close() error {
+	_, err := io.WriteString(lw.w, "]\n")
+	return err
+}
+
+var openPowerliftingColumns = []string{"Name", "Sex", "Event", "Equipment", "BodyweightKg", "Best3SquatKg", "Best3BenchKg", "Best3DeadliftKg", "TotalKg", "Date"}
+
+// openPowerliftingWriter summarizes every training day with a squat, bench press or deadlift
+// single as one meet entry, using the heaviest single of each lift. Good meet attempts are
+// logged as singles. Weights are always in kilograms as
+// in the OpenPowerlifting data set, so rows must be streamed in metric units.
+type openPowerliftingWriter struct {
+	w      *csv.Writer
+	lifter Lifter
+	day    string
+	best   map[string]float64
+	weight float64
+}
+
+// openPowerliftingLifts maps the catalog exercises of the lifts onto their event letter.
+var openPowerliftingLifts = map[string]string{
+	"Back Squat":  "S",
+	"Bench Press": "B",
+	"Deadlift":    "D",
+}
+
+// ResolveLifts returns the event letters of the user's exercises performed as a squat, bench
+// press or deadlift, the catalog lifts and the exercises named or aliased like them.
+func ResolveLifts(ctx context.Context, q *db.Queries, userID int32) (map[int32]string, error) {
+	names := make([]string, 0, len(openPowerliftingLifts))
+	for name := range openPowerliftingLifts {
+		names = append(names, name)
+	}
+	exercises, err := q.ListLiftExercises(ctx, db.ListLiftExercisesParams{UserID: userID, Names: names})
+	if err != nil {
+		return nil, err
+	}
+	lifts := make(map[int32]string, len(exercises))
+	for _, exercise := range exercises {
+		lifts[exercise.ID] = openPowerliftingLifts[exercise.Lift]
+	}
+	return lifts, nil
+}
+
+func newOpenPowerliftingWriter(w io.Writer, lifter Lifter) (*openPowerliftingWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(openPowerliftingColumns); err != nil {
+		return nil, err
+	}
+	return &openPowerliftingWriter{w: cw, lifter: lifter, best: make(map[string]float64)}, nil
+}
+
+func (lw *openPowerliftingWriter) Write(row LogRow) error {
+	if row.Type != "exercise" || row.ExerciseID == nil || row.Weight == nil || row.Reps == nil || *row.Reps != 1 {
+		return nil
+	}
+	lift, ok := lw.lifter.Lifts[*row.ExerciseID]
+	if !ok {
+		return nil
+	}
+
+	day := row.Date.UTC().Format("2006-01-02")
+	if day != lw.day {
+		if err := lw.flush(); err != nil {
+			return err
+		}
+		lw.day = day
+	}
+
+	if *row.Weight > lw.best[lift] {
+		lw.best[lift] = *row.Weight
+	}
+	lw.weight = row.Bodyweight
+	return nil
+}
+
+func (lw *openPowerliftingWriter) flush() error {
+	if len(lw.best) == 0 {
+		return nil
+	}
+
+	var event string
+	var total float64
+	for _, lift := range []string{"S", "B", "D"} {
+		if weight, ok := lw.best[lift]; ok {
+			event += lift
+			total += weight
+		}
+	}
+	// A total only counts when all three lifts were performed
+	totalKg := ""
+	if event == "SBD" {
+		totalKg = formatFloat(total)
+	}
+
+	sex := ""
+	switch lw.lifter.Sex {
+	case "male":
+		sex = "M"
+	case "female":
+		sex = "F"
+	}
+
+	err := lw.w.Write([]string{
+		lw.lifter.Name,
+		sex,
+		event,
+		"Raw",
+		formatFloat(lw.weight),
+		bestLift(lw.best, "S"),
+		bestLift(lw.best, "B"),
+		bestLift(lw.best, "D"),
+		totalKg,
+		lw.day,
+	})
+	lw.best = make(map[string]float64)
+	return err
+}
+
+func (lw *openPowerliftingWriter) Close() error {
+	if err := lw.flush(); err != nil {
+		return err
+	}
+	lw.w.Flush()
+	return lw.w.Error()
+}
+
+func bestLift(best map[string]float64, lift string) string {
+	weight, ok := best[lift]
+	if !ok {
+		return ""
+	}
+	return formatFloat(weight)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func optionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return formatFloat(*f)
+}
+
+func optionalInt(i *int32) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*i))
+}
+
+func optionalString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,110 @@
+package dataexport
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/conversion"
+)
+
+// LogRow is a single exercise or bodyweight log, with weights in the unit of the export.
+type LogRow struct {
+	Type             string    `json:"type"` // exercise or bodyweight
+	Date             time.Time `json:"date"`
+	ExerciseID       *int32    `json:"-"`
+	Exercise         *string   `json:"exercise,omitempty"`
+	Reps             *int32    `json:"reps,omitempty"`
+	Weight           *float64  `json:"weight,omitempty"`
+	AdditionalWeight *float64  `json:"additional_weight,omitempty"`
+	DurationSeconds  *int32    `json:"duration_seconds,omitempty"`
+	Distance         *float64  `json:"distance,omitempty"`
+	RPE              *float64  `json:"rpe,omitempty"`
+	RIR              *int32    `json:"rir,omitempty"`
+	Tempo            *string   `json:"tempo,omitempty"`
+	RestSeconds      *int32    `json:"rest_seconds,omitempty"`
+	Notes            *string   `json:"notes,omitempty"`
+	Bodyweight       float64   `json:"bodyweight"`
+	Unit             string    `json:"unit"`          // kg or lbs
+	DistanceUnit     string    `json:"distance_unit"` // m or yd
+}
+
+// streamLogsQuery is not generated by sqlc because generated queries load every row into a slice.
+const streamLogsQuery = `
+SELECT 'exercise' AS type, el.log_date, el.exercise_id, e.name, el.reps, el.weight, el.additional_weight, el.duration_seconds, el.distance_meters,
+       el.rpe, el.rir, el.tempo, el.rest_seconds, el.notes, bl.bodyweight
+FROM exercise_logs el
+JOIN exercises e ON e.id = el.exercise_id
+JOIN bodyweight_logs bl ON bl.id = el.bodyweight_id
+WHERE el.user_id = $1
+  AND ($2::timestamptz IS NULL OR el.log_date >= $2)
+  AND ($3::timestamptz IS NULL OR el.log_date < $3)
+UNION ALL
+SELECT 'bodyweight' AS type, log_date, NULL, NULL, NULL, NULL, NULL, NULL, NULL,
+       NULL, NULL, NULL, NULL, NULL, bodyweight
+FROM bodyweight_logs
+WHERE user_id = $1
+  AND ($2::timestamptz IS NULL OR log_date >= $2)
+  AND ($3::timestamptz IS NULL OR log_date < $3)
+ORDER BY log_date, type
+`
+
+// StreamLogs calls fn for every log of the user between from (inclusive) and to (exclusive),
+// oldest first. Rows are read one at a time so exports of any size use constant memory.
+func StreamLogs(ctx context.Context, conn db.DBTX, userID int32, from, to pgtype.Timestamptz, units db.UnitSystem, fn func(LogRow) error) error {
+	rows, err := conn.Query(ctx, streamLogsQuery, userID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	unit, distanceUnit := "kg", "m"
+	if units == db.UnitSystemImperial {
+		unit, distanceUnit = "lbs", "yd"
+	}
+
+	for rows.Next() {
+		row := LogRow{Unit: unit, DistanceUnit: distanceUnit}
+		if err := rows.Scan(&row.Type, &row.Date, &row.ExerciseID, &row.Exercise, &row.Reps, &row.Weight, &row.AdditionalWeight, &row.DurationSeconds, &row.Distance,
+			&row.RPE, &row.RIR, &row.Tempo, &row.RestSeconds, &row.Notes, &row.Bodyweight); err != nil {
+			return err
+		}
+		row.Weight = convertWeight(row.Weight, units)
+		row.AdditionalWeight = convertWeight(row.AdditionalWeight, units)
+		row.Bodyweight = *convertWeight(&row.Bodyweight, units)
+		row.Distance = convertDistance(row.Distance, units)
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// convertDistance converts a distance stored in meters to the given unit system, rounded to two decimals.
+func convertDistance(meters *float64, units db.UnitSystem) *float64 {
+	if meters == nil {
+		return nil
+	}
+	distance := *meters
+	if units == db.UnitSystemImperial {
+		distance = conversion.MetersToYards(distance)
+	}
+	distance = math.Round(distance*100) / 100
+	return &distance
+}
+
+// convertWeight converts a weight stored in kilograms to the given unit system, rounded to two decimals.
+func convertWeight(kg *float64, units db.UnitSystem) *float64 {
+	if kg == nil {
+		return nil
+	}
+	weight := *kg
+	if units == db.UnitSystemImperial {
+		weight = conversion.KgToLbs(weight)
+	}
+	weight = math.Round(weight*100) / 100
+	return &weight
+}
@@ -0,0 +1,93 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The minimal set of parts a spreadsheet application needs to open a single-sheet workbook.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Logs" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+// xlsxNumericColumns are the columns of logColumns written as numbers instead of text.
+var xlsxNumericColumns = map[int]bool{3: true, 4: true, 5: true, 6: true, 7: true, 8: true, 9: true, 11: true, 13: true}
+
+// xlsxLogWriter streams rows into the worksheet of a workbook. The worksheet is the last
+// entry of the zip file, so rows can be written as they arrive.
+type xlsxLogWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+}
+
+func newXLSXLogWriter(w io.Writer, header []string) (*xlsxLogWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+
+	lw := &xlsxLogWriter{zw: zw, sheet: sheet}
+	if err := lw.writeRecord(header, false); err != nil {
+		return nil, err
+	}
+	return lw, nil
+}
+
+func (lw *xlsxLogWriter) Write(row LogRow) error {
+	return lw.writeRecord(logRecord(row), true)
+}
+
+func (lw *xlsxLogWriter) writeRecord(record []string, typed bool) error {
+	var b strings.Builder
+	b.WriteString("<row>")
+	for i, value := range record {
+		switch {
+		case value == "":
+			b.WriteString("<c/>")
+		case typed && xlsxNumericColumns[i]:
+			fmt.Fprintf(&b, "<c><v>%s</v></c>", value)
+		default:
+			b.WriteString(`<c t="inlineStr"><is><t>`)
+			xml.EscapeText(&b, []byte(value))
+			b.WriteString("</t></is></c>")
+		}
+	}
+	b.WriteString("</row>")
+	_, err := io.WriteString(lw.sheet, b.String())
+	return err
+}
+
+func (lw *xlsxLogWriter) Close() error {
+	if _, err := io.WriteString(lw.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	return lw.zw.Close()
+}
@@ -0,0 +1,153 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"new-chainsaw/db"
+)
+
+// Type names an event stored in the events table. Payloads are JSON with weights in kilograms.
+type Type string
+
+const (
+	GoalReached       Type = "goal.reached"
+	ChallengeInvited  Type = "challenge.invited"
+	ChallengeFinished Type = "challenge.finished"
+	CoachAssigned     Type = "coach.assigned"
+	CommentMentioned  Type = "comment.mentioned"
+	CommentAdded      Type = "comment.added"
+	TrophyUnlocked    Type = "trophy.unlocked"
+	PRAchieved        Type = "pr.achieved"
+	WorkoutLogged     Type = "workout.logged"
+	BodyweightLogged  Type = "bodyweight.logged"
+)
+
+// GoalReachedPayload is the payload of a GoalReached event.
+type GoalReachedPayload struct {
+	GoalID     int32   `json:"goal_id"`
+	ExerciseID int32   `json:"exercise_id"`
+	Exercise   string  `json:"exercise"`
+	Metric     string  `json:"metric"`
+	Target     float64 `json:"target"` // Kilograms, or repetitions for most_reps
+	Value      float64 `json:"value"`
+}
+
+// ChallengeInvitedPayload is the payload of a ChallengeInvited event, emitted to the invited user.
+type ChallengeInvitedPayload struct {
+	ChallengeID int32  `json:"challenge_id"`
+	Challenge   string `json:"challenge"`
+	InvitedBy   string `json:"invited_by"` // Username
+}
+
+// ChallengeFinishedPayload is the payload of a ChallengeFinished event, emitted to every
+// participant when the final ranks are saved.
+type ChallengeFinishedPayload struct {
+	ChallengeID  int32   `json:"challenge_id"`
+	Challenge    string  `json:"challenge"`
+	Metric       string  `json:"metric"`
+	Rank         int     `json:"rank"`
+	Value        float64 `json:"value"` // Kilograms for tonnage and best_e1rm
+	Participants int     `json:"participants"`
+	Won          bool    `json:"won"`
+}
+
+// CoachAssignedPayload is the payload of a CoachAssigned event, emitted to the athlete when a
+// coach assigns them a template or a program.
+type CoachAssignedPayload struct {
+	AssignmentID   int32  `json:"assignment_id"`
+	OrganizationID int32  `json:"organization_id"`
+	Organization   string `json:"organization"`
+	Coach          string `json:"coach"`       // Username
+	TemplateID     *int32 `json:"template_id"` // The athlete's copy
+	ProgramID      *int32 `json:"program_id"`
+	Name           string `json:"name"` // Of the template or program
+}
+
+// CommentMentionedPayload is the payload of a CommentMentioned event, emitted to every user
+// mentioned in a comment the first time they are mentioned in it.
+type CommentMentionedPayload struct {
+	CommentID int32  `json:"comment_id"`
+	Subject   string `json:"subject"`    // workout or personal_record
+	SubjectID int32  `json:"subject_id"` // Workout or exercise log ID
+	Author    string `json:"author"`     // Username
+	Body      string `json:"body"`
+}
+
+// CommentAddedPayload is the payload of a CommentAdded event, emitted to the owner of the
+// workout or record and to the author of the comment replied to.
+type CommentAddedPayload struct {
+	CommentID int32  `json:"comment_id"`
+	ParentID  *int32 `json:"parent_id"`  // Set for replies
+	Reply     bool   `json:"reply"`      // The comment replies to a comment of the user
+	Subject   string `json:"subject"`    // workout or personal_record
+	SubjectID int32  `json:"subject_id"` // Workout or exercise log ID
+	Author    string `json:"author"`     // Username
+	Body      string `json:"body"`
+}
+
+// TrophyUnlockedPayload is the payload of a TrophyUnlocked event.
+type TrophyUnlockedPayload struct {
+	TrophyID int32  `json:"trophy_id"`
+	Trophy   string `json:"trophy"`
+}
+
+// PRAchievedPayload is the payload of a PRAchieved event, emitted when a logged set beats the
+// user's earlier sets of the exercise.
+type PRAchievedPayload struct {
+	ExerciseLogID int32              `json:"exercise_log_id"`
+	ExerciseID    int32              `json:"exercise_id"`
+	Exercise      string             `json:"exercise"`
+	Records       []PRAchievedRecord `json:"records"`
+}
+
+// PRAchievedRecord is a record beaten by a set. Values are in kilograms, repetitions, seconds,
+// meters or seconds per kilometer depending on the type.
+type PRAchievedRecord struct {
+	Type     string  `json:"type"`
+	Value    float64 `json:"value"`
+	Previous float64 `json:"previous"`
+}
+
+// WorkoutLoggedPayload is the payload of a WorkoutLogged event, emitted once for the sets
+// logged together, by a request or by completing a workout.
+type WorkoutLoggedPayload struct {
+	WorkoutID *int32             `json:"workout_id"` // Set when a workout was completed
+	Sets      []WorkoutLoggedSet `json:"sets"`
+}
+
+// WorkoutLoggedSet is a set of a WorkoutLogged event. The weight of a set of a bodyweight
+// exercise is the additional weight.
+type WorkoutLoggedSet struct {
+	ExerciseID       int32     `json:"exercise_id"`
+	LogDate          time.Time `json:"log_date"`
+	Reps             int32     `json:"reps"`
+	Weight           float64   `json:"weight"`
+	AdditionalWeight *float64  `json:"additional_weight"`
+	DurationSeconds  *int32    `json:"duration_seconds"`
+	DistanceMeters   *float64  `json:"distance_meters"`
+}
+
+// BodyweightLoggedPayload is the payload of a BodyweightLogged event, emitted when the first
+// bodyweight of a day is logged.
+type BodyweightLoggedPayload struct {
+	BodyweightLogID int32     `json:"bodyweight_log_id"`
+	Bodyweight      float64   `json:"bodyweight"`
+	LogDate         time.Time `json:"log_date"`
+}
+
+// Emit records an event for a user and queues its deliveries to the webhooks subscribed to it,
+// in the transaction of q when it runs in one.
+func Emit(ctx context.Context, q *db.Queries, userID int32, eventType Type, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	id, err := q.InsertEvent(ctx, db.InsertEventParams{UserID: userID, Type: string(eventType), Payload: data})
+	if err != nil {
+		return err
+	}
+	_, err = q.EnqueueWebhookDeliveries(ctx, id)
+	return err
+}
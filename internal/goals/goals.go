@@ -0,0 +1,179 @@
+package goals
+
+import (
+	"math"
+	"time"
+
+	"new-chainsaw/db"
+	"new-chainsaw/internal/streaks"
+	"new-chainsaw/internal/strength"
+)
+
+const (
+	// StartWeeks of logs before a goal is set give its starting value.
+	StartWeeks = 12
+	// TrendWeeks of weekly bests the rate of progress is fitted through.
+	TrendWeeks = 8
+	// minTrendWeeks trained in the trend window are needed to project a date.
+	minTrendWeeks = 3
+	// maxProjectionDays is how far ahead a goal is projected. Slower progress gives no date.
+	maxProjectionDays = 5 * 365
+	// maxEstimateReps matches the records, longer sets do not estimate a one rep max.
+	maxEstimateReps = 12
+)
+
+// Status is how a goal is going.
+type Status string
+
+const (
+	StatusAchieved     Status = "achieved"
+	StatusOnTrack      Status = "on_track"
+	StatusBehind       Status = "behind"  // Projected after the deadline, or not progressing
+	StatusMissed       Status = "missed"  // The deadline passed
+	StatusInsufficient Status = "no_data" // Too few recent weeks trained to project
+)
+
+// Goal is a target value of a metric for an exercise by a deadline.
+type Goal struct {
+	Metric     db.GoalMetric
+	Target     float64 // Kilograms, or repetitions for most_reps
+	Deadline   time.Time
+	StartValue float64
+	CreatedAt  time.Time
+	AchievedAt *time.Time // Set once the goal was reached, it stays reached
+}
+
+// Progress is where a lifter stands on a goal.
+type Progress struct {
+	Current       float64    // Best value since the goal was set, at least the starting value
+	Remaining     float64    // Target minus current, zero once reached
+	Percent       float64    // Of the way from the starting value to the target
+	WeeklyChange  *float64   // Slope of the weekly bests, nil with too few weeks trained
+	ProjectedDate *time.Time // When the trend reaches the target, nil if it does not progress
+	AchievedAt    *time.Time
+	Status        Status
+}
+
+// Value returns what a set counts for towards a metric. Sets too long to estimate a one rep
+// max from do not count for estimated_1rm.
+func Value(metric db.GoalMetric, set strength.Set) (float64, bool) {
+	if set.Reps <= 0 {
+		return 0, false
+	}
+	switch metric {
+	case db.GoalMetricEstimated1rm:
+		if set.RPE != nil {
+			if float64(set.Reps)+10-*set.RPE > maxEstimateReps {
+				return 0, false
+			}
+			return strength.EstimatedOneRepMaxAtRPE(set.LoadKg, set.Reps, *set.RPE), true
+		}
+		if set.Reps > maxEstimateReps {
+			return 0, false
+		}
+		return strength.EstimatedOneRepMax(set.LoadKg, set.Reps), true
+	case db.GoalMetricHeaviestWeight:
+		return set.LoadKg, true
+	case db.GoalMetricMostReps:
+		return float64(set.Reps), true
+	}
+	return 0, false
+}
+
+// Best returns the best value of sets for a metric, zero without a counting set.
+func Best(metric db.GoalMetric, sets []strength.Set) float64 {
+	best := 0.0
+	for _, set := range sets {
+		if value, ok := Value(metric, set); ok {
+			best = max(best, value)
+		}
+	}
+	return round(best)
+}
+
+// Track measures the progress on a goal from sets of its exercise ordered oldest first. They
+// should cover the time since the goal was set and the trend weeks before today.
+func Track(goal Goal, sets []strength.Set, today time.Time) Progress {
+	today = today.UTC().Truncate(24 * time.Hour)
+	since := goal.CreatedAt.UTC().Truncate(24 * time.Hour)
+
+	progress := Progress{Current: goal.StartValue, AchievedAt: goal.AchievedAt}
+	weekly := make(map[time.Time]float64)
+	for _, set := range sets {
+		value, ok := Value(goal.Metric, set)
+		if !ok {
+			continue
+		}
+		if !set.Date.Before(since) {
+			progress.Current = max(progress.Current, value)
+			if progress.AchievedAt == nil && value >= goal.Target {
+				date := set.Date
+				progress.AchievedAt = &date
+			}
+		}
+		if week := streaks.WeekStart(set.Date); !week.Before(today.AddDate(0, 0, -7*TrendWeeks)) {
+			weekly[week] = max(weekly[week], value)
+		}
+	}
+	progress.Current = round(progress.Current)
+	progress.Remaining = round(math.Max(0, goal.Target-progress.Current))
+	if total := goal.Target - goal.StartValue; total > 0 {
+		progress.Percent = round(math.Max(0, math.Min(100, (progress.Current-goal.StartValue)/total*100)))
+	}
+
+	if progress.AchievedAt != nil {
+		progress.Percent = 100
+		progress.Remaining = 0
+		progress.Status = StatusAchieved
+		return progress
+	}
+
+	level, slope, ok := fit(weekly, today)
+	if ok {
+		weeklyChange := round(slope * 7)
+		progress.WeeklyChange = &weeklyChange
+		if level >= goal.Target {
+			progress.ProjectedDate = &today
+		} else if slope > 0 {
+			if days := math.Ceil((goal.Target - level) / slope); days <= maxProjectionDays {
+				projected := today.AddDate(0, 0, int(days))
+				progress.ProjectedDate = &projected
+			}
+		}
+	}
+
+	switch {
+	case today.After(goal.Deadline):
+		progress.Status = StatusMissed
+	case !ok:
+		progress.Status = StatusInsufficient
+	case progress.ProjectedDate != nil && !progress.ProjectedDate.After(goal.Deadline):
+		progress.Status = StatusOnTrack
+	default:
+		progress.Status = StatusBehind
+	}
+	return progress
+}
+
+// fit fits a least squares line through the weekly bests and returns its value today and its
+// slope per day.
+func fit(weekly map[time.Time]float64, today time.Time) (float64, float64, bool) {
+	if len(weekly) < minTrendWeeks {
+		return 0, 0, false
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for week, value := range weekly {
+		x := week.Sub(today).Hours() / 24 // Days before today, so the intercept is the value today
+		sumX += x
+		sumY += value
+		sumXY += x * value
+		sumXX += x * x
+	}
+	n := float64(len(weekly))
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	return (sumY - slope*sumX) / n, slope, true
+}
+
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/response"
+)
+
+const (
+	analyticsPeriodWeek  = "week"
+	analyticsPeriodMonth = "month"
+)
+
+var (
+	defaultAnalyticsPeriods = 12
+	maxAnalyticsPeriods     = map[string]int{analyticsPeriodWeek: 52, analyticsPeriodMonth: 24}
+)
+
+// intensityBuckets are the lower bounds of the intensity distribution in percent of the
+// estimated one rep max, matching GetIntensityDistribution.
+var intensityBuckets = []int32{0, 60, 70, 80, 90}
+
+// TrainingAnalytics is the cached analytics of a user. Weights are in kilograms.
+type TrainingAnalytics struct {
+	Period     string              `json:"period"`
+	From       time.Time           `json:"from"`
+	Unit       string              `json:"unit"`
+	Volume     []VolumePeriod      `json:"volume"`
+	Frequency  []ExerciseFrequency `json:"frequency"`
+	Intensity  []IntensityBucket   `json:"intensity"`
+	ComputedAt time.Time           `json:"computed_at"`
+}
+
+type VolumePeriod struct {
+	Start         time.Time         `json:"start"`
+	Tonnage       float64           `json:"tonnage"`
+	Sets          int64             `json:"sets"`
+	Reps          int64             `json:"reps"`
+	TrainingDays  int64             `json:"training_days"`
+	ChangePercent *float64          `json:"change_percent"` // Tonnage change from the previous period, nil without previous tonnage
+	MuscleGroups  []MuscleGroupSets `json:"muscle_groups"`
+}
+
+// MuscleGroupSets counts the sets of a muscle group in a period. Sets where the muscle group is
+// secondary count half towards the effective sets.
+type MuscleGroupSets struct {
+	MuscleGroup   string  `json:"muscle_group"`
+	PrimarySets   int64   `json:"primary_sets"`
+	SecondarySets int64   `json:"secondary_sets"`
+	EffectiveSets float64 `json:"effective_sets"`
+}
+
+type ExerciseFrequency struct {
+	ExerciseID    int32     `json:"exercise_id"`
+	ExerciseName  string    `json:"exercise_name"`
+	TrainingDays  int64     `json:"training_days"`
+	Sets          int64     `json:"sets"`
+	DaysPerWeek   float64   `json:"days_per_week"`
+	LastPerformed time.Time `json:"last_performed"`
+}
+
+type IntensityBucket struct {
+	FromPercent int32   `json:"from_percent"`
+	ToPercent   *int32  `json:"to_percent"` // Exclusive, nil for the top bucket
+	Sets        int64   `json:"sets"`
+	Share       float64 `json:"share"` // Percent of the sets in the window
+}
+
+// GetTrainingAnalyticsHandler returns tonnage, muscle group sets, exercise frequency and
+// intensity distribution over the last periods weeks or months, including the current one.
+// Results are cached until the user's exercise logs change.
+func GetTrainingAnalyticsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	period := c.DefaultQuery("period", analyticsPeriodWeek)
+	maxPeriods, ok := maxAnalyticsPeriods[period]
+	if !ok {
+		response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Period must be %s or %s", analyticsPeriodWeek, analyticsPeriodMonth), nil, nil)
+		return
+	}
+	periods := defaultAnalyticsPeriods
+	if p := c.Query("periods"); p != "" {
+		parsed, err := strconv.Atoi(p)
+		if err != nil || parsed < 1 || parsed > maxPeriods {
+			response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Periods must be between 1 and %d", maxPeriods), nil, err)
+			return
+		}
+		periods = parsed
+	}
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	from := analyticsWindowStart(now, period, periods)
+	cacheKey := fmt.Sprintf("%s:%d:%s", period, periods, from.Format("2006-01-02"))
+
+	var analytics TrainingAnalytics
+	cached := true
+	payload, err := queries.GetAnalyticsCache(ctx, db.GetAnalyticsCacheParams{UserID: int32(userID), CacheKey: cacheKey})
+	if err == nil {
+		err = json.Unmarshal(payload, &analytics)
+	}
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Ignoring analytics cache of user %d: %v\n", userID, err)
+		}
+		cached = false
+		// Read before the logs, analytics are only cached when no log was written since
+		version, err := queries.GetAnalyticsVersion(ctx, int32(userID))
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to compute analytics", nil, err)
+			return
+		}
+		analytics, err = computeTrainingAnalytics(ctx, int32(userID), period, from, now)
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to compute analytics", nil, err)
+			return
+		}
+		if payload, err := json.Marshal(analytics); err == nil {
+			_, err = queries.SaveAnalyticsCache(ctx, db.SaveAnalyticsCacheParams{UserID: int32(userID), CacheKey: cacheKey, Payload: payload, Version: version})
+			if err != nil {
+				log.Printf("Failed to cache analytics of user %d: %v\n", userID, err)
+			}
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"analytics": inUnits(analytics, units), "cached": cached}, nil)
+}
+
+func computeTrainingAnalytics(ctx context.Context, userID int32, period string, from time.Time, now time.Time) (TrainingAnalytics, error) {
+	fromDate := pgtype.Timestamptz{Time: from, Valid: true}
+	analytics := TrainingAnalytics{
+		Period:     period,
+		From:       from,
+		Unit:       string(db.UnitSystemMetric),
+		Volume:     []VolumePeriod{},
+		Frequency:  []ExerciseFrequency{},
+		Intensity:  []IntensityBucket{},
+		ComputedAt: now,
+	}
+
+	volume, err := queries.GetTrainingVolume(ctx, db.GetTrainingVolumeParams{Period: period, FromDate: fromDate, UserID: userID})
+	if err != nil {
+		return TrainingAnalytics{}, err
+	}
+	muscleGroups, err := queries.GetMuscleGroupSets(ctx, db.GetMuscleGroupSetsParams{Period: period, UserID: userID, FromDate: fromDate})
+	if err != nil {
+		return TrainingAnalytics{}, err
+	}
+	setsByPeriod := make(map[time.Time][]MuscleGroupSets)
+	for _, m := range muscleGroups {
+		setsByPeriod[m.PeriodStart.Time] = append(setsByPeriod[m.PeriodStart.Time], MuscleGroupSets{
+			MuscleGroup:   m.MuscleGroup,
+			PrimarySets:   m.PrimarySets,
+			SecondarySets: m.SecondarySets,
+			EffectiveSets: float64(m.PrimarySets) + float64(m.SecondarySets)/2,
+		})
+	}
+	for _, v := range volume {
+		p := VolumePeriod{
+			Start:        v.PeriodStart.Time,
+			Tonnage:      roundTo(numericToFloat(v.Tonnage), 2),
+			Sets:         v.Sets,
+			Reps:         v.Reps,
+			TrainingDays: v.TrainingDays,
+			MuscleGroups: setsByPeriod[v.PeriodStart.Time],
+		}
+		if previous := numericToFloat(v.PreviousTonnage); previous > 0 {
+			change := roundTo((p.Tonnage-previous)/previous*100, 1)
+			p.ChangePercent = &change
+		}
+		if p.MuscleGroups == nil {
+			p.MuscleGroups = []MuscleGroupSets{}
+		}
+		analytics.Volume = append(analytics.Volume, p)
+	}
+
+	frequency, err := queries.GetExerciseFrequency(ctx, db.GetExerciseFrequencyParams{UserID: userID, FromDate: fromDate})
+	if err != nil {
+		return TrainingAnalytics{}, err
+	}
+	weeks := now.Sub(from).Hours() / 24 / 7
+	for _, f := range frequency {
+		analytics.Frequency = append(analytics.Frequency, ExerciseFrequency{
+			ExerciseID:    f.ExerciseID,
+			ExerciseName:  f.ExerciseName,
+			TrainingDays:  f.TrainingDays,
+			Sets:          f.Sets,
+			DaysPerWeek:   roundTo(float64(f.TrainingDays)/max(weeks, 1), 2),
+			LastPerformed: f.LastPerformed.Time,
+		})
+	}
+
+	intensity, err := queries.GetIntensityDistribution(ctx, db.GetIntensityDistributionParams{UserID: userID, FromDate: fromDate})
+	if err != nil {
+		return TrainingAnalytics{}, err
+	}
+	counts := make(map[int32]int64, len(intensity))
+	var total int64
+	for _, bucket := range intensity {
+		counts[bucket.PercentFrom] = bucket.Sets
+		total += bucket.Sets
+	}
+	for i, percent := range intensityBuckets {
+		bucket := IntensityBucket{FromPercent: percent, Sets: counts[percent]}
+		if i+1 < len(intensityBuckets) {
+			bucket.ToPercent = &intensityBuckets[i+1]
+		}
+		if total > 0 {
+			bucket.Share = roundTo(float64(bucket.Sets)/float64(total)*100, 1)
+		}
+		analytics.Intensity = append(analytics.Intensity, bucket)
+	}
+
+	return analytics, nil
+}
+
+// analyticsWindowStart returns the start of the period periods-1 periods before the current
+// one. Weeks start on Monday like date_trunc('week').
+func analyticsWindowStart(now time.Time, period string, periods int) time.Time {
+	day := startOfDay(now)
+	if period == analyticsPeriodMonth {
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-periods, 0)
+	}
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	return monday.AddDate(0, 0, -7*(periods-1))
+}
+
+// inUnits converts the tonnage of analytics to the user's units.
+func inUnits(analytics TrainingAnalytics, units db.UnitSystem) TrainingAnalytics {
+	if units != db.UnitSystemImperial {
+		return analytics
+	}
+	analytics.Unit = string(units)
+	volume := make([]VolumePeriod, len(analytics.Volume))
+	for i, p := range analytics.Volume {
+		p.Tonnage = roundTo(conversion.KgToLbs(p.Tonnage), 2)
+		volume[i] = p
+	}
+	analytics.Volume = volume
+	return analytics
+}
@@ -52,7 +52,14 @@ func AuthCallback(c *gin.Context) {
 	fmt.Println("Username: ", username)
 	fmt.Println("Name: ", name)
 
-	token, err := middleware.GenerateJWT(userID, username, name, user.Email, user.AvatarURL, isNewUser)
+	// Accounts inside their deletion grace period can sign in, but only to restore the account
+	deletionStatus, err := queries.GetUserDeletionStatus(context.Background(), int32(userID))
+	if err != nil {
+		response.LogErrorAndRespond(c, http.StatusInternalServerError, "Failed to fetch account status: "+err.Error(), "Failed to fetch account status", err)
+		return
+	}
+
+	token, err := middleware.GenerateJWT(userID, username, name, user.Email, user.AvatarURL, isNewUser, deletionStatus.DeletedAt.Valid)
 	if err != nil {
 		response.LogErrorAndRespond(c, http.StatusInternalServerError, "Failed to generate JWT token: "+err.Error(), "Failed to generate JWT token", err)
 		return
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/bodyweight"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+const (
+	defaultTrendDays = 90
+	maxTrendDays     = 2 * 366
+	// trendWarmupDays of weigh-ins before the requested range settle the smoothed trend
+	trendWarmupDays = 30
+)
+
+type BodyweightGoalRequest struct {
+	TargetWeight float64 `json:"target_weight"`
+	TargetDate   string  `json:"target_date"` // YYYY-MM-DD
+	Unit         string  `json:"unit"`        // Unit of the target weight, defaults to the preferred units
+}
+
+type BodyweightTrendDay struct {
+	Date         time.Time `json:"date"`
+	Weight       float64   `json:"weight"`
+	Trend        float64   `json:"trend"`
+	Interpolated bool      `json:"interpolated"` // No weigh-in on this day, the weight is interpolated
+}
+
+type BodyweightTrendDetails struct {
+	Unit         string               `json:"unit"`
+	From         time.Time            `json:"from"`
+	To           time.Time            `json:"to"`
+	Current      *float64             `json:"current"`       // Latest trend weight, nil without weigh-ins
+	WeeklyChange *float64             `json:"weekly_change"` // nil without enough recent weigh-ins
+	Days         []BodyweightTrendDay `json:"days"`
+}
+
+type BodyweightGoalDetails struct {
+	Unit                 string     `json:"unit"`
+	TargetWeight         float64    `json:"target_weight"`
+	TargetDate           time.Time  `json:"target_date"`
+	StartWeight          float64    `json:"start_weight"`
+	CurrentWeight        float64    `json:"current_weight"` // Trend weight, or the latest weigh-in without a recent trend
+	RemainingWeight      float64    `json:"remaining_weight"`
+	Progress             float64    `json:"progress"` // Percent
+	Achieved             bool       `json:"achieved"`
+	RequiredWeeklyChange *float64   `json:"required_weekly_change"`
+	WeeklyChange         *float64   `json:"weekly_change"`
+	ProjectedDate        *time.Time `json:"projected_date"`
+	OnTrack              bool       `json:"on_track"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+// GetBodyweightTrendHandler returns the smoothed bodyweight trend of every day between from and
+// to, which default to the last 90 days. Days without a weigh-in are interpolated when the gap
+// is short, and the trend restarts after a long one.
+func GetBodyweightTrendHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	to := startOfDay(time.Now())
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, "Query parameter to must be a date like 2006-01-02", nil, err)
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultTrendDays)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, "Query parameter from must be a date like 2006-01-02", nil, err)
+			return
+		}
+		from = parsed
+	}
+	if from.After(to) || to.Sub(from).Hours()/24 >= maxTrendDays {
+		response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("From must be before to and at most %d days apart", maxTrendDays), nil, nil)
+		return
+	}
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+
+	trend, err := bodyweightTrend(ctx, int32(userID), from, to)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch bodyweight logs", nil, err)
+		return
+	}
+
+	details := BodyweightTrendDetails{Unit: string(units), From: from, To: to, Days: []BodyweightTrendDay{}}
+	for _, day := range trend.Days {
+		if day.Date.Before(from) {
+			continue
+		}
+		details.Days = append(details.Days, BodyweightTrendDay{
+			Date:         day.Date,
+			Weight:       unitWeight(day.WeightKg, units),
+			Trend:        unitWeight(day.TrendKg, units),
+			Interpolated: day.Interpolated,
+		})
+	}
+	if len(details.Days) > 0 {
+		current := details.Days[len(details.Days)-1].Trend
+		details.Current = &current
+		if trend.WeeklyChangeKg != nil {
+			change := unitWeight(*trend.WeeklyChangeKg, units)
+			details.WeeklyChange = &change
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"trend": details}, nil)
+}
+
+// GetBodyweightGoalHandler returns the user's goal with the progress of their current trend.
+func GetBodyweightGoalHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	goal, err := queries.GetBodyweightGoal(ctx, int32(userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.JSONResponse(c, http.StatusNotFound, "No bodyweight goal set", nil, err)
+		return
+	}
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch bodyweight goal", nil, err)
+		return
+	}
+
+	details, err := toBodyweightGoalDetails(ctx, goal)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to project bodyweight goal", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"goal": details}, nil)
+}
+
+// SetBodyweightGoalHandler sets the user's target weight and date, replacing any previous goal.
+// The latest weigh-in is the starting point the progress is measured from.
+func SetBodyweightGoalHandler(c *gin.Context) {
+	var req BodyweightGoalRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	if req.Unit != "" {
+		if units, err = parseUnitSystem(req.Unit); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+	}
+
+	targetDate, err := time.Parse("2006-01-02", req.TargetDate)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Target date must be a date like 2006-01-02", nil, err)
+		return
+	}
+	targetWeight := convertUnits(req.TargetWeight, units, db.UnitSystemMetric)
+	if err := validation.ValidateBodyweightGoal(targetWeight, targetDate, startOfDay(time.Now())); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	startWeight, err := queries.GetLatestBodyWeight(ctx, int32(userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.JSONResponse(c, http.StatusBadRequest, "Log your bodyweight before setting a goal", nil, err)
+		return
+	}
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch latest bodyweight", nil, err)
+		return
+	}
+
+	goal, err := queries.UpsertBodyweightGoal(ctx, db.UpsertBodyweightGoalParams{
+		UserID:       int32(userID),
+		TargetWeight: conversion.ToNumeric(targetWeight),
+		TargetDate:   pgtype.Date{Time: targetDate, Valid: true},
+		StartWeight:  startWeight,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save bodyweight goal", nil, err)
+		return
+	}
+
+	details, err := toBodyweightGoalDetails(ctx, goal)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to project bodyweight goal", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Bodyweight goal saved", gin.H{"goal": details}, nil)
+}
+
+func DeleteBodyweightGoalHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	deleted, err := queries.DeleteBodyweightGoal(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete bodyweight goal", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "No bodyweight goal set", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Bodyweight goal deleted", nil, nil)
+}
+
+// bodyweightTrend smooths the weigh-ins up to the end of the to day, starting a few weeks
+// before from so the trend has settled by then.
+func bodyweightTrend(ctx context.Context, userID int32, from time.Time, to time.Time) (bodyweight.Trend, error) {
+	logs, err := queries.GetBodyWeightLogsBetween(ctx, db.GetBodyWeightLogsBetweenParams{
+		UserID:   userID,
+		FromDate: pgtype.Timestamptz{Time: from.AddDate(0, 0, -trendWarmupDays), Valid: true},
+		ToDate:   pgtype.Timestamptz{Time: to.AddDate(0, 0, 1), Valid: true},
+	})
+	if err != nil {
+		return bodyweight.Trend{}, err
+	}
+
+	points := make([]bodyweight.Point, len(logs))
+	for i, entry := range logs {
+		points[i] = bodyweight.Point{Date: entry.LogDate.Time, WeightKg: numericToFloat(entry.Bodyweight)}
+	}
+	return bodyweight.Smooth(points), nil
+}
+
+// toBodyweightGoalDetails projects a goal from the trend of the last weeks in the user's
+// preferred units.
+func toBodyweightGoalDetails(ctx context.Context, goal db.BodyweightGoal) (BodyweightGoalDetails, error) {
+	units, err := queries.GetUserPreferredUnit(ctx, goal.UserID)
+	if err != nil {
+		return BodyweightGoalDetails{}, err
+	}
+
+	today := startOfDay(time.Now())
+	trend, err := bodyweightTrend(ctx, goal.UserID, today.AddDate(0, 0, 1-defaultTrendDays), today)
+	if err != nil {
+		return BodyweightGoalDetails{}, err
+	}
+	current, ok := trend.Current()
+	if !ok {
+		latest, err := queries.GetLatestBodyWeight(ctx, goal.UserID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return BodyweightGoalDetails{}, err
+		}
+		current = numericToFloat(latest)
+	}
+
+	projection := bodyweight.Project(bodyweight.Goal{
+		TargetKg:   numericToFloat(goal.TargetWeight),
+		TargetDate: goal.TargetDate.Time,
+		StartKg:    numericToFloat(goal.StartWeight),
+	}, current, trend.WeeklyChangeKg, today)
+
+	details := BodyweightGoalDetails{
+		Unit:            string(units),
+		TargetWeight:    unitWeight(numericToFloat(goal.TargetWeight), units),
+		TargetDate:      goal.TargetDate.Time,
+		StartWeight:     unitWeight(numericToFloat(goal.StartWeight), units),
+		CurrentWeight:   unitWeight(current, units),
+		RemainingWeight: unitWeight(projection.RemainingKg, units),
+		Progress:        projection.Progress,
+		Achieved:        projection.Achieved,
+		ProjectedDate:   projection.ProjectedDate,
+		OnTrack:         projection.OnTrack,
+		CreatedAt:       goal.CreatedAt.Time,
+	}
+	if projection.RequiredWeeklyKg != nil {
+		required := unitWeight(*projection.RequiredWeeklyKg, units)
+		details.RequiredWeeklyChange = &required
+	}
+	if trend.WeeklyChangeKg != nil {
+		change := unitWeight(*trend.WeeklyChangeKg, units)
+		details.WeeklyChange = &change
+	}
+	return details, nil
+}
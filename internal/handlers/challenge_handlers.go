@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/challenges"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+// maxOpenChallenges is how many open challenges are listed.
+const maxOpenChallenges = 50
+
+type ChallengeRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Metric      string  `json:"metric"`      // total_reps, tonnage, sessions or best_e1rm
+	ExerciseID  *int32  `json:"exercise_id"` // Only sets of this exercise count, every exercise when omitted
+	StartsOn    string  `json:"starts_on"`   // YYYY-MM-DD
+	EndsOn      string  `json:"ends_on"`     // YYYY-MM-DD, inclusive
+	OpenJoin    bool    `json:"open_join"`   // Anyone can join, otherwise only invited users
+}
+
+type ChallengeInviteRequest struct {
+	Username string `json:"username"`
+}
+
+type ChallengeSummary struct {
+	ID                int32     `json:"id"`
+	OwnerUserID       int32     `json:"owner_user_id"`
+	Name              string    `json:"name"`
+	Metric            string    `json:"metric"`
+	ExerciseID        *int32    `json:"exercise_id"`
+	StartsOn          time.Time `json:"starts_on"`
+	EndsOn            time.Time `json:"ends_on"`
+	OpenJoin          bool      `json:"open_join"`
+	Status            string    `json:"status"`             // upcoming, active, ended or finalized
+	ParticipantStatus *string   `json:"participant_status"` // invited or joined, nil when not taking part
+}
+
+type ChallengeDetails struct {
+	ChallengeSummary
+	Description *string             `json:"description"`
+	Exercise    *string             `json:"exercise"`
+	Unit        string              `json:"unit"` // kg or lb for tonnage and best_e1rm, otherwise reps or sessions
+	FinalizedAt *time.Time          `json:"finalized_at"`
+	Leaderboard []ChallengeStanding `json:"leaderboard"` // Live until the challenge is finalized
+	Invited     []ChallengeInvitee  `json:"invited"`     // Invites not accepted yet
+}
+
+type ChallengeStanding struct {
+	Rank     int     `json:"rank"`
+	UserID   int32   `json:"user_id"`
+	Username string  `json:"username"`
+	Name     *string `json:"name"`
+	Value    float64 `json:"value"`
+	Won      bool    `json:"won"`
+}
+
+type ChallengeInvitee struct {
+	UserID   int32  `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ListChallengesHandler returns the challenges the user organizes, joined or is invited to.
+func ListChallengesHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	list, err := queries.ListUserChallenges(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch challenges", nil, err)
+		return
+	}
+
+	today := startOfDay(time.Now())
+	summaries := make([]ChallengeSummary, len(list))
+	for i, row := range list {
+		summaries[i] = challengeSummary(db.Challenge{
+			ID:          row.ID,
+			OwnerUserID: row.OwnerUserID,
+			Name:        row.Name,
+			Metric:      row.Metric,
+			ExerciseID:  row.ExerciseID,
+			StartsOn:    row.StartsOn,
+			EndsOn:      row.EndsOn,
+			OpenJoin:    row.OpenJoin,
+			FinalizedAt: row.FinalizedAt,
+		}, today)
+		if row.ParticipantStatus.Valid {
+			status := string(row.ParticipantStatus.ChallengeParticipantStatus)
+			summaries[i].ParticipantStatus = &status
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"challenges": summaries}, nil)
+}
+
+// ListOpenChallengesHandler returns the challenges anyone can join that have not ended.
+func ListOpenChallengesHandler(c *gin.Context) {
+	list, err := queries.ListOpenChallenges(context.Background(), maxOpenChallenges)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch challenges", nil, err)
+		return
+	}
+
+	today := startOfDay(time.Now())
+	summaries := make([]ChallengeSummary, len(list))
+	for i, challenge := range list {
+		summaries[i] = challengeSummary(challenge, today)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"challenges": summaries}, nil)
+}
+
+// CreateChallengeHandler creates a challenge the organizer takes part in. Exercise filters are
+// limited to catalog exercises, which every participant can log.
+func CreateChallengeHandler(c *gin.Context) {
+	var req ChallengeRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	startsOn, err := time.Parse("2006-01-02", req.StartsOn)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Start date must be a date like 2006-01-02", nil, err)
+		return
+	}
+	endsOn, err := time.Parse("2006-01-02", req.EndsOn)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "End date must be a date like 2006-01-02", nil, err)
+		return
+	}
+	metric := db.ChallengeMetric(req.Metric)
+	if err := validation.ValidateChallenge(req.Name, metric, req.ExerciseID, startsOn, endsOn, startOfDay(time.Now())); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	if req.ExerciseID != nil {
+		exercise, err := queries.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{ID: *req.ExerciseID, UserID: int32(userID)})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				response.JSONResponse(c, http.StatusBadRequest, "Unknown exercise", nil, err)
+				return
+			}
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise", nil, err)
+			return
+		}
+		if exercise.OwnerUserID.Valid {
+			response.JSONResponse(c, http.StatusBadRequest, "Challenges can only be limited to catalog exercises", nil, nil)
+			return
+		}
+		if metric != db.ChallengeMetricSessions && exercise.MeasurementKind != db.MeasurementKindRepsWeight {
+			response.JSONResponse(c, http.StatusBadRequest, "Only sessions can be counted for exercises not logged with reps and weight", nil, nil)
+			return
+		}
+	}
+
+	description := pgtype.Text{}
+	if req.Description != nil && strings.TrimSpace(*req.Description) != "" {
+		description = pgtype.Text{String: strings.TrimSpace(*req.Description), Valid: true}
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create challenge", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	challenge, err := qtx.CreateChallenge(ctx, db.CreateChallengeParams{
+		OwnerUserID: int32(userID),
+		Name:        strings.TrimSpace(req.Name),
+		Description: description,
+		Metric:      metric,
+		ExerciseID:  optionalInt4(req.ExerciseID),
+		StartsOn:    pgtype.Date{Time: startsOn, Valid: true},
+		EndsOn:      pgtype.Date{Time: endsOn, Valid: true},
+		OpenJoin:    req.OpenJoin,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create challenge", nil, err)
+		return
+	}
+	if err := qtx.JoinChallenge(ctx, db.JoinChallengeParams{ChallengeID: challenge.ID, UserID: int32(userID)}); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create challenge", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create challenge", nil, err)
+		return
+	}
+
+	respondWithChallenge(c, http.StatusCreated, "Challenge created", challenge)
+}
+
+// GetChallengeHandler returns a challenge with its leaderboard.
+func GetChallengeHandler(c *gin.Context) {
+	challenge, _, ok := fetchChallenge(c)
+	if !ok {
+		return
+	}
+	respondWithChallenge(c, http.StatusOK, "", challenge)
+}
+
+// DeleteChallengeHandler deletes a challenge that was not finalized.
+func DeleteChallengeHandler(c *gin.Context) {
+	challenge, _, ok := fetchChallenge(c)
+	if !ok {
+		return
+	}
+	if challenge.OwnerUserID != int32(c.GetInt("userID")) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the organizer can delete the challenge", nil, nil)
+		return
+	}
+
+	deleted, err := queries.DeleteChallenge(context.Background(), db.DeleteChallengeParams{ID: challenge.ID, OwnerUserID: challenge.OwnerUserID})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete challenge", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusConflict, "Finalized challenges cannot be deleted", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Challenge deleted", nil, nil)
+}
+
+// InviteToChallengeHandler invites a user to a challenge that has not ended.
+func InviteToChallengeHandler(c *gin.Context) {
+	challenge, _, ok := fetchChallenge(c)
+	if !ok {
+		return
+	}
+	var req ChallengeInviteRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	if challenge.OwnerUserID != int32(c.GetInt("userID")) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the organizer can invite to the challenge", nil, nil)
+		return
+	}
+	if !challengeRunning(challenge) {
+		response.JSONResponse(c, http.StatusConflict, "The challenge has ended", nil, nil)
+		return
+	}
+
+	invitee, err := queries.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown user", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch user", nil, err)
+		return
+	}
+	organizer, err := queries.GetUserAccount(ctx, challenge.OwnerUserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch user", nil, err)
+		return
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to invite user", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	invited, err := qtx.InviteChallengeParticipant(ctx, db.InviteChallengeParticipantParams{
+		ChallengeID: challenge.ID,
+		UserID:      invitee.ID,
+		InvitedBy:   pgtype.Int4{Int32: organizer.ID, Valid: true},
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to invite user", nil, err)
+		return
+	}
+	if invited == 0 {
+		response.JSONResponse(c, http.StatusConflict, "The user is already invited or taking part", nil, nil)
+		return
+	}
+	err = events.Emit(ctx, qtx, invitee.ID, events.ChallengeInvited, events.ChallengeInvitedPayload{
+		ChallengeID: challenge.ID,
+		Challenge:   challenge.Name,
+		InvitedBy:   organizer.Username,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to invite user", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to invite user", nil, err)
+		return
+	}
+
+	respondWithChallenge(c, http.StatusCreated, "User invited", challenge)
+}
+
+// JoinChallengeHandler joins an open challenge or accepts an invite, until the challenge ends.
+func JoinChallengeHandler(c *gin.Context) {
+	challenge, participant, ok := fetchChallenge(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	if !challengeRunning(challenge) {
+		response.JSONResponse(c, http.StatusConflict, "The challenge has ended", nil, nil)
+		return
+	}
+	if participant != nil && participant.Status == db.ChallengeParticipantStatusJoined {
+		response.JSONResponse(c, http.StatusConflict, "You already joined this challenge", nil, nil)
+		return
+	}
+	if participant == nil && !challenge.OpenJoin {
+		response.JSONResponse(c, http.StatusForbidden, "This challenge is invite only", nil, nil)
+		return
+	}
+
+	if err := queries.JoinChallenge(context.Background(), db.JoinChallengeParams{ChallengeID: challenge.ID, UserID: int32(userID)}); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to join challenge", nil, err)
+		return
+	}
+
+	respondWithChallenge(c, http.StatusOK, "Joined challenge", challenge)
+}
+
+// LeaveChallengeHandler leaves a challenge or declines an invite. Once a challenge ended its
+// participants are fixed.
+func LeaveChallengeHandler(c *gin.Context) {
+	challenge, participant, ok := fetchChallenge(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	switch {
+	case participant == nil:
+		response.JSONResponse(c, http.StatusConflict, "You are not taking part in this challenge", nil, nil)
+		return
+	case challenge.OwnerUserID == int32(userID):
+		response.JSONResponse(c, http.StatusConflict, "The organizer cannot leave, delete the challenge instead", nil, nil)
+		return
+	case !challengeRunning(challenge):
+		response.JSONResponse(c, http.StatusConflict, "The challenge has ended", nil, nil)
+		return
+	}
+
+	if _, err := queries.LeaveChallenge(context.Background(), db.LeaveChallengeParams{ChallengeID: challenge.ID, UserID: int32(userID)}); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to leave challenge", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Left challenge", nil, nil)
+}
+
+// fetchChallenge returns the challenge of the request with the user's participation, nil when
+// they take no part. Challenges that are invite only are hidden from everybody else.
+func fetchChallenge(c *gin.Context) (db.Challenge, *db.ChallengeParticipant, bool) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	challengeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid challenge ID", nil, err)
+		return db.Challenge{}, nil, false
+	}
+
+	challenge, err := queries.GetChallenge(ctx, int32(challengeID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Challenge not found", nil, err)
+			return db.Challenge{}, nil, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch challenge", nil, err)
+		return db.Challenge{}, nil, false
+	}
+
+	var participant *db.ChallengeParticipant
+	p, err := queries.GetChallengeParticipant(ctx, db.GetChallengeParticipantParams{ChallengeID: challenge.ID, UserID: int32(userID)})
+	switch {
+	case err == nil:
+		participant = &p
+	case !errors.Is(err, pgx.ErrNoRows):
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch challenge", nil, err)
+		return db.Challenge{}, nil, false
+	}
+
+	if participant == nil && !challenge.OpenJoin && challenge.OwnerUserID != int32(userID) {
+		response.JSONResponse(c, http.StatusNotFound, "Challenge not found", nil, nil)
+		return db.Challenge{}, nil, false
+	}
+	return challenge, participant, true
+}
+
+// challengeRunning reports whether a challenge has not ended yet.
+func challengeRunning(challenge db.Challenge) bool {
+	return !challenge.FinalizedAt.Valid && !startOfDay(time.Now()).After(challenge.EndsOn.Time)
+}
+
+func respondWithChallenge(c *gin.Context, status int, message string, challenge db.Challenge) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	details, err := challengeDetails(ctx, challenge, int32(userID), units)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch challenge", nil, err)
+		return
+	}
+	response.JSONResponse(c, status, message, gin.H{"challenge": details}, nil)
+}
+
+func challengeSummary(challenge db.Challenge, today time.Time) ChallengeSummary {
+	return ChallengeSummary{
+		ID:          challenge.ID,
+		OwnerUserID: challenge.OwnerUserID,
+		Name:        challenge.Name,
+		Metric:      string(challenge.Metric),
+		ExerciseID:  optionalInt32(challenge.ExerciseID),
+		StartsOn:    challenge.StartsOn.Time,
+		EndsOn:      challenge.EndsOn.Time,
+		OpenJoin:    challenge.OpenJoin,
+		Status:      string(challenges.StatusOn(challenge.StartsOn.Time, challenge.EndsOn.Time, challenge.FinalizedAt.Valid, today)),
+	}
+}
+
+// challengeDetails builds the leaderboard of a challenge, live from the participants' logs
+// until the final ranks are saved.
+func challengeDetails(ctx context.Context, challenge db.Challenge, userID int32, units db.UnitSystem) (ChallengeDetails, error) {
+	details := ChallengeDetails{
+		ChallengeSummary: challengeSummary(challenge, startOfDay(time.Now())),
+		Leaderboard:      []ChallengeStanding{},
+		Invited:          []ChallengeInvitee{},
+	}
+	if challenge.Description.Valid {
+		details.Description = &challenge.Description.String
+	}
+	if challenge.FinalizedAt.Valid {
+		details.FinalizedAt = &challenge.FinalizedAt.Time
+	}
+	switch challenge.Metric {
+	case db.ChallengeMetricTotalReps:
+		details.Unit = "reps"
+	case db.ChallengeMetricSessions:
+		details.Unit = "sessions"
+	default:
+		details.Unit = weightUnit(units)
+	}
+	if challenge.ExerciseID.Valid {
+		exercise, err := queries.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{ID: challenge.ExerciseID.Int32, UserID: userID})
+		if err != nil {
+			return ChallengeDetails{}, err
+		}
+		details.Exercise = &exercise.Name
+	}
+
+	participants, err := queries.ListChallengeParticipants(ctx, challenge.ID)
+	if err != nil {
+		return ChallengeDetails{}, err
+	}
+	joined := make(map[int32]db.ListChallengeParticipantsRow, len(participants))
+	for _, participant := range participants {
+		if participant.UserID == userID {
+			status := string(participant.Status)
+			details.ParticipantStatus = &status
+		}
+		if participant.Status == db.ChallengeParticipantStatusInvited {
+			details.Invited = append(details.Invited, ChallengeInvitee{UserID: participant.UserID, Username: participant.Username})
+			continue
+		}
+		joined[participant.UserID] = participant
+	}
+
+	var ranked []challenges.Ranked
+	if challenge.FinalizedAt.Valid {
+		for _, participant := range joined {
+			if !participant.FinalRank.Valid {
+				continue
+			}
+			ranked = append(ranked, challenges.Ranked{
+				Standing: challenges.Standing{UserID: participant.UserID, Value: numericToFloat(participant.FinalValue), JoinedAt: participant.JoinedAt.Time},
+				Rank:     int(participant.FinalRank.Int32),
+			})
+		}
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].Rank != ranked[j].Rank {
+				return ranked[i].Rank < ranked[j].Rank
+			}
+			return ranked[i].JoinedAt.Before(ranked[j].JoinedAt)
+		})
+	} else {
+		rows, err := queries.GetChallengeStandings(ctx, challenge.ID)
+		if err != nil {
+			return ChallengeDetails{}, err
+		}
+		ranked = challenges.Rank(challenges.Standings(challenge.Metric, rows))
+	}
+
+	for _, r := range ranked {
+		participant := joined[r.UserID]
+		standing := ChallengeStanding{
+			Rank:     r.Rank,
+			UserID:   r.UserID,
+			Username: participant.Username,
+			Value:    r.Value,
+			Won:      challenge.FinalizedAt.Valid && r.Won(),
+		}
+		if participant.Name.Valid {
+			standing.Name = &participant.Name.String
+		}
+		if challenges.IsWeight(challenge.Metric) {
+			standing.Value = unitWeight(r.Value, units)
+		}
+		details.Leaderboard = append(details.Leaderboard, standing)
+	}
+	return details, nil
+}
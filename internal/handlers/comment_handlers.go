@@ -0,0 +1,670 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/comments"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/strength"
+	"new-chainsaw/internal/validation"
+)
+
+// reactionKinds lists the reactions in the order they are shown.
+var reactionKinds = []db.ReactionKind{db.ReactionKindLike, db.ReactionKindFire, db.ReactionKindStrong, db.ReactionKindClap}
+
+type CommentRequest struct {
+	Body     string `json:"body"`
+	ParentID *int32 `json:"parent_id"` // The top level comment replied to
+}
+
+type ReactionRequest struct {
+	Kind string `json:"kind"` // like, fire, strong or clap
+}
+
+type CommentDetails struct {
+	ID        int32            `json:"id"`
+	ParentID  *int32           `json:"parent_id"`
+	UserID    int32            `json:"user_id"`
+	Username  string           `json:"username"`
+	Name      *string          `json:"name"`
+	AvatarURL *string          `json:"avatar_url"`
+	Body      string           `json:"body"`
+	EditedAt  *time.Time       `json:"edited_at"`
+	CreatedAt time.Time        `json:"created_at"`
+	CanEdit   bool             `json:"can_edit"`
+	CanDelete bool             `json:"can_delete"`
+	Replies   []CommentDetails `json:"replies,omitempty"` // Only on top level comments
+}
+
+type ReactionCount struct {
+	Kind    string `json:"kind"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted"` // The user reacted with this kind
+}
+
+// commentSubject is the workout or personal record comments and reactions are on.
+type commentSubject struct {
+	Kind    comments.Subject
+	ID      int32 // Workout or exercise log ID
+	OwnerID int32
+}
+
+func (s commentSubject) workoutID() pgtype.Int4 {
+	if s.Kind != comments.SubjectWorkout {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: s.ID, Valid: true}
+}
+
+func (s commentSubject) exerciseLogID() pgtype.Int4 {
+	if s.Kind != comments.SubjectPersonalRecord {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: s.ID, Valid: true}
+}
+
+// ListWorkoutCommentsHandler returns the comments and reactions on a workout.
+func ListWorkoutCommentsHandler(c *gin.Context) {
+	subject, ok := fetchWorkoutSubject(c)
+	if !ok {
+		return
+	}
+	respondWithDiscussion(c, http.StatusOK, "", subject, nil)
+}
+
+// ListPersonalRecordCommentsHandler returns the comments and reactions on a personal record.
+func ListPersonalRecordCommentsHandler(c *gin.Context) {
+	subject, ok := fetchPersonalRecordSubject(c)
+	if !ok {
+		return
+	}
+	respondWithDiscussion(c, http.StatusOK, "", subject, nil)
+}
+
+func CreateWorkoutCommentHandler(c *gin.Context) {
+	subject, ok := fetchWorkoutSubject(c)
+	if !ok {
+		return
+	}
+	createComment(c, subject)
+}
+
+func CreatePersonalRecordCommentHandler(c *gin.Context) {
+	subject, ok := fetchPersonalRecordSubject(c)
+	if !ok {
+		return
+	}
+	createComment(c, subject)
+}
+
+// UpdateCommentHandler changes the body of the user's comment. Users mentioned for the first
+// time are notified.
+func UpdateCommentHandler(c *gin.Context) {
+	comment, subject, ok := fetchComment(c)
+	if !ok {
+		return
+	}
+	var req CommentRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	if comment.UserID != int32(userID) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the author can edit the comment", nil, nil)
+		return
+	}
+	if err := validation.ValidateComment(req.Body); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update comment", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	comment, err = qtx.UpdateComment(ctx, db.UpdateCommentParams{ID: comment.ID, Body: strings.TrimSpace(req.Body)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update comment", nil, err)
+		return
+	}
+	mentioned, err := notifyMentions(ctx, qtx, comment, subject)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update comment", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update comment", nil, err)
+		return
+	}
+
+	respondWithDiscussion(c, http.StatusOK, "Comment updated", subject, gin.H{"comment_id": comment.ID, "mentioned": mentioned})
+}
+
+// DeleteCommentHandler deletes a comment with its replies. Authors delete their comments and
+// owners moderate the comments on their workouts and records.
+func DeleteCommentHandler(c *gin.Context) {
+	comment, subject, ok := fetchComment(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	if !comments.CanDelete(int32(userID), comment.UserID, subject.OwnerID) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the author or the owner can delete the comment", nil, nil)
+		return
+	}
+
+	if err := queries.DeleteComment(context.Background(), comment.ID); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete comment", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Comment deleted", nil, nil)
+}
+
+func AddWorkoutReactionHandler(c *gin.Context) {
+	subject, ok := fetchWorkoutSubject(c)
+	if !ok {
+		return
+	}
+	addReaction(c, subject)
+}
+
+func AddPersonalRecordReactionHandler(c *gin.Context) {
+	subject, ok := fetchPersonalRecordSubject(c)
+	if !ok {
+		return
+	}
+	addReaction(c, subject)
+}
+
+func RemoveWorkoutReactionHandler(c *gin.Context) {
+	subject, ok := fetchWorkoutSubject(c)
+	if !ok {
+		return
+	}
+	removeReaction(c, subject)
+}
+
+func RemovePersonalRecordReactionHandler(c *gin.Context) {
+	subject, ok := fetchPersonalRecordSubject(c)
+	if !ok {
+		return
+	}
+	removeReaction(c, subject)
+}
+
+// createComment comments on a subject or replies to one of its top level comments, notifying
+// the users mentioned who can see the subject.
+func createComment(c *gin.Context, subject commentSubject) {
+	var req CommentRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	if err := validation.ValidateComment(req.Body); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	var parentAuthorID int32
+	if req.ParentID != nil {
+		parent, err := queries.GetComment(ctx, *req.ParentID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch comment", nil, err)
+			return
+		}
+		if err != nil || parent.WorkoutID != subject.workoutID() || parent.ExerciseLogID != subject.exerciseLogID() {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown parent comment", nil, err)
+			return
+		}
+		if parent.ParentID.Valid {
+			response.JSONResponse(c, http.StatusBadRequest, "Replies cannot be replied to, reply to the comment instead", nil, nil)
+			return
+		}
+		parentAuthorID = parent.UserID
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to comment", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	comment, err := qtx.CreateComment(ctx, db.CreateCommentParams{
+		UserID:        int32(userID),
+		WorkoutID:     subject.workoutID(),
+		ExerciseLogID: subject.exerciseLogID(),
+		ParentID:      optionalInt4(req.ParentID),
+		Body:          strings.TrimSpace(req.Body),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to comment", nil, err)
+		return
+	}
+	mentioned, err := notifyMentions(ctx, qtx, comment, subject)
+	if err == nil {
+		err = notifyCommentAdded(ctx, qtx, comment, subject, parentAuthorID, mentioned)
+	}
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to comment", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to comment", nil, err)
+		return
+	}
+
+	respondWithDiscussion(c, http.StatusCreated, "Comment added", subject, gin.H{"comment_id": comment.ID, "mentioned": mentioned})
+}
+
+// notifyMentions records the users mentioned in a comment and notifies those mentioned in it
+// for the first time. Users who cannot see the subject are left out, so are unknown usernames,
+// accounts pending deletion and the author. It returns the usernames of the users mentioned.
+func notifyMentions(ctx context.Context, qtx *db.Queries, comment db.Comment, subject commentSubject) ([]string, error) {
+	mentioned := []string{}
+	usernames := comments.Mentions(comment.Body)
+	if len(usernames) == 0 {
+		return mentioned, nil
+	}
+
+	author, err := qtx.GetUserAccount(ctx, comment.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, username := range usernames {
+		user, err := qtx.GetActiveUserByUsername(ctx, username)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if user.ID == author.ID {
+			continue
+		}
+		visible, err := canViewTraining(ctx, user.ID, subject.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+
+		mentioned = append(mentioned, user.Username)
+		added, err := qtx.AddCommentMention(ctx, db.AddCommentMentionParams{CommentID: comment.ID, UserID: user.ID})
+		if err != nil {
+			return nil, err
+		}
+		if added == 0 {
+			continue
+		}
+		err = events.Emit(ctx, qtx, user.ID, events.CommentMentioned, events.CommentMentionedPayload{
+			CommentID: comment.ID,
+			Subject:   string(subject.Kind),
+			SubjectID: subject.ID,
+			Author:    author.Username,
+			Body:      comment.Body,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mentioned, nil
+}
+
+// notifyCommentAdded lets the owner of the subject and the author of the comment replied to know
+// about a comment, unless they wrote it, were mentioned in it or can no longer see the subject.
+func notifyCommentAdded(ctx context.Context, qtx *db.Queries, comment db.Comment, subject commentSubject, parentAuthorID int32, mentioned []string) error {
+	recipients := []int32{subject.OwnerID}
+	if parentAuthorID != 0 && parentAuthorID != subject.OwnerID {
+		recipients = append(recipients, parentAuthorID)
+	}
+
+	author, err := qtx.GetUserAccount(ctx, comment.UserID)
+	if err != nil {
+		return err
+	}
+	for _, userID := range recipients {
+		if userID == comment.UserID {
+			continue
+		}
+		user, err := qtx.GetUserAccount(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(mentioned, user.Username) {
+			continue
+		}
+		visible, err := canViewTraining(ctx, userID, subject.OwnerID)
+		if err != nil {
+			return err
+		}
+		if !visible {
+			continue
+		}
+
+		err = events.Emit(ctx, qtx, userID, events.CommentAdded, events.CommentAddedPayload{
+			CommentID: comment.ID,
+			ParentID:  optionalInt32(comment.ParentID),
+			Reply:     userID == parentAuthorID,
+			Subject:   string(subject.Kind),
+			SubjectID: subject.ID,
+			Author:    author.Username,
+			Body:      comment.Body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addReaction(c *gin.Context, subject commentSubject) {
+	var req ReactionRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	kind, ok := parseReactionKind(c, req.Kind)
+	if !ok {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	_, err := queries.AddReaction(context.Background(), db.AddReactionParams{
+		UserID:        int32(userID),
+		WorkoutID:     subject.workoutID(),
+		ExerciseLogID: subject.exerciseLogID(),
+		Kind:          kind,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to react", nil, err)
+		return
+	}
+
+	respondWithReactions(c, http.StatusOK, "Reaction added", subject)
+}
+
+func removeReaction(c *gin.Context, subject commentSubject) {
+	kind, ok := parseReactionKind(c, c.Param("kind"))
+	if !ok {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	_, err := queries.RemoveReaction(context.Background(), db.RemoveReactionParams{
+		UserID:        int32(userID),
+		Kind:          kind,
+		WorkoutID:     subject.workoutID(),
+		ExerciseLogID: subject.exerciseLogID(),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to remove reaction", nil, err)
+		return
+	}
+
+	respondWithReactions(c, http.StatusOK, "Reaction removed", subject)
+}
+
+func parseReactionKind(c *gin.Context, value string) (db.ReactionKind, bool) {
+	for _, kind := range reactionKinds {
+		if string(kind) == value {
+			return kind, true
+		}
+	}
+	response.JSONResponse(c, http.StatusBadRequest, "Reaction must be like, fire, strong or clap", nil, nil)
+	return "", false
+}
+
+// canViewTraining reports whether a user can see the workouts and records of another. Users
+// share them with the coaches of their organizations unless they opted out.
+func canViewTraining(ctx context.Context, viewerID int32, ownerID int32) (bool, error) {
+	if viewerID == ownerID {
+		return true, nil
+	}
+	coaches, err := queries.IsCoachOf(ctx, db.IsCoachOfParams{CoachUserID: viewerID, AthleteUserID: ownerID})
+	if err != nil || !coaches {
+		return false, err
+	}
+	privacy, err := userPrivacySettings(ctx, ownerID)
+	if err != nil {
+		return false, err
+	}
+	return privacy.ShareTrainingWithCoaches, nil
+}
+
+// isPersonalRecord reports whether a logged set set a personal record of its exercise when it
+// was logged.
+func isPersonalRecord(ctx context.Context, log db.GetExerciseLogWithExerciseRow) (bool, error) {
+	logs, err := queries.GetExerciseLogsForStats(ctx, db.GetExerciseLogsForStatsParams{
+		UserID:     log.UserID,
+		ExerciseID: log.ExerciseID,
+		ToDate:     pgtype.Timestamptz{Time: log.LogDate.Time.Add(time.Microsecond), Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, record := range strength.PersonalRecords(log.MeasurementKind, toStrengthSets(logs)) {
+		if record.Date.Equal(log.LogDate.Time) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fetchWorkoutSubject returns the workout of the request when the user can see it.
+func fetchWorkoutSubject(c *gin.Context) (commentSubject, bool) {
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid workout ID", nil, err)
+		return commentSubject{}, false
+	}
+	subject, err := loadCommentSubject(context.Background(), comments.SubjectWorkout, int32(workoutID), int32(c.GetInt("userID")))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Workout not found", nil, err)
+			return commentSubject{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch workout", nil, err)
+		return commentSubject{}, false
+	}
+	return subject, true
+}
+
+// fetchPersonalRecordSubject returns the logged set of the request when the user can see it
+// and it was a personal record.
+func fetchPersonalRecordSubject(c *gin.Context) (commentSubject, bool) {
+	ctx := context.Background()
+
+	logID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid exercise log ID", nil, err)
+		return commentSubject{}, false
+	}
+	subject, err := loadCommentSubject(ctx, comments.SubjectPersonalRecord, int32(logID), int32(c.GetInt("userID")))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Personal record not found", nil, err)
+			return commentSubject{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch personal record", nil, err)
+		return commentSubject{}, false
+	}
+
+	log, err := queries.GetExerciseLogWithExercise(ctx, subject.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch personal record", nil, err)
+		return commentSubject{}, false
+	}
+	record, err := isPersonalRecord(ctx, log)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch personal record", nil, err)
+		return commentSubject{}, false
+	}
+	if !record {
+		response.JSONResponse(c, http.StatusNotFound, "Personal record not found", nil, nil)
+		return commentSubject{}, false
+	}
+	return subject, true
+}
+
+// fetchComment returns the comment of the request with its subject when the user can see it.
+func fetchComment(c *gin.Context) (db.Comment, commentSubject, bool) {
+	ctx := context.Background()
+
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid comment ID", nil, err)
+		return db.Comment{}, commentSubject{}, false
+	}
+
+	comment, err := queries.GetComment(ctx, int32(commentID))
+	if err == nil {
+		kind, id := comments.SubjectWorkout, comment.WorkoutID.Int32
+		if comment.ExerciseLogID.Valid {
+			kind, id = comments.SubjectPersonalRecord, comment.ExerciseLogID.Int32
+		}
+		var subject commentSubject
+		if subject, err = loadCommentSubject(ctx, kind, id, int32(c.GetInt("userID"))); err == nil {
+			return comment, subject, true
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.JSONResponse(c, http.StatusNotFound, "Comment not found", nil, err)
+		return db.Comment{}, commentSubject{}, false
+	}
+	response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch comment", nil, err)
+	return db.Comment{}, commentSubject{}, false
+}
+
+// loadCommentSubject returns a workout or logged set with its owner, pgx.ErrNoRows when it does
+// not exist or the viewer cannot see it.
+func loadCommentSubject(ctx context.Context, kind comments.Subject, id int32, viewerID int32) (commentSubject, error) {
+	subject := commentSubject{Kind: kind, ID: id}
+	var err error
+	if kind == comments.SubjectWorkout {
+		subject.OwnerID, err = queries.GetWorkoutSessionOwner(ctx, id)
+	} else {
+		var log db.GetExerciseLogWithExerciseRow
+		log, err = queries.GetExerciseLogWithExercise(ctx, id)
+		subject.OwnerID = log.UserID
+	}
+	if err != nil {
+		return commentSubject{}, err
+	}
+
+	visible, err := canViewTraining(ctx, viewerID, subject.OwnerID)
+	if err != nil {
+		return commentSubject{}, err
+	}
+	if !visible {
+		return commentSubject{}, pgx.ErrNoRows
+	}
+	return subject, nil
+}
+
+// respondWithDiscussion responds with the comments of a subject, replies nested under the
+// comments they reply to, and its reactions. extra is added to the response data.
+func respondWithDiscussion(c *gin.Context, status int, message string, subject commentSubject, extra gin.H) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	rows, err := queries.ListComments(ctx, db.ListCommentsParams{WorkoutID: subject.workoutID(), ExerciseLogID: subject.exerciseLogID()})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch comments", nil, err)
+		return
+	}
+	reactions, err := subjectReactions(ctx, subject, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch reactions", nil, err)
+		return
+	}
+
+	threads := []CommentDetails{}
+	positions := make(map[int32]int)
+	for _, row := range rows {
+		details := CommentDetails{
+			ID:        row.ID,
+			ParentID:  optionalInt32(row.ParentID),
+			UserID:    row.UserID,
+			Username:  row.Username,
+			Name:      optionalText(row.Name),
+			AvatarURL: optionalText(row.AvatarUrl),
+			Body:      row.Body,
+			CreatedAt: row.CreatedAt.Time,
+			CanEdit:   row.UserID == int32(userID),
+			CanDelete: comments.CanDelete(int32(userID), row.UserID, subject.OwnerID),
+		}
+		if row.EditedAt.Valid {
+			details.EditedAt = &row.EditedAt.Time
+		}
+		if i, ok := positions[row.ParentID.Int32]; row.ParentID.Valid && ok {
+			threads[i].Replies = append(threads[i].Replies, details)
+			continue
+		}
+		positions[row.ID] = len(threads)
+		threads = append(threads, details)
+	}
+
+	data := gin.H{"subject": subject.Kind, "subject_id": subject.ID, "comments": threads, "reactions": reactions}
+	for key, value := range extra {
+		data[key] = value
+	}
+	response.JSONResponse(c, status, message, data, nil)
+}
+
+func respondWithReactions(c *gin.Context, status int, message string, subject commentSubject) {
+	reactions, err := subjectReactions(context.Background(), subject, int32(c.GetInt("userID")))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch reactions", nil, err)
+		return
+	}
+	response.JSONResponse(c, status, message, gin.H{"reactions": reactions}, nil)
+}
+
+// subjectReactions counts the reactions on a subject of every kind.
+func subjectReactions(ctx context.Context, subject commentSubject, userID int32) ([]ReactionCount, error) {
+	rows, err := queries.CountReactions(ctx, db.CountReactionsParams{
+		UserID:        userID,
+		WorkoutID:     subject.workoutID(),
+		ExerciseLogID: subject.exerciseLogID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[db.ReactionKind]db.CountReactionsRow, len(rows))
+	for _, row := range rows {
+		counts[row.Kind] = row
+	}
+
+	reactions := make([]ReactionCount, len(reactionKinds))
+	for i, kind := range reactionKinds {
+		reactions[i] = ReactionCount{Kind: string(kind), Count: counts[kind].Count, Reacted: counts[kind].Reacted}
+	}
+	return reactions, nil
+}
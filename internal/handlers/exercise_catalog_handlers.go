@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"strings"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/catalog"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+type ExerciseDetails struct {
+	ID                    int32    `json:"id"`
+	Name                  string   `json:"name"`
+	Custom                bool     `json:"custom"`
+	PrimaryMuscleGroups   []string `json:"primary_muscle_groups"`
+	SecondaryMuscleGroups []string `json:"secondary_muscle_groups"`
+	Equipment             *string  `json:"equipment"`
+	MovementPattern       *string  `json:"movement_pattern"`
+	IsUnilateral          bool     `json:"is_unilateral"`
+	DefaultExerciseType   *string  `json:"default_exercise_type"`
+	MeasurementKind       string   `json:"measurement_kind"`
+}
+
+type ExerciseDefinitionRequest struct {
+	Name                  string   `json:"name"`
+	PrimaryMuscleGroups   []string `json:"primary_muscle_groups"`
+	SecondaryMuscleGroups []string `json:"secondary_muscle_groups"`
+	Equipment             string   `json:"equipment"`
+	MovementPattern       string   `json:"movement_pattern"`
+	IsUnilateral          bool     `json:"is_unilateral"`
+	DefaultExerciseType   string   `json:"default_exercise_type"`
+	MeasurementKind       string   `json:"measurement_kind"` // Defaults to reps_weight
+}
+
+func toExerciseDetails(exercise db.Exercise) ExerciseDetails {
+	details := ExerciseDetails{
+		ID:                    exercise.ID,
+		Name:                  exercise.Name,
+		Custom:                exercise.OwnerUserID.Valid,
+		PrimaryMuscleGroups:   nonNilStrings(exercise.PrimaryMuscleGroups),
+		SecondaryMuscleGroups: nonNilStrings(exercise.SecondaryMuscleGroups),
+		IsUnilateral:          exercise.IsUnilateral,
+		MeasurementKind:       string(exercise.MeasurementKind),
+	}
+	if exercise.Equipment.Valid {
+		equipment := string(exercise.Equipment.EquipmentType)
+		details.Equipment = &equipment
+	}
+	if exercise.MovementPattern.Valid {
+		pattern := string(exercise.MovementPattern.MovementPattern)
+		details.MovementPattern = &pattern
+	}
+	if exercise.DefaultExerciseType.Valid {
+		exerciseType := string(exercise.DefaultExerciseType.ExerciseType)
+		details.DefaultExerciseType = &exerciseType
+	}
+	return details
+}
+
+// ListExercisesHandler returns the global catalog and the user's custom exercises.
+// Supports the filters q, muscle_group, equipment, movement_pattern and custom=true.
+func ListExercisesHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	equipment := c.Query("equipment")
+	if err := validation.ValidateEquipment(equipment); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	movementPattern := c.Query("movement_pattern")
+	if err := validation.ValidateMovementPattern(movementPattern); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	search := strings.TrimSpace(c.Query("q"))
+	muscleGroup := c.Query("muscle_group")
+
+	exercises, err := queries.SearchExercises(context.Background(), db.SearchExercisesParams{
+		UserID:          int32(userID),
+		Search:          pgtype.Text{String: search, Valid: search != ""},
+		MuscleGroup:     pgtype.Text{String: muscleGroup, Valid: muscleGroup != ""},
+		Equipment:       db.NullEquipmentType{EquipmentType: db.EquipmentType(equipment), Valid: equipment != ""},
+		MovementPattern: db.NullMovementPattern{MovementPattern: db.MovementPattern(movementPattern), Valid: movementPattern != ""},
+		CustomOnly:      c.Query("custom") == "true",
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercises", nil, err)
+		return
+	}
+
+	details := make([]ExerciseDetails, len(exercises))
+	for i, exercise := range exercises {
+		details[i] = toExerciseDetails(exercise)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"exercises": details}, nil)
+}
+
+func CreateCustomExerciseHandler(c *gin.Context) {
+	var req ExerciseDefinitionRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	if err := validateExerciseDefinition(&req); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	exercise, err := queries.CreateCustomExercise(context.Background(), db.CreateCustomExerciseParams{
+		Name:                  req.Name,
+		OwnerUserID:           int32(userID),
+		PrimaryMuscleGroups:   nonNilStrings(req.PrimaryMuscleGroups),
+		SecondaryMuscleGroups: nonNilStrings(req.SecondaryMuscleGroups),
+		Equipment:             db.NullEquipmentType{EquipmentType: db.EquipmentType(req.Equipment), Valid: req.Equipment != ""},
+		MovementPattern:       db.NullMovementPattern{MovementPattern: db.MovementPattern(req.MovementPattern), Valid: req.MovementPattern != ""},
+		IsUnilateral:          req.IsUnilateral,
+		DefaultExerciseType:   db.NullExerciseType{ExerciseType: db.ExerciseType(req.DefaultExerciseType), Valid: req.DefaultExerciseType != ""},
+		MeasurementKind:       db.MeasurementKind(req.MeasurementKind),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			response.JSONResponse(c, http.StatusConflict, "You already have an exercise with this name", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create exercise", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Exercise created", gin.H{"exercise": toExerciseDetails(exercise)}, nil)
+}
+
+func UpdateCustomExerciseHandler(c *gin.Context) {
+	exerciseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid exercise ID", nil, err)
+		return
+	}
+
+	var req ExerciseDefinitionRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	if err := validateExerciseDefinition(&req); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update exercise", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	// Logged sets are only valid for the measurement kind they were logged with
+	current, err := qtx.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{
+		ID:     int32(exerciseID),
+		UserID: int32(userID),
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise", nil, err)
+		return
+	}
+	if err == nil && catalog.Editable(int32(userID), current.OwnerUserID) && current.MeasurementKind != db.MeasurementKind(req.MeasurementKind) {
+		logCount, err := qtx.CountExerciseLogsByExercise(ctx, current.ID)
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to check exercise logs", nil, err)
+			return
+		}
+		if logCount > 0 {
+			response.JSONResponse(c, http.StatusConflict, "The measurement kind of an exercise with logged sets cannot be changed", gin.H{"logs": logCount}, nil)
+			return
+		}
+	}
+
+	exercise, err := qtx.UpdateCustomExercise(ctx, db.UpdateCustomExerciseParams{
+		Name:                  req.Name,
+		PrimaryMuscleGroups:   nonNilStrings(req.PrimaryMuscleGroups),
+		SecondaryMuscleGroups: nonNilStrings(req.SecondaryMuscleGroups),
+		Equipment:             db.NullEquipmentType{EquipmentType: db.EquipmentType(req.Equipment), Valid: req.Equipment != ""},
+		MovementPattern:       db.NullMovementPattern{MovementPattern: db.MovementPattern(req.MovementPattern), Valid: req.MovementPattern != ""},
+		IsUnilateral:          req.IsUnilateral,
+		DefaultExerciseType:   db.NullExerciseType{ExerciseType: db.ExerciseType(req.DefaultExerciseType), Valid: req.DefaultExerciseType != ""},
+		MeasurementKind:       db.MeasurementKind(req.MeasurementKind),
+		ID:                    int32(exerciseID),
+		OwnerUserID:           int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Catalog exercises and other users' exercises are not editable
+			response.JSONResponse(c, http.StatusNotFound, "Custom exercise not found", nil, err)
+			return
+		}
+		if isUniqueViolation(err) {
+			response.JSONResponse(c, http.StatusConflict, "You already have an exercise with this name", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update exercise", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update exercise", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Exercise updated", gin.H{"exercise": toExerciseDetails(exercise)}, nil)
+}
+
+// DeleteCustomExerciseHandler deletes a custom exercise. Exercises with logged sets are kept,
+// deleting them would delete the logs as well.
+func DeleteCustomExerciseHandler(c *gin.Context) {
+	exerciseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid exercise ID", nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	exercise, err := queries.GetAccessibleExercise(context.Background(), db.GetAccessibleExerciseParams{
+		ID:     int32(exerciseID),
+		UserID: int32(userID),
+	})
+	if err != nil || !catalog.Editable(int32(userID), exercise.OwnerUserID) {
+		if err == nil || errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Custom exercise not found", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise", nil, err)
+		return
+	}
+
+	logCount, err := queries.CountExerciseLogsByExercise(context.Background(), exercise.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to check exercise logs", nil, err)
+		return
+	}
+	if logCount > 0 {
+		response.JSONResponse(c, http.StatusConflict, "Exercise has logged sets and cannot be deleted", gin.H{"logs": logCount}, nil)
+		return
+	}
+
+	deleted, err := queries.DeleteCustomExercise(context.Background(), db.DeleteCustomExerciseParams{
+		ID:          exercise.ID,
+		OwnerUserID: int32(userID),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete exercise", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Custom exercise not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Exercise deleted", nil, nil)
+}
+
+func validateExerciseDefinition(req *ExerciseDefinitionRequest) error {
+	if req.MeasurementKind == "" {
+		req.MeasurementKind = string(db.MeasurementKindRepsWeight)
+	}
+	if err := validation.ValidateMeasurementKind(req.MeasurementKind); err != nil {
+		return err
+	}
+	if err := validation.ValidateExerciseName(req.Name); err != nil {
+		return err
+	}
+	if err := validation.ValidateMuscleGroups(req.PrimaryMuscleGroups); err != nil {
+		return err
+	}
+	if err := validation.ValidateMuscleGroups(req.SecondaryMuscleGroups); err != nil {
+		return err
+	}
+	if err := validation.ValidateMuscleGroupOverlap(req.PrimaryMuscleGroups, req.SecondaryMuscleGroups); err != nil {
+		return err
+	}
+	if err := validation.ValidateEquipment(req.Equipment); err != nil {
+		return err
+	}
+	if err := validation.ValidateMovementPattern(req.MovementPattern); err != nil {
+		return err
+	}
+	return validation.ValidateExerciseType(req.DefaultExerciseType)
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
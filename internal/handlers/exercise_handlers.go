@@ -6,16 +6,21 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"log"
 	"math/big"
 	"net/http"
+	"strings"
 	"time"
 	"new-chainsaw/db"
 	"new-chainsaw/internal/binding"
 	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/events"
 	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/strength"
+	"new-chainsaw/internal/validation"
 )
 
 // Convert sql.NullString to db.NullExerciseType
@@ -40,6 +45,7 @@ func GetLatestExercises(c *gin.Context) {
 
 type ExerciseRequest struct {
 	ExerciseID       int32    `json:"exercise_id"`
+	Exercise         string   `json:"exercise"` // Name or alias, resolved when exercise_id is not set
 	Reps             int32    `json:"reps"`
 	Weight           float64  `json:"weight"`
 	Unit             string   `json:"unit"`
@@ -47,16 +53,32 @@ type ExerciseRequest struct {
 	LogDate          string   `json:"log_date"`
 	AdditionalWeight *float64 `json:"additional_weight"`
 	ExerciseType     string   `json:"exercise_type"`
+	DurationSeconds  *int32   `json:"duration_seconds"`
+	Distance         *float64 `json:"distance"`
+	DistanceUnit     string   `json:"distance_unit"` // m, km, mi or yd, defaults to m for metric and yd for imperial
+	RPE              *float64 `json:"rpe"`
+	RIR              *int32   `json:"rir"`
+	Tempo            string   `json:"tempo"` // Eccentric-pause-concentric-pause, e.g. 3-1-1-0
+	RestSeconds      *int32   `json:"rest_seconds"`
+	Notes            string   `json:"notes"`
 }
 
 type ExerciseResponse struct {
-	ExerciseID       int32   `json:"exercise_id"`
-	Reps             int32   `json:"reps"`
-	Weight           float64 `json:"weight"`
-	Unit             string  `json:"unit"`
-	LogDate          string  `json:"log_date"`
-	AdditionalWeight float64 `json:"additional_weight"`
-	ExerciseType     string  `json:"exercise_type"`
+	ExerciseID       int32    `json:"exercise_id"`
+	Reps             int32    `json:"reps"`
+	Weight           float64  `json:"weight"`
+	Unit             string   `json:"unit"`
+	LogDate          string   `json:"log_date"`
+	AdditionalWeight float64  `json:"additional_weight"`
+	ExerciseType     string   `json:"exercise_type"`
+	DurationSeconds  *int32   `json:"duration_seconds,omitempty"`
+	Distance         *float64 `json:"distance,omitempty"`
+	DistanceUnit     string   `json:"distance_unit,omitempty"`
+	RPE              *float64 `json:"rpe,omitempty"`
+	RIR              *int32   `json:"rir,omitempty"`
+	Tempo            string   `json:"tempo,omitempty"`
+	RestSeconds      *int32   `json:"rest_seconds,omitempty"`
+	Notes            string   `json:"notes,omitempty"`
 }
 
 func LogExerciseHandler(c *gin.Context) {
@@ -66,46 +88,213 @@ func LogExerciseHandler(c *gin.Context) {
 		return
 	}
 
+	for i := range reqs {
+		if err := validateSetDetails(&reqs[i]); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+	}
+
 	userID := c.GetInt("userID")
 	var duplicates []ExerciseResponse
 	var logged []ExerciseResponse
 
+	if err := resolveExercises(userID, reqs); err != nil {
+		var resolutionErr *ExerciseResolutionError
+		if errors.As(err, &resolutionErr) {
+			response.JSONResponse(c, http.StatusBadRequest, resolutionErr.Error(), gin.H{"exercise": resolutionErr}, err)
+			return
+		}
+		if errors.Is(err, strength.ErrInvalidMeasurement) {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown exercise", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercises", nil, err)
+		return
+	}
+
+	ctx := context.Background()
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to log exercises", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+
 	for _, req := range reqs {
 		log.Printf("Received request payload: %+v\n", req)
-		if err := processExerciseLog(userID, req, &logged, &duplicates); err != nil {
+		if err := processExerciseLog(ctx, tx, userID, req, &logged, &duplicates); err != nil {
 			response.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, err)
 			return
 		}
 	}
 
-	// Trigger trophy validation
-	err := checkAndUpdateUserTrophies(int32(userID))
+	err = events.Emit(ctx, queries.WithTx(tx), int32(userID), events.WorkoutLogged, events.WorkoutLoggedPayload{Sets: toWorkoutLoggedSets(reqs)})
 	if err != nil {
-		fmt.Printf("Failed to update trophies for user %d: %v\n", userID, err)
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to log exercises", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to log exercises", nil, err)
 		return
 	}
 
+	// Trigger trophy validation
+	if err := checkAndUpdateUserTrophies(int32(userID)); err != nil {
+		log.Printf("Failed to update trophies for user %d: %v\n", userID, err)
+	}
+	if err := checkStrengthGoals(int32(userID)); err != nil {
+		log.Printf("Failed to check strength goals for user %d: %v\n", userID, err)
+	}
+	if err := checkPersonalRecords(int32(userID), toLoggedSets(reqs)); err != nil {
+		log.Printf("Failed to check personal records for user %d: %v\n", userID, err)
+	}
+
 	response.JSONResponse(c, http.StatusOK, "Exercises and body weight logged successfully", gin.H{"logged": logged, "duplicates": duplicates}, nil)
 }
 
-func processExerciseLog(userID int, req ExerciseRequest, logged *[]ExerciseResponse, duplicates *[]ExerciseResponse) error {
+// resolveExercises resolves exercise names, checks that every exercise is in the catalog or owned
+// by the user, and fills in the exercise's default exercise type when a request does not specify one.
+// Sets are validated against the exercise's measurement kind.
+func resolveExercises(userID int, reqs []ExerciseRequest) error {
+	exercises := make(map[int32]db.Exercise)
+	for i, req := range reqs {
+		if req.ExerciseID == 0 && req.Exercise != "" {
+			exerciseID, err := resolveExerciseName(int32(userID), req.Exercise)
+			if err != nil {
+				return err
+			}
+			reqs[i].ExerciseID = exerciseID
+			req.ExerciseID = exerciseID
+		}
+
+		exercise, ok := exercises[req.ExerciseID]
+		if !ok {
+			var err error
+			exercise, err = queries.GetAccessibleExercise(context.Background(), db.GetAccessibleExerciseParams{
+				ID:     req.ExerciseID,
+				UserID: int32(userID),
+			})
+			if err != nil {
+				return err
+			}
+			exercises[req.ExerciseID] = exercise
+		}
+		if req.ExerciseType == "" && exercise.DefaultExerciseType.Valid {
+			reqs[i].ExerciseType = string(exercise.DefaultExerciseType.ExerciseType)
+		}
+
+		if err := validateMeasurement(exercise.MeasurementKind, &reqs[i]); err != nil {
+			return fmt.Errorf("%s: %w", exercise.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateSetDetails checks the optional effort, tempo, rest and notes of a set. Tempo
+// notation is normalized to an upper case X for explosive phases.
+func validateSetDetails(req *ExerciseRequest) error {
+	req.Tempo = strings.ToUpper(strings.TrimSpace(req.Tempo))
+	req.Notes = strings.TrimSpace(req.Notes)
+
+	if err := validation.ValidateRPE(req.RPE); err != nil {
+		return err
+	}
+	if err := validation.ValidateRIR(req.RIR); err != nil {
+		return err
+	}
+	if err := validation.ValidateTempo(req.Tempo); err != nil {
+		return err
+	}
+	if err := validation.ValidateRestSeconds(req.RestSeconds); err != nil {
+		return err
+	}
+	return validation.ValidateSetNotes(req.Notes)
+}
+
+func validateMeasurement(kind db.MeasurementKind, req *ExerciseRequest) error {
+	measurement := strength.Measurement{
+		Reps:            req.Reps,
+		WeightKg:        calculateWeight(req.Weight, req.Unit),
+		DurationSeconds: req.DurationSeconds,
+	}
+	if req.Distance != nil {
+		meters, err := calculateDistance(*req.Distance, req.DistanceUnit, req.Unit)
+		if err != nil {
+			return err
+		}
+		measurement.DistanceMeters = &meters
+	}
+	if err := strength.Validate(kind, measurement); err != nil {
+		return err
+	}
+
+	// Time and distance based sets are stored as a single repetition
+	if kind != db.MeasurementKindRepsWeight {
+		req.Reps = 1
+	}
+	return nil
+}
+
+// processExerciseLog logs a set and its body weight in the transaction of the request.
+func processExerciseLog(ctx context.Context, tx pgx.Tx, userID int, req ExerciseRequest, logged *[]ExerciseResponse, duplicates *[]ExerciseResponse) error {
 	logDate, err := parseLogDate(req.LogDate)
 	if err != nil {
 		return err
 	}
 
-	bodyweightID, err := getOrCreateBodyweightID(userID, req.BodyWeight, req.Unit, logDate)
+	bodyweightID, err := getOrCreateBodyweightID(ctx, tx, userID, req.BodyWeight, req.Unit, logDate)
 	if err != nil {
 		return err
 	}
 
-	if err := logOrUpdateExercise(userID, req, logDate, bodyweightID, logged, duplicates); err != nil {
+	if err := logOrUpdateExercise(ctx, tx, userID, req, logDate, bodyweightID, logged, duplicates); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// toLoggedSets identifies the sets of a validated request.
+func toLoggedSets(reqs []ExerciseRequest) []loggedSet {
+	sets := make([]loggedSet, 0, len(reqs))
+	for _, req := range reqs {
+		if logDate, err := parseLogDate(req.LogDate); err == nil {
+			sets = append(sets, loggedSet{ExerciseID: req.ExerciseID, LogDate: logDate})
+		}
+	}
+	return sets
+}
+
+// toWorkoutLoggedSets describes the sets of a validated request for a WorkoutLogged event.
+func toWorkoutLoggedSets(reqs []ExerciseRequest) []events.WorkoutLoggedSet {
+	sets := make([]events.WorkoutLoggedSet, 0, len(reqs))
+	for _, req := range reqs {
+		logDate, err := parseLogDate(req.LogDate)
+		if err != nil {
+			continue
+		}
+		set := events.WorkoutLoggedSet{
+			ExerciseID:       req.ExerciseID,
+			LogDate:          logDate,
+			Reps:             req.Reps,
+			Weight:           calculateWeight(req.Weight, req.Unit),
+			AdditionalWeight: req.AdditionalWeight,
+			DurationSeconds:  req.DurationSeconds,
+		}
+		if req.Distance != nil {
+			meters, _ := calculateDistance(*req.Distance, req.DistanceUnit, req.Unit)
+			set.DistanceMeters = &meters
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
+
 func parseLogDate(dateStr string) (time.Time, error) {
 	logDate, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
@@ -126,9 +315,16 @@ type ExerciseLogData struct {
 	weight           pgtype.Numeric
 	additionalWeight pgtype.Numeric
 	exerciseType     db.NullExerciseType
+	durationSeconds  pgtype.Int4
+	distanceMeters   pgtype.Numeric
+	rpe              pgtype.Numeric
+	rir              pgtype.Int4
+	tempo            pgtype.Text
+	restSeconds      pgtype.Int4
+	notes            pgtype.Text
 }
 
-func logOrUpdateExercise(userID int, req ExerciseRequest, logDate time.Time, bodyweightID int32, logged *[]ExerciseResponse, duplicates *[]ExerciseResponse) error {
+func logOrUpdateExercise(ctx context.Context, tx pgx.Tx, userID int, req ExerciseRequest, logDate time.Time, bodyweightID int32, logged *[]ExerciseResponse, duplicates *[]ExerciseResponse) error {
 	data := ExerciseLogRequest{
 		userID:       userID,
 		req:          req,
@@ -144,32 +340,54 @@ func logOrUpdateExercise(userID int, req ExerciseRequest, logDate time.Time, bod
 		weight:           weight,
 		additionalWeight: additionalWeight,
 		exerciseType:     exerciseType,
+		durationSeconds:  optionalInt4(req.DurationSeconds),
+		rir:              optionalInt4(req.RIR),
+		tempo:            pgtype.Text{String: req.Tempo, Valid: req.Tempo != ""},
+		restSeconds:      optionalInt4(req.RestSeconds),
+		notes:            pgtype.Text{String: req.Notes, Valid: req.Notes != ""},
+	}
+	if req.RPE != nil {
+		logData.rpe = conversion.ToNumeric(*req.RPE)
+	}
+	if req.Distance != nil {
+		// The distance unit was validated when the request was resolved
+		meters, _ := calculateDistance(*req.Distance, req.DistanceUnit, req.Unit)
+		logData.distanceMeters = conversion.ToNumeric(meters)
 	}
 
 	// Try to insert the exercise log
-	err := queries.LogExercise(context.Background(), db.LogExerciseParams{
-		UserID:           int32(data.userID),
-		ExerciseID:       data.req.ExerciseID,
-		Reps:             data.req.Reps,
-		Weight:           logData.weight,
-		AdditionalWeight: logData.additionalWeight,
-		ExerciseType:     logData.exerciseType,
-		BodyweightID:     data.bodyweightID,
-		LogDate:          pgtype.Timestamptz{Time: data.logDate, Valid: true},
+	err := inSavepoint(ctx, tx, func(q *db.Queries) error {
+		return q.LogExercise(ctx, db.LogExerciseParams{
+			UserID:           int32(data.userID),
+			ExerciseID:       data.req.ExerciseID,
+			Reps:             data.req.Reps,
+			Weight:           logData.weight,
+			AdditionalWeight: logData.additionalWeight,
+			ExerciseType:     logData.exerciseType,
+			BodyweightID:     data.bodyweightID,
+			LogDate:          pgtype.Timestamptz{Time: data.logDate, Valid: true},
+			DurationSeconds:  logData.durationSeconds,
+			DistanceMeters:   logData.distanceMeters,
+			Rpe:              logData.rpe,
+			Rir:              logData.rir,
+			Tempo:            logData.tempo,
+			RestSeconds:      logData.restSeconds,
+			Notes:            logData.notes,
+		})
 	})
 
 	if err != nil {
-		return handleExerciseLogError(err, data, logData, logged, duplicates)
+		return handleExerciseLogError(ctx, queries.WithTx(tx), err, data, logData, logged, duplicates)
 	}
 
 	appendToLogged(logged, data.req, logData.additionalWeight)
 	return nil
 }
 
-func handleExerciseLogError(err error, data ExerciseLogRequest, logData ExerciseLogData, logged *[]ExerciseResponse, duplicates *[]ExerciseResponse) error {
+func handleExerciseLogError(ctx context.Context, qtx *db.Queries, err error, data ExerciseLogRequest, logData ExerciseLogData, logged *[]ExerciseResponse, duplicates *[]ExerciseResponse) error {
 	if isUniqueViolation(err) {
 		appendToDuplicates(duplicates, data.req)
-		updateErr := queries.UpdateExerciseLog(context.Background(), db.UpdateExerciseLogParams{
+		updateErr := qtx.UpdateExerciseLog(ctx, db.UpdateExerciseLogParams{
 			UserID:           int32(data.userID),
 			ExerciseID:       data.req.ExerciseID,
 			Reps:             data.req.Reps,
@@ -178,6 +396,13 @@ func handleExerciseLogError(err error, data ExerciseLogRequest, logData Exercise
 			ExerciseType:     logData.exerciseType,
 			BodyweightID:     data.bodyweightID,
 			LogDate:          pgtype.Timestamptz{Time: data.logDate, Valid: true},
+			DurationSeconds:  logData.durationSeconds,
+			DistanceMeters:   logData.distanceMeters,
+			Rpe:              logData.rpe,
+			Rir:              logData.rir,
+			Tempo:            logData.tempo,
+			RestSeconds:      logData.restSeconds,
+			Notes:            logData.notes,
 		})
 		if updateErr != nil {
 			return errors.New("failed to update exercise log")
@@ -197,6 +422,28 @@ func calculateWeight(weight float64, unit string) float64 {
 	return weight
 }
 
+// calculateDistance converts a distance to meters. Without a distance unit, metric distances
+// are in meters and imperial distances in yards.
+func calculateDistance(distance float64, distanceUnit string, unit string) (float64, error) {
+	if distanceUnit == "" {
+		distanceUnit = "m"
+		if unit == "imperial" {
+			distanceUnit = "yd"
+		}
+	}
+	switch distanceUnit {
+	case "m":
+		return distance, nil
+	case "km":
+		return conversion.KmToMeters(distance), nil
+	case "mi":
+		return conversion.MilesToMeters(distance), nil
+	case "yd":
+		return conversion.YardsToMeters(distance), nil
+	}
+	return 0, fmt.Errorf("%w: distance unit must be m, km, mi or yd", strength.ErrInvalidMeasurement)
+}
+
 func convertAdditionalWeight(additionalWeight *float64) pgtype.Numeric {
 	if additionalWeight != nil {
 		return convertToPgNumeric(*additionalWeight)
@@ -204,6 +451,13 @@ func convertAdditionalWeight(additionalWeight *float64) pgtype.Numeric {
 	return pgtype.Numeric{Valid: false}
 }
 
+func optionalInt4(value *int32) pgtype.Int4 {
+	if value != nil {
+		return pgtype.Int4{Int32: *value, Valid: true}
+	}
+	return pgtype.Int4{Valid: false}
+}
+
 func isUniqueViolation(err error) bool {
 	var pgErr *pgconn.PgError
 	return errors.As(err, &pgErr) && pgErr.Code == "23505"
@@ -225,6 +479,14 @@ func appendToDuplicates(duplicates *[]ExerciseResponse, req ExerciseRequest) {
 		LogDate:          req.LogDate,
 		AdditionalWeight: additionalWeightVal,
 		ExerciseType:     req.ExerciseType,
+		DurationSeconds:  req.DurationSeconds,
+		Distance:         req.Distance,
+		DistanceUnit:     req.DistanceUnit,
+		RPE:              req.RPE,
+		RIR:              req.RIR,
+		Tempo:            req.Tempo,
+		RestSeconds:      req.RestSeconds,
+		Notes:            req.Notes,
 	})
 }
 
@@ -245,43 +507,70 @@ func appendToLogged(logged *[]ExerciseResponse, req ExerciseRequest, additionalW
 		LogDate:          req.LogDate,
 		AdditionalWeight: additionalWeightVal,
 		ExerciseType:     req.ExerciseType,
+		DurationSeconds:  req.DurationSeconds,
+		Distance:         req.Distance,
+		DistanceUnit:     req.DistanceUnit,
+		RPE:              req.RPE,
+		RIR:              req.RIR,
+		Tempo:            req.Tempo,
+		RestSeconds:      req.RestSeconds,
+		Notes:            req.Notes,
 	})
 }
 
-func getOrCreateBodyweightID(userID int, bodyWeight float64, unit string, logDate time.Time) (int32, error) {
+func getOrCreateBodyweightID(ctx context.Context, tx pgx.Tx, userID int, bodyWeight float64, unit string, logDate time.Time) (int32, error) {
 	bodyWeightKg := bodyWeight
 	if unit == "imperial" {
 		bodyWeightKg = conversion.LbsToKg(bodyWeight)
 	}
 
+	qtx := queries.WithTx(tx)
 	weight := convertToPgNumeric(bodyWeightKg)
-	bodyweightID, err := queries.LogBodyWeight(context.Background(), db.LogBodyWeightParams{
-		UserID:     int32(userID),
-		Bodyweight: weight,
-		LogDate:    pgtype.Timestamptz{Time: logDate, Valid: true},
+	var bodyweightID int32
+	err := inSavepoint(ctx, tx, func(q *db.Queries) error {
+		var err error
+		bodyweightID, err = logBodyWeight(ctx, q, int32(userID), bodyWeightKg, logDate)
+		return err
 	})
 
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			log.Printf("Conflict logging body weight, attempting to update: %v\n", err)
-			bodyweightID, err = fetchBodyweightID(userID, logDate)
+			bodyweightID, err = fetchBodyweightID(ctx, qtx, userID, logDate)
 			if err != nil {
 				return 0, err
 			}
 			// Update the existing bodyweight record
-			if updateErr := updateBodyWeight(userID, weight, logDate); updateErr != nil {
+			if updateErr := updateBodyWeight(ctx, qtx, userID, weight, logDate); updateErr != nil {
 				return 0, updateErr
 			}
 			return bodyweightID, nil
 		}
-		return 0, handleBodyWeightError(err, userID, weight, logDate)
+		return 0, handleBodyWeightError(ctx, qtx, err, userID, weight, logDate)
 	}
 	return bodyweightID, nil
 }
 
-func fetchBodyweightID(userID int, logDate time.Time) (int32, error) {
-	id, err := queries.GetBodyweightLogByUserIDAndDate(context.Background(), db.GetBodyweightLogByUserIDAndDateParams{
+// logBodyWeight inserts a body weight log in kilograms and emits its BodyweightLogged event.
+func logBodyWeight(ctx context.Context, qtx *db.Queries, userID int32, bodyWeightKg float64, logDate time.Time) (int32, error) {
+	id, err := qtx.LogBodyWeight(ctx, db.LogBodyWeightParams{
+		UserID:     userID,
+		Bodyweight: convertToPgNumeric(bodyWeightKg),
+		LogDate:    pgtype.Timestamptz{Time: logDate, Valid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+	payload := events.BodyweightLoggedPayload{BodyweightLogID: id, Bodyweight: bodyWeightKg, LogDate: logDate}
+	if err := events.Emit(ctx, qtx, userID, events.BodyweightLogged, payload); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func fetchBodyweightID(ctx context.Context, qtx *db.Queries, userID int, logDate time.Time) (int32, error) {
+	id, err := qtx.GetBodyweightLogByUserIDAndDate(ctx, db.GetBodyweightLogByUserIDAndDateParams{
 		UserID:  int32(userID),
 		LogDate: pgtype.Timestamptz{Time: logDate, Valid: true},
 	})
@@ -293,18 +582,18 @@ func fetchBodyweightID(userID int, logDate time.Time) (int32, error) {
 	return id, nil
 }
 
-func handleBodyWeightError(err error, userID int, weight pgtype.Numeric, logDate time.Time) error {
+func handleBodyWeightError(ctx context.Context, qtx *db.Queries, err error, userID int, weight pgtype.Numeric, logDate time.Time) error {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 		log.Printf("Conflict logging body weight, attempting to update: %v\n", err)
-		return updateBodyWeight(userID, weight, logDate)
+		return updateBodyWeight(ctx, qtx, userID, weight, logDate)
 	}
 	log.Printf("Error logging body weight: %v\n", err)
 	return errors.New("failed to log body weight")
 }
 
-func updateBodyWeight(userID int, weight pgtype.Numeric, logDate time.Time) error {
-	err := queries.UpdateBodyWeight(context.Background(), db.UpdateBodyWeightParams{
+func updateBodyWeight(ctx context.Context, qtx *db.Queries, userID int, weight pgtype.Numeric, logDate time.Time) error {
+	err := qtx.UpdateBodyWeight(ctx, db.UpdateBodyWeightParams{
 		UserID:     int32(userID),
 		Bodyweight: weight,
 		LogDate:    pgtype.Timestamptz{Time: logDate, Valid: true},
@@ -317,6 +606,22 @@ func updateBodyWeight(userID int, weight pgtype.Numeric, logDate time.Time) erro
 	return nil
 }
 
+// inSavepoint runs fn in a savepoint of tx, so a failed statement such as a conflicting insert
+// can be handled without aborting the transaction.
+func inSavepoint(ctx context.Context, tx pgx.Tx, fn func(*db.Queries) error) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(queries.WithTx(savepoint)); err != nil {
+		if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return savepoint.Commit(ctx)
+}
+
 func convertToPgNumeric(value float64) pgtype.Numeric {
 	num := pgtype.Numeric{
 		Int:   new(big.Int),
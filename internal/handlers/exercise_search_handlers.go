@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/response"
+)
+
+const (
+	fuzzySearchMinScore   = 0.2
+	fuzzySearchMaxResults = 10
+	maxFuzzySearchResults = 50
+
+	// A fuzzy match is only used to log sets when it is close and clearly better than the runner-up
+	fuzzyResolveMinScore = 0.5
+	fuzzyResolveMinGap   = 0.15
+)
+
+type ExerciseMatch struct {
+	ID          int32   `json:"id"`
+	Name        string  `json:"name"`
+	MatchedName string  `json:"matched_name"`
+	Score       float32 `json:"score"`
+}
+
+// ExerciseResolutionError is returned when an exercise name does not identify exactly one exercise.
+type ExerciseResolutionError struct {
+	Name       string          `json:"name"`
+	Reason     string          `json:"reason"` // unknown or ambiguous
+	Candidates []ExerciseMatch `json:"candidates"`
+}
+
+func (e *ExerciseResolutionError) Error() string {
+	if e.Reason == "ambiguous" {
+		return fmt.Sprintf("exercise %q is ambiguous", e.Name)
+	}
+	return fmt.Sprintf("unknown exercise %q", e.Name)
+}
+
+// SearchExercisesHandler finds exercises whose name or alias is similar to q.
+func SearchExercisesHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		response.JSONResponse(c, http.StatusBadRequest, "Query parameter q is required", nil, nil)
+		return
+	}
+
+	limit := fuzzySearchMaxResults
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 || parsed > maxFuzzySearchResults {
+			response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Limit must be between 1 and %d", maxFuzzySearchResults), nil, err)
+			return
+		}
+		limit = parsed
+	}
+
+	matches, err := fuzzySearchExercises(int32(userID), query, limit)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to search exercises", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"exercises": matches}, nil)
+}
+
+func fuzzySearchExercises(userID int32, query string, limit int) ([]ExerciseMatch, error) {
+	rows, err := queries.FuzzySearchExercises(context.Background(), db.FuzzySearchExercisesParams{
+		Query:      query,
+		UserID:     userID,
+		MinScore:   fuzzySearchMinScore,
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]ExerciseMatch, len(rows))
+	for i, row := range rows {
+		matches[i] = ExerciseMatch{ID: row.ID, Name: row.Name, MatchedName: row.MatchedName, Score: row.Score}
+	}
+	return matches, nil
+}
+
+// resolveExerciseName maps a name or alias onto an exercise ID. Exact matches win, otherwise
+// the best fuzzy match is used if it is unambiguous.
+func resolveExerciseName(userID int32, name string) (int32, error) {
+	name = strings.TrimSpace(name)
+
+	exact, err := queries.ResolveExerciseName(context.Background(), db.ResolveExerciseNameParams{
+		UserID: userID,
+		Name:   name,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(exact) == 1 {
+		return exact[0].ID, nil
+	}
+	if len(exact) > 1 {
+		candidates := make([]ExerciseMatch, len(exact))
+		for i, e := range exact {
+			candidates[i] = ExerciseMatch{ID: e.ID, Name: e.Name, MatchedName: name, Score: 1}
+		}
+		return 0, &ExerciseResolutionError{Name: name, Reason: "ambiguous", Candidates: candidates}
+	}
+
+	matches, err := fuzzySearchExercises(userID, name, 5)
+	if err != nil {
+		return 0, err
+	}
+	if len(matches) == 0 {
+		return 0, &ExerciseResolutionError{Name: name, Reason: "unknown", Candidates: []ExerciseMatch{}}
+	}
+	if matches[0].Score < fuzzyResolveMinScore {
+		return 0, &ExerciseResolutionError{Name: name, Reason: "unknown", Candidates: matches}
+	}
+	if len(matches) > 1 && matches[0].Score-matches[1].Score < fuzzyResolveMinGap {
+		return 0, &ExerciseResolutionError{Name: name, Reason: "ambiguous", Candidates: matches}
+	}
+	return matches[0].ID, nil
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/config"
+	"new-chainsaw/internal/dataexport"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/signedurl"
+)
+
+type DataExportResponse struct {
+	ID          int32      `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+}
+
+// urlSigningSecret keys the download links, it is separate from the JWT secret so rotating
+// one does not affect the other.
+func urlSigningSecret() string {
+	return config.EnvVars["URL_SIGNING_SECRET"]
+}
+
+func dataExportDownloadPath(exportID int32) string {
+	return fmt.Sprintf("/exports/%d/download", exportID)
+}
+
+func toDataExportResponse(export db.DataExport) DataExportResponse {
+	resp := DataExportResponse{
+		ID:        export.ID,
+		Status:    string(export.Status),
+		CreatedAt: export.CreatedAt.Time,
+	}
+	if export.CompletedAt.Valid {
+		resp.CompletedAt = &export.CompletedAt.Time
+	}
+	if export.ExpiresAt.Valid {
+		resp.ExpiresAt = &export.ExpiresAt.Time
+	}
+
+	// Only hand out a download link while the archive is still kept around
+	if export.Status == db.DataExportStatusCompleted && export.ExpiresAt.Valid && time.Now().Before(export.ExpiresAt.Time) {
+		linkExpiresAt := time.Now().Add(config.DataExportLinkLifetime)
+		if linkExpiresAt.After(export.ExpiresAt.Time) {
+			linkExpiresAt = export.ExpiresAt.Time
+		}
+		resp.DownloadURL = config.EnvVars["BACKEND_URL"] + signedurl.Sign(urlSigningSecret(), dataExportDownloadPath(export.ID), linkExpiresAt)
+	}
+	return resp
+}
+
+func RequestDataExportHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	// Reuse an export that is still being built instead of starting another one
+	active, err := queries.GetActiveDataExport(context.Background(), int32(userID))
+	if err == nil {
+		response.JSONResponse(c, http.StatusAccepted, "Data export already in progress", gin.H{"export": toDataExportResponse(active)}, nil)
+		return
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to check existing exports", nil, err)
+		return
+	}
+
+	export, err := queries.CreateDataExport(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to start data export", nil, err)
+		return
+	}
+
+	go runDataExport(export.ID, int32(userID))
+
+	response.JSONResponse(c, http.StatusAccepted, "Data export started", gin.H{"export": toDataExportResponse(export)}, nil)
+}
+
+func GetDataExportHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	exportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid export ID", nil, err)
+		return
+	}
+
+	export, err := queries.GetDataExport(context.Background(), db.GetDataExportParams{
+		ID:     int32(exportID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Export not found", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch export", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"export": toDataExportResponse(export)}, nil)
+}
+
+// DownloadDataExportHandler serves a finished archive. It is not behind the JWT middleware,
+// the signed link is what authorizes the download.
+func DownloadDataExportHandler(c *gin.Context) {
+	exportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid export ID", nil, err)
+		return
+	}
+
+	err = signedurl.Verify(urlSigningSecret(), dataExportDownloadPath(int32(exportID)), c.Query("expires"), c.Query("signature"), time.Now())
+	if err != nil {
+		response.JSONResponse(c, http.StatusForbidden, err.Error(), nil, err)
+		return
+	}
+
+	export, err := queries.GetDataExportByID(context.Background(), int32(exportID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Export not found", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch export", nil, err)
+		return
+	}
+
+	// Archives are deleted from storage once they expire
+	if export.ExpiresAt.Valid && time.Now().After(export.ExpiresAt.Time) {
+		response.JSONResponse(c, http.StatusGone, "Export has expired", nil, nil)
+		return
+	}
+	if export.Status != db.DataExportStatusCompleted || !export.StorageKey.Valid {
+		response.JSONResponse(c, http.StatusConflict, "Export is not ready", nil, nil)
+		return
+	}
+
+	archive, err := fileStorage.Open(c.Request.Context(), export.StorageKey.String)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to open export", nil, err)
+		return
+	}
+	defer archive.Close()
+
+	filename := fmt.Sprintf("new-chainsaw-export-%d.zip", export.ID)
+	c.DataFromReader(http.StatusOK, -1, "application/zip", archive, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, filename),
+	})
+}
+
+// runDataExport builds an archive in the background. Exports interrupted by a restart are
+// failed by the maintain-data-exports job once their lease runs out.
+func runDataExport(exportID int32, userID int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.DataExportTimeout)
+	defer cancel()
+
+	if err := queries.MarkDataExportProcessing(ctx, exportID); err != nil {
+		log.Printf("Failed to mark data export %d as processing: %v\n", exportID, err)
+		return
+	}
+
+	key := fmt.Sprintf("exports/%d/%d.zip", userID, exportID)
+
+	// Stream the archive straight into storage instead of buffering it in memory
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(dataexport.WriteArchive(ctx, queries, userID, pw))
+	}()
+
+	if err := fileStorage.Put(ctx, key, pr); err != nil {
+		pr.CloseWithError(err)
+		log.Printf("Failed to build data export %d: %v\n", exportID, err)
+		if failErr := queries.FailDataExport(ctx, db.FailDataExportParams{
+			ID:    exportID,
+			Error: pgtype.Text{String: err.Error(), Valid: true},
+		}); failErr != nil {
+			log.Printf("Failed to mark data export %d as failed: %v\n", exportID, failErr)
+		}
+		return
+	}
+
+	err := queries.CompleteDataExport(ctx, db.CompleteDataExportParams{
+		ID:         exportID,
+		StorageKey: pgtype.Text{String: key, Valid: true},
+		ExpiresAt:  pgtype.Timestamptz{Time: time.Now().Add(config.DataExportExpiration), Valid: true},
+	})
+	if err != nil {
+		log.Printf("Failed to complete data export %d: %v\n", exportID, err)
+	}
+}
+
+// ExportLogsHandler streams the user's exercise and bodyweight logs in the requested format.
+// Dates are inclusive and formatted as YYYY-MM-DD.
+func ExportLogsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	format := c.DefaultQuery("format", dataexport.FormatCSV)
+	if !dataexport.IsFormat(format) {
+		response.JSONResponse(c, http.StatusBadRequest, "Format must be csv, json, xlsx or openpowerlifting", nil, nil)
+		return
+	}
+
+	from, err := parseExportDate(c.Query("from"), 0)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD", nil, err)
+		return
+	}
+	// The to date is inclusive, so the range ends at the start of the following day
+	to, err := parseExportDate(c.Query("to"), 1)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD", nil, err)
+		return
+	}
+
+	user, err := queries.GetUserAccount(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch user", nil, err)
+		return
+	}
+
+	lifter := dataexport.Lifter{Name: user.Username, Sex: user.Sex.String}
+	if user.Name.Valid && user.Name.String != "" {
+		lifter.Name = user.Name.String
+	}
+
+	units := user.PreferredUnits
+	if format == dataexport.FormatOpenPowerlifting {
+		units = db.UnitSystemMetric
+		lifter.Lifts, err = dataexport.ResolveLifts(context.Background(), queries, int32(userID))
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to resolve lifts", nil, err)
+			return
+		}
+	}
+
+	contentType, extension := dataexport.ContentType(format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="training-log-%s.%s"`, time.Now().Format("20060102"), extension))
+
+	writer, err := dataexport.NewLogWriter(format, c.Writer, lifter)
+	if err != nil {
+		log.Printf("Error starting log export: %v\n", err)
+		return
+	}
+
+	// Large exports take longer than the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(config.LogExportWriteTimeout)); err != nil {
+		log.Printf("Failed to extend write deadline: %v\n", err)
+	}
+
+	// Headers are already sent once rows are written, so errors can only be logged
+	err = dataexport.StreamLogs(c.Request.Context(), dbPool, int32(userID), from, to, units, writer.Write)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Printf("Error streaming log export for user %d: %v\n", userID, err)
+	}
+}
+
+// parseExportDate parses an optional YYYY-MM-DD date, shifted by offsetDays.
+func parseExportDate(value string, offsetDays int) (pgtype.Timestamptz, error) {
+	if value == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return pgtype.Timestamptz{}, err
+	}
+	return pgtype.Timestamptz{Time: date.AddDate(0, 0, offsetDays), Valid: true}, nil
+}
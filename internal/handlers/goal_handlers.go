@@ -0,0 +1,422 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/goals"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/strength"
+	"new-chainsaw/internal/validation"
+)
+
+type StrengthGoalRequest struct {
+	ExerciseID int32   `json:"exercise_id"`
+	Metric     string  `json:"metric"` // estimated_1rm, heaviest_weight or most_reps
+	Target     float64 `json:"target"`
+	Deadline   string  `json:"deadline"` // YYYY-MM-DD
+	Unit       string  `json:"unit"`     // Unit of a target weight, defaults to the preferred units
+}
+
+type UpdateStrengthGoalRequest struct {
+	Target   float64 `json:"target"`
+	Deadline string  `json:"deadline"` // YYYY-MM-DD
+	Unit     string  `json:"unit"`
+}
+
+type StrengthGoalDetails struct {
+	ID            int32      `json:"id"`
+	ExerciseID    int32      `json:"exercise_id"`
+	Exercise      string     `json:"exercise"`
+	Metric        string     `json:"metric"`
+	Unit          string     `json:"unit"` // kg, lb or reps
+	Target        float64    `json:"target"`
+	Deadline      time.Time  `json:"deadline"`
+	StartValue    float64    `json:"start_value"`
+	Current       float64    `json:"current"` // Best since the goal was set
+	Remaining     float64    `json:"remaining"`
+	Progress      float64    `json:"progress"`      // Percent
+	WeeklyChange  *float64   `json:"weekly_change"` // Fitted through the weekly bests of the last 8 weeks
+	ProjectedDate *time.Time `json:"projected_date"`
+	Status        string     `json:"status"` // achieved, on_track, behind, missed or no_data
+	AchievedAt    *time.Time `json:"achieved_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ListStrengthGoalsHandler returns the user's strength goals with their progress, open goals
+// first by deadline.
+func ListStrengthGoalsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	list, err := queries.ListStrengthGoals(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch strength goals", nil, err)
+		return
+	}
+
+	details := make([]StrengthGoalDetails, len(list))
+	for i, goal := range list {
+		if details[i], err = strengthGoalDetails(ctx, goal, units); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to track strength goals", nil, err)
+			return
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"goals": details}, nil)
+}
+
+func GetStrengthGoalHandler(c *gin.Context) {
+	goal, ok := fetchStrengthGoal(c)
+	if !ok {
+		return
+	}
+	respondWithStrengthGoal(c, http.StatusOK, "", goal)
+}
+
+// CreateStrengthGoalHandler sets a target for an exercise. The best value of the last 12 weeks
+// is the starting point, and the target has to be above it.
+func CreateStrengthGoalHandler(c *gin.Context) {
+	var req StrengthGoalRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	exercise, err := queries.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{ID: req.ExerciseID, UserID: int32(userID)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown exercise", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise", nil, err)
+		return
+	}
+	if exercise.MeasurementKind != db.MeasurementKindRepsWeight {
+		response.JSONResponse(c, http.StatusBadRequest, "Goals can only be set for exercises logged with reps and weight", nil, nil)
+		return
+	}
+
+	metric := db.GoalMetric(req.Metric)
+	target, deadline, ok := parseStrengthGoalTarget(c, int32(userID), metric, req.Target, req.Deadline, req.Unit)
+	if !ok {
+		return
+	}
+
+	today := startOfDay(time.Now())
+	sets, err := strengthGoalSets(ctx, int32(userID), exercise.ID, today.AddDate(0, 0, -7*goals.StartWeeks))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise logs", nil, err)
+		return
+	}
+	start := goals.Best(metric, sets)
+	if target <= start {
+		units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Target must be above your recent best of %g %s", goalValue(metric, start, units), goalUnit(metric, units)), nil, nil)
+		return
+	}
+
+	goal, err := queries.CreateStrengthGoal(ctx, db.CreateStrengthGoalParams{
+		UserID:     int32(userID),
+		ExerciseID: exercise.ID,
+		Metric:     metric,
+		Target:     conversion.ToNumeric(target),
+		Deadline:   pgtype.Date{Time: deadline, Valid: true},
+		StartValue: conversion.ToNumeric(start),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save strength goal", nil, err)
+		return
+	}
+
+	respondWithStrengthGoal(c, http.StatusCreated, "Strength goal saved", goal)
+}
+
+// UpdateStrengthGoalHandler changes the target and deadline of a goal. A reached goal is open
+// again when its target changes.
+func UpdateStrengthGoalHandler(c *gin.Context) {
+	goal, ok := fetchStrengthGoal(c)
+	if !ok {
+		return
+	}
+	var req UpdateStrengthGoalRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	target, deadline, ok := parseStrengthGoalTarget(c, goal.UserID, goal.Metric, req.Target, req.Deadline, req.Unit)
+	if !ok {
+		return
+	}
+	if target <= numericToFloat(goal.StartValue) {
+		response.JSONResponse(c, http.StatusBadRequest, "Target must be above the starting value of the goal", nil, nil)
+		return
+	}
+
+	goal, err := queries.UpdateStrengthGoal(context.Background(), db.UpdateStrengthGoalParams{
+		Target:   conversion.ToNumeric(target),
+		Deadline: pgtype.Date{Time: deadline, Valid: true},
+		ID:       goal.ID,
+		UserID:   goal.UserID,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save strength goal", nil, err)
+		return
+	}
+
+	respondWithStrengthGoal(c, http.StatusOK, "Strength goal saved", goal)
+}
+
+func DeleteStrengthGoalHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	goalID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid goal ID", nil, err)
+		return
+	}
+
+	deleted, err := queries.DeleteStrengthGoal(context.Background(), db.DeleteStrengthGoalParams{ID: int32(goalID), UserID: int32(userID)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete strength goal", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Strength goal not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Strength goal deleted", nil, nil)
+}
+
+// checkStrengthGoals marks the open goals of a user reached by their logs, emitting a goal
+// reached event for each.
+func checkStrengthGoals(userID int32) error {
+	ctx := context.Background()
+	open, err := queries.ListOpenStrengthGoals(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch strength goals: %w", err)
+	}
+	for _, goal := range open {
+		if _, _, err := trackStrengthGoal(ctx, goal); err != nil {
+			return fmt.Errorf("failed to track strength goal %d: %w", goal.ID, err)
+		}
+	}
+	return nil
+}
+
+func fetchStrengthGoal(c *gin.Context) (db.StrengthGoal, bool) {
+	userID := c.GetInt("userID")
+
+	goalID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid goal ID", nil, err)
+		return db.StrengthGoal{}, false
+	}
+
+	goal, err := queries.GetStrengthGoal(context.Background(), db.GetStrengthGoalParams{ID: int32(goalID), UserID: int32(userID)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Strength goal not found", nil, err)
+			return db.StrengthGoal{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch strength goal", nil, err)
+		return db.StrengthGoal{}, false
+	}
+	return goal, true
+}
+
+func respondWithStrengthGoal(c *gin.Context, status int, message string, goal db.StrengthGoal) {
+	ctx := context.Background()
+	units, err := queries.GetUserPreferredUnit(ctx, goal.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	details, err := strengthGoalDetails(ctx, goal, units)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to track strength goal", nil, err)
+		return
+	}
+	response.JSONResponse(c, status, message, gin.H{"goal": details}, nil)
+}
+
+// parseStrengthGoalTarget validates a target and deadline, converting a target weight to
+// kilograms. It writes the error response when they are invalid.
+func parseStrengthGoalTarget(c *gin.Context, userID int32, metric db.GoalMetric, target float64, deadlineValue string, unit string) (float64, time.Time, bool) {
+	deadline, err := time.Parse("2006-01-02", deadlineValue)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Deadline must be a date like 2006-01-02", nil, err)
+		return 0, time.Time{}, false
+	}
+
+	if metric != db.GoalMetricMostReps {
+		units, err := queries.GetUserPreferredUnit(context.Background(), userID)
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+			return 0, time.Time{}, false
+		}
+		if unit != "" {
+			if units, err = parseUnitSystem(unit); err != nil {
+				response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+				return 0, time.Time{}, false
+			}
+		}
+		target = convertUnits(target, units, db.UnitSystemMetric)
+	}
+
+	if err := validation.ValidateStrengthGoal(metric, target, deadline, startOfDay(time.Now())); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return 0, time.Time{}, false
+	}
+	return target, deadline, true
+}
+
+// trackStrengthGoal measures the progress on a goal from the logs since it was set and of the
+// recent weeks, and marks it reached the first time it is.
+func trackStrengthGoal(ctx context.Context, goal db.StrengthGoal) (goals.Progress, db.Exercise, error) {
+	exercise, err := queries.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{ID: goal.ExerciseID, UserID: goal.UserID})
+	if err != nil {
+		return goals.Progress{}, db.Exercise{}, err
+	}
+
+	today := startOfDay(time.Now())
+	from := today.AddDate(0, 0, -7*(goals.TrendWeeks+1))
+	if created := startOfDay(goal.CreatedAt.Time); created.Before(from) {
+		from = created
+	}
+	sets, err := strengthGoalSets(ctx, goal.UserID, goal.ExerciseID, from)
+	if err != nil {
+		return goals.Progress{}, db.Exercise{}, err
+	}
+
+	tracked := goals.Goal{
+		Metric:     goal.Metric,
+		Target:     numericToFloat(goal.Target),
+		Deadline:   goal.Deadline.Time,
+		StartValue: numericToFloat(goal.StartValue),
+		CreatedAt:  goal.CreatedAt.Time,
+	}
+	if goal.AchievedAt.Valid {
+		tracked.AchievedAt = &goal.AchievedAt.Time
+	}
+	progress := goals.Track(tracked, sets, today)
+
+	if progress.AchievedAt != nil && !goal.AchievedAt.Valid {
+		if err := reachStrengthGoal(ctx, goal, exercise, progress); err != nil {
+			return goals.Progress{}, db.Exercise{}, err
+		}
+	}
+	return progress, exercise, nil
+}
+
+// reachStrengthGoal marks a goal reached and emits the event in one transaction, so the event
+// is emitted once even when goals are checked concurrently.
+func reachStrengthGoal(ctx context.Context, goal db.StrengthGoal, exercise db.Exercise, progress goals.Progress) error {
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	marked, err := qtx.MarkStrengthGoalAchieved(ctx, db.MarkStrengthGoalAchievedParams{
+		ID:         goal.ID,
+		AchievedAt: pgtype.Timestamptz{Time: *progress.AchievedAt, Valid: true},
+	})
+	if err != nil || marked == 0 {
+		return err
+	}
+
+	err = events.Emit(ctx, qtx, goal.UserID, events.GoalReached, events.GoalReachedPayload{
+		GoalID:     goal.ID,
+		ExerciseID: exercise.ID,
+		Exercise:   exercise.Name,
+		Metric:     string(goal.Metric),
+		Target:     numericToFloat(goal.Target),
+		Value:      progress.Current,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// strengthGoalSets returns the sets of an exercise logged since from, oldest first.
+func strengthGoalSets(ctx context.Context, userID int32, exerciseID int32, from time.Time) ([]strength.Set, error) {
+	logs, err := queries.GetExerciseLogsForStats(ctx, db.GetExerciseLogsForStatsParams{
+		UserID:     userID,
+		ExerciseID: exerciseID,
+		FromDate:   pgtype.Timestamptz{Time: from, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toStrengthSets(logs), nil
+}
+
+func strengthGoalDetails(ctx context.Context, goal db.StrengthGoal, units db.UnitSystem) (StrengthGoalDetails, error) {
+	progress, exercise, err := trackStrengthGoal(ctx, goal)
+	if err != nil {
+		return StrengthGoalDetails{}, err
+	}
+
+	details := StrengthGoalDetails{
+		ID:            goal.ID,
+		ExerciseID:    exercise.ID,
+		Exercise:      exercise.Name,
+		Metric:        string(goal.Metric),
+		Unit:          goalUnit(goal.Metric, units),
+		Target:        goalValue(goal.Metric, numericToFloat(goal.Target), units),
+		Deadline:      goal.Deadline.Time,
+		StartValue:    goalValue(goal.Metric, numericToFloat(goal.StartValue), units),
+		Current:       goalValue(goal.Metric, progress.Current, units),
+		Remaining:     goalValue(goal.Metric, progress.Remaining, units),
+		Progress:      progress.Percent,
+		ProjectedDate: progress.ProjectedDate,
+		Status:        string(progress.Status),
+		AchievedAt:    progress.AchievedAt,
+		CreatedAt:     goal.CreatedAt.Time,
+	}
+	if progress.WeeklyChange != nil {
+		change := goalValue(goal.Metric, *progress.WeeklyChange, units)
+		details.WeeklyChange = &change
+	}
+	return details, nil
+}
+
+// goalValue converts a goal value to the preferred units, repetitions stay as they are.
+func goalValue(metric db.GoalMetric, value float64, units db.UnitSystem) float64 {
+	if metric == db.GoalMetricMostReps {
+		return roundTo(value, 2)
+	}
+	return unitWeight(value, units)
+}
+
+func goalUnit(metric db.GoalMetric, units db.UnitSystem) string {
+	if metric == db.GoalMetricMostReps {
+		return "reps"
+	}
+	return weightUnit(units)
+}
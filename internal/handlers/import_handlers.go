@@ -0,0 +1,557 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/importer"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/strength"
+)
+
+const (
+	maxImportFileSize = 10 << 20 // 10 MB
+	importSampleSize  = 20
+)
+
+type ImportExercise struct {
+	Name       string `json:"name"`
+	Sets       int    `json:"sets"`
+	ExerciseID *int32 `json:"exercise_id"`
+}
+
+type ImportRowOutcome struct {
+	Line    int    `json:"line"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type ImportReport struct {
+	Imported   int                `json:"imported"`
+	Duplicates int                `json:"duplicates"`
+	Skipped    int                `json:"skipped"`
+	Failed     int                `json:"failed"`
+	Rows       []ImportRowOutcome `json:"rows"`
+}
+
+type ImportPreview struct {
+	ID                int32               `json:"id"`
+	Source            string              `json:"source"`
+	Status            string              `json:"status"`
+	TotalRows         int                 `json:"total_rows"`
+	FirstDate         *time.Time          `json:"first_date"`
+	LastDate          *time.Time          `json:"last_date"`
+	Exercises         []ImportExercise    `json:"exercises"`
+	UnmappedExercises []string            `json:"unmapped_exercises"`
+	ParseErrors       []importer.RowError `json:"parse_errors"`
+	SampleRows        []importer.Row      `json:"sample_rows"`
+	Report            *ImportReport       `json:"report,omitempty"`
+}
+
+type ImportMappingsRequest struct {
+	// Mappings maps source exercise names to exercise IDs, 0 skips the exercise
+	Mappings map[string]*int32 `json:"mappings"`
+}
+
+type CommitImportRequest struct {
+	// BodyWeight is logged for workout days that have no earlier bodyweight log
+	BodyWeight *float64 `json:"body_weight"`
+	Unit       string   `json:"unit"`
+}
+
+func CreateImportHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	parser, source, err := importParser(c, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "A CSV file is required", nil, err)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Failed to read file", nil, err)
+		return
+	}
+	defer file.Close()
+
+	rows, parseErrors, err := parser.Parse(file)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	if len(rows) == 0 {
+		response.JSONResponse(c, http.StatusBadRequest, "No importable rows found", gin.H{"parse_errors": parseErrors}, nil)
+		return
+	}
+	importer.AssignTimestamps(rows)
+
+	catalog, err := importCatalog(int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercises", nil, err)
+		return
+	}
+	mappings := importer.MatchExercises(importer.ExerciseNames(rows), catalog)
+
+	parsedRows, _ := json.Marshal(rows)
+	parseErrorsJSON, _ := json.Marshal(nonNilRowErrors(parseErrors))
+	mappingsJSON, _ := json.Marshal(mappings)
+
+	workoutImport, err := queries.CreateWorkoutImport(context.Background(), db.CreateWorkoutImportParams{
+		UserID:           int32(userID),
+		Source:           source,
+		ParsedRows:       parsedRows,
+		ParseErrors:      parseErrorsJSON,
+		ExerciseMappings: mappingsJSON,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save import", nil, err)
+		return
+	}
+
+	preview, err := buildImportPreview(workoutImport)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to build preview", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Import ready for review", gin.H{"import": preview}, nil)
+}
+
+// importCatalog returns the exercises available to the user together with their aliases.
+func importCatalog(userID int32) ([]importer.CatalogExercise, error) {
+	exercises, err := queries.ListExercises(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := queries.ListExerciseAliases(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasesByExercise := make(map[int32][]string)
+	for _, alias := range aliases {
+		aliasesByExercise[alias.ExerciseID] = append(aliasesByExercise[alias.ExerciseID], alias.Alias)
+	}
+
+	catalog := make([]importer.CatalogExercise, len(exercises))
+	for i, e := range exercises {
+		catalog[i] = importer.CatalogExercise{ID: e.ID, Name: e.Name, Aliases: aliasesByExercise[e.ID]}
+	}
+	return catalog, nil
+}
+
+func importParser(c *gin.Context, userID int32) (importer.Parser, db.ImportSource, error) {
+	unit := c.PostForm("unit")
+	if unit == "" {
+		preferredUnits, err := queries.GetUserPreferredUnit(context.Background(), userID)
+		if err != nil {
+			return nil, "", errors.New("failed to fetch preferred units")
+		}
+		unit = string(preferredUnits)
+	}
+	if unit != string(db.UnitSystemMetric) && unit != string(db.UnitSystemImperial) {
+		return nil, "", errors.New("unit must be metric or imperial")
+	}
+
+	switch db.ImportSource(c.PostForm("source")) {
+	case db.ImportSourceStrong:
+		return importer.StrongParser{Unit: unit}, db.ImportSourceStrong, nil
+	case db.ImportSourceHevy:
+		return importer.HevyParser{}, db.ImportSourceHevy, nil
+	case db.ImportSourceGeneric:
+		var mapping importer.ColumnMapping
+		if err := json.Unmarshal([]byte(c.PostForm("mapping")), &mapping); err != nil {
+			return nil, "", errors.New("generic imports require a valid column mapping")
+		}
+		return importer.GenericParser{Mapping: mapping, Unit: unit}, db.ImportSourceGeneric, nil
+	}
+	return nil, "", errors.New("source must be strong, hevy or generic")
+}
+
+func GetImportHandler(c *gin.Context) {
+	workoutImport, ok := fetchWorkoutImport(c)
+	if !ok {
+		return
+	}
+
+	preview, err := buildImportPreview(workoutImport)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to build preview", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"import": preview}, nil)
+}
+
+func UpdateImportMappingsHandler(c *gin.Context) {
+	var req ImportMappingsRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	workoutImport, ok := fetchWorkoutImport(c)
+	if !ok {
+		return
+	}
+	if workoutImport.Status != db.ImportStatusPreview {
+		response.JSONResponse(c, http.StatusConflict, "Import has already been committed", nil, nil)
+		return
+	}
+
+	var mappings map[string]*int32
+	if err := json.Unmarshal(workoutImport.ExerciseMappings, &mappings); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read import", nil, err)
+		return
+	}
+
+	catalog, err := queries.ListExercises(context.Background(), workoutImport.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercises", nil, err)
+		return
+	}
+	known := make(map[int32]bool, len(catalog))
+	for _, e := range catalog {
+		known[e.ID] = true
+	}
+
+	for name, exerciseID := range req.Mappings {
+		if _, ok := mappings[name]; !ok {
+			response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Exercise %q is not part of this import", name), nil, nil)
+			return
+		}
+		if exerciseID != nil && *exerciseID != 0 && !known[*exerciseID] {
+			response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Unknown exercise ID %d", *exerciseID), nil, nil)
+			return
+		}
+		mappings[name] = exerciseID
+	}
+
+	mappingsJSON, _ := json.Marshal(mappings)
+	err = queries.UpdateWorkoutImportMappings(context.Background(), db.UpdateWorkoutImportMappingsParams{
+		ID:               workoutImport.ID,
+		UserID:           workoutImport.UserID,
+		ExerciseMappings: mappingsJSON,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update mappings", nil, err)
+		return
+	}
+
+	workoutImport.ExerciseMappings = mappingsJSON
+	preview, err := buildImportPreview(workoutImport)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to build preview", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Mappings updated", gin.H{"import": preview}, nil)
+}
+
+func CommitImportHandler(c *gin.Context) {
+	var req CommitImportRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	workoutImport, ok := fetchWorkoutImport(c)
+	if !ok {
+		return
+	}
+	if workoutImport.Status != db.ImportStatusPreview {
+		response.JSONResponse(c, http.StatusConflict, "Import has already been committed", nil, nil)
+		return
+	}
+
+	var rows []importer.Row
+	var mappings map[string]*int32
+	if err := json.Unmarshal(workoutImport.ParsedRows, &rows); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read import", nil, err)
+		return
+	}
+	if err := json.Unmarshal(workoutImport.ExerciseMappings, &mappings); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read import", nil, err)
+		return
+	}
+
+	if unmapped := unmappedExercises(mappings); len(unmapped) > 0 {
+		response.JSONResponse(c, http.StatusBadRequest, "Every exercise must be mapped or skipped before committing", gin.H{"unmapped_exercises": unmapped}, nil)
+		return
+	}
+
+	var fallbackBodyweight *float64
+	if req.BodyWeight != nil {
+		if *req.BodyWeight <= 0 {
+			response.JSONResponse(c, http.StatusBadRequest, "Body weight must be positive", nil, nil)
+			return
+		}
+		bodyWeightKg := calculateWeight(*req.BodyWeight, req.Unit)
+		fallbackBodyweight = &bodyWeightKg
+	}
+
+	report, err := commitWorkoutImport(c.Request.Context(), workoutImport, rows, mappings, fallbackBodyweight)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to import workouts", nil, err)
+		return
+	}
+
+	// Trigger trophy validation and goal checks
+	if report.Imported > 0 {
+		if err := checkAndUpdateUserTrophies(workoutImport.UserID); err != nil {
+			log.Printf("Failed to update trophies for user %d: %v\n", workoutImport.UserID, err)
+		}
+		if err := checkStrengthGoals(workoutImport.UserID); err != nil {
+			log.Printf("Failed to check strength goals for user %d: %v\n", workoutImport.UserID, err)
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Import committed", gin.H{"report": report}, nil)
+}
+
+// commitWorkoutImport writes all rows in a single transaction. Each row runs in its own
+// savepoint so a duplicate or invalid row is reported instead of aborting the import.
+func commitWorkoutImport(ctx context.Context, workoutImport db.WorkoutImport, rows []importer.Row, mappings map[string]*int32, fallbackBodyweight *float64) (ImportReport, error) {
+	report := ImportReport{Rows: []ImportRowOutcome{}}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+	userID := workoutImport.UserID
+	bodyweightIDs := make(map[string]int32)
+	exercises := make(map[int32]db.Exercise)
+
+	for _, row := range rows {
+		exerciseID := mappings[row.Exercise]
+		if exerciseID == nil || *exerciseID == 0 {
+			report.Skipped++
+			report.Rows = append(report.Rows, ImportRowOutcome{Line: row.Line, Status: "skipped", Message: fmt.Sprintf("exercise %q was skipped", row.Exercise)})
+			continue
+		}
+
+		// Imported sets only record reps and weight, so rows mapped to a time or distance
+		// based exercise are reported instead of logged
+		exercise, ok := exercises[*exerciseID]
+		if !ok {
+			exercise, err = qtx.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{
+				ID:     *exerciseID,
+				UserID: userID,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				report.Failed++
+				report.Rows = append(report.Rows, ImportRowOutcome{Line: row.Line, Status: "failed", Message: fmt.Sprintf("exercise %d no longer exists", *exerciseID)})
+				continue
+			}
+			if err != nil {
+				return report, err
+			}
+			exercises[*exerciseID] = exercise
+		}
+		measurement := strength.Measurement{Reps: row.Reps, WeightKg: calculateWeight(row.Weight, row.Unit)}
+		if err := strength.Validate(exercise.MeasurementKind, measurement); err != nil {
+			report.Failed++
+			report.Rows = append(report.Rows, ImportRowOutcome{Line: row.Line, Status: "failed", Message: fmt.Sprintf("%s: %v", exercise.Name, err)})
+			continue
+		}
+
+		// The bodyweight is logged in the row's savepoint, so a row that fails takes it along
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return report, err
+		}
+		sqtx := queries.WithTx(savepoint)
+
+		day := row.Date.Format("2006-01-02")
+		bodyweightID, ok := bodyweightIDs[day]
+		if !ok {
+			bodyweightID, err = importBodyweightID(ctx, sqtx, userID, row.Date, fallbackBodyweight)
+			if err != nil {
+				if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+					return report, rollbackErr
+				}
+				report.Failed++
+				report.Rows = append(report.Rows, ImportRowOutcome{Line: row.Line, Status: "failed", Message: err.Error()})
+				continue
+			}
+		}
+
+		err = sqtx.LogExercise(ctx, db.LogExerciseParams{
+			UserID:       userID,
+			ExerciseID:   *exerciseID,
+			Reps:         row.Reps,
+			Weight:       conversion.ToNumeric(calculateWeight(row.Weight, row.Unit)),
+			BodyweightID: bodyweightID,
+			LogDate:      pgtype.Timestamptz{Time: row.Date, Valid: true},
+		})
+		if err != nil {
+			if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+				return report, rollbackErr
+			}
+			if isUniqueViolation(err) {
+				report.Duplicates++
+				report.Rows = append(report.Rows, ImportRowOutcome{Line: row.Line, Status: "duplicate", Message: "a set is already logged for this exercise at this time"})
+				continue
+			}
+			report.Failed++
+			report.Rows = append(report.Rows, ImportRowOutcome{Line: row.Line, Status: "failed", Message: "failed to log exercise"})
+			log.Printf("Error importing line %d of import %d: %v\n", row.Line, workoutImport.ID, err)
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return report, err
+		}
+		bodyweightIDs[day] = bodyweightID
+		report.Imported++
+	}
+
+	reportJSON, _ := json.Marshal(report)
+	committed, err := qtx.CommitWorkoutImport(ctx, db.CommitWorkoutImportParams{
+		ID:     workoutImport.ID,
+		UserID: userID,
+		Report: reportJSON,
+	})
+	if err != nil {
+		return report, err
+	}
+	if committed == 0 {
+		return report, errors.New("import was committed concurrently")
+	}
+
+	return report, tx.Commit(ctx)
+}
+
+// importBodyweightID finds the latest bodyweight log on or before the workout, falling back
+// to logging the provided body weight on the day of the workout.
+func importBodyweightID(ctx context.Context, qtx *db.Queries, userID int32, date time.Time, fallbackBodyweight *float64) (int32, error) {
+	id, err := qtx.GetBodyweightLogOnOrBefore(ctx, db.GetBodyweightLogOnOrBeforeParams{
+		UserID:  userID,
+		LogDate: pgtype.Timestamptz{Time: date, Valid: true},
+	})
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	if fallbackBodyweight == nil {
+		return 0, errors.New("no bodyweight logged on or before this date, provide body_weight to import it")
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return logBodyWeight(ctx, qtx, userID, *fallbackBodyweight, day)
+}
+
+func fetchWorkoutImport(c *gin.Context) (db.WorkoutImport, bool) {
+	userID := c.GetInt("userID")
+
+	importID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid import ID", nil, err)
+		return db.WorkoutImport{}, false
+	}
+
+	workoutImport, err := queries.GetWorkoutImport(context.Background(), db.GetWorkoutImportParams{
+		ID:     int32(importID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Import not found", nil, err)
+			return db.WorkoutImport{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch import", nil, err)
+		return db.WorkoutImport{}, false
+	}
+	return workoutImport, true
+}
+
+func buildImportPreview(workoutImport db.WorkoutImport) (ImportPreview, error) {
+	var rows []importer.Row
+	var parseErrors []importer.RowError
+	var mappings map[string]*int32
+	if err := json.Unmarshal(workoutImport.ParsedRows, &rows); err != nil {
+		return ImportPreview{}, err
+	}
+	if err := json.Unmarshal(workoutImport.ParseErrors, &parseErrors); err != nil {
+		return ImportPreview{}, err
+	}
+	if err := json.Unmarshal(workoutImport.ExerciseMappings, &mappings); err != nil {
+		return ImportPreview{}, err
+	}
+
+	preview := ImportPreview{
+		ID:                workoutImport.ID,
+		Source:            string(workoutImport.Source),
+		Status:            string(workoutImport.Status),
+		TotalRows:         len(rows),
+		ParseErrors:       nonNilRowErrors(parseErrors),
+		UnmappedExercises: unmappedExercises(mappings),
+	}
+
+	sets := make(map[string]int)
+	for i, row := range rows {
+		sets[row.Exercise]++
+		if preview.FirstDate == nil || row.Date.Before(*preview.FirstDate) {
+			preview.FirstDate = &rows[i].Date
+		}
+		if preview.LastDate == nil || row.Date.After(*preview.LastDate) {
+			preview.LastDate = &rows[i].Date
+		}
+	}
+	for _, name := range importer.ExerciseNames(rows) {
+		preview.Exercises = append(preview.Exercises, ImportExercise{Name: name, Sets: sets[name], ExerciseID: mappings[name]})
+	}
+
+	preview.SampleRows = rows
+	if len(rows) > importSampleSize {
+		preview.SampleRows = rows[:importSampleSize]
+	}
+
+	if workoutImport.Report != nil {
+		var report ImportReport
+		if err := json.Unmarshal(workoutImport.Report, &report); err != nil {
+			return ImportPreview{}, err
+		}
+		preview.Report = &report
+	}
+
+	return preview, nil
+}
+
+func unmappedExercises(mappings map[string]*int32) []string {
+	unmapped := []string{}
+	for name, exerciseID := range mappings {
+		if exerciseID == nil {
+			unmapped = append(unmapped, name)
+		}
+	}
+	return unmapped
+}
+
+func nonNilRowErrors(rowErrors []importer.RowError) []importer.RowError {
+	if rowErrors == nil {
+		return []importer.RowError{}
+	}
+	return rowErrors
+}
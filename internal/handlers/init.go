@@ -3,10 +3,29 @@ package handlers
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"new-chainsaw/db"
+	"new-chainsaw/internal/realtime"
+	"new-chainsaw/internal/storage"
 )
 
 var queries *db.Queries
 
-func InitializeQueries(dbPool *pgxpool.Pool) {
-	queries = db.New(dbPool)
+// dbPool is used by handlers that need to run several queries in one transaction
+var dbPool *pgxpool.Pool
+
+var fileStorage storage.Storage
+
+// streams fans the realtime updates out to the event streams open on this server
+var streams *realtime.Hub
+
+func InitializeQueries(pool *pgxpool.Pool) {
+	dbPool = pool
+	queries = db.New(pool)
+}
+
+func InitializeStorage(s storage.Storage) {
+	fileStorage = s
+}
+
+func InitializeRealtime(hub *realtime.Hub) {
+	streams = hub
 }
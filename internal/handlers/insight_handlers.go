@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/insights"
+	"new-chainsaw/internal/response"
+)
+
+type InsightDetails struct {
+	ID            int32      `json:"id"`
+	Kind          string     `json:"kind"`
+	Message       string     `json:"message"`
+	ExerciseID    int32      `json:"exercise_id"`
+	Exercise      string     `json:"exercise"`
+	ExerciseLogID *int32     `json:"exercise_log_id"` // The suspicious set
+	Unit          string     `json:"unit"`
+	Best          float64    `json:"best"` // Recent best estimated one rep max, or the load of the suspicious set
+	PreviousBest  *float64   `json:"previous_best"`
+	ChangePercent *float64   `json:"change_percent"`
+	Since         time.Time  `json:"since"`
+	DetectedAt    time.Time  `json:"detected_at"`
+	DismissedAt   *time.Time `json:"dismissed_at"`
+}
+
+// ListInsightsHandler returns the insights found in the user's training, newest first. Dismissed
+// insights are only included with include_dismissed=true.
+func ListInsightsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	includeDismissed, _ := strconv.ParseBool(c.DefaultQuery("include_dismissed", "false"))
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	rows, err := queries.ListInsights(ctx, db.ListInsightsParams{UserID: int32(userID), IncludeDismissed: includeDismissed})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch insights", nil, err)
+		return
+	}
+
+	details := []InsightDetails{}
+	for _, row := range rows {
+		var insight insights.Insight
+		if err := json.Unmarshal(row.Details, &insight); err != nil {
+			log.Printf("Skipping insight %d with invalid details: %v\n", row.ID, err)
+			continue
+		}
+		details = append(details, toInsightDetails(row, insight, units))
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"insights": details}, nil)
+}
+
+// DismissInsightHandler hides an insight. It stays dismissed when later scans find it again.
+func DismissInsightHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	insightID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid insight ID", nil, err)
+		return
+	}
+
+	dismissed, err := queries.DismissInsight(context.Background(), db.DismissInsightParams{ID: int32(insightID), UserID: int32(userID)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to dismiss insight", nil, err)
+		return
+	}
+	if dismissed == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Insight not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Insight dismissed", nil, nil)
+}
+
+func toInsightDetails(row db.ListInsightsRow, insight insights.Insight, units db.UnitSystem) InsightDetails {
+	details := InsightDetails{
+		ID:            row.ID,
+		Kind:          string(row.Kind),
+		Message:       insightMessage(insight, units),
+		ExerciseID:    row.ExerciseID,
+		Exercise:      insight.Exercise,
+		ExerciseLogID: optionalInt32(row.ExerciseLogID),
+		Unit:          string(units),
+		Best:          unitWeight(insight.BestKg, units),
+		Since:         insight.Since,
+		DetectedAt:    row.DetectedAt.Time,
+	}
+	if insight.PreviousBestKg > 0 {
+		previous := unitWeight(insight.PreviousBestKg, units)
+		details.PreviousBest = &previous
+	}
+	if insight.Kind != insights.SuspiciousEntry {
+		change := insight.ChangePercent
+		details.ChangePercent = &change
+	}
+	if row.DismissedAt.Valid {
+		details.DismissedAt = &row.DismissedAt.Time
+	}
+	return details
+}
+
+func insightMessage(insight insights.Insight, units db.UnitSystem) string {
+	unit := weightUnit(units)
+	best := unitWeight(insight.BestKg, units)
+	previous := unitWeight(insight.PreviousBestKg, units)
+
+	switch insight.Kind {
+	case insights.Plateau:
+		return fmt.Sprintf("Your estimated %s max has stayed around %g %s for %d weeks without beating %g %s. Consider changing rep ranges, volume or taking a deload.",
+			insight.Exercise, best, unit, insight.Weeks, previous, unit)
+	case insights.Regression:
+		return fmt.Sprintf("Your estimated %s max dropped %g%% to %g %s from %g %s %d weeks ago. Check your recovery, sleep and nutrition.",
+			insight.Exercise, -insight.ChangePercent, best, unit, previous, unit, insight.Weeks)
+	case insights.FastGain:
+		return fmt.Sprintf("Your estimated %s max went up %g%% to %g %s in the last weeks. Great progress, make sure the logged weights are right.",
+			insight.Exercise, insight.ChangePercent, best, unit)
+	case insights.SuspiciousEntry:
+		return fmt.Sprintf("The %s set of %g %s on %s looks off, it is %s. Edit or delete it if it was logged by mistake.",
+			insight.Exercise, best, unit, insight.Since.Format("2006-01-02"), insight.Reason)
+	}
+	return ""
+}
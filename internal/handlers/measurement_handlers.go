@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/measurements"
+	"new-chainsaw/internal/response"
+)
+
+var measurementSources = []db.MeasurementSource{
+	db.MeasurementSourceTape,
+	db.MeasurementSourceCalipers,
+	db.MeasurementSourceSmartScale,
+	db.MeasurementSourceDexa,
+	db.MeasurementSourceOther,
+}
+
+type BodyMeasurementRequest struct {
+	MeasuredAt   string                 `json:"measured_at"` // YYYY-MM-DD, defaults to today
+	Unit         string                 `json:"unit"`        // Unit of lengths, defaults to the preferred units
+	Source       string                 `json:"source"`      // tape, calipers, smart_scale, dexa or other
+	Measurements []BodyMeasurementValue `json:"measurements"`
+}
+
+type BodyMeasurementValue struct {
+	Kind  string  `json:"kind"`
+	Value float64 `json:"value"` // Centimeters or inches, percent for body_fat
+}
+
+type BodyMeasurementDetails struct {
+	ID          int32                   `json:"id"`
+	Kind        string                  `json:"kind"`
+	Value       float64                 `json:"value"`
+	Unit        string                  `json:"unit"` // cm, in or %
+	Source      *string                 `json:"source"`
+	MeasuredAt  time.Time               `json:"measured_at"`
+	Composition *BodyCompositionDetails `json:"composition"` // Of body fat readings with a weigh-in within a week
+}
+
+type BodyCompositionDetails struct {
+	Unit       string  `json:"unit"` // kg or lb
+	Bodyweight float64 `json:"bodyweight"`
+	LeanMass   float64 `json:"lean_mass"`
+	FatMass    float64 `json:"fat_mass"`
+}
+
+type BodyMeasurementTrendDetails struct {
+	Kind         string                   `json:"kind"`
+	Unit         string                   `json:"unit"`
+	First        *float64                 `json:"first"` // nil without readings
+	Latest       *float64                 `json:"latest"`
+	Change       *float64                 `json:"change"`
+	WeeklyChange *float64                 `json:"weekly_change"` // nil when the readings span less than a week
+	LeanMass     *LeanMassTrendDetails    `json:"lean_mass"`     // Body fat readings with a weigh-in only
+	Readings     []BodyMeasurementDetails `json:"readings"`
+}
+
+type LeanMassTrendDetails struct {
+	Unit         string   `json:"unit"`
+	First        float64  `json:"first"`
+	Latest       float64  `json:"latest"`
+	Change       float64  `json:"change"`
+	WeeklyChange *float64 `json:"weekly_change"`
+}
+
+// LogBodyMeasurementsHandler saves the measurements taken on a day. Measuring a kind again on
+// the same day replaces the earlier value.
+func LogBodyMeasurementsHandler(c *gin.Context) {
+	var req BodyMeasurementRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	if len(req.Measurements) == 0 || len(req.Measurements) > len(measurements.Kinds) {
+		response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Between 1 and %d measurements are required", len(measurements.Kinds)), nil, nil)
+		return
+	}
+	measuredAt := startOfDay(time.Now())
+	if req.MeasuredAt != "" {
+		parsed, err := time.Parse("2006-01-02", req.MeasuredAt)
+		if err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, "Measured at must be a date like 2006-01-02", nil, err)
+			return
+		}
+		measuredAt = parsed
+	}
+	source := db.NullMeasurementSource{MeasurementSource: db.MeasurementSource(req.Source), Valid: req.Source != ""}
+	if source.Valid && !validMeasurementSource(source.MeasurementSource) {
+		response.JSONResponse(c, http.StatusBadRequest, "Source must be one of tape, calipers, smart_scale, dexa or other", nil, nil)
+		return
+	}
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	inputUnits := units
+	if req.Unit != "" {
+		if inputUnits, err = parseUnitSystem(req.Unit); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+	}
+
+	params := make([]db.UpsertBodyMeasurementParams, len(req.Measurements))
+	seen := make(map[measurements.Kind]bool, len(req.Measurements))
+	for i, m := range req.Measurements {
+		kind, err := measurements.ParseKind(m.Kind)
+		if err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+		if seen[kind] {
+			response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Kind %s is listed twice", kind), nil, nil)
+			return
+		}
+		seen[kind] = true
+
+		value := m.Value
+		if kind.Dimension() == measurements.Length && inputUnits == db.UnitSystemImperial {
+			value = conversion.InchesToCm(value)
+		}
+		if err := kind.Validate(value); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+		params[i] = db.UpsertBodyMeasurementParams{
+			UserID:     int32(userID),
+			Kind:       db.BodyMeasurementKind(kind),
+			Value:      conversion.ToNumeric(value),
+			Source:     source,
+			MeasuredAt: pgtype.Timestamptz{Time: measuredAt, Valid: true},
+		}
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save measurements", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	for _, p := range params {
+		if _, err := qtx.UpsertBodyMeasurement(ctx, p); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to save measurements", nil, err)
+			return
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save measurements", nil, err)
+		return
+	}
+
+	// Reread the day to pick up the weigh-ins body fat readings are composed with
+	saved, err := queries.ListBodyMeasurements(ctx, db.ListBodyMeasurementsParams{
+		UserID:   int32(userID),
+		FromDate: pgtype.Timestamptz{Time: measuredAt, Valid: true},
+		ToDate:   pgtype.Timestamptz{Time: measuredAt.AddDate(0, 0, 1), Valid: true},
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch measurements", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Measurements saved", gin.H{"measurements": toBodyMeasurementDetailsList(saved, units)}, nil)
+}
+
+// ListBodyMeasurementsHandler returns the measurement history, optionally of one kind and
+// between from and to.
+func ListBodyMeasurementsHandler(c *gin.Context) {
+	params, ok := bodyMeasurementFilter(c)
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, params.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	rows, err := queries.ListBodyMeasurements(ctx, params)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch measurements", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"measurements": toBodyMeasurementDetailsList(rows, units)}, nil)
+}
+
+// GetLatestBodyMeasurementsHandler returns the latest measurement of every kind measured.
+func GetLatestBodyMeasurementsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	latest, err := queries.GetLatestBodyMeasurements(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch measurements", nil, err)
+		return
+	}
+
+	rows := make([]db.ListBodyMeasurementsRow, len(latest))
+	for i, row := range latest {
+		rows[i] = db.ListBodyMeasurementsRow(row)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"measurements": toBodyMeasurementDetailsList(rows, units)}, nil)
+}
+
+// GetBodyMeasurementTrendHandler summarizes how a kind changed between from and to. Body fat
+// also gets the trend of lean mass where a weigh-in is close to the readings.
+func GetBodyMeasurementTrendHandler(c *gin.Context) {
+	params, ok := bodyMeasurementFilter(c)
+	if !ok {
+		return
+	}
+	if !params.Kind.Valid {
+		response.JSONResponse(c, http.StatusBadRequest, "Query parameter kind is required", nil, nil)
+		return
+	}
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, params.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	rows, err := queries.ListBodyMeasurements(ctx, params)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch measurements", nil, err)
+		return
+	}
+
+	kind := measurements.Kind(params.Kind.BodyMeasurementKind)
+	details := BodyMeasurementTrendDetails{
+		Kind:     string(kind),
+		Unit:     measurementUnit(kind, units),
+		Readings: toBodyMeasurementDetailsList(rows, units),
+	}
+
+	readings := make([]measurements.Reading, len(details.Readings))
+	leanMass := []measurements.Reading{}
+	for i, reading := range details.Readings {
+		readings[i] = measurements.Reading{Date: reading.MeasuredAt, Value: reading.Value}
+		if reading.Composition != nil {
+			leanMass = append(leanMass, measurements.Reading{Date: reading.MeasuredAt, Value: reading.Composition.LeanMass})
+		}
+	}
+	if trend, ok := measurements.Summarize(readings); ok {
+		details.First = &trend.First
+		details.Latest = &trend.Latest
+		details.Change = &trend.Change
+		details.WeeklyChange = trend.WeeklyChange
+	}
+	if trend, ok := measurements.Summarize(leanMass); ok {
+		details.LeanMass = &LeanMassTrendDetails{
+			Unit:         weightUnit(units),
+			First:        trend.First,
+			Latest:       trend.Latest,
+			Change:       trend.Change,
+			WeeklyChange: trend.WeeklyChange,
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"trend": details}, nil)
+}
+
+func DeleteBodyMeasurementHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	measurementID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid measurement ID", nil, err)
+		return
+	}
+
+	deleted, err := queries.DeleteBodyMeasurement(context.Background(), db.DeleteBodyMeasurementParams{ID: int32(measurementID), UserID: int32(userID)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete measurement", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Measurement not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Measurement deleted", nil, nil)
+}
+
+// bodyMeasurementFilter reads the kind, from and to query parameters. To is inclusive.
+func bodyMeasurementFilter(c *gin.Context) (db.ListBodyMeasurementsParams, bool) {
+	params := db.ListBodyMeasurementsParams{UserID: int32(c.GetInt("userID"))}
+
+	if value := c.Query("kind"); value != "" {
+		kind, err := measurements.ParseKind(value)
+		if err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return params, false
+		}
+		params.Kind = db.NullBodyMeasurementKind{BodyMeasurementKind: db.BodyMeasurementKind(kind), Valid: true}
+	}
+
+	var err error
+	if params.FromDate, err = parseExportDate(c.Query("from"), 0); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Query parameter from must be a date like 2006-01-02", nil, err)
+		return params, false
+	}
+	if params.ToDate, err = parseExportDate(c.Query("to"), 1); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Query parameter to must be a date like 2006-01-02", nil, err)
+		return params, false
+	}
+	return params, true
+}
+
+func toBodyMeasurementDetailsList(rows []db.ListBodyMeasurementsRow, units db.UnitSystem) []BodyMeasurementDetails {
+	details := make([]BodyMeasurementDetails, len(rows))
+	for i, row := range rows {
+		details[i] = toBodyMeasurementDetails(row, units)
+	}
+	return details
+}
+
+func toBodyMeasurementDetails(row db.ListBodyMeasurementsRow, units db.UnitSystem) BodyMeasurementDetails {
+	kind := measurements.Kind(row.Kind)
+	value := numericToFloat(row.Value)
+	details := BodyMeasurementDetails{
+		ID:         row.ID,
+		Kind:       string(kind),
+		Value:      roundTo(value, 2),
+		Unit:       measurementUnit(kind, units),
+		MeasuredAt: row.MeasuredAt.Time,
+	}
+	if row.Source.Valid {
+		source := string(row.Source.MeasurementSource)
+		details.Source = &source
+	}
+
+	switch {
+	case kind.Dimension() == measurements.Length && units == db.UnitSystemImperial:
+		details.Value = roundTo(conversion.CmToInches(value), 2)
+	case kind.Dimension() == measurements.Percentage && row.Bodyweight.Valid:
+		bodyweight := numericToFloat(row.Bodyweight)
+		composition := measurements.Compose(bodyweight, value)
+		details.Composition = &BodyCompositionDetails{
+			Unit:       weightUnit(units),
+			Bodyweight: unitWeight(bodyweight, units),
+			LeanMass:   unitWeight(composition.LeanMass, units),
+			FatMass:    unitWeight(composition.FatMass, units),
+		}
+	}
+	return details
+}
+
+func measurementUnit(kind measurements.Kind, units db.UnitSystem) string {
+	switch {
+	case kind.Dimension() == measurements.Percentage:
+		return "%"
+	case units == db.UnitSystemImperial:
+		return "in"
+	}
+	return "cm"
+}
+
+func weightUnit(units db.UnitSystem) string {
+	if units == db.UnitSystemImperial {
+		return "lb"
+	}
+	return "kg"
+}
+
+func validMeasurementSource(source db.MeasurementSource) bool {
+	for _, s := range measurementSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
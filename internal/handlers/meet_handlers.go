@@ -0,0 +1,1064 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/meets"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+type MeetRequest struct {
+	Name    string `json:"name"`
+	Date    string `json:"date"`    // YYYY-MM-DD
+	Scoring string `json:"scoring"` // dots or ipf_gl, defaults to dots
+}
+
+type MeetDivisionRequest struct {
+	Name        string   `json:"name"`
+	Sex         string   `json:"sex"`
+	WeightClass *float64 `json:"weight_class"` // Upper limit, omitted for an open class
+	MinAge      *int     `json:"min_age"`
+	MaxAge      *int     `json:"max_age"`
+	Unit        string   `json:"unit"` // Of the weight class, defaults to the preferred units
+}
+
+type MeetEntryRequest struct {
+	Username   string `json:"username"`
+	DivisionID int32  `json:"division_id"`
+	Age        *int   `json:"age"` // On the meet date, required by divisions with an age limit
+}
+
+type WeighInRequest struct {
+	Bodyweight float64 `json:"bodyweight"`
+	Unit       string  `json:"unit"`
+}
+
+type DeclareAttemptRequest struct {
+	Lift    string  `json:"lift"` // squat, bench or deadlift
+	Attempt int     `json:"attempt"`
+	Weight  float64 `json:"weight"`
+	Unit    string  `json:"unit"`
+}
+
+type JudgeAttemptRequest struct {
+	Result string `json:"result"` // good or no_lift
+}
+
+type MeetDetails struct {
+	ID          int32                 `json:"id"`
+	OwnerUserID int32                 `json:"owner_user_id"`
+	Name        string                `json:"name"`
+	Date        time.Time             `json:"date"`
+	Scoring     string                `json:"scoring"`
+	Status      string                `json:"status"`
+	ClosedAt    *time.Time            `json:"closed_at"`
+	Unit        string                `json:"unit"`
+	Divisions   []MeetDivisionDetails `json:"divisions"`
+	Entries     []MeetEntryDetails    `json:"entries"` // By division, then place
+}
+
+type MeetDivisionDetails struct {
+	ID          int32    `json:"id"`
+	Name        string   `json:"name"`
+	Sex         string   `json:"sex"`
+	WeightClass *float64 `json:"weight_class"`
+	MinAge      *int     `json:"min_age"`
+	MaxAge      *int     `json:"max_age"`
+}
+
+type MeetEntryDetails struct {
+	ID           int32                `json:"id"`
+	UserID       int32                `json:"user_id"`
+	Username     string               `json:"username"`
+	Name         string               `json:"name"`
+	Sex          string               `json:"sex"`
+	Age          *int                 `json:"age"`
+	DivisionID   int32                `json:"division_id"`
+	Bodyweight   *float64             `json:"bodyweight"`
+	Confirmed    bool                 `json:"confirmed"` // The lifter accepted the entry
+	Attempts     []MeetAttemptDetails `json:"attempts"`
+	Best         map[string]float64   `json:"best"`  // Heaviest good attempt of each lift
+	Total        *float64             `json:"total"` // Only with a good attempt on every lift
+	Dots         *float64             `json:"dots"`
+	Goodlift     *float64             `json:"goodlift"`
+	Place        *int                 `json:"place"` // In the division
+	OverallPlace *int                 `json:"overall_place"`
+}
+
+type MeetAttemptDetails struct {
+	ID      int32   `json:"id"`
+	Lift    string  `json:"lift"`
+	Attempt int     `json:"attempt"`
+	Weight  float64 `json:"weight"`
+	Result  string  `json:"result"`
+}
+
+// meetResults is a meet with its divisions and the ranked entries, in the order of the results.
+type meetResults struct {
+	meet      db.Meet
+	divisions []db.MeetDivision
+	entries   map[int32]db.ListMeetEntriesRow
+	placings  []meets.Placing
+}
+
+// ListMeetsHandler returns the meets the user organizes or is entered in, latest first.
+func ListMeetsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	list, err := queries.ListMeets(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch meets", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"meets": list}, nil)
+}
+
+// CreateMeetHandler creates a meet organized by the user.
+func CreateMeetHandler(c *gin.Context) {
+	var req MeetRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	if req.Scoring == "" {
+		req.Scoring = string(db.MeetScoringDots)
+	}
+	if err := validation.ValidateMeet(req.Name, db.MeetScoring(req.Scoring)); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Date must be a date like 2006-01-02", nil, err)
+		return
+	}
+
+	meet, err := queries.CreateMeet(context.Background(), db.CreateMeetParams{
+		OwnerUserID: int32(userID),
+		Name:        strings.TrimSpace(req.Name),
+		MeetDate:    pgtype.Date{Time: date, Valid: true},
+		Scoring:     db.MeetScoring(req.Scoring),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create meet", nil, err)
+		return
+	}
+
+	respondWithMeet(c, http.StatusCreated, "Meet created", meet)
+}
+
+// GetMeetHandler returns a meet with its divisions and the live standings of its lifters.
+func GetMeetHandler(c *gin.Context) {
+	meet, ok := fetchMeet(c)
+	if !ok {
+		return
+	}
+	respondWithMeet(c, http.StatusOK, "", meet)
+}
+
+// DeleteMeetHandler deletes an open meet with its divisions, entries and attempts.
+func DeleteMeetHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := queries.DeleteMeet(context.Background(), db.DeleteMeetParams{ID: meet.ID, OwnerUserID: meet.OwnerUserID})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete meet", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusConflict, "The meet is closed", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Meet deleted", nil, nil)
+}
+
+func CreateMeetDivisionHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+	var req MeetDivisionRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	weightClass := req.WeightClass
+	if weightClass != nil {
+		units, ok := requestUnits(c, meet.OwnerUserID, req.Unit)
+		if !ok {
+			return
+		}
+		kg := convertUnits(*weightClass, units, db.UnitSystemMetric)
+		weightClass = &kg
+	}
+	if err := validation.ValidateMeetDivision(req.Name, req.Sex, weightClass, req.MinAge, req.MaxAge); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	params := db.CreateMeetDivisionParams{
+		MeetID: meet.ID,
+		Name:   strings.TrimSpace(req.Name),
+		Sex:    req.Sex,
+		MinAge: optionalInt2(req.MinAge),
+		MaxAge: optionalInt2(req.MaxAge),
+	}
+	if weightClass != nil {
+		params.WeightClass = conversion.ToNumeric(*weightClass)
+	}
+	if _, err := queries.CreateMeetDivision(ctx, params); err != nil {
+		if isUniqueViolation(err) {
+			response.JSONResponse(c, http.StatusConflict, "The meet already has a division with this name", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create division", nil, err)
+		return
+	}
+
+	respondWithMeet(c, http.StatusCreated, "Division created", meet)
+}
+
+// DeleteMeetDivisionHandler deletes a division nobody is entered in.
+func DeleteMeetDivisionHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+	division, ok := fetchMeetDivision(c, meet.ID, c.Param("division_id"))
+	if !ok {
+		return
+	}
+
+	deleted, err := queries.DeleteMeetDivision(context.Background(), db.DeleteMeetDivisionParams{ID: division.ID, MeetID: meet.ID})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete division", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusConflict, "Lifters are entered in this division", nil, nil)
+		return
+	}
+
+	respondWithMeet(c, http.StatusOK, "Division deleted", meet)
+}
+
+// CreateMeetEntryHandler enters a lifter in a division they are eligible for. The organizer's
+// own entry is confirmed, other lifters are invited and compete once they accept.
+func CreateMeetEntryHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+	var req MeetEntryRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	lifter, err := queries.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown lifter", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch lifter", nil, err)
+		return
+	}
+	division, err := queries.GetMeetDivision(ctx, db.GetMeetDivisionParams{ID: req.DivisionID, MeetID: meet.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown division", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch division", nil, err)
+		return
+	}
+
+	if req.Age != nil && (*req.Age < 1 || *req.Age > 120) {
+		response.JSONResponse(c, http.StatusBadRequest, "Age must be between 1 and 120", nil, nil)
+		return
+	}
+	if err := meetDivision(division).Admits(lifter.Sex.String, req.Age); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	var confirmedAt pgtype.Timestamptz
+	if lifter.ID == meet.OwnerUserID {
+		confirmedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+	_, err = queries.CreateMeetEntry(ctx, db.CreateMeetEntryParams{
+		MeetID:      meet.ID,
+		DivisionID:  division.ID,
+		UserID:      lifter.ID,
+		Age:         optionalInt2(req.Age),
+		ConfirmedAt: confirmedAt,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			response.JSONResponse(c, http.StatusConflict, "The lifter is already entered in this meet", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to enter lifter", nil, err)
+		return
+	}
+
+	if !confirmedAt.Valid {
+		respondWithMeet(c, http.StatusCreated, "Lifter invited, they compete once they accept", meet)
+		return
+	}
+	respondWithMeet(c, http.StatusCreated, "Lifter entered", meet)
+}
+
+// AcceptMeetEntryHandler confirms the user's entry in an open meet they were invited to.
+func AcceptMeetEntryHandler(c *gin.Context) {
+	meet, entry, ok := fetchOwnMeetEntry(c)
+	if !ok {
+		return
+	}
+	if entry.ConfirmedAt.Valid {
+		response.JSONResponse(c, http.StatusConflict, "The entry is already accepted", nil, nil)
+		return
+	}
+
+	confirmed, err := queries.ConfirmMeetEntry(context.Background(), db.ConfirmMeetEntryParams{ID: entry.ID, UserID: entry.UserID})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to accept entry", nil, err)
+		return
+	}
+	if confirmed == 0 {
+		response.JSONResponse(c, http.StatusConflict, "The entry is already accepted", nil, nil)
+		return
+	}
+
+	respondWithMeet(c, http.StatusOK, "Entry accepted", meet)
+}
+
+// DeclineMeetEntryHandler withdraws the user from an open meet, declining an invite or leaving
+// after accepting it.
+func DeclineMeetEntryHandler(c *gin.Context) {
+	meet, entry, ok := fetchOwnMeetEntry(c)
+	if !ok {
+		return
+	}
+
+	if _, err := queries.DeleteMeetEntry(context.Background(), db.DeleteMeetEntryParams{ID: entry.ID, MeetID: meet.ID}); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to withdraw from meet", nil, err)
+		return
+	}
+
+	respondWithMeet(c, http.StatusOK, "Withdrawn from meet", meet)
+}
+
+// DeleteMeetEntryHandler withdraws a lifter with their attempts.
+func DeleteMeetEntryHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+	entry, ok := fetchMeetEntry(c, meet.ID)
+	if !ok {
+		return
+	}
+
+	if _, err := queries.DeleteMeetEntry(context.Background(), db.DeleteMeetEntryParams{ID: entry.ID, MeetID: meet.ID}); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to withdraw lifter", nil, err)
+		return
+	}
+
+	respondWithMeet(c, http.StatusOK, "Lifter withdrawn", meet)
+}
+
+// WeighInHandler records the bodyweight of a lifter, which has to make their weight class.
+func WeighInHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+	entry, ok := fetchMeetEntry(c, meet.ID)
+	if !ok {
+		return
+	}
+	var req WeighInRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	if !entry.ConfirmedAt.Valid {
+		response.JSONResponse(c, http.StatusConflict, "The lifter has to accept the entry before weighing in", nil, nil)
+		return
+	}
+
+	units, ok := requestUnits(c, meet.OwnerUserID, req.Unit)
+	if !ok {
+		return
+	}
+	bodyweight := convertUnits(req.Bodyweight, units, db.UnitSystemMetric)
+	if err := validation.ValidateWeighIn(bodyweight); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	division, err := queries.GetMeetDivision(ctx, db.GetMeetDivisionParams{ID: entry.DivisionID, MeetID: meet.ID})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch division", nil, err)
+		return
+	}
+	if err := meetDivision(division).CheckWeighIn(bodyweight); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	err = queries.UpdateMeetEntryWeighIn(ctx, db.UpdateMeetEntryWeighInParams{ID: entry.ID, Bodyweight: conversion.ToNumeric(bodyweight)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save weigh-in", nil, err)
+		return
+	}
+
+	respondWithMeet(c, http.StatusOK, "Weigh-in saved", meet)
+}
+
+// DeclareAttemptHandler declares the weight of a lifter's attempt or changes it until it is
+// judged. Attempts are declared in order once the lifter weighed in.
+func DeclareAttemptHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+	entry, ok := fetchMeetEntry(c, meet.ID)
+	if !ok {
+		return
+	}
+	var req DeclareAttemptRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	if !entry.Bodyweight.Valid {
+		response.JSONResponse(c, http.StatusConflict, "The lifter has to weigh in before declaring attempts", nil, nil)
+		return
+	}
+	units, ok := requestUnits(c, meet.OwnerUserID, req.Unit)
+	if !ok {
+		return
+	}
+	weight := roundTo(convertUnits(req.Weight, units, db.UnitSystemMetric), 2)
+
+	attempts, err := queries.ListMeetAttempts(ctx, meet.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch attempts", nil, err)
+		return
+	}
+	declared := []meets.Attempt{}
+	for _, attempt := range attempts {
+		if attempt.EntryID == entry.ID {
+			declared = append(declared, meetAttempt(attempt))
+		}
+	}
+	lift := db.MeetLift(req.Lift)
+	if err := meets.CheckDeclaration(declared, lift, req.Attempt, weight); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	_, err = queries.DeclareMeetAttempt(ctx, db.DeclareMeetAttemptParams{
+		EntryID: entry.ID,
+		Lift:    lift,
+		Attempt: int16(req.Attempt),
+		Weight:  conversion.ToNumeric(weight),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusConflict, "The attempt was already judged", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to declare attempt", nil, err)
+		return
+	}
+
+	respondWithMeet(c, http.StatusOK, "Attempt declared", meet)
+}
+
+// JudgeAttemptHandler records the decision on an attempt, which is final.
+func JudgeAttemptHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+	var req JudgeAttemptRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	result := db.AttemptResult(req.Result)
+	if result != db.AttemptResultGood && result != db.AttemptResultNoLift {
+		response.JSONResponse(c, http.StatusBadRequest, "Result must be good or no_lift", nil, nil)
+		return
+	}
+
+	attemptID, err := strconv.Atoi(c.Param("attempt_id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid attempt ID", nil, err)
+		return
+	}
+	attempt, err := queries.GetMeetAttempt(ctx, db.GetMeetAttemptParams{ID: int32(attemptID), MeetID: meet.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Attempt not found", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch attempt", nil, err)
+		return
+	}
+	if attempt.Result != db.AttemptResultPending {
+		response.JSONResponse(c, http.StatusConflict, "The attempt was already judged", nil, nil)
+		return
+	}
+
+	if _, err := queries.JudgeMeetAttempt(ctx, db.JudgeMeetAttemptParams{ID: attempt.ID, Result: result}); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to judge attempt", nil, err)
+		return
+	}
+
+	respondWithMeet(c, http.StatusOK, "Attempt judged", meet)
+}
+
+// CloseMeetHandler closes a meet once every attempt is judged. The good lifts are logged to
+// the lifters' training logs on the meet date with their weigh-in as bodyweight, which counts
+// them towards trophies and goals.
+func CloseMeetHandler(c *gin.Context) {
+	meet, ok := fetchOpenMeet(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+
+	attempts, err := queries.ListMeetAttempts(ctx, meet.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch attempts", nil, err)
+		return
+	}
+	for _, attempt := range attempts {
+		if attempt.Result == db.AttemptResultPending {
+			response.JSONResponse(c, http.StatusConflict, "Every attempt has to be judged before the meet closes", nil, nil)
+			return
+		}
+	}
+
+	lifters, logged, err := closeMeet(ctx, meet, attempts)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to close meet", nil, err)
+		return
+	}
+
+	for _, userID := range lifters {
+		if err := checkAndUpdateUserTrophies(userID); err != nil {
+			log.Printf("Failed to update trophies for user %d: %v\n", userID, err)
+		}
+		if err := checkStrengthGoals(userID); err != nil {
+			log.Printf("Failed to check strength goals for user %d: %v\n", userID, err)
+		}
+		if err := checkPersonalRecords(userID, logged[userID]); err != nil {
+			log.Printf("Failed to check personal records for user %d: %v\n", userID, err)
+		}
+	}
+
+	meet, err = queries.GetMeet(ctx, meet.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch meet", nil, err)
+		return
+	}
+	respondWithMeet(c, http.StatusOK, "Meet closed", meet)
+}
+
+// MeetResultsHandler exports the results of a meet as JSON or in the OpenPowerlifting CSV
+// format, which is always in kilograms.
+func MeetResultsHandler(c *gin.Context) {
+	meet, ok := fetchMeet(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		response.JSONResponse(c, http.StatusBadRequest, "Format must be json or csv", nil, nil)
+		return
+	}
+
+	results, err := loadMeetResults(context.Background(), meet)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch meet results", nil, err)
+		return
+	}
+
+	if format == "json" {
+		userID := c.GetInt("userID")
+		units, err := queries.GetUserPreferredUnit(context.Background(), int32(userID))
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusOK, "", gin.H{"results": results.details(units)}, nil)
+		return
+	}
+
+	divisions := make(map[int32]meets.Division, len(results.divisions))
+	for _, division := range results.divisions {
+		divisions[division.ID] = meetDivision(division)
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="meet-%d-results.csv"`, meet.ID))
+	c.Status(http.StatusOK)
+	if err := meets.WriteCSV(c.Writer, meet.Name, meet.MeetDate.Time.Format("2006-01-02"), results.placings, divisions); err != nil {
+		log.Printf("Error writing results of meet %d: %v\n", meet.ID, err)
+	}
+}
+
+// closeMeet logs the good lifts of a meet and closes it in one transaction, returning the
+// lifters with logged lifts and the sets logged for each. Each lifter's weigh-in is logged on the meet date unless they
+// already logged their bodyweight then. Nothing is logged for lifters who did not accept
+// their entry.
+func closeMeet(ctx context.Context, meet db.Meet, attempts []db.MeetAttempt) ([]int32, map[int32][]loggedSet, error) {
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	names := make([]string, 0, len(meets.ExerciseNames))
+	for _, name := range meets.ExerciseNames {
+		names = append(names, name)
+	}
+	exercises, err := qtx.GetMeetLiftExercises(ctx, names)
+	if err != nil {
+		return nil, nil, err
+	}
+	exerciseIDs := make(map[string]int32, len(exercises))
+	for _, exercise := range exercises {
+		exerciseIDs[exercise.Name] = exercise.ID
+	}
+
+	entries, err := qtx.ListMeetEntries(ctx, meet.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int32]db.ListMeetEntriesRow, len(entries))
+	for _, entry := range entries {
+		byID[entry.ID] = entry
+	}
+
+	day := meet.MeetDate.Time
+	bodyweightIDs := make(map[int32]int32)
+	lifters := []int32{}
+	logged := make(map[int32][]loggedSet)
+	for _, attempt := range attempts {
+		exerciseID, ok := exerciseIDs[meets.ExerciseNames[attempt.Lift]]
+		if attempt.Result != db.AttemptResultGood || !ok {
+			continue
+		}
+		entry := byID[attempt.EntryID]
+		if !entry.ConfirmedAt.Valid {
+			continue
+		}
+
+		bodyweightID, ok := bodyweightIDs[entry.UserID]
+		if !ok {
+			if bodyweightID, err = meetBodyweightID(ctx, qtx, entry, day); err != nil {
+				return nil, nil, err
+			}
+			bodyweightIDs[entry.UserID] = bodyweightID
+			lifters = append(lifters, entry.UserID)
+		}
+
+		// Lifts are logged from 9:00 in the order they are contested, so they never share a time
+		liftIndex := 0
+		for i, lift := range meets.Lifts {
+			if lift == attempt.Lift {
+				liftIndex = i
+			}
+		}
+		logDate := day.Add(9*time.Hour + time.Duration(liftIndex)*time.Hour + time.Duration(attempt.Attempt)*10*time.Minute)
+
+		logID, err := qtx.LogMeetAttempt(ctx, db.LogMeetAttemptParams{
+			UserID:       entry.UserID,
+			ExerciseID:   exerciseID,
+			Weight:       attempt.Weight,
+			BodyweightID: bodyweightID,
+			LogDate:      pgtype.Timestamptz{Time: logDate, Valid: true},
+			Notes:        pgtype.Text{String: fmt.Sprintf("%s, %s attempt %d", meet.Name, attempt.Lift, attempt.Attempt), Valid: true},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := qtx.LinkMeetAttemptLog(ctx, db.LinkMeetAttemptLogParams{ID: attempt.ID, ExerciseLogID: pgtype.Int4{Int32: logID, Valid: true}}); err != nil {
+			return nil, nil, err
+		}
+		logged[entry.UserID] = append(logged[entry.UserID], loggedSet{ExerciseID: exerciseID, LogDate: logDate})
+	}
+
+	closed, err := qtx.CloseMeet(ctx, meet.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if closed == 0 {
+		return nil, nil, errors.New("meet was closed concurrently")
+	}
+	return lifters, logged, tx.Commit(ctx)
+}
+
+// meetBodyweightID returns the bodyweight log of a lifter on the meet date, logging their
+// weigh-in when there is none.
+func meetBodyweightID(ctx context.Context, qtx *db.Queries, entry db.ListMeetEntriesRow, day time.Time) (int32, error) {
+	logDate := pgtype.Timestamptz{Time: day, Valid: true}
+	id, err := qtx.GetBodyweightLogByUserIDAndDate(ctx, db.GetBodyweightLogByUserIDAndDateParams{UserID: entry.UserID, LogDate: logDate})
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	return logBodyWeight(ctx, qtx, entry.UserID, numericToFloat(entry.Bodyweight), day)
+}
+
+// fetchMeet returns the meet of the request, which any user can view.
+func fetchMeet(c *gin.Context) (db.Meet, bool) {
+	meetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid meet ID", nil, err)
+		return db.Meet{}, false
+	}
+
+	meet, err := queries.GetMeet(context.Background(), int32(meetID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Meet not found", nil, err)
+			return db.Meet{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch meet", nil, err)
+		return db.Meet{}, false
+	}
+	return meet, true
+}
+
+// fetchOpenMeet returns the meet of the request when the user organizes it and it is not
+// closed yet, as only then can it change.
+func fetchOpenMeet(c *gin.Context) (db.Meet, bool) {
+	meet, ok := fetchMeet(c)
+	if !ok {
+		return db.Meet{}, false
+	}
+	if meet.OwnerUserID != int32(c.GetInt("userID")) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the organizer can change the meet", nil, nil)
+		return db.Meet{}, false
+	}
+	if meet.Status == db.MeetStatusClosed {
+		response.JSONResponse(c, http.StatusConflict, "The meet is closed", nil, nil)
+		return db.Meet{}, false
+	}
+	return meet, true
+}
+
+func fetchMeetDivision(c *gin.Context, meetID int32, param string) (db.MeetDivision, bool) {
+	divisionID, err := strconv.Atoi(param)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid division ID", nil, err)
+		return db.MeetDivision{}, false
+	}
+
+	division, err := queries.GetMeetDivision(context.Background(), db.GetMeetDivisionParams{ID: int32(divisionID), MeetID: meetID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Division not found", nil, err)
+			return db.MeetDivision{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch division", nil, err)
+		return db.MeetDivision{}, false
+	}
+	return division, true
+}
+
+func fetchMeetEntry(c *gin.Context, meetID int32) (db.MeetEntry, bool) {
+	entryID, err := strconv.Atoi(c.Param("entry_id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid entry ID", nil, err)
+		return db.MeetEntry{}, false
+	}
+
+	entry, err := queries.GetMeetEntry(context.Background(), db.GetMeetEntryParams{ID: int32(entryID), MeetID: meetID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Entry not found", nil, err)
+			return db.MeetEntry{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch entry", nil, err)
+		return db.MeetEntry{}, false
+	}
+	return entry, true
+}
+
+// fetchOwnMeetEntry returns the open meet of the request and the user's entry in it, named by
+// the entry_id parameter.
+func fetchOwnMeetEntry(c *gin.Context) (db.Meet, db.MeetEntry, bool) {
+	meet, ok := fetchMeet(c)
+	if !ok {
+		return db.Meet{}, db.MeetEntry{}, false
+	}
+	entry, ok := fetchMeetEntry(c, meet.ID)
+	if !ok {
+		return db.Meet{}, db.MeetEntry{}, false
+	}
+	if entry.UserID != int32(c.GetInt("userID")) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the lifter can accept or decline their entry", nil, nil)
+		return db.Meet{}, db.MeetEntry{}, false
+	}
+	if meet.Status != db.MeetStatusOpen {
+		response.JSONResponse(c, http.StatusConflict, "The meet is closed", nil, nil)
+		return db.Meet{}, db.MeetEntry{}, false
+	}
+	return meet, entry, true
+}
+
+// requestUnits returns the units of weights in a request, the user's preferred units unless
+// the request names them. It writes the error response when they cannot be determined.
+func requestUnits(c *gin.Context, userID int32, unit string) (db.UnitSystem, bool) {
+	if unit != "" {
+		units, err := parseUnitSystem(unit)
+		if err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return "", false
+		}
+		return units, true
+	}
+	units, err := queries.GetUserPreferredUnit(context.Background(), userID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return "", false
+	}
+	return units, true
+}
+
+func respondWithMeet(c *gin.Context, status int, message string, meet db.Meet) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	results, err := loadMeetResults(ctx, meet)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch meet", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, status, message, gin.H{"meet": results.details(units)}, nil)
+}
+
+// loadMeetResults ranks the entries of a meet, ordered by division, then by place with the
+// lifters without a total last.
+func loadMeetResults(ctx context.Context, meet db.Meet) (meetResults, error) {
+	divisions, err := queries.ListMeetDivisions(ctx, meet.ID)
+	if err != nil {
+		return meetResults{}, err
+	}
+	entries, err := queries.ListMeetEntries(ctx, meet.ID)
+	if err != nil {
+		return meetResults{}, err
+	}
+	attempts, err := queries.ListMeetAttempts(ctx, meet.ID)
+	if err != nil {
+		return meetResults{}, err
+	}
+
+	byEntry := make(map[int32][]meets.Attempt)
+	for _, attempt := range attempts {
+		byEntry[attempt.EntryID] = append(byEntry[attempt.EntryID], meetAttempt(attempt))
+	}
+	results := meetResults{meet: meet, divisions: divisions, entries: make(map[int32]db.ListMeetEntriesRow, len(entries))}
+	lifters := make([]meets.Entry, len(entries))
+	for i, entry := range entries {
+		results.entries[entry.ID] = entry
+		lifters[i] = meets.Entry{
+			ID:         entry.ID,
+			Name:       entry.Username,
+			Sex:        entry.Sex.String,
+			Age:        optionalInt(entry.Age),
+			DivisionID: entry.DivisionID,
+			Attempts:   byEntry[entry.ID],
+		}
+		if entry.Name.Valid && entry.Name.String != "" {
+			lifters[i].Name = entry.Name.String
+		}
+		if entry.Bodyweight.Valid {
+			bodyweight := numericToFloat(entry.Bodyweight)
+			lifters[i].BodyweightKg = &bodyweight
+		}
+	}
+
+	order := make(map[int32]int, len(divisions))
+	for i, division := range divisions {
+		order[division.ID] = i
+	}
+	results.placings = meets.Rank(lifters, meet.Scoring)
+	sort.SliceStable(results.placings, func(i, j int) bool {
+		a, b := results.placings[i], results.placings[j]
+		if order[a.DivisionID] != order[b.DivisionID] {
+			return order[a.DivisionID] < order[b.DivisionID]
+		}
+		if (a.Place == 0) != (b.Place == 0) {
+			return a.Place != 0
+		}
+		return a.Place < b.Place
+	})
+	return results, nil
+}
+
+func (r meetResults) details(units db.UnitSystem) MeetDetails {
+	details := MeetDetails{
+		ID:          r.meet.ID,
+		OwnerUserID: r.meet.OwnerUserID,
+		Name:        r.meet.Name,
+		Date:        r.meet.MeetDate.Time,
+		Scoring:     string(r.meet.Scoring),
+		Status:      string(r.meet.Status),
+		Unit:        weightUnit(units),
+		Divisions:   make([]MeetDivisionDetails, len(r.divisions)),
+		Entries:     make([]MeetEntryDetails, len(r.placings)),
+	}
+	if r.meet.ClosedAt.Valid {
+		details.ClosedAt = &r.meet.ClosedAt.Time
+	}
+
+	for i, division := range r.divisions {
+		details.Divisions[i] = MeetDivisionDetails{
+			ID:     division.ID,
+			Name:   division.Name,
+			Sex:    division.Sex,
+			MinAge: optionalInt(division.MinAge),
+			MaxAge: optionalInt(division.MaxAge),
+		}
+		if division.WeightClass.Valid {
+			weightClass := unitWeight(numericToFloat(division.WeightClass), units)
+			details.Divisions[i].WeightClass = &weightClass
+		}
+	}
+
+	optionalWeight := func(kg *float64) *float64 {
+		if kg == nil {
+			return nil
+		}
+		weight := unitWeight(*kg, units)
+		return &weight
+	}
+	for i, placing := range r.placings {
+		entry := r.entries[placing.ID]
+		detail := MeetEntryDetails{
+			ID:         placing.ID,
+			UserID:     entry.UserID,
+			Username:   entry.Username,
+			Name:       placing.Name,
+			Sex:        placing.Sex,
+			Age:        placing.Age,
+			DivisionID: placing.DivisionID,
+			Bodyweight: optionalWeight(placing.BodyweightKg),
+			Confirmed:  entry.ConfirmedAt.Valid,
+			Attempts:   make([]MeetAttemptDetails, len(placing.Attempts)),
+			Best:       make(map[string]float64, len(placing.BestKg)),
+			Total:      optionalWeight(placing.TotalKg),
+			Dots:       placing.Dots,
+			Goodlift:   placing.Goodlift,
+		}
+		for j, attempt := range placing.Attempts {
+			detail.Attempts[j] = MeetAttemptDetails{
+				ID:      attempt.ID,
+				Lift:    string(attempt.Lift),
+				Attempt: attempt.Number,
+				Weight:  unitWeight(attempt.WeightKg, units),
+				Result:  string(attempt.Result),
+			}
+		}
+		for lift, kg := range placing.BestKg {
+			detail.Best[string(lift)] = unitWeight(kg, units)
+		}
+		if placing.Place > 0 {
+			place, overallPlace := placing.Place, placing.OverallPlace
+			detail.Place = &place
+			detail.OverallPlace = &overallPlace
+		}
+		details.Entries[i] = detail
+	}
+	return details
+}
+
+func meetDivision(division db.MeetDivision) meets.Division {
+	d := meets.Division{
+		ID:     division.ID,
+		Name:   division.Name,
+		Sex:    division.Sex,
+		MinAge: optionalInt(division.MinAge),
+		MaxAge: optionalInt(division.MaxAge),
+	}
+	if division.WeightClass.Valid {
+		weightClass := numericToFloat(division.WeightClass)
+		d.WeightClassKg = &weightClass
+	}
+	return d
+}
+
+func meetAttempt(attempt db.MeetAttempt) meets.Attempt {
+	return meets.Attempt{
+		ID:       attempt.ID,
+		Lift:     attempt.Lift,
+		Number:   int(attempt.Attempt),
+		WeightKg: numericToFloat(attempt.Weight),
+		Result:   attempt.Result,
+	}
+}
+
+func optionalInt(i pgtype.Int2) *int {
+	if !i.Valid {
+		return nil
+	}
+	value := int(i.Int16)
+	return &value
+}
+
+func optionalInt2(value *int) pgtype.Int2 {
+	if value == nil {
+		return pgtype.Int2{}
+	}
+	return pgtype.Int2{Int16: int16(*value), Valid: true}
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/config"
+	"new-chainsaw/internal/notifications"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+type NotificationDetails struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data"` // The event payload, see internal/events
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type NotificationPreferenceDetails struct {
+	Type string `json:"type"`
+	notifications.Preference
+}
+
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceDetails `json:"preferences"`
+}
+
+type PushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"` // As reported by PushSubscription.toJSON() in the browser
+}
+
+// ListNotificationsHandler returns the user's notifications newest first with the number unread.
+// Pages end at the notification before the cursor, next_cursor is null on the last page.
+func ListNotificationsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	limit, before, ok := parseCursorPage(c, defaultNotificationPageSize, maxNotificationPageSize)
+	if !ok {
+		return
+	}
+
+	// One more than the page tells whether there is a next page
+	rows, err := queries.ListNotifications(ctx, db.ListNotificationsParams{UserID: int32(userID), BeforeID: before, MaxResults: int32(limit + 1)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch notifications", nil, err)
+		return
+	}
+	unread, err := queries.CountUnreadNotifications(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to count unread notifications", nil, err)
+		return
+	}
+
+	var nextCursor *string
+	if len(rows) > limit {
+		rows = rows[:limit]
+		cursor := strconv.FormatInt(rows[limit-1].ID, 10)
+		nextCursor = &cursor
+	}
+	details := make([]NotificationDetails, len(rows))
+	for i, row := range rows {
+		details[i] = toNotificationDetails(row)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"notifications": details, "unread_count": unread, "next_cursor": nextCursor}, nil)
+}
+
+func GetUnreadNotificationCountHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	unread, err := queries.CountUnreadNotifications(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to count unread notifications", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"unread_count": unread}, nil)
+}
+
+func MarkNotificationReadHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	notificationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid notification ID", nil, err)
+		return
+	}
+
+	marked, err := queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{ID: notificationID, UserID: int32(userID)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to mark notification read", nil, err)
+		return
+	}
+	if marked == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Notification not found", nil, nil)
+		return
+	}
+	respondWithUnreadCount(c, "Notification marked read")
+}
+
+func MarkAllNotificationsReadHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	if _, err := queries.MarkAllNotificationsRead(context.Background(), int32(userID)); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to mark notifications read", nil, err)
+		return
+	}
+	respondWithUnreadCount(c, "Notifications marked read")
+}
+
+// GetNotificationPreferencesHandler returns the channels the user is notified on for every type,
+// the defaults for types the user did not choose.
+func GetNotificationPreferencesHandler(c *gin.Context) {
+	respondWithNotificationPreferences(c, "")
+}
+
+// UpdateNotificationPreferencesHandler saves the channels of the given types, the other types
+// keep theirs.
+func UpdateNotificationPreferencesHandler(c *gin.Context) {
+	var req NotificationPreferencesRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	for _, pref := range req.Preferences {
+		if err := validation.ValidateNotificationType(pref.Type); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update notification preferences", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	for _, pref := range req.Preferences {
+		err := qtx.UpsertNotificationPreference(ctx, db.UpsertNotificationPreferenceParams{
+			UserID: int32(userID),
+			Type:   pref.Type,
+			InApp:  pref.InApp,
+			Email:  pref.Email,
+			Push:   pref.Push,
+		})
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to update notification preferences", nil, err)
+			return
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update notification preferences", nil, err)
+		return
+	}
+
+	respondWithNotificationPreferences(c, "Notification preferences updated")
+}
+
+// GetPushKeyHandler returns the public key browsers subscribe to push notifications with.
+func GetPushKeyHandler(c *gin.Context) {
+	publicKey := config.EnvVars["VAPID_PUBLIC_KEY"]
+	if publicKey == "" {
+		response.JSONResponse(c, http.StatusNotFound, "Push notifications are not enabled", nil, nil)
+		return
+	}
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"public_key": publicKey}, nil)
+}
+
+// CreatePushSubscriptionHandler subscribes a browser to the user's push notifications.
+func CreatePushSubscriptionHandler(c *gin.Context) {
+	var req PushSubscriptionRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	if err := validation.ValidatePushSubscription(req.Endpoint, req.Keys.P256dh, req.Keys.Auth); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	subscription, err := queries.UpsertPushSubscription(context.Background(), db.UpsertPushSubscriptionParams{
+		UserID:   int32(userID),
+		Endpoint: req.Endpoint,
+		P256dh:   req.Keys.P256dh,
+		Auth:     req.Keys.Auth,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to subscribe to push notifications", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Subscribed to push notifications", gin.H{"id": subscription.ID}, nil)
+}
+
+func DeletePushSubscriptionHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid subscription ID", nil, err)
+		return
+	}
+
+	deleted, err := queries.DeletePushSubscription(context.Background(), db.DeletePushSubscriptionParams{ID: int32(subscriptionID), UserID: int32(userID)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to unsubscribe from push notifications", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Subscription not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Unsubscribed from push notifications", nil, nil)
+}
+
+func respondWithUnreadCount(c *gin.Context, message string) {
+	unread, err := queries.CountUnreadNotifications(context.Background(), int32(c.GetInt("userID")))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to count unread notifications", nil, err)
+		return
+	}
+	response.JSONResponse(c, http.StatusOK, message, gin.H{"unread_count": unread}, nil)
+}
+
+func respondWithNotificationPreferences(c *gin.Context, message string) {
+	saved, err := queries.ListNotificationPreferences(context.Background(), int32(c.GetInt("userID")))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch notification preferences", nil, err)
+		return
+	}
+	chosen := make(map[string]notifications.Preference, len(saved))
+	for _, pref := range saved {
+		chosen[pref.Type] = notifications.Preference{InApp: pref.InApp, Email: pref.Email, Push: pref.Push}
+	}
+
+	prefs := make([]NotificationPreferenceDetails, len(notifications.Types))
+	for i, t := range notifications.Types {
+		pref, ok := chosen[string(t)]
+		if !ok {
+			pref = notifications.DefaultPreference(t)
+		}
+		prefs[i] = NotificationPreferenceDetails{Type: string(t), Preference: pref}
+	}
+	response.JSONResponse(c, http.StatusOK, message, gin.H{"preferences": prefs}, nil)
+}
+
+func toNotificationDetails(n db.Notification) NotificationDetails {
+	details := NotificationDetails{
+		ID:        n.ID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Body:      n.Body,
+		Data:      n.Data,
+		CreatedAt: n.CreatedAt.Time,
+	}
+	if n.ReadAt.Valid {
+		details.ReadAt = &n.ReadAt.Time
+	}
+	return details
+}
@@ -0,0 +1,1128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/orgs"
+	"new-chainsaw/internal/program"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+const (
+	// dashboardWorkouts is how many recent workouts an athlete dashboard lists.
+	dashboardWorkouts = 10
+	// dashboardWeeks is how many weeks of analytics and bodyweight trend an athlete dashboard covers.
+	dashboardWeeks = 8
+)
+
+type PrivacySettings struct {
+	ShareTrainingWithCoaches bool `json:"share_training_with_coaches"` // Workouts, streaks, programs, goals and analytics
+	ShareBodyWithCoaches     bool `json:"share_body_with_coaches"`     // Bodyweight and body measurements
+}
+
+type OrganizationRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+type OrganizationInviteRequest struct {
+	Role          string `json:"role"`            // coach or athlete
+	ExpiresInDays *int   `json:"expires_in_days"` // Defaults to a week
+	MaxUses       *int32 `json:"max_uses"`        // Unlimited until the invite expires when omitted
+}
+
+type OrganizationMemberRequest struct {
+	Role string `json:"role"` // coach or athlete
+}
+
+type TemplateAssignmentRequest struct {
+	TemplateID int32   `json:"template_id"` // One of the coach's templates
+	Note       *string `json:"note"`
+}
+
+type ProgramAssignmentRequest struct {
+	ProgramID int32   `json:"program_id"` // A built-in program or one of the coach's programs
+	Note      *string `json:"note"`
+	ProgramEnrollmentRequest
+}
+
+type OrganizationSummary struct {
+	ID          int32     `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Role        string    `json:"role"` // The user's role: owner, coach or athlete
+	MemberCount int64     `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type OrganizationDetails struct {
+	ID          int32                       `json:"id"`
+	Name        string                      `json:"name"`
+	Description *string                     `json:"description"`
+	Role        string                      `json:"role"` // The user's role: owner, coach or athlete
+	Members     []OrganizationMemberDetails `json:"members"`
+	CreatedAt   time.Time                   `json:"created_at"`
+}
+
+type OrganizationMemberDetails struct {
+	UserID    int32     `json:"user_id"`
+	Username  string    `json:"username"`
+	Name      *string   `json:"name"`
+	AvatarURL *string   `json:"avatar_url"`
+	Role      string    `json:"role"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+type OrganizationInviteDetails struct {
+	ID        int32     `json:"id"`
+	Code      string    `json:"code"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+	MaxUses   *int32    `json:"max_uses"`
+	Uses      int32     `json:"uses"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CoachAssignmentDetails struct {
+	ID              int32     `json:"id"`
+	AthleteUserID   int32     `json:"athlete_user_id"`
+	AthleteUsername string    `json:"athlete_username"`
+	CoachUserID     *int32    `json:"coach_user_id"`
+	CoachUsername   *string   `json:"coach_username"`
+	TemplateID      *int32    `json:"template_id"` // The athlete's copy, nil once they deleted it
+	TemplateName    *string   `json:"template_name"`
+	ProgramID       *int32    `json:"program_id"`
+	ProgramName     *string   `json:"program_name"`
+	EnrollmentID    *int32    `json:"enrollment_id"`
+	Note            *string   `json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AthleteDashboard is what coaches see of an athlete. Sections the athlete does not share with
+// coaches are nil.
+type AthleteDashboard struct {
+	Athlete        OrganizationMemberDetails  `json:"athlete"`
+	Unit           string                     `json:"unit"` // The coach's preferred units
+	Privacy        PrivacySettings            `json:"privacy"`
+	Streaks        *StreakDetails             `json:"streaks"`
+	RecentWorkouts []WorkoutDetails           `json:"recent_workouts"`
+	Programs       []ProgramEnrollmentDetails `json:"programs"`
+	Goals          []StrengthGoalDetails      `json:"goals"`
+	Analytics      *TrainingAnalytics         `json:"analytics"`
+	Body           *AthleteBodyDetails        `json:"body"`
+	Assignments    []CoachAssignmentDetails   `json:"assignments"`
+}
+
+type AthleteBodyDetails struct {
+	Bodyweight   *float64                 `json:"bodyweight"`    // Latest trend weight, nil without recent weigh-ins
+	WeeklyChange *float64                 `json:"weekly_change"` // nil without enough recent weigh-ins
+	Measurements []BodyMeasurementDetails `json:"measurements"`  // Latest of every kind
+}
+
+func GetPrivacySettingsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	settings, err := userPrivacySettings(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch privacy settings", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"privacy": settings}, nil)
+}
+
+// UpdatePrivacySettingsHandler sets what the user shares with the coaches of their organizations.
+func UpdatePrivacySettingsHandler(c *gin.Context) {
+	var req PrivacySettings
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	err := queries.UpsertPrivacySettings(context.Background(), db.UpsertPrivacySettingsParams{
+		UserID:                   int32(userID),
+		ShareTrainingWithCoaches: req.ShareTrainingWithCoaches,
+		ShareBodyWithCoaches:     req.ShareBodyWithCoaches,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update privacy settings", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Privacy settings updated", gin.H{"privacy": req}, nil)
+}
+
+// ListOrganizationsHandler returns the organizations the user is a member of.
+func ListOrganizationsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	list, err := queries.ListUserOrganizations(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch organizations", nil, err)
+		return
+	}
+
+	summaries := make([]OrganizationSummary, len(list))
+	for i, row := range list {
+		summaries[i] = OrganizationSummary{
+			ID:          row.ID,
+			Name:        row.Name,
+			Description: optionalText(row.Description),
+			Role:        string(row.Role),
+			MemberCount: row.MemberCount,
+			CreatedAt:   row.CreatedAt.Time,
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"organizations": summaries}, nil)
+}
+
+// CreateOrganizationHandler creates an organization owned by the user.
+func CreateOrganizationHandler(c *gin.Context) {
+	var req OrganizationRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	if err := validation.ValidateOrganization(req.Name); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create organization", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	organization, err := qtx.CreateOrganization(ctx, db.CreateOrganizationParams{
+		Name:        strings.TrimSpace(req.Name),
+		Description: trimmedText(req.Description),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create organization", nil, err)
+		return
+	}
+	_, err = qtx.AddOrganizationMember(ctx, db.AddOrganizationMemberParams{
+		OrganizationID: organization.ID,
+		UserID:         int32(userID),
+		Role:           db.OrganizationRoleOwner,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create organization", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create organization", nil, err)
+		return
+	}
+
+	respondWithOrganization(c, http.StatusCreated, "Organization created", organization, db.OrganizationRoleOwner)
+}
+
+// GetOrganizationHandler returns an organization with its roster.
+func GetOrganizationHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	respondWithOrganization(c, http.StatusOK, "", organization, member.Role)
+}
+
+func UpdateOrganizationHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	var req OrganizationRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	if member.Role != db.OrganizationRoleOwner {
+		response.JSONResponse(c, http.StatusForbidden, "Only the owner can change the organization", nil, nil)
+		return
+	}
+	if err := validation.ValidateOrganization(req.Name); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	organization, err := queries.UpdateOrganization(context.Background(), db.UpdateOrganizationParams{
+		ID:          organization.ID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: trimmedText(req.Description),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update organization", nil, err)
+		return
+	}
+
+	respondWithOrganization(c, http.StatusOK, "Organization updated", organization, member.Role)
+}
+
+// DeleteOrganizationHandler deletes an organization with its memberships, invites and the
+// record of assignments. Templates and programs assigned to athletes stay in their accounts.
+func DeleteOrganizationHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	if member.Role != db.OrganizationRoleOwner {
+		response.JSONResponse(c, http.StatusForbidden, "Only the owner can delete the organization", nil, nil)
+		return
+	}
+
+	if err := queries.DeleteOrganization(context.Background(), organization.ID); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete organization", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Organization deleted", nil, nil)
+}
+
+// ListOrganizationInvitesHandler returns the invites of an organization that have not expired.
+func ListOrganizationInvitesHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	if !orgs.CanCoach(member.Role) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the owner and coaches can see invites", nil, nil)
+		return
+	}
+
+	invites, err := queries.ListOrganizationInvites(context.Background(), organization.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch invites", nil, err)
+		return
+	}
+
+	details := make([]OrganizationInviteDetails, len(invites))
+	for i, invite := range invites {
+		details[i] = toOrganizationInviteDetails(invite)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"invites": details}, nil)
+}
+
+// CreateOrganizationInviteHandler creates an invite link anyone can join the organization with
+// in the role of the invite until it expires or is used up.
+func CreateOrganizationInviteHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	var req OrganizationInviteRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	role := db.OrganizationRole(req.Role)
+	expiresInDays := orgs.DefaultInviteDays
+	if req.ExpiresInDays != nil {
+		expiresInDays = *req.ExpiresInDays
+	}
+	if err := validation.ValidateOrganizationInvite(role, expiresInDays, req.MaxUses); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	if !orgs.CanInvite(member.Role, role) {
+		response.JSONResponse(c, http.StatusForbidden, "You cannot invite members with this role", nil, nil)
+		return
+	}
+
+	code, err := orgs.InviteCode()
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create invite", nil, err)
+		return
+	}
+	invite, err := queries.CreateOrganizationInvite(context.Background(), db.CreateOrganizationInviteParams{
+		OrganizationID: organization.ID,
+		Code:           code,
+		Role:           role,
+		CreatedBy:      pgtype.Int4{Int32: member.UserID, Valid: true},
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, expiresInDays), Valid: true},
+		MaxUses:        optionalInt4(req.MaxUses),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create invite", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Invite created", gin.H{"invite": toOrganizationInviteDetails(invite)}, nil)
+}
+
+// DeleteOrganizationInviteHandler revokes an invite. Members who joined with it stay.
+func DeleteOrganizationInviteHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	if !orgs.CanCoach(member.Role) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the owner and coaches can revoke invites", nil, nil)
+		return
+	}
+
+	ctx := context.Background()
+
+	inviteID, err := strconv.Atoi(c.Param("invite_id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid invite ID", nil, err)
+		return
+	}
+	invites, err := queries.ListOrganizationInvites(ctx, organization.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch invites", nil, err)
+		return
+	}
+	var invite *db.OrganizationInvite
+	for i := range invites {
+		if invites[i].ID == int32(inviteID) {
+			invite = &invites[i]
+		}
+	}
+	if invite == nil {
+		response.JSONResponse(c, http.StatusNotFound, "Invite not found", nil, nil)
+		return
+	}
+	if !orgs.CanInvite(member.Role, invite.Role) {
+		response.JSONResponse(c, http.StatusForbidden, "You cannot revoke invites for this role", nil, nil)
+		return
+	}
+
+	if _, err := queries.DeleteOrganizationInvite(ctx, db.DeleteOrganizationInviteParams{ID: invite.ID, OrganizationID: organization.ID}); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to revoke invite", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Invite revoked", nil, nil)
+}
+
+// GetOrganizationInviteHandler returns which organization an invite link is for and whether it
+// can still be accepted.
+func GetOrganizationInviteHandler(c *gin.Context) {
+	invite, ok := fetchOrganizationInvite(c)
+	if !ok {
+		return
+	}
+
+	valid := orgs.CheckInvite(invite.ExpiresAt.Time, optionalInt32(invite.MaxUses), invite.Uses, time.Now()) == nil
+	response.JSONResponse(c, http.StatusOK, "", gin.H{
+		"invite": gin.H{
+			"organization_id":   invite.OrganizationID,
+			"organization_name": invite.OrganizationName,
+			"role":              invite.Role,
+			"expires_at":        invite.ExpiresAt.Time,
+			"valid":             valid,
+		},
+	}, nil)
+}
+
+// AcceptOrganizationInviteHandler joins the organization of an invite link in its role.
+func AcceptOrganizationInviteHandler(c *gin.Context) {
+	invite, ok := fetchOrganizationInvite(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	if err := orgs.CheckInvite(invite.ExpiresAt.Time, optionalInt32(invite.MaxUses), invite.Uses, time.Now()); err != nil {
+		response.JSONResponse(c, http.StatusGone, err.Error(), nil, err)
+		return
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to join organization", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	added, err := qtx.AddOrganizationMember(ctx, db.AddOrganizationMemberParams{
+		OrganizationID: invite.OrganizationID,
+		UserID:         int32(userID),
+		Role:           invite.Role,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to join organization", nil, err)
+		return
+	}
+	if added == 0 {
+		response.JSONResponse(c, http.StatusConflict, "You are already a member of this organization", nil, nil)
+		return
+	}
+	used, err := qtx.UseOrganizationInvite(ctx, invite.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to join organization", nil, err)
+		return
+	}
+	if used == 0 {
+		response.JSONResponse(c, http.StatusGone, orgs.ErrInviteUsedUp.Error(), nil, nil)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to join organization", nil, err)
+		return
+	}
+
+	organization, err := queries.GetOrganization(ctx, invite.OrganizationID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch organization", nil, err)
+		return
+	}
+	respondWithOrganization(c, http.StatusOK, "Joined organization", organization, invite.Role)
+}
+
+// UpdateOrganizationMemberHandler changes a member between coach and athlete.
+func UpdateOrganizationMemberHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	var req OrganizationMemberRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	if member.Role != db.OrganizationRoleOwner {
+		response.JSONResponse(c, http.StatusForbidden, "Only the owner can change roles", nil, nil)
+		return
+	}
+	role := db.OrganizationRole(req.Role)
+	if role != db.OrganizationRoleCoach && role != db.OrganizationRoleAthlete {
+		response.JSONResponse(c, http.StatusBadRequest, "Role must be coach or athlete", nil, nil)
+		return
+	}
+	target, ok := fetchOrganizationMember(c, organization)
+	if !ok {
+		return
+	}
+
+	updated, err := queries.UpdateOrganizationMemberRole(context.Background(), db.UpdateOrganizationMemberRoleParams{
+		OrganizationID: organization.ID,
+		UserID:         target.UserID,
+		Role:           role,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to change role", nil, err)
+		return
+	}
+	if updated == 0 {
+		response.JSONResponse(c, http.StatusConflict, "The owner's role cannot be changed", nil, nil)
+		return
+	}
+
+	respondWithOrganization(c, http.StatusOK, "Role changed", organization, member.Role)
+}
+
+// RemoveOrganizationMemberHandler removes a member, or lets a member leave.
+func RemoveOrganizationMemberHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+	target, ok := fetchOrganizationMember(c, organization)
+	if !ok {
+		return
+	}
+
+	if target.Role == db.OrganizationRoleOwner {
+		response.JSONResponse(c, http.StatusConflict, "The owner cannot leave, delete the organization instead", nil, nil)
+		return
+	}
+	if !orgs.CanRemove(member, target) {
+		response.JSONResponse(c, http.StatusForbidden, "You cannot remove this member", nil, nil)
+		return
+	}
+
+	_, err := queries.RemoveOrganizationMember(context.Background(), db.RemoveOrganizationMemberParams{
+		OrganizationID: organization.ID,
+		UserID:         target.UserID,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to remove member", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Member removed", nil, nil)
+}
+
+// GetAthleteDashboardHandler returns what an athlete shares with the coaches of the
+// organization, in the coach's units.
+func GetAthleteDashboardHandler(c *gin.Context) {
+	organization, athlete, ok := fetchCoachedAthlete(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	privacy, err := userPrivacySettings(ctx, athlete.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch privacy settings", nil, err)
+		return
+	}
+	members, err := queries.ListOrganizationMembers(ctx, organization.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch members", nil, err)
+		return
+	}
+	assignments, err := queries.ListCoachAssignments(ctx, db.ListCoachAssignmentsParams{
+		OrganizationID: organization.ID,
+		AthleteUserID:  pgtype.Int4{Int32: athlete.UserID, Valid: true},
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch assignments", nil, err)
+		return
+	}
+
+	dashboard := AthleteDashboard{
+		Unit:        string(units),
+		Privacy:     privacy,
+		Assignments: toCoachAssignmentDetailsList(assignments),
+	}
+	for _, m := range members {
+		if m.UserID == athlete.UserID {
+			dashboard.Athlete = toOrganizationMemberDetails(m)
+		}
+	}
+
+	if privacy.ShareTrainingWithCoaches {
+		if err := addTrainingToDashboard(ctx, &dashboard, athlete.UserID, units); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch training", nil, err)
+			return
+		}
+	}
+	if privacy.ShareBodyWithCoaches {
+		if err := addBodyToDashboard(ctx, &dashboard, athlete.UserID, units); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch body data", nil, err)
+			return
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"dashboard": dashboard}, nil)
+}
+
+// ListCoachAssignmentsHandler returns the templates and programs assigned in an organization.
+// Athletes only see their own.
+func ListCoachAssignmentsHandler(c *gin.Context) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return
+	}
+
+	athleteID := pgtype.Int4{Int32: member.UserID, Valid: true}
+	if orgs.CanCoach(member.Role) {
+		athleteID = pgtype.Int4{}
+		if value := c.Query("athlete_id"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				response.JSONResponse(c, http.StatusBadRequest, "Invalid athlete ID", nil, err)
+				return
+			}
+			athleteID = pgtype.Int4{Int32: int32(parsed), Valid: true}
+		}
+	}
+
+	assignments, err := queries.ListCoachAssignments(context.Background(), db.ListCoachAssignmentsParams{
+		OrganizationID: organization.ID,
+		AthleteUserID:  athleteID,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch assignments", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"assignments": toCoachAssignmentDetailsList(assignments)}, nil)
+}
+
+// AssignTemplateHandler copies one of the coach's templates into the athlete's templates.
+// Custom exercises of the coach are left out of the copy and reported as skipped.
+func AssignTemplateHandler(c *gin.Context) {
+	organization, athlete, ok := fetchCoachedAthlete(c)
+	if !ok {
+		return
+	}
+	var req TemplateAssignmentRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	source, err := queries.GetWorkoutTemplate(ctx, db.GetWorkoutTemplateParams{ID: req.TemplateID, UserID: int32(userID)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown template", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch template", nil, err)
+		return
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to assign template", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	template, skipped, err := copyWorkoutTemplate(ctx, qtx, source, athlete.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to assign template", nil, err)
+		return
+	}
+	assignment, ok := recordCoachAssignment(c, qtx, organization, athlete, db.CreateCoachAssignmentParams{
+		TemplateID: pgtype.Int4{Int32: template.ID, Valid: true},
+		Note:       trimmedText(req.Note),
+	}, template.Name)
+	if !ok {
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to assign template", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Template assigned", gin.H{"assignment": assignment, "skipped_exercises": skipped}, nil)
+}
+
+// AssignProgramHandler enrolls the athlete in a program. Programs of the coach are copied into
+// the athlete's programs first. Lifts without a training max in the request start from the
+// athlete's latest logged sets.
+func AssignProgramHandler(c *gin.Context) {
+	organization, athlete, ok := fetchCoachedAthlete(c)
+	if !ok {
+		return
+	}
+	var req ProgramAssignmentRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	source, err := queries.GetAccessibleTrainingProgram(ctx, db.GetAccessibleTrainingProgramParams{ID: req.ProgramID, UserID: int32(userID)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusBadRequest, "Unknown program", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch program", nil, err)
+		return
+	}
+	var definition program.Definition
+	if err := json.Unmarshal(source.Definition, &definition); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read program", nil, err)
+		return
+	}
+	_, encoded, ok := newProgramState(c, athlete.UserID, definition, req.ProgramEnrollmentRequest)
+	if !ok {
+		return
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to assign program", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	p := source
+	if source.OwnerUserID.Valid {
+		p, err = qtx.CreateTrainingProgram(ctx, db.CreateTrainingProgramParams{
+			OwnerUserID: athlete.UserID,
+			Name:        source.Name,
+			Description: source.Description,
+			Definition:  source.Definition,
+		})
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to assign program", nil, err)
+			return
+		}
+	}
+	enrollment, err := qtx.CreateProgramEnrollment(ctx, db.CreateProgramEnrollmentParams{
+		UserID:    athlete.UserID,
+		ProgramID: p.ID,
+		State:     encoded,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			response.JSONResponse(c, http.StatusConflict, "The athlete is already following a program", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to assign program", nil, err)
+		return
+	}
+	assignment, ok := recordCoachAssignment(c, qtx, organization, athlete, db.CreateCoachAssignmentParams{
+		ProgramID:    pgtype.Int4{Int32: p.ID, Valid: true},
+		EnrollmentID: pgtype.Int4{Int32: enrollment.ID, Valid: true},
+		Note:         trimmedText(req.Note),
+	}, p.Name)
+	if !ok {
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to assign program", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Program assigned", gin.H{"assignment": assignment}, nil)
+}
+
+// recordCoachAssignment records what the coach assigned to the athlete and lets the athlete
+// know. name is the name of the assigned template or program.
+func recordCoachAssignment(c *gin.Context, qtx *db.Queries, organization db.Organization, athlete db.OrganizationMember, params db.CreateCoachAssignmentParams, name string) (CoachAssignmentDetails, bool) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	coach, err := qtx.GetUserAccount(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch user", nil, err)
+		return CoachAssignmentDetails{}, false
+	}
+	athleteAccount, err := qtx.GetUserAccount(ctx, athlete.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch user", nil, err)
+		return CoachAssignmentDetails{}, false
+	}
+
+	params.OrganizationID = organization.ID
+	params.CoachUserID = pgtype.Int4{Int32: coach.ID, Valid: true}
+	params.AthleteUserID = athlete.UserID
+	assignment, err := qtx.CreateCoachAssignment(ctx, params)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to record assignment", nil, err)
+		return CoachAssignmentDetails{}, false
+	}
+
+	row := db.ListCoachAssignmentsRow{
+		ID:              assignment.ID,
+		AthleteUserID:   assignment.AthleteUserID,
+		AthleteUsername: athleteAccount.Username,
+		CoachUserID:     assignment.CoachUserID,
+		CoachUsername:   pgtype.Text{String: coach.Username, Valid: true},
+		TemplateID:      assignment.TemplateID,
+		ProgramID:       assignment.ProgramID,
+		EnrollmentID:    assignment.EnrollmentID,
+		Note:            assignment.Note,
+		CreatedAt:       assignment.CreatedAt,
+	}
+	if assignment.TemplateID.Valid {
+		row.TemplateName = pgtype.Text{String: name, Valid: true}
+	}
+	if assignment.ProgramID.Valid {
+		row.ProgramName = pgtype.Text{String: name, Valid: true}
+	}
+
+	err = events.Emit(ctx, qtx, athlete.UserID, events.CoachAssigned, events.CoachAssignedPayload{
+		AssignmentID:   assignment.ID,
+		OrganizationID: organization.ID,
+		Organization:   organization.Name,
+		Coach:          coach.Username,
+		TemplateID:     optionalInt32(assignment.TemplateID),
+		ProgramID:      optionalInt32(assignment.ProgramID),
+		Name:           name,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to record assignment", nil, err)
+		return CoachAssignmentDetails{}, false
+	}
+	return toCoachAssignmentDetails(row), true
+}
+
+// addTrainingToDashboard adds the streaks, recent workouts, active programs, goals and
+// analytics of an athlete to their dashboard.
+func addTrainingToDashboard(ctx context.Context, dashboard *AthleteDashboard, athleteID int32, units db.UnitSystem) error {
+	s, err := userStreaks(ctx, athleteID)
+	if err != nil {
+		return err
+	}
+	trophies, err := queries.ListTrophies(ctx)
+	if err != nil {
+		return err
+	}
+	streakDetails := toStreakDetails(s, trophies)
+	dashboard.Streaks = &streakDetails
+
+	sessions, err := queries.ListWorkoutSessions(ctx, db.ListWorkoutSessionsParams{UserID: athleteID, Limit: dashboardWorkouts})
+	if err != nil {
+		return err
+	}
+	dashboard.RecentWorkouts = make([]WorkoutDetails, len(sessions))
+	for i, session := range sessions {
+		dashboard.RecentWorkouts[i] = toWorkoutDetails(session, units)
+	}
+
+	enrollments, err := queries.ListProgramEnrollments(ctx, athleteID)
+	if err != nil {
+		return err
+	}
+	active := []db.ListProgramEnrollmentsRow{}
+	for _, e := range enrollments {
+		if e.Status == db.ProgramEnrollmentStatusActive {
+			active = append(active, e)
+		}
+	}
+	if dashboard.Programs, err = toProgramEnrollmentSummaries(active); err != nil {
+		return err
+	}
+
+	goals, err := queries.ListStrengthGoals(ctx, athleteID)
+	if err != nil {
+		return err
+	}
+	dashboard.Goals = make([]StrengthGoalDetails, len(goals))
+	for i, goal := range goals {
+		if dashboard.Goals[i], err = strengthGoalDetails(ctx, goal, units); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	analytics, err := computeTrainingAnalytics(ctx, athleteID, analyticsPeriodWeek, analyticsWindowStart(now, analyticsPeriodWeek, dashboardWeeks), now)
+	if err != nil {
+		return err
+	}
+	analytics = inUnits(analytics, units)
+	dashboard.Analytics = &analytics
+	return nil
+}
+
+// addBodyToDashboard adds the bodyweight trend and latest body measurements of an athlete to
+// their dashboard.
+func addBodyToDashboard(ctx context.Context, dashboard *AthleteDashboard, athleteID int32, units db.UnitSystem) error {
+	to := startOfDay(time.Now())
+	trend, err := bodyweightTrend(ctx, athleteID, to.AddDate(0, 0, -7*dashboardWeeks), to)
+	if err != nil {
+		return err
+	}
+	latest, err := queries.GetLatestBodyMeasurements(ctx, athleteID)
+	if err != nil {
+		return err
+	}
+	rows := make([]db.ListBodyMeasurementsRow, len(latest))
+	for i, row := range latest {
+		rows[i] = db.ListBodyMeasurementsRow(row)
+	}
+
+	body := &AthleteBodyDetails{Measurements: toBodyMeasurementDetailsList(rows, units)}
+	if len(trend.Days) > 0 {
+		current := unitWeight(trend.Days[len(trend.Days)-1].TrendKg, units)
+		body.Bodyweight = &current
+		if trend.WeeklyChangeKg != nil {
+			change := unitWeight(*trend.WeeklyChangeKg, units)
+			body.WeeklyChange = &change
+		}
+	}
+	dashboard.Body = body
+	return nil
+}
+
+// userPrivacySettings returns what a user shares with coaches, the defaults until they change it.
+func userPrivacySettings(ctx context.Context, userID int32) (PrivacySettings, error) {
+	settings, err := queries.GetPrivacySettings(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PrivacySettings{ShareTrainingWithCoaches: true}, nil
+	}
+	if err != nil {
+		return PrivacySettings{}, err
+	}
+	return PrivacySettings{
+		ShareTrainingWithCoaches: settings.ShareTrainingWithCoaches,
+		ShareBodyWithCoaches:     settings.ShareBodyWithCoaches,
+	}, nil
+}
+
+// fetchOrganization returns the organization of the request with the user's membership.
+// Organizations are hidden from everybody but their members.
+func fetchOrganization(c *gin.Context) (db.Organization, db.OrganizationMember, bool) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	organizationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid organization ID", nil, err)
+		return db.Organization{}, db.OrganizationMember{}, false
+	}
+
+	member, err := queries.GetOrganizationMember(ctx, db.GetOrganizationMemberParams{OrganizationID: int32(organizationID), UserID: int32(userID)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Organization not found", nil, err)
+			return db.Organization{}, db.OrganizationMember{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch organization", nil, err)
+		return db.Organization{}, db.OrganizationMember{}, false
+	}
+	organization, err := queries.GetOrganization(ctx, member.OrganizationID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch organization", nil, err)
+		return db.Organization{}, db.OrganizationMember{}, false
+	}
+	return organization, member, true
+}
+
+// fetchOrganizationMember returns the member of the organization named by the user_id parameter.
+func fetchOrganizationMember(c *gin.Context, organization db.Organization) (db.OrganizationMember, bool) {
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid user ID", nil, err)
+		return db.OrganizationMember{}, false
+	}
+
+	member, err := queries.GetOrganizationMember(context.Background(), db.GetOrganizationMemberParams{OrganizationID: organization.ID, UserID: int32(memberID)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Member not found", nil, err)
+			return db.OrganizationMember{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch member", nil, err)
+		return db.OrganizationMember{}, false
+	}
+	return member, true
+}
+
+// fetchCoachedAthlete returns the organization of the request and the athlete named by the
+// user_id parameter when the user coaches in the organization.
+func fetchCoachedAthlete(c *gin.Context) (db.Organization, db.OrganizationMember, bool) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return db.Organization{}, db.OrganizationMember{}, false
+	}
+	if !orgs.CanCoach(member.Role) {
+		response.JSONResponse(c, http.StatusForbidden, "Only the owner and coaches can coach athletes", nil, nil)
+		return db.Organization{}, db.OrganizationMember{}, false
+	}
+	athlete, ok := fetchOrganizationMember(c, organization)
+	if !ok {
+		return db.Organization{}, db.OrganizationMember{}, false
+	}
+	if athlete.Role != db.OrganizationRoleAthlete {
+		response.JSONResponse(c, http.StatusNotFound, "Athlete not found", nil, nil)
+		return db.Organization{}, db.OrganizationMember{}, false
+	}
+	return organization, athlete, true
+}
+
+func fetchOrganizationInvite(c *gin.Context) (db.GetOrganizationInviteByCodeRow, bool) {
+	invite, err := queries.GetOrganizationInviteByCode(context.Background(), c.Param("code"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Invite not found", nil, err)
+			return db.GetOrganizationInviteByCodeRow{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch invite", nil, err)
+		return db.GetOrganizationInviteByCodeRow{}, false
+	}
+	return invite, true
+}
+
+func respondWithOrganization(c *gin.Context, status int, message string, organization db.Organization, role db.OrganizationRole) {
+	members, err := queries.ListOrganizationMembers(context.Background(), organization.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch members", nil, err)
+		return
+	}
+
+	details := OrganizationDetails{
+		ID:          organization.ID,
+		Name:        organization.Name,
+		Description: optionalText(organization.Description),
+		Role:        string(role),
+		Members:     make([]OrganizationMemberDetails, len(members)),
+		CreatedAt:   organization.CreatedAt.Time,
+	}
+	for i, m := range members {
+		details.Members[i] = toOrganizationMemberDetails(m)
+	}
+
+	response.JSONResponse(c, status, message, gin.H{"organization": details}, nil)
+}
+
+func toOrganizationMemberDetails(m db.ListOrganizationMembersRow) OrganizationMemberDetails {
+	return OrganizationMemberDetails{
+		UserID:    m.UserID,
+		Username:  m.Username,
+		Name:      optionalText(m.Name),
+		AvatarURL: optionalText(m.AvatarUrl),
+		Role:      string(m.Role),
+		JoinedAt:  m.JoinedAt.Time,
+	}
+}
+
+func toOrganizationInviteDetails(invite db.OrganizationInvite) OrganizationInviteDetails {
+	return OrganizationInviteDetails{
+		ID:        invite.ID,
+		Code:      invite.Code,
+		Role:      string(invite.Role),
+		ExpiresAt: invite.ExpiresAt.Time,
+		MaxUses:   optionalInt32(invite.MaxUses),
+		Uses:      invite.Uses,
+		CreatedAt: invite.CreatedAt.Time,
+	}
+}
+
+func toCoachAssignmentDetailsList(rows []db.ListCoachAssignmentsRow) []CoachAssignmentDetails {
+	details := make([]CoachAssignmentDetails, len(rows))
+	for i, row := range rows {
+		details[i] = toCoachAssignmentDetails(row)
+	}
+	return details
+}
+
+func toCoachAssignmentDetails(row db.ListCoachAssignmentsRow) CoachAssignmentDetails {
+	return CoachAssignmentDetails{
+		ID:              row.ID,
+		AthleteUserID:   row.AthleteUserID,
+		AthleteUsername: row.AthleteUsername,
+		CoachUserID:     optionalInt32(row.CoachUserID),
+		CoachUsername:   optionalText(row.CoachUsername),
+		TemplateID:      optionalInt32(row.TemplateID),
+		TemplateName:    optionalText(row.TemplateName),
+		ProgramID:       optionalInt32(row.ProgramID),
+		ProgramName:     optionalText(row.ProgramName),
+		EnrollmentID:    optionalInt32(row.EnrollmentID),
+		Note:            optionalText(row.Note),
+		CreatedAt:       row.CreatedAt.Time,
+	}
+}
+
+// trimmedText returns optional text without surrounding whitespace, NULL when blank.
+func trimmedText(value *string) pgtype.Text {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: strings.TrimSpace(*value), Valid: true}
+}
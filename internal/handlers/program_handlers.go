@@ -0,0 +1,860 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/program"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+type TrainingProgramRequest struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Definition  program.Definition `json:"definition"`
+}
+
+type TrainingProgramDetails struct {
+	ID          int32              `json:"id"`
+	Name        string             `json:"name"`
+	Description *string            `json:"description"`
+	Custom      bool               `json:"custom"`
+	Definition  program.Definition `json:"definition"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type ProgramEnrollmentRequest struct {
+	TrainingMaxes map[string]float64 `json:"training_maxes"` // Per lift key, estimated from the latest logs when left out
+	Unit          string             `json:"unit"`           // Unit of the training maxes, metric or imperial
+}
+
+// AdvanceProgramRequest moves an enrollment to its next day. Skip moves on without judging the
+// current day, so nothing progresses or counts as a failure.
+type AdvanceProgramRequest struct {
+	Skip bool `json:"skip"`
+}
+
+type EnrolledLiftDetails struct {
+	Lift         string  `json:"lift"`
+	ExerciseID   int32   `json:"exercise_id"`
+	ExerciseName string  `json:"exercise_name"`
+	TrainingMax  float64 `json:"training_max"`
+	Failures     int     `json:"failures"`
+}
+
+type ProgramEnrollmentDetails struct {
+	ID           int32                 `json:"id"`
+	ProgramID    int32                 `json:"program_id"`
+	ProgramName  string                `json:"program_name"`
+	Status       string                `json:"status"`
+	Cycle        int                   `json:"cycle"`
+	Week         int                   `json:"week"`
+	Day          int                   `json:"day"`
+	Unit         string                `json:"unit,omitempty"`
+	Lifts        []EnrolledLiftDetails `json:"lifts,omitempty"`
+	DayStartedAt time.Time             `json:"day_started_at"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+type ProgramSetDetails struct {
+	Reps   int32   `json:"reps"`
+	Weight float64 `json:"weight"`
+	AMRAP  bool    `json:"amrap"`
+}
+
+type ProgramExerciseDetails struct {
+	Lift         string              `json:"lift"`
+	ExerciseID   int32               `json:"exercise_id"`
+	ExerciseName string              `json:"exercise_name"`
+	TrainingMax  float64             `json:"training_max"`
+	Sets         []ProgramSetDetails `json:"sets"`
+}
+
+type ProgramWorkoutDetails struct {
+	Cycle     int                      `json:"cycle"`
+	Week      int                      `json:"week"`
+	Day       int                      `json:"day"`
+	Name      string                   `json:"name"`
+	Unit      string                   `json:"unit"`
+	Exercises []ProgramExerciseDetails `json:"exercises"`
+}
+
+type TrainingMaxAdjustment struct {
+	Lift string  `json:"lift"`
+	Kind string  `json:"kind"` // progress or deload
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+}
+
+// programEnrollment is an enrollment with its program definition and decoded state.
+type programEnrollment struct {
+	db.ProgramEnrollment
+	Program    db.TrainingProgram
+	Definition program.Definition
+	State      program.State
+}
+
+func ListTrainingProgramsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	programs, err := queries.ListTrainingPrograms(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch programs", nil, err)
+		return
+	}
+
+	details := make([]TrainingProgramDetails, len(programs))
+	for i, p := range programs {
+		details[i], err = toTrainingProgramDetails(p)
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to read program", nil, err)
+			return
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"programs": details}, nil)
+}
+
+func GetTrainingProgramHandler(c *gin.Context) {
+	p, ok := fetchTrainingProgram(c)
+	if !ok {
+		return
+	}
+
+	details, err := toTrainingProgramDetails(p)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read program", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"program": details}, nil)
+}
+
+// CreateTrainingProgramHandler stores a custom program. Its lifts must name exercises the user
+// can log, so enrolling later does not fail on them.
+func CreateTrainingProgramHandler(c *gin.Context) {
+	var req TrainingProgramRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	if err := validation.ValidateProgramName(req.Name); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	req.Description = strings.TrimSpace(req.Description)
+	if err := req.Definition.Validate(); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	if _, err := resolveProgramLifts(int32(userID), req.Definition); err != nil {
+		respondWithLiftResolutionError(c, err)
+		return
+	}
+
+	definition, err := json.Marshal(req.Definition)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create program", nil, err)
+		return
+	}
+
+	p, err := queries.CreateTrainingProgram(context.Background(), db.CreateTrainingProgramParams{
+		OwnerUserID: int32(userID),
+		Name:        strings.TrimSpace(req.Name),
+		Description: pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		Definition:  definition,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			response.JSONResponse(c, http.StatusConflict, "You already have a program with this name", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create program", nil, err)
+		return
+	}
+
+	details, err := toTrainingProgramDetails(p)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read program", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Program created", gin.H{"program": details}, nil)
+}
+
+// DeleteTrainingProgramHandler deletes a custom program together with its enrollments.
+func DeleteTrainingProgramHandler(c *gin.Context) {
+	programID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid program ID", nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	deleted, err := queries.DeleteTrainingProgram(context.Background(), db.DeleteTrainingProgramParams{
+		ID:          int32(programID),
+		OwnerUserID: int32(userID),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete program", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Program not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Program deleted", nil, nil)
+}
+
+// EnrollInProgramHandler starts a program at its first day. Lifts without a training max in
+// the request start at the program's percentage of the estimated one rep max of their latest
+// logged set.
+func EnrollInProgramHandler(c *gin.Context) {
+	p, ok := fetchTrainingProgram(c)
+	if !ok {
+		return
+	}
+
+	var req ProgramEnrollmentRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	userID := c.GetInt("userID")
+
+	var definition program.Definition
+	if err := json.Unmarshal(p.Definition, &definition); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read program", nil, err)
+		return
+	}
+	state, encoded, ok := newProgramState(c, int32(userID), definition, req)
+	if !ok {
+		return
+	}
+
+	enrollment, err := queries.CreateProgramEnrollment(ctx, db.CreateProgramEnrollmentParams{
+		UserID:    int32(userID),
+		ProgramID: p.ID,
+		State:     encoded,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			response.JSONResponse(c, http.StatusConflict, "You are already following a program, stop it before enrolling in another", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to enroll in program", nil, err)
+		return
+	}
+
+	e := &programEnrollment{ProgramEnrollment: enrollment, Program: p, Definition: definition, State: state}
+	respondWithProgramEnrollment(c, http.StatusCreated, "Enrolled in program", e, nil)
+}
+
+func ListProgramEnrollmentsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	enrollments, err := queries.ListProgramEnrollments(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch enrollments", nil, err)
+		return
+	}
+
+	details, err := toProgramEnrollmentSummaries(enrollments)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read enrollment", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"enrollments": details}, nil)
+}
+
+// GetProgramEnrollmentHandler returns an enrollment with its training maxes, after catching up
+// on the program days logged before today.
+func GetProgramEnrollmentHandler(c *gin.Context) {
+	e, ok := fetchProgramEnrollment(c)
+	if !ok {
+		return
+	}
+
+	adjustments, ok := catchUpProgramEnrollment(c, e, startOfDay(time.Now()))
+	if !ok {
+		return
+	}
+
+	respondWithProgramEnrollment(c, http.StatusOK, "", e, adjustments)
+}
+
+// GetProgramWorkoutHandler prescribes today's workout of an enrollment. Program days logged
+// before today are judged first, so the prescription follows what the user actually lifted.
+func GetProgramWorkoutHandler(c *gin.Context) {
+	e, ok := fetchProgramEnrollment(c)
+	if !ok {
+		return
+	}
+	if e.Status != db.ProgramEnrollmentStatusActive {
+		response.JSONResponse(c, http.StatusConflict, "This program enrollment has been stopped", nil, nil)
+		return
+	}
+
+	adjustments, ok := catchUpProgramEnrollment(c, e, startOfDay(time.Now()))
+	if !ok {
+		return
+	}
+
+	units, err := queries.GetUserPreferredUnit(context.Background(), e.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	workout, err := toProgramWorkoutDetails(e, units)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercises", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{
+		"workout":     workout,
+		"adjustments": toTrainingMaxAdjustments(adjustments, units),
+	}, nil)
+}
+
+// AdvanceProgramEnrollmentHandler judges the current day from the sets logged so far and moves
+// on to the next day, instead of waiting for the day to end.
+func AdvanceProgramEnrollmentHandler(c *gin.Context) {
+	e, ok := fetchProgramEnrollment(c)
+	if !ok {
+		return
+	}
+	if e.Status != db.ProgramEnrollmentStatusActive {
+		response.JSONResponse(c, http.StatusConflict, "This program enrollment has been stopped", nil, nil)
+		return
+	}
+
+	var req AdvanceProgramRequest
+	if c.Request.ContentLength > 0 {
+		if err := binding.BindJSON(c, &req); err != nil {
+			return
+		}
+	}
+
+	now := time.Now()
+	if req.Skip {
+		e.State, _ = program.Advance(e.Definition, e.State, nil)
+		e.DayStartedAt = pgtype.Timestamptz{Time: now, Valid: true}
+		if err := saveProgramEnrollment(context.Background(), e); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to advance program", nil, err)
+			return
+		}
+		respondWithProgramEnrollment(c, http.StatusOK, "Program day skipped", e, nil)
+		return
+	}
+
+	day := e.State
+	adjustments, ok := catchUpProgramEnrollment(c, e, now)
+	if !ok {
+		return
+	}
+	if e.State.Cycle == day.Cycle && e.State.Week == day.Week && e.State.Day == day.Day {
+		response.JSONResponse(c, http.StatusConflict, "No sets of the current program day have been logged yet", nil, nil)
+		return
+	}
+
+	respondWithProgramEnrollment(c, http.StatusOK, "Program advanced", e, adjustments)
+}
+
+// StartProgramWorkoutHandler starts a workout session with today's prescribed sets.
+func StartProgramWorkoutHandler(c *gin.Context) {
+	e, ok := fetchProgramEnrollment(c)
+	if !ok {
+		return
+	}
+	if e.Status != db.ProgramEnrollmentStatusActive {
+		response.JSONResponse(c, http.StatusConflict, "This program enrollment has been stopped", nil, nil)
+		return
+	}
+
+	if _, ok := catchUpProgramEnrollment(c, e, startOfDay(time.Now())); !ok {
+		return
+	}
+
+	ctx := context.Background()
+	units, err := queries.GetUserPreferredUnit(ctx, e.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	workout := program.Prescribe(e.Definition, e.State, func(kg float64) float64 {
+		return loadableWeight(kg, units)
+	})
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	session, err := qtx.CreateWorkoutSession(ctx, db.CreateWorkoutSessionParams{
+		UserID:    e.UserID,
+		Name:      programWorkoutName(e.Program.Name, workout),
+		StartedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+		return
+	}
+
+	for i, exercise := range workout.Exercises {
+		for j, set := range exercise.Sets {
+			err := qtx.AddWorkoutSessionSet(ctx, db.AddWorkoutSessionSetParams{
+				SessionID:    session.ID,
+				ExerciseID:   exercise.ExerciseID,
+				Position:     int32(i + 1),
+				SetNumber:    int32(j + 1),
+				TargetReps:   pgtype.Int4{Int32: set.Reps, Valid: true},
+				TargetWeight: conversion.ToNumeric(set.WeightKg),
+			})
+			if err != nil {
+				response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+		return
+	}
+
+	respondWithWorkout(c, http.StatusCreated, "Workout started", session)
+}
+
+func StopProgramEnrollmentHandler(c *gin.Context) {
+	enrollmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid enrollment ID", nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	stopped, err := queries.StopProgramEnrollment(context.Background(), db.StopProgramEnrollmentParams{
+		ID:     int32(enrollmentID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to stop program", nil, err)
+		return
+	}
+	if stopped == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Active enrollment not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Program stopped", nil, nil)
+}
+
+// catchUpProgramEnrollment advances an active enrollment over the program days logged from the
+// start of its current day until the given time. Every UTC calendar day with sets of the current
+// day's lifts completes one program day and is judged against that day's prescription.
+func catchUpProgramEnrollment(c *gin.Context, e *programEnrollment, until time.Time) ([]program.Adjustment, bool) {
+	adjustments := []program.Adjustment{}
+	if e.Status != db.ProgramEnrollmentStatusActive || !until.After(e.DayStartedAt.Time) {
+		return adjustments, true
+	}
+
+	ctx := context.Background()
+	units, err := queries.GetUserPreferredUnit(ctx, e.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return nil, false
+	}
+
+	exerciseIDs := make([]int32, 0, len(e.State.Lifts))
+	for _, lift := range e.State.Lifts {
+		exerciseIDs = append(exerciseIDs, lift.ExerciseID)
+	}
+	logs, err := queries.GetExerciseLogsForProgram(ctx, db.GetExerciseLogsForProgramParams{
+		UserID:      e.UserID,
+		ExerciseIds: exerciseIDs,
+		FromDate:    e.DayStartedAt,
+		ToDate:      pgtype.Timestamptz{Time: until, Valid: true},
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise logs", nil, err)
+		return nil, false
+	}
+
+	round := func(kg float64) float64 { return loadableWeight(kg, units) }
+	advanced := false
+	for start := 0; start < len(logs); {
+		date := startOfDay(logs[start].LogDate.Time)
+		var sets []program.LoggedSet
+		for ; start < len(logs) && startOfDay(logs[start].LogDate.Time).Equal(date); start++ {
+			sets = append(sets, program.LoggedSet{
+				ExerciseID: logs[start].ExerciseID,
+				Reps:       logs[start].Reps,
+				WeightKg:   numericToFloat(logs[start].Weight),
+			})
+		}
+
+		results, done := program.Evaluate(program.Prescribe(e.Definition, e.State, round), sets)
+		if !done {
+			continue
+		}
+		var adjusted []program.Adjustment
+		e.State, adjusted = program.Advance(e.Definition, e.State, results)
+		e.DayStartedAt = pgtype.Timestamptz{Time: date.AddDate(0, 0, 1), Valid: true}
+		adjustments = append(adjustments, adjusted...)
+		advanced = true
+	}
+
+	if advanced {
+		if err := saveProgramEnrollment(ctx, e); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to advance program", nil, err)
+			return nil, false
+		}
+	}
+	return adjustments, true
+}
+
+func saveProgramEnrollment(ctx context.Context, e *programEnrollment) error {
+	state, err := json.Marshal(e.State)
+	if err != nil {
+		return err
+	}
+	return queries.UpdateProgramEnrollmentState(ctx, db.UpdateProgramEnrollmentStateParams{
+		ID:           e.ID,
+		State:        state,
+		DayStartedAt: e.DayStartedAt,
+	})
+}
+
+// resolveProgramLifts maps the lifts of a program onto the user's exercises.
+func resolveProgramLifts(userID int32, definition program.Definition) (map[string]int32, error) {
+	exerciseIDs := make(map[string]int32, len(definition.Lifts))
+	for _, lift := range definition.Lifts {
+		exerciseID, err := resolveExerciseName(userID, lift.Exercise)
+		if err != nil {
+			return nil, fmt.Errorf("lift %s: %w", lift.Key, err)
+		}
+		exerciseIDs[lift.Key] = exerciseID
+	}
+	return exerciseIDs, nil
+}
+
+// newProgramState starts a program for a user at its first day and returns the encoded state.
+// Lifts without a training max in the request start at the program's percentage of the
+// estimated one rep max of their latest logged set.
+func newProgramState(c *gin.Context, userID int32, definition program.Definition, req ProgramEnrollmentRequest) (program.State, []byte, bool) {
+	ctx := context.Background()
+
+	exerciseIDs, err := resolveProgramLifts(userID, definition)
+	if err != nil {
+		respondWithLiftResolutionError(c, err)
+		return program.State{}, nil, false
+	}
+	latestLogs, err := queries.GetExercisesWithLatestLogDate(ctx, userID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch latest logs", nil, err)
+		return program.State{}, nil, false
+	}
+	latest := make(map[int32]db.GetExercisesWithLatestLogDateRow, len(latestLogs))
+	for _, l := range latestLogs {
+		latest[l.ID] = l
+	}
+
+	lifts := make(map[string]program.LiftState, len(definition.Lifts))
+	missing := []string{}
+	for _, lift := range definition.Lifts {
+		state := program.LiftState{ExerciseID: exerciseIDs[lift.Key]}
+		if trainingMax, ok := req.TrainingMaxes[lift.Key]; ok {
+			state.TrainingMax = calculateWeight(trainingMax, req.Unit)
+		} else if l, ok := latest[state.ExerciseID]; ok {
+			state.TrainingMax = roundTo(latestOneRepMax(l)*definition.TrainingMaxPercent/100, 2)
+		}
+		if state.TrainingMax == 0 {
+			missing = append(missing, lift.Key)
+			continue
+		}
+		if err := validation.ValidateTrainingMax(lift.Key, state.TrainingMax); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return program.State{}, nil, false
+		}
+		lifts[lift.Key] = state
+	}
+	if len(missing) > 0 {
+		response.JSONResponse(c, http.StatusBadRequest, "Training maxes are needed for lifts without logged sets", gin.H{"missing_training_maxes": missing}, nil)
+		return program.State{}, nil, false
+	}
+
+	state, err := program.NewState(definition, lifts)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return program.State{}, nil, false
+	}
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to enroll in program", nil, err)
+		return program.State{}, nil, false
+	}
+	return state, encoded, true
+}
+
+func respondWithLiftResolutionError(c *gin.Context, err error) {
+	var resolutionErr *ExerciseResolutionError
+	if errors.As(err, &resolutionErr) {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), gin.H{"exercise": resolutionErr}, err)
+		return
+	}
+	response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercises", nil, err)
+}
+
+func fetchTrainingProgram(c *gin.Context) (db.TrainingProgram, bool) {
+	userID := c.GetInt("userID")
+
+	programID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid program ID", nil, err)
+		return db.TrainingProgram{}, false
+	}
+
+	p, err := queries.GetAccessibleTrainingProgram(context.Background(), db.GetAccessibleTrainingProgramParams{
+		ID:     int32(programID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Program not found", nil, err)
+			return db.TrainingProgram{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch program", nil, err)
+		return db.TrainingProgram{}, false
+	}
+	return p, true
+}
+
+// fetchProgramEnrollment loads an enrollment of the user with its program and decoded state.
+func fetchProgramEnrollment(c *gin.Context) (*programEnrollment, bool) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	enrollmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid enrollment ID", nil, err)
+		return nil, false
+	}
+
+	enrollment, err := queries.GetProgramEnrollment(ctx, db.GetProgramEnrollmentParams{
+		ID:     int32(enrollmentID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Enrollment not found", nil, err)
+			return nil, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch enrollment", nil, err)
+		return nil, false
+	}
+	p, err := queries.GetAccessibleTrainingProgram(ctx, db.GetAccessibleTrainingProgramParams{
+		ID:     enrollment.ProgramID,
+		UserID: int32(userID),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch program", nil, err)
+		return nil, false
+	}
+
+	e := &programEnrollment{ProgramEnrollment: enrollment, Program: p}
+	if err := json.Unmarshal(p.Definition, &e.Definition); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read program", nil, err)
+		return nil, false
+	}
+	if err := json.Unmarshal(enrollment.State, &e.State); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to read enrollment", nil, err)
+		return nil, false
+	}
+	return e, true
+}
+
+func respondWithProgramEnrollment(c *gin.Context, status int, message string, e *programEnrollment, adjustments []program.Adjustment) {
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, e.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	names, err := exerciseNames(e.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercises", nil, err)
+		return
+	}
+
+	details := ProgramEnrollmentDetails{
+		ID:           e.ID,
+		ProgramID:    e.ProgramID,
+		ProgramName:  e.Program.Name,
+		Status:       string(e.Status),
+		Cycle:        e.State.Cycle + 1,
+		Week:         e.State.Week + 1,
+		Day:          e.State.Day + 1,
+		Unit:         string(units),
+		Lifts:        make([]EnrolledLiftDetails, 0, len(e.Definition.Lifts)),
+		DayStartedAt: e.DayStartedAt.Time,
+		CreatedAt:    e.CreatedAt.Time,
+	}
+	for _, lift := range e.Definition.Lifts {
+		state := e.State.Lifts[lift.Key]
+		details.Lifts = append(details.Lifts, EnrolledLiftDetails{
+			Lift:         lift.Key,
+			ExerciseID:   state.ExerciseID,
+			ExerciseName: names[state.ExerciseID],
+			TrainingMax:  unitWeight(state.TrainingMax, units),
+			Failures:     state.Failures,
+		})
+	}
+
+	response.JSONResponse(c, status, message, gin.H{
+		"enrollment":  details,
+		"adjustments": toTrainingMaxAdjustments(adjustments, units),
+	}, nil)
+}
+
+// toProgramEnrollmentSummaries returns where each enrollment is in its program, without the
+// training maxes.
+func toProgramEnrollmentSummaries(enrollments []db.ListProgramEnrollmentsRow) ([]ProgramEnrollmentDetails, error) {
+	details := make([]ProgramEnrollmentDetails, len(enrollments))
+	for i, e := range enrollments {
+		var state program.State
+		if err := json.Unmarshal(e.State, &state); err != nil {
+			return nil, err
+		}
+		details[i] = ProgramEnrollmentDetails{
+			ID:           e.ID,
+			ProgramID:    e.ProgramID,
+			ProgramName:  e.ProgramName,
+			Status:       string(e.Status),
+			Cycle:        state.Cycle + 1,
+			Week:         state.Week + 1,
+			Day:          state.Day + 1,
+			DayStartedAt: e.DayStartedAt.Time,
+			CreatedAt:    e.CreatedAt.Time,
+		}
+	}
+	return details, nil
+}
+
+func toTrainingProgramDetails(p db.TrainingProgram) (TrainingProgramDetails, error) {
+	details := TrainingProgramDetails{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: optionalText(p.Description),
+		Custom:      p.OwnerUserID.Valid,
+		CreatedAt:   p.CreatedAt.Time,
+	}
+	err := json.Unmarshal(p.Definition, &details.Definition)
+	return details, err
+}
+
+// toProgramWorkoutDetails prescribes the current day of an enrollment in the user's units.
+func toProgramWorkoutDetails(e *programEnrollment, units db.UnitSystem) (ProgramWorkoutDetails, error) {
+	names, err := exerciseNames(e.UserID)
+	if err != nil {
+		return ProgramWorkoutDetails{}, err
+	}
+
+	workout := program.Prescribe(e.Definition, e.State, func(kg float64) float64 {
+		return loadableWeight(kg, units)
+	})
+	details := ProgramWorkoutDetails{
+		Cycle:     workout.Cycle + 1,
+		Week:      workout.Week + 1,
+		Day:       workout.Day + 1,
+		Name:      workout.Name,
+		Unit:      string(units),
+		Exercises: make([]ProgramExerciseDetails, len(workout.Exercises)),
+	}
+	for i, exercise := range workout.Exercises {
+		sets := make([]ProgramSetDetails, len(exercise.Sets))
+		for j, set := range exercise.Sets {
+			sets[j] = ProgramSetDetails{Reps: set.Reps, Weight: unitWeight(set.WeightKg, units), AMRAP: set.AMRAP}
+		}
+		details.Exercises[i] = ProgramExerciseDetails{
+			Lift:         exercise.Lift,
+			ExerciseID:   exercise.ExerciseID,
+			ExerciseName: names[exercise.ExerciseID],
+			TrainingMax:  unitWeight(exercise.TrainingMaxKg, units),
+			Sets:         sets,
+		}
+	}
+	return details, nil
+}
+
+func toTrainingMaxAdjustments(adjustments []program.Adjustment, units db.UnitSystem) []TrainingMaxAdjustment {
+	converted := make([]TrainingMaxAdjustment, len(adjustments))
+	for i, adjustment := range adjustments {
+		converted[i] = TrainingMaxAdjustment{
+			Lift: adjustment.Lift,
+			Kind: adjustment.Kind,
+			From: unitWeight(adjustment.FromKg, units),
+			To:   unitWeight(adjustment.ToKg, units),
+		}
+	}
+	return converted
+}
+
+// programWorkoutName names a workout session after the program and its day.
+func programWorkoutName(programName string, workout program.Workout) string {
+	name := fmt.Sprintf("%s week %d day %d", programName, workout.Week+1, workout.Day+1)
+	if workout.Name != "" {
+		name = fmt.Sprintf("%s: %s", programName, workout.Name)
+	}
+	if len(name) > 100 {
+		return programName
+	}
+	return name
+}
+
+func exerciseNames(userID int32) (map[int32]string, error) {
+	exercises, err := queries.ListExercises(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int32]string, len(exercises))
+	for _, exercise := range exercises {
+		names[exercise.ID] = exercise.Name
+	}
+	return names, nil
+}
+
+// unitWeight converts a weight in kilograms to the user's units.
+func unitWeight(kg float64, units db.UnitSystem) float64 {
+	if units == db.UnitSystemImperial {
+		kg = conversion.KgToLbs(kg)
+	}
+	return roundTo(kg, 2)
+}
+
+// startOfDay truncates a time to midnight UTC.
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -16,16 +16,29 @@ func SessionHandler(c *gin.Context) {
 	avatarURL := c.GetString("avatar_url")
 	name := c.GetString("name")
 	isNewUser := c.GetBool("is_new_user")
+	pendingDeletion := c.GetBool("pending_deletion")
 
 	sessionData := map[string]interface{}{
-		"username":    username,
-		"email":       email,
-		"avatar_url":  avatarURL,
-		"name":        name,
-		"is_new_user": isNewUser,
+		"username":         username,
+		"email":            email,
+		"avatar_url":       avatarURL,
+		"name":             name,
+		"is_new_user":      isNewUser,
+		"pending_deletion": pendingDeletion,
 	}
 
 	userID := c.GetInt("userID")
+
+	// Let the client offer a restore while the account is inside its grace period
+	if pendingDeletion {
+		status, err := queries.GetUserDeletionStatus(c, int32(userID))
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch account status", nil, err)
+			return
+		}
+		sessionData["purge_after"] = status.PurgeAfter.Time
+	}
+
 	prefs, err := queries.GetUserPreferences(c, int32(userID))
 	if err != nil {
 		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferences", nil, err)
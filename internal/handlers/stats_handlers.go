@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/strength"
+)
+
+type StatsUnits struct {
+	Weight   string `json:"weight"`
+	Distance string `json:"distance"`
+	Pace     string `json:"pace"`
+}
+
+// GetExerciseStatsHandler returns the personal records and a summary of the user's sets of an
+// exercise, optionally limited to the YYYY-MM-DD dates from and to (inclusive).
+func GetExerciseStatsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	exerciseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid exercise ID", nil, err)
+		return
+	}
+	from, err := parseExportDate(c.Query("from"), 0)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD", nil, err)
+		return
+	}
+	to, err := parseExportDate(c.Query("to"), 1)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD", nil, err)
+		return
+	}
+
+	exercise, err := queries.GetAccessibleExercise(context.Background(), db.GetAccessibleExerciseParams{
+		ID:     int32(exerciseID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Exercise not found", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise", nil, err)
+		return
+	}
+
+	preferredUnits, err := queries.GetUserPreferredUnit(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+
+	logs, err := queries.GetExerciseLogsForStats(context.Background(), db.GetExerciseLogsForStatsParams{
+		UserID:     int32(userID),
+		ExerciseID: exercise.ID,
+		FromDate:   from,
+		ToDate:     to,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch exercise logs", nil, err)
+		return
+	}
+
+	sets := toStrengthSets(logs)
+	records := strength.PersonalRecords(exercise.MeasurementKind, sets)
+	summary := strength.Summarize(exercise.MeasurementKind, sets)
+
+	units := StatsUnits{Weight: "kg", Distance: "m", Pace: "s/km"}
+	if preferredUnits == db.UnitSystemImperial {
+		units = StatsUnits{Weight: "lbs", Distance: "yd", Pace: "s/mi"}
+		for i := range records {
+			records[i].Value = toImperialRecord(records[i])
+		}
+		summary.VolumeKg = conversion.KgToLbs(summary.VolumeKg)
+		summary.DistanceMeters = conversion.MetersToYards(summary.DistanceMeters)
+	}
+	for i := range records {
+		records[i].Value = roundTo(records[i].Value, 2)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{
+		"exercise": toExerciseDetails(exercise),
+		"units":    units,
+		"summary": gin.H{
+			"sets":             summary.Sets,
+			"total_reps":       summary.TotalReps,
+			"volume":           roundTo(summary.VolumeKg, 2),
+			"duration_seconds": summary.DurationSeconds,
+			"distance":         roundTo(summary.DistanceMeters, 2),
+		},
+		"records": records,
+	}, nil)
+}
+
+func toImperialRecord(record strength.Record) float64 {
+	switch record.Type {
+	case strength.RecordHeaviestWeight, strength.RecordEstimated1RM, strength.RecordBestSetVolume:
+		return conversion.KgToLbs(record.Value)
+	case strength.RecordLongestDistance:
+		return conversion.MetersToYards(record.Value)
+	case strength.RecordFastestPace:
+		// Seconds per kilometer to seconds per mile
+		return record.Value * conversion.MetersToKm(conversion.MilesToMeters(1))
+	}
+	return record.Value
+}
+
+// toStrengthSets prepares logged sets for record, summary and goal calculations.
+func toStrengthSets(logs []db.GetExerciseLogsForStatsRow) []strength.Set {
+	sets := make([]strength.Set, len(logs))
+	for i, l := range logs {
+		sets[i] = strength.Set{
+			Date:           l.LogDate.Time,
+			Reps:           l.Reps,
+			LoadKg:         strength.Load(numericToFloat(l.Weight), numericToFloat(l.AdditionalWeight), l.ExerciseType, numericToFloat(l.Bodyweight)),
+			DistanceMeters: optionalNumeric(l.DistanceMeters),
+			RPE:            optionalNumeric(l.Rpe),
+		}
+		if l.DurationSeconds.Valid {
+			duration := l.DurationSeconds.Int32
+			sets[i].DurationSeconds = &duration
+		}
+	}
+	return sets
+}
+
+// loggedSet identifies a set just logged, logs are unique per exercise and time.
+type loggedSet struct {
+	ExerciseID int32
+	LogDate    time.Time
+}
+
+// checkPersonalRecords emits a PRAchieved event for each logged set that beat the earlier sets
+// of its exercise, in one transaction.
+func checkPersonalRecords(userID int32, logged []loggedSet) error {
+	ctx := context.Background()
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	var exerciseIDs []int32
+	dates := make(map[int32][]time.Time)
+	for _, set := range logged {
+		if _, ok := dates[set.ExerciseID]; !ok {
+			exerciseIDs = append(exerciseIDs, set.ExerciseID)
+		}
+		dates[set.ExerciseID] = append(dates[set.ExerciseID], set.LogDate)
+	}
+
+	for _, exerciseID := range exerciseIDs {
+		exercise, err := qtx.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{ID: exerciseID, UserID: userID})
+		if err != nil {
+			return fmt.Errorf("failed to fetch exercise %d: %w", exerciseID, err)
+		}
+		logs, err := qtx.GetExerciseLogsForStats(ctx, db.GetExerciseLogsForStatsParams{UserID: userID, ExerciseID: exerciseID})
+		if err != nil {
+			return fmt.Errorf("failed to fetch logs of exercise %d: %w", exerciseID, err)
+		}
+		sets := toStrengthSets(logs)
+
+		for _, date := range dates[exerciseID] {
+			improved := strength.ImprovedRecords(exercise.MeasurementKind, sets, date)
+			if len(improved) == 0 {
+				continue
+			}
+			logID, err := qtx.GetExerciseLogID(ctx, db.GetExerciseLogIDParams{
+				UserID:     userID,
+				ExerciseID: exerciseID,
+				LogDate:    pgtype.Timestamptz{Time: date, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to fetch log of exercise %d: %w", exerciseID, err)
+			}
+
+			records := make([]events.PRAchievedRecord, len(improved))
+			for i, record := range improved {
+				records[i] = events.PRAchievedRecord{Type: record.Type, Value: roundTo(record.Value, 2), Previous: roundTo(record.Previous, 2)}
+			}
+			err = events.Emit(ctx, qtx, userID, events.PRAchieved, events.PRAchievedPayload{
+				ExerciseLogID: logID,
+				ExerciseID:    exerciseID,
+				Exercise:      exercise.Name,
+				Records:       records,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record personal record: %w", err)
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func numericToFloat(n pgtype.Numeric) float64 {
+	f, err := n.Float64Value()
+	if err != nil || !f.Valid {
+		return 0
+	}
+	return f.Float64
+}
+
+func optionalNumeric(n pgtype.Numeric) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	f := numericToFloat(n)
+	return &f
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
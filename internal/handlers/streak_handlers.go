@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/streaks"
+)
+
+// heatmapLevels is the number of intensity levels above an empty day in the calendar.
+const heatmapLevels = 4
+
+type StreakTargetRequest struct {
+	DaysPerWeek int `json:"days_per_week"`
+}
+
+type CalendarDay struct {
+	Date     time.Time `json:"date"`
+	Sessions int64     `json:"sessions"` // Completed workouts
+	Sets     int64     `json:"sets"`
+	Tonnage  float64   `json:"tonnage"`
+	Level    int       `json:"level"` // 0 for a rest day up to 4 for the most sets of the year
+}
+
+type TrainingCalendar struct {
+	Year         int           `json:"year"`
+	Unit         string        `json:"unit"`
+	TrainingDays int           `json:"training_days"`
+	Sessions     int64         `json:"sessions"`
+	Sets         int64         `json:"sets"`
+	Tonnage      float64       `json:"tonnage"`
+	Days         []CalendarDay `json:"days"` // Every day of the year up to today
+}
+
+type StreakDetails struct {
+	TargetDaysPerWeek int                      `json:"target_days_per_week"`
+	CurrentWeeks      int                      `json:"current_weeks"`
+	LongestWeeks      int                      `json:"longest_weeks"`
+	CurrentWeekDays   int                      `json:"current_week_days"`
+	ConsistencyScore  float64                  `json:"consistency_score"` // Percent of the target reached over the last 12 weeks
+	Milestones        []StreakMilestoneDetails `json:"milestones"`
+}
+
+type StreakMilestoneDetails struct {
+	Weeks    int    `json:"weeks"`
+	TrophyID *int32 `json:"trophy_id"`
+	Trophy   string `json:"trophy"`
+	Unlocked bool   `json:"unlocked"` // The trophy can be displayed
+}
+
+// GetTrainingCalendarHandler returns the training activity of every day of a year for a
+// heatmap, the current year by default.
+func GetTrainingCalendarHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	today := startOfDay(time.Now())
+	year := today.Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1970 || parsed > today.Year() {
+			response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Year must be between 1970 and %d", today.Year()), nil, err)
+			return
+		}
+		year = parsed
+	}
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	activity, err := queries.GetTrainingCalendar(ctx, db.GetTrainingCalendarParams{
+		UserID:   int32(userID),
+		FromDate: pgtype.Timestamptz{Time: from, Valid: true},
+		ToDate:   pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch training calendar", nil, err)
+		return
+	}
+
+	calendar := TrainingCalendar{Year: year, Unit: string(units), Days: []CalendarDay{}}
+	byDay := make(map[time.Time]db.GetTrainingCalendarRow, len(activity))
+	var mostSets int64
+	for _, day := range activity {
+		byDay[day.Day.Time] = day
+		mostSets = max(mostSets, day.Sets)
+	}
+	for date := from; date.Before(to) && !date.After(today); date = date.AddDate(0, 0, 1) {
+		day := CalendarDay{Date: date}
+		if a, ok := byDay[date]; ok {
+			day.Sessions = a.Sessions
+			day.Sets = a.Sets
+			day.Tonnage = unitWeight(numericToFloat(a.Tonnage), units)
+			day.Level = heatmapLevel(a.Sets, a.Sessions, mostSets)
+			calendar.TrainingDays++
+			calendar.Sessions += a.Sessions
+			calendar.Sets += a.Sets
+			calendar.Tonnage += numericToFloat(a.Tonnage)
+		}
+		calendar.Days = append(calendar.Days, day)
+	}
+	calendar.Tonnage = unitWeight(calendar.Tonnage, units)
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"calendar": calendar}, nil)
+}
+
+// GetStreaksHandler returns the user's weekly streaks, consistency score and streak trophies.
+func GetStreaksHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	s, err := userStreaks(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to compute streaks", nil, err)
+		return
+	}
+	trophies, err := queries.ListTrophies(context.Background())
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch trophies", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"streaks": toStreakDetails(s, trophies)}, nil)
+}
+
+// UpdateStreakTargetHandler sets how many days a week count towards a streak.
+func UpdateStreakTargetHandler(c *gin.Context) {
+	var req StreakTargetRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	if req.DaysPerWeek < streaks.MinTarget || req.DaysPerWeek > streaks.MaxTarget {
+		response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Days per week must be between %d and %d", streaks.MinTarget, streaks.MaxTarget), nil, nil)
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	err := queries.UpsertStreakTarget(ctx, db.UpsertStreakTargetParams{UserID: int32(userID), TargetDaysPerWeek: int16(req.DaysPerWeek)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save streak target", nil, err)
+		return
+	}
+
+	s, err := userStreaks(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to compute streaks", nil, err)
+		return
+	}
+	trophies, err := queries.ListTrophies(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch trophies", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Streak target saved", gin.H{"streaks": toStreakDetails(s, trophies)}, nil)
+}
+
+// userStreaks computes the streaks of a user against their target, the default target when
+// they have not set one.
+func userStreaks(ctx context.Context, userID int32) (streaks.Streaks, error) {
+	target, err := queries.GetStreakTarget(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		target, err = streaks.DefaultTarget, nil
+	}
+	if err != nil {
+		return streaks.Streaks{}, err
+	}
+
+	rows, err := queries.GetWeeklyTrainingDays(ctx, userID)
+	if err != nil {
+		return streaks.Streaks{}, err
+	}
+	weeks := make([]streaks.Week, len(rows))
+	for i, row := range rows {
+		weeks[i] = streaks.Week{Start: row.WeekStart.Time, Days: int(row.TrainingDays)}
+	}
+	return streaks.Compute(weeks, int(target), time.Now()), nil
+}
+
+func toStreakDetails(s streaks.Streaks, trophies []db.ListTrophiesRow) StreakDetails {
+	details := StreakDetails{
+		TargetDaysPerWeek: s.Target,
+		CurrentWeeks:      s.Current,
+		LongestWeeks:      s.Longest,
+		CurrentWeekDays:   s.CurrentWeekDays,
+		ConsistencyScore:  s.Consistency,
+		Milestones:        make([]StreakMilestoneDetails, len(streaks.Milestones)),
+	}
+	for i, milestone := range streaks.Milestones {
+		details.Milestones[i] = StreakMilestoneDetails{
+			Weeks:    milestone.Weeks,
+			Trophy:   milestone.Trophy,
+			Unlocked: s.Longest >= milestone.Weeks,
+		}
+		for _, trophy := range trophies {
+			if trophy.Name == milestone.Trophy {
+				details.Milestones[i].TrophyID = &trophy.ID
+				break
+			}
+		}
+	}
+	return details
+}
+
+// heatmapLevel scales the sets of a day to the most sets of the year. Days with only a
+// completed workout still get the lowest level.
+func heatmapLevel(sets int64, sessions int64, mostSets int64) int {
+	if sets == 0 || mostSets == 0 {
+		if sessions > 0 {
+			return 1
+		}
+		return 0
+	}
+	return max(1, int(math.Ceil(float64(sets)/float64(mostSets)*heatmapLevels)))
+}
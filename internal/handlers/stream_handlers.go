@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"net/http"
+	"time"
+	"new-chainsaw/internal/config"
+	"new-chainsaw/internal/realtime"
+	"new-chainsaw/internal/response"
+)
+
+// Event names of the stream besides the event types of internal/events
+const (
+	streamEventReady        = "ready"
+	streamEventNotification = "notification"
+)
+
+type EventDetails struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"` // See internal/events
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// StreamEventsHandler streams the user's updates as Server-Sent Events: a ready event with the
+// unread count, then every event named after its type, such as trophy.unlocked and pr.achieved,
+// and every new notification named notification. Updates missed while disconnected are not
+// replayed, clients refresh when ready is received again. The stream closes when the access
+// token expires or the account enters pending deletion, reconnecting authenticates again.
+func StreamEventsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	unread, err := queries.CountUnreadNotifications(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to count unread notifications", nil, err)
+		return
+	}
+
+	messages, unsubscribe := streams.Subscribe(int32(userID))
+	defer unsubscribe()
+
+	// The stream outlives the write timeout of the server
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Failed to clear the write deadline of an event stream: %v\n", err)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.SSEvent(streamEventReady, gin.H{"unread_count": unread})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(config.EventStreamHeartbeat)
+	defer heartbeat.Stop()
+	expiry := time.NewTimer(time.Until(c.GetTime("token_expires_at")))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-expiry.C:
+			return
+		case msg := <-messages:
+			c.SSEvent(msg.Event, msg.Data)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if !streamAccountActive(c.Request.Context(), int32(userID)) {
+				return
+			}
+			// A comment keeps proxies from closing an idle stream
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// streamAccountActive reports whether a stream may stay open. Accounts entering pending deletion
+// or purged lose their streams, a failed check keeps them open until the next heartbeat.
+func streamAccountActive(ctx context.Context, userID int32) bool {
+	status, err := queries.GetUserDeletionStatus(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to check the account of user %d for an event stream: %v\n", userID, err)
+		return true
+	}
+	return !status.DeletedAt.Valid
+}
+
+// PublishAnnouncement streams a new event or notification to the user's streams open on this
+// server.
+func PublishAnnouncement(ctx context.Context, announcement realtime.Announcement) {
+	if !streams.HasSubscribers(announcement.UserID) {
+		return
+	}
+
+	var msg realtime.Message
+	switch announcement.Table {
+	case "events":
+		event, err := queries.GetEvent(ctx, announcement.ID)
+		if err != nil {
+			logAnnouncementError(announcement, err)
+			return
+		}
+		msg = realtime.Message{Event: event.Type, Data: EventDetails{
+			ID:        event.ID,
+			Type:      event.Type,
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt.Time,
+		}}
+	case "notifications":
+		notification, err := queries.GetNotification(ctx, announcement.ID)
+		if err != nil {
+			logAnnouncementError(announcement, err)
+			return
+		}
+		unread, err := queries.CountUnreadNotifications(ctx, announcement.UserID)
+		if err != nil {
+			logAnnouncementError(announcement, err)
+			return
+		}
+		msg = realtime.Message{Event: streamEventNotification, Data: gin.H{
+			"notification": toNotificationDetails(notification),
+			"unread_count": unread,
+		}}
+	default:
+		return
+	}
+	streams.Publish(announcement.UserID, msg)
+}
+
+func logAnnouncementError(announcement realtime.Announcement, err error) {
+	// Rows deleted since they were announced are not an error
+	if !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Failed to publish %s: %v\n", announcement, err)
+	}
+}
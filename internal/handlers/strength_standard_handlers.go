@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/standards"
+)
+
+type StrengthStandardDetails struct {
+	ExerciseID      int32              `json:"exercise_id"`
+	Exercise        string             `json:"exercise"`
+	Version         string             `json:"version"` // Of the standards
+	Unit            string             `json:"unit"`
+	OneRepMax       float64            `json:"one_rep_max"` // Estimated from the best set relative to bodyweight
+	Bodyweight      float64            `json:"bodyweight"`  // Logged with the best set
+	BodyweightClass float64            `json:"bodyweight_class"`
+	Date            time.Time          `json:"date"`
+	Level           string             `json:"level"`
+	NextLevel       *string            `json:"next_level"`
+	NextLevelAt     *float64           `json:"next_level_at"` // One rep max of the next level
+	Standards       map[string]float64 `json:"standards"`     // One rep max of each level
+	Percentile      *float64           `json:"percentile"`    // Share of lifters of the same sex with a weaker lift, nil with too few
+	Population      int64              `json:"population"`
+}
+
+// UserProfile is a profile with the strength standards of the user's lifts.
+type UserProfile struct {
+	db.GetUserProfileByIDRow
+	StrengthStandards []StrengthStandardDetails `json:"strength_standards"`
+}
+
+type PublicUserProfile struct {
+	db.GetUserProfileByUsernameRow
+	StrengthStandards []StrengthStandardDetails `json:"strength_standards"`
+}
+
+// userStrengthStandards classifies the user's best lifts of the exercises with standards and
+// ranks them against the other users, as of the last refresh-strength-standings run. Standards
+// depend on sex, so there are none without it.
+func userStrengthStandards(ctx context.Context, userID int32, sex pgtype.Text, units db.UnitSystem) ([]StrengthStandardDetails, error) {
+	details := []StrengthStandardDetails{}
+	if !sex.Valid {
+		return details, nil
+	}
+
+	rows, err := queries.ListCurrentStrengthStandards(ctx, sex.String)
+	if err != nil {
+		return nil, err
+	}
+	version := ""
+	byExercise := make(map[int32][]standards.Standard)
+	for _, row := range rows {
+		version = row.Version
+		byExercise[row.ExerciseID] = append(byExercise[row.ExerciseID], standards.Standard{
+			BodyweightClassKg: numericToFloat(row.BodyweightClass),
+			ThresholdsKg: [5]float64{
+				numericToFloat(row.Beginner),
+				numericToFloat(row.Novice),
+				numericToFloat(row.Intermediate),
+				numericToFloat(row.Advanced),
+				numericToFloat(row.Elite),
+			},
+		})
+	}
+
+	standings, err := queries.GetStrengthStandings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, standing := range standings {
+		oneRepMax := numericToFloat(standing.OneRepMax)
+		bodyweight := numericToFloat(standing.Bodyweight)
+		classification, ok := standards.Classify(byExercise[standing.ExerciseID], bodyweight, oneRepMax)
+		if !ok {
+			continue
+		}
+
+		detail := StrengthStandardDetails{
+			ExerciseID:      standing.ExerciseID,
+			Exercise:        standing.ExerciseName,
+			Version:         version,
+			Unit:            weightUnit(units),
+			OneRepMax:       unitWeight(oneRepMax, units),
+			Bodyweight:      unitWeight(bodyweight, units),
+			BodyweightClass: unitWeight(classification.BodyweightClassKg, units),
+			Date:            standing.LogDate.Time,
+			Level:           string(classification.Level),
+			Standards:       make(map[string]float64, len(classification.Thresholds)),
+			Population:      standing.Population,
+		}
+		if classification.NextLevel != nil {
+			next := string(*classification.NextLevel)
+			nextAt := unitWeight(*classification.NextKg, units)
+			detail.NextLevel = &next
+			detail.NextLevelAt = &nextAt
+		}
+		for level, threshold := range classification.Thresholds {
+			detail.Standards[string(level)] = unitWeight(threshold, units)
+		}
+		if standing.Population >= standards.MinPopulation {
+			percentile := roundTo(standing.Percentile, 1)
+			detail.Percentile = &percentile
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
@@ -0,0 +1,711 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/plates"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/strength"
+	"new-chainsaw/internal/templates"
+	"new-chainsaw/internal/validation"
+)
+
+type TemplateExerciseRequest struct {
+	ExerciseID       int32    `json:"exercise_id"`
+	TargetSets       int32    `json:"target_sets"`
+	TargetReps       *int32   `json:"target_reps"`
+	TargetWeight     *float64 `json:"target_weight"`
+	TargetPercentage *float64 `json:"target_percentage"` // Percent of the estimated one rep max
+	RestSeconds      *int32   `json:"rest_seconds"`
+	Notes            string   `json:"notes"`
+}
+
+type WorkoutTemplateRequest struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Unit        string                    `json:"unit"` // Unit of the target weights, metric or imperial
+	Exercises   []TemplateExerciseRequest `json:"exercises"`
+}
+
+type TemplateExerciseDetails struct {
+	Position         int32    `json:"position"`
+	ExerciseID       int32    `json:"exercise_id"`
+	ExerciseName     string   `json:"exercise_name"`
+	TargetSets       int32    `json:"target_sets"`
+	TargetReps       *int32   `json:"target_reps"`
+	TargetWeight     *float64 `json:"target_weight"`
+	TargetPercentage *float64 `json:"target_percentage"`
+	RestSeconds      *int32   `json:"rest_seconds"`
+	Notes            *string  `json:"notes"`
+}
+
+type WorkoutTemplateDetails struct {
+	ID           int32                     `json:"id"`
+	Name         string                    `json:"name"`
+	Description  *string                   `json:"description"`
+	ShareCode    *string                   `json:"share_code,omitempty"`
+	ClonedFromID *int32                    `json:"cloned_from_id"`
+	Unit         string                    `json:"unit"`
+	Exercises    []TemplateExerciseDetails `json:"exercises"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+func ListWorkoutTemplatesHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	units, err := queries.GetUserPreferredUnit(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+
+	templates, err := queries.ListWorkoutTemplates(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch templates", nil, err)
+		return
+	}
+
+	details := make([]WorkoutTemplateDetails, len(templates))
+	for i, template := range templates {
+		details[i], err = loadWorkoutTemplateDetails(template, units)
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch template exercises", nil, err)
+			return
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"templates": details}, nil)
+}
+
+func GetWorkoutTemplateHandler(c *gin.Context) {
+	template, ok := fetchWorkoutTemplate(c)
+	if !ok {
+		return
+	}
+	respondWithWorkoutTemplate(c, http.StatusOK, "", template)
+}
+
+func CreateWorkoutTemplateHandler(c *gin.Context) {
+	var req WorkoutTemplateRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	if err := validateWorkoutTemplate(int32(userID), &req); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	ctx := context.Background()
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create template", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	template, err := qtx.CreateWorkoutTemplate(ctx, db.CreateWorkoutTemplateParams{
+		UserID:      int32(userID),
+		Name:        strings.TrimSpace(req.Name),
+		Description: pgtype.Text{String: req.Description, Valid: req.Description != ""},
+	})
+	if err == nil {
+		err = addTemplateExercises(ctx, qtx, template.ID, req)
+	}
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create template", nil, err)
+		return
+	}
+
+	respondWithWorkoutTemplate(c, http.StatusCreated, "Template created", template)
+}
+
+// UpdateWorkoutTemplateHandler replaces the name, description and exercises of a template.
+func UpdateWorkoutTemplateHandler(c *gin.Context) {
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid template ID", nil, err)
+		return
+	}
+
+	var req WorkoutTemplateRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	if err := validateWorkoutTemplate(int32(userID), &req); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	ctx := context.Background()
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update template", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	template, err := qtx.UpdateWorkoutTemplate(ctx, db.UpdateWorkoutTemplateParams{
+		ID:          int32(templateID),
+		UserID:      int32(userID),
+		Name:        strings.TrimSpace(req.Name),
+		Description: pgtype.Text{String: req.Description, Valid: req.Description != ""},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Template not found", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update template", nil, err)
+		return
+	}
+
+	err = qtx.DeleteWorkoutTemplateExercises(ctx, template.ID)
+	if err == nil {
+		err = addTemplateExercises(ctx, qtx, template.ID, req)
+	}
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update template", nil, err)
+		return
+	}
+
+	respondWithWorkoutTemplate(c, http.StatusOK, "Template updated", template)
+}
+
+func DeleteWorkoutTemplateHandler(c *gin.Context) {
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid template ID", nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	deleted, err := queries.DeleteWorkoutTemplate(context.Background(), db.DeleteWorkoutTemplateParams{
+		ID:     int32(templateID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete template", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "Template not found", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Template deleted", nil, nil)
+}
+
+func CloneWorkoutTemplateHandler(c *gin.Context) {
+	template, ok := fetchWorkoutTemplate(c)
+	if !ok {
+		return
+	}
+	cloneWorkoutTemplate(c, template)
+}
+
+// ShareWorkoutTemplateHandler creates a share code other users can view and clone the template
+// with. Sharing an already shared template keeps its code.
+func ShareWorkoutTemplateHandler(c *gin.Context) {
+	template, ok := fetchWorkoutTemplate(c)
+	if !ok {
+		return
+	}
+
+	if !template.ShareCode.Valid {
+		code := make([]byte, 12)
+		if _, err := rand.Read(code); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to share template", nil, err)
+			return
+		}
+
+		var err error
+		template, err = queries.SetWorkoutTemplateShareCode(context.Background(), db.SetWorkoutTemplateShareCodeParams{
+			ShareCode: pgtype.Text{String: hex.EncodeToString(code), Valid: true},
+			ID:        template.ID,
+			UserID:    template.UserID,
+		})
+		if err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to share template", nil, err)
+			return
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Template shared", gin.H{"share_code": template.ShareCode.String}, nil)
+}
+
+// UnshareWorkoutTemplateHandler revokes the share code of a template. Existing clones are kept.
+func UnshareWorkoutTemplateHandler(c *gin.Context) {
+	template, ok := fetchWorkoutTemplate(c)
+	if !ok {
+		return
+	}
+
+	_, err := queries.SetWorkoutTemplateShareCode(context.Background(), db.SetWorkoutTemplateShareCodeParams{
+		ID:     template.ID,
+		UserID: template.UserID,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to stop sharing template", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Template is no longer shared", nil, nil)
+}
+
+func GetSharedWorkoutTemplateHandler(c *gin.Context) {
+	template, ok := fetchSharedWorkoutTemplate(c)
+	if !ok {
+		return
+	}
+	respondWithWorkoutTemplate(c, http.StatusOK, "", template)
+}
+
+func CloneSharedWorkoutTemplateHandler(c *gin.Context) {
+	template, ok := fetchSharedWorkoutTemplate(c)
+	if !ok {
+		return
+	}
+	cloneWorkoutTemplate(c, template)
+}
+
+// cloneWorkoutTemplate copies a template into the user's templates. Custom exercises the user
+// has no access to are left out of the copy and reported as skipped.
+func cloneWorkoutTemplate(c *gin.Context, source db.WorkoutTemplate) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to clone template", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	template, skipped, err := copyWorkoutTemplate(ctx, qtx, source, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to clone template", nil, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to clone template", nil, err)
+		return
+	}
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	details, err := loadWorkoutTemplateDetails(template, units)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch template exercises", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Template cloned", gin.H{"template": details, "skipped_exercises": skipped}, nil)
+}
+
+// copyWorkoutTemplate copies a template into the templates of a user, returning the names of
+// the custom exercises left out because the user has no access to them.
+func copyWorkoutTemplate(ctx context.Context, qtx *db.Queries, source db.WorkoutTemplate, userID int32) (db.WorkoutTemplate, []string, error) {
+	exercises, err := qtx.ListWorkoutTemplateExercises(ctx, source.ID)
+	if err != nil {
+		return db.WorkoutTemplate{}, nil, err
+	}
+
+	name := source.Name
+	if source.UserID == userID {
+		name = fmt.Sprintf("%s (copy)", name)
+		if len(name) > 100 {
+			name = source.Name
+		}
+	}
+	template, err := qtx.CreateWorkoutTemplate(ctx, db.CreateWorkoutTemplateParams{
+		UserID:       userID,
+		Name:         name,
+		Description:  source.Description,
+		ClonedFromID: pgtype.Int4{Int32: source.ID, Valid: true},
+	})
+	if err != nil {
+		return db.WorkoutTemplate{}, nil, err
+	}
+
+	skipped := []string{}
+	position := int32(0)
+	for _, exercise := range exercises {
+		_, err := qtx.GetAccessibleExercise(ctx, db.GetAccessibleExerciseParams{
+			ID:     exercise.ExerciseID,
+			UserID: userID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			skipped = append(skipped, exercise.ExerciseName)
+			continue
+		}
+		if err != nil {
+			return db.WorkoutTemplate{}, nil, err
+		}
+
+		position++
+		err = qtx.AddWorkoutTemplateExercise(ctx, db.AddWorkoutTemplateExerciseParams{
+			TemplateID:       template.ID,
+			Position:         position,
+			ExerciseID:       exercise.ExerciseID,
+			TargetSets:       exercise.TargetSets,
+			TargetReps:       exercise.TargetReps,
+			TargetWeight:     exercise.TargetWeight,
+			TargetPercentage: exercise.TargetPercentage,
+			RestSeconds:      exercise.RestSeconds,
+			Notes:            exercise.Notes,
+		})
+		if err != nil {
+			return db.WorkoutTemplate{}, nil, err
+		}
+	}
+	return template, skipped, nil
+}
+
+// StartWorkoutTemplateHandler starts a workout session with the sets of a template. Target
+// weights are filled in from the template, percentages are taken of the estimated one rep max
+// of the latest logged set, and exercises without a target use the latest logged numbers.
+func StartWorkoutTemplateHandler(c *gin.Context) {
+	template, ok := fetchWorkoutTemplate(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	userID := template.UserID
+
+	exercises, err := queries.ListWorkoutTemplateExercises(ctx, template.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch template exercises", nil, err)
+		return
+	}
+	latestLogs, err := queries.GetExercisesWithLatestLogDate(ctx, userID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch latest logs", nil, err)
+		return
+	}
+	latest := make(map[int32]db.GetExercisesWithLatestLogDateRow, len(latestLogs))
+	for _, l := range latestLogs {
+		latest[l.ID] = l
+	}
+	units, err := queries.GetUserPreferredUnit(ctx, userID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	session, err := qtx.CreateWorkoutSession(ctx, db.CreateWorkoutSessionParams{
+		UserID:     userID,
+		TemplateID: pgtype.Int4{Int32: template.ID, Valid: true},
+		Name:       template.Name,
+		StartedAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+		return
+	}
+
+	for _, exercise := range exercises {
+		latestLog, hasLatest := latest[exercise.ExerciseID]
+		targetReps, targetWeight := prescribeTemplateExercise(exercise, latestLog, hasLatest, units)
+
+		for setNumber := int32(1); setNumber <= exercise.TargetSets; setNumber++ {
+			err := qtx.AddWorkoutSessionSet(ctx, db.AddWorkoutSessionSetParams{
+				SessionID:    session.ID,
+				ExerciseID:   exercise.ExerciseID,
+				Position:     exercise.Position,
+				SetNumber:    setNumber,
+				TargetReps:   targetReps,
+				TargetWeight: targetWeight,
+				RestSeconds:  exercise.RestSeconds,
+			})
+			if err != nil {
+				response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to start workout", nil, err)
+		return
+	}
+
+	respondWithWorkout(c, http.StatusCreated, "Workout started", session)
+}
+
+// prescribeTemplateExercise returns the target reps and weight in kilograms of the sets of a
+// template exercise, see templates.Prescribe.
+func prescribeTemplateExercise(exercise db.ListWorkoutTemplateExercisesRow, latest db.GetExercisesWithLatestLogDateRow, hasLatest bool, units db.UnitSystem) (pgtype.Int4, pgtype.Numeric) {
+	target := templates.Target{
+		WeightKg:   optionalNumeric(exercise.TargetWeight),
+		Percentage: optionalNumeric(exercise.TargetPercentage),
+	}
+	if exercise.TargetReps.Valid {
+		target.Reps = &exercise.TargetReps.Int32
+	}
+	var latestSet *templates.LatestSet
+	if hasLatest {
+		latestSet = &templates.LatestSet{Reps: latest.Reps, WeightKg: numericToFloat(latest.Weight), RPE: optionalNumeric(latest.Rpe)}
+	}
+
+	reps, weight := templates.Prescribe(target, latestSet, func(kg float64) float64 { return loadableWeight(kg, units) })
+	targetReps := optionalInt4(reps)
+	if weight == nil {
+		return targetReps, pgtype.Numeric{}
+	}
+	return targetReps, conversion.ToNumeric(*weight)
+}
+
+// latestOneRepMax estimates the one rep max in kilograms from the latest logged set of an exercise.
+func latestOneRepMax(latest db.GetExercisesWithLatestLogDateRow) float64 {
+	if latest.Rpe.Valid {
+		return strength.EstimatedOneRepMaxAtRPE(numericToFloat(latest.Weight), latest.Reps, numericToFloat(latest.Rpe))
+	}
+	return strength.EstimatedOneRepMax(numericToFloat(latest.Weight), latest.Reps)
+}
+
+// loadableWeight rounds a weight in kilograms to the nearest 2.5 kg, or 5 lbs for imperial users.
+func loadableWeight(kg float64, units db.UnitSystem) float64 {
+	return plates.LoadableWeight(kg, units == db.UnitSystemImperial)
+}
+
+func validateWorkoutTemplate(userID int32, req *WorkoutTemplateRequest) error {
+	if err := validation.ValidateTemplateName(req.Name); err != nil {
+		return err
+	}
+	req.Description = strings.TrimSpace(req.Description)
+	if err := validation.ValidateTemplateExerciseCount(len(req.Exercises)); err != nil {
+		return err
+	}
+
+	exercises := make(map[int32]db.Exercise)
+	for i := range req.Exercises {
+		exercise := &req.Exercises[i]
+		exercise.Notes = strings.TrimSpace(exercise.Notes)
+
+		if err := validation.ValidateTargetSets(exercise.TargetSets); err != nil {
+			return err
+		}
+		if err := validation.ValidateTargetReps(exercise.TargetReps); err != nil {
+			return err
+		}
+		if err := validation.ValidateTargetLoad(exercise.TargetWeight, exercise.TargetPercentage); err != nil {
+			return err
+		}
+		if err := validation.ValidateRestSeconds(exercise.RestSeconds); err != nil {
+			return err
+		}
+		if err := validation.ValidateSetNotes(exercise.Notes); err != nil {
+			return err
+		}
+
+		if _, ok := exercises[exercise.ExerciseID]; ok {
+			continue
+		}
+		details, err := queries.GetAccessibleExercise(context.Background(), db.GetAccessibleExerciseParams{
+			ID:     exercise.ExerciseID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("unknown exercise %d", exercise.ExerciseID)
+			}
+			return err
+		}
+		if details.MeasurementKind != db.MeasurementKindRepsWeight {
+			return fmt.Errorf("%s is not measured in reps and weight and cannot be added to a template", details.Name)
+		}
+		exercises[exercise.ExerciseID] = details
+	}
+	return nil
+}
+
+// addTemplateExercises stores the exercises of a template in request order.
+func addTemplateExercises(ctx context.Context, qtx *db.Queries, templateID int32, req WorkoutTemplateRequest) error {
+	for i, exercise := range req.Exercises {
+		targetWeight := pgtype.Numeric{}
+		if exercise.TargetWeight != nil {
+			targetWeight = conversion.ToNumeric(calculateWeight(*exercise.TargetWeight, req.Unit))
+		}
+		targetPercentage := pgtype.Numeric{}
+		if exercise.TargetPercentage != nil {
+			targetPercentage = conversion.ToNumeric(*exercise.TargetPercentage)
+		}
+		err := qtx.AddWorkoutTemplateExercise(ctx, db.AddWorkoutTemplateExerciseParams{
+			TemplateID:       templateID,
+			Position:         int32(i + 1),
+			ExerciseID:       exercise.ExerciseID,
+			TargetSets:       exercise.TargetSets,
+			TargetReps:       optionalInt4(exercise.TargetReps),
+			TargetWeight:     targetWeight,
+			TargetPercentage: targetPercentage,
+			RestSeconds:      optionalInt4(exercise.RestSeconds),
+			Notes:            pgtype.Text{String: exercise.Notes, Valid: exercise.Notes != ""},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fetchWorkoutTemplate(c *gin.Context) (db.WorkoutTemplate, bool) {
+	userID := c.GetInt("userID")
+
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid template ID", nil, err)
+		return db.WorkoutTemplate{}, false
+	}
+
+	template, err := queries.GetWorkoutTemplate(context.Background(), db.GetWorkoutTemplateParams{
+		ID:     int32(templateID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Template not found", nil, err)
+			return db.WorkoutTemplate{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch template", nil, err)
+		return db.WorkoutTemplate{}, false
+	}
+	return template, true
+}
+
+func fetchSharedWorkoutTemplate(c *gin.Context) (db.WorkoutTemplate, bool) {
+	template, err := queries.GetSharedWorkoutTemplate(context.Background(), pgtype.Text{String: c.Param("code"), Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Shared template not found", nil, err)
+			return db.WorkoutTemplate{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch template", nil, err)
+		return db.WorkoutTemplate{}, false
+	}
+	return template, true
+}
+
+func respondWithWorkoutTemplate(c *gin.Context, status int, message string, template db.WorkoutTemplate) {
+	userID := c.GetInt("userID")
+
+	units, err := queries.GetUserPreferredUnit(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+
+	details, err := loadWorkoutTemplateDetails(template, units)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch template exercises", nil, err)
+		return
+	}
+	// Only the owner gets to see the share code
+	if template.UserID != int32(userID) {
+		details.ShareCode = nil
+	}
+
+	response.JSONResponse(c, status, message, gin.H{"template": details}, nil)
+}
+
+// loadWorkoutTemplateDetails fetches the exercises of a template, with weights in the given units.
+func loadWorkoutTemplateDetails(template db.WorkoutTemplate, units db.UnitSystem) (WorkoutTemplateDetails, error) {
+	exercises, err := queries.ListWorkoutTemplateExercises(context.Background(), template.ID)
+	if err != nil {
+		return WorkoutTemplateDetails{}, err
+	}
+
+	details := WorkoutTemplateDetails{
+		ID:          template.ID,
+		Name:        template.Name,
+		Description: optionalText(template.Description),
+		ShareCode:   optionalText(template.ShareCode),
+		Unit:        string(units),
+		Exercises:   make([]TemplateExerciseDetails, len(exercises)),
+		CreatedAt:   template.CreatedAt.Time,
+		UpdatedAt:   template.UpdatedAt.Time,
+	}
+	if template.ClonedFromID.Valid {
+		details.ClonedFromID = &template.ClonedFromID.Int32
+	}
+	for i, exercise := range exercises {
+		details.Exercises[i] = TemplateExerciseDetails{
+			Position:         exercise.Position,
+			ExerciseID:       exercise.ExerciseID,
+			ExerciseName:     exercise.ExerciseName,
+			TargetSets:       exercise.TargetSets,
+			TargetReps:       optionalInt32(exercise.TargetReps),
+			TargetWeight:     displayWeight(exercise.TargetWeight, units),
+			TargetPercentage: optionalNumeric(exercise.TargetPercentage),
+			RestSeconds:      optionalInt32(exercise.RestSeconds),
+			Notes:            optionalText(exercise.Notes),
+		}
+	}
+	return details, nil
+}
+
+// displayWeight converts a weight stored in kilograms to the user's units.
+func displayWeight(kg pgtype.Numeric, units db.UnitSystem) *float64 {
+	weight := optionalNumeric(kg)
+	if weight == nil {
+		return nil
+	}
+	if units == db.UnitSystemImperial {
+		*weight = conversion.KgToLbs(*weight)
+	}
+	*weight = roundTo(*weight, 2)
+	return weight
+}
+
+func optionalInt32(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func optionalText(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
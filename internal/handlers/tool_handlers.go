@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"math"
+	"net/http"
+	"strconv"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/plates"
+	"new-chainsaw/internal/response"
+)
+
+type PlateInventoryRequest struct {
+	Unit   string         `json:"unit"` // Unit of the bar, collars and plates, metric or imperial
+	Bar    float64        `json:"bar"`
+	Collar float64        `json:"collar"`
+	Plates []plates.Plate `json:"plates"`
+}
+
+type PlateInventoryDetails struct {
+	Unit    string         `json:"unit"`
+	Bar     float64        `json:"bar"`
+	Collar  float64        `json:"collar"`
+	Plates  []plates.Plate `json:"plates"`
+	Default bool           `json:"default"` // Whether the user has not set up an inventory yet
+}
+
+// BarLoadingDetails is a loaded bar with weights in the requested unit and the plates in the
+// unit of the inventory.
+type BarLoadingDetails struct {
+	Unit          string         `json:"unit"`
+	Total         float64        `json:"total"`
+	Bar           float64        `json:"bar"`
+	Collars       float64        `json:"collars"`
+	PlateUnit     string         `json:"plate_unit"` // kg or lb
+	PlatesPerSide []plates.Plate `json:"plates_per_side"`
+}
+
+type WarmupSetDetails struct {
+	Reps    int32   `json:"reps"`
+	Percent float64 `json:"percent"`
+	BarLoadingDetails
+}
+
+// plateInventory is an inventory with the unit system its weights are in.
+type plateInventory struct {
+	plates.Inventory
+	Unit    db.UnitSystem
+	Default bool
+}
+
+func GetPlateInventoryHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	inventory, err := fetchPlateInventory(int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch plate inventory", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"inventory": toPlateInventoryDetails(inventory)}, nil)
+}
+
+// UpdatePlateInventoryHandler replaces the bar, collars and plates the user loads from.
+func UpdatePlateInventoryHandler(c *gin.Context) {
+	var req PlateInventoryRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	if req.Unit != "" {
+		if units, err = parseUnitSystem(req.Unit); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return
+		}
+	}
+
+	inventory := plates.Inventory{Bar: req.Bar, Collar: req.Collar, Plates: req.Plates}
+	if err := inventory.Validate(); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	encoded, err := json.Marshal(inventory.Plates)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save plate inventory", nil, err)
+		return
+	}
+
+	saved, err := queries.UpsertPlateInventory(ctx, db.UpsertPlateInventoryParams{
+		UserID:       int32(userID),
+		Unit:         units,
+		BarWeight:    conversion.ToNumeric(inventory.Bar),
+		CollarWeight: conversion.ToNumeric(inventory.Collar),
+		Plates:       encoded,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to save plate inventory", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Plate inventory saved", gin.H{"inventory": toPlateInventoryDetails(plateInventory{
+		Inventory: inventory,
+		Unit:      saved.Unit,
+	})}, nil)
+}
+
+// ResetPlateInventoryHandler goes back to the default inventory of the user's preferred units.
+func ResetPlateInventoryHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	if _, err := queries.DeletePlateInventory(context.Background(), int32(userID)); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to reset plate inventory", nil, err)
+		return
+	}
+
+	inventory, err := fetchPlateInventory(int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch plate inventory", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Plate inventory reset", gin.H{"inventory": toPlateInventoryDetails(inventory)}, nil)
+}
+
+// PlateLoadingHandler works out the plates to load for a weight. Weights that cannot be loaded
+// exactly get the closest loadable weight.
+func PlateLoadingHandler(c *gin.Context) {
+	inventory, units, ok := plateInventoryForRequest(c)
+	if !ok {
+		return
+	}
+	weight, ok := weightQuery(c, "weight")
+	if !ok {
+		return
+	}
+
+	target := convertUnits(weight, units, inventory.Unit)
+	loading := inventory.Load(target)
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{
+		"target":  weight,
+		"exact":   math.Abs(loading.Total-target) < 0.01,
+		"loading": toBarLoadingDetails(loading, inventory.Unit, units),
+	}, nil)
+}
+
+// WarmupHandler ramps up from the empty bar to a working weight with loadable warm-up sets.
+func WarmupHandler(c *gin.Context) {
+	inventory, units, ok := plateInventoryForRequest(c)
+	if !ok {
+		return
+	}
+	weight, ok := weightQuery(c, "target")
+	if !ok {
+		return
+	}
+
+	target := convertUnits(weight, units, inventory.Unit)
+	warmup := inventory.Warmup(target)
+	sets := make([]WarmupSetDetails, len(warmup))
+	for i, set := range warmup {
+		sets[i] = WarmupSetDetails{
+			Reps:              set.Reps,
+			Percent:           set.Percent,
+			BarLoadingDetails: toBarLoadingDetails(set.Loading, inventory.Unit, units),
+		}
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{
+		"target":      weight,
+		"warmup":      sets,
+		"working_set": toBarLoadingDetails(inventory.Load(target), inventory.Unit, units),
+	}, nil)
+}
+
+// plateInventoryForRequest loads the user's inventory and the units of the request, which
+// default to the preferred units. A bar query parameter replaces the inventory's bar.
+func plateInventoryForRequest(c *gin.Context) (plateInventory, db.UnitSystem, bool) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	inventory, err := fetchPlateInventory(int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch plate inventory", nil, err)
+		return plateInventory{}, "", false
+	}
+
+	units, err := queries.GetUserPreferredUnit(ctx, int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return plateInventory{}, "", false
+	}
+	if unit := c.Query("unit"); unit != "" {
+		if units, err = parseUnitSystem(unit); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return plateInventory{}, "", false
+		}
+	}
+
+	if c.Query("bar") != "" {
+		bar, ok := weightQuery(c, "bar")
+		if !ok {
+			return plateInventory{}, "", false
+		}
+		inventory.Bar = convertUnits(bar, units, inventory.Unit)
+		if err := inventory.Validate(); err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+			return plateInventory{}, "", false
+		}
+	}
+	return inventory, units, true
+}
+
+// fetchPlateInventory returns the user's inventory, or the default one of their preferred
+// units when they have not set one up.
+func fetchPlateInventory(userID int32) (plateInventory, error) {
+	ctx := context.Background()
+
+	saved, err := queries.GetPlateInventory(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		units, err := queries.GetUserPreferredUnit(ctx, userID)
+		if err != nil {
+			return plateInventory{}, err
+		}
+		return plateInventory{
+			Inventory: plates.DefaultInventory(units == db.UnitSystemImperial),
+			Unit:      units,
+			Default:   true,
+		}, nil
+	}
+	if err != nil {
+		return plateInventory{}, err
+	}
+
+	inventory := plateInventory{
+		Inventory: plates.Inventory{
+			Bar:    numericToFloat(saved.BarWeight),
+			Collar: numericToFloat(saved.CollarWeight),
+		},
+		Unit: saved.Unit,
+	}
+	err = json.Unmarshal(saved.Plates, &inventory.Plates)
+	return inventory, err
+}
+
+func weightQuery(c *gin.Context, name string) (float64, bool) {
+	weight, err := strconv.ParseFloat(c.Query(name), 64)
+	if err != nil || weight <= 0 || math.IsInf(weight, 0) {
+		response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("Query parameter %s must be a positive weight", name), nil, err)
+		return 0, false
+	}
+	return weight, true
+}
+
+func parseUnitSystem(unit string) (db.UnitSystem, error) {
+	switch db.UnitSystem(unit) {
+	case db.UnitSystemMetric, db.UnitSystemImperial:
+		return db.UnitSystem(unit), nil
+	}
+	return "", fmt.Errorf("unit must be %s or %s", db.UnitSystemMetric, db.UnitSystemImperial)
+}
+
+// convertUnits converts a weight between kilograms and pounds.
+func convertUnits(weight float64, from db.UnitSystem, to db.UnitSystem) float64 {
+	switch {
+	case from == to:
+		return weight
+	case to == db.UnitSystemImperial:
+		return conversion.KgToLbs(weight)
+	}
+	return conversion.LbsToKg(weight)
+}
+
+func toBarLoadingDetails(loading plates.Loading, inventoryUnits db.UnitSystem, units db.UnitSystem) BarLoadingDetails {
+	plateUnit := "kg"
+	if inventoryUnits == db.UnitSystemImperial {
+		plateUnit = "lb"
+	}
+	return BarLoadingDetails{
+		Unit:          string(units),
+		Total:         roundTo(convertUnits(loading.Total, inventoryUnits, units), 2),
+		Bar:           roundTo(convertUnits(loading.Bar, inventoryUnits, units), 2),
+		Collars:       roundTo(convertUnits(loading.Collars, inventoryUnits, units), 2),
+		PlateUnit:     plateUnit,
+		PlatesPerSide: loading.PerSide,
+	}
+}
+
+func toPlateInventoryDetails(inventory plateInventory) PlateInventoryDetails {
+	return PlateInventoryDetails{
+		Unit:    string(inventory.Unit),
+		Bar:     inventory.Bar,
+		Collar:  inventory.Collar,
+		Plates:  inventory.Plates,
+		Default: inventory.Default,
+	}
+}
@@ -6,13 +6,17 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 	"new-chainsaw/db"
 	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/challenges"
 	"new-chainsaw/internal/config"
+	"new-chainsaw/internal/events"
 	"new-chainsaw/internal/httpclient"
 	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/streaks"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -65,7 +69,7 @@ func ValidateAndSaveTrophiesHandler(c *gin.Context) {
 	}
 
 	// Call validateTrophies to get evaluated trophies
-	evaluatedTrophies, err := validateTrophies(req, userDetails.Sex, userDetails.LatestBodyWeight, userDetails.PreferredUnits, exerciseLogs)
+	evaluatedTrophies, err := validateTrophies(int32(userID), req, userDetails.Sex, userDetails.LatestBodyWeight, userDetails.PreferredUnits, exerciseLogs)
 	if err != nil {
 		response.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, err)
 		return
@@ -164,7 +168,7 @@ func checkAndUpdateUserTrophies(userID int32) error {
 	}
 
 	// Call validateTrophies to get evaluated trophies
-	evaluatedTrophies, err := validateTrophies(validateReq, userDetails.Sex, userDetails.LatestBodyWeight, userDetails.PreferredUnits, exerciseLogs)
+	evaluatedTrophies, err := validateTrophies(userID, validateReq, userDetails.Sex, userDetails.LatestBodyWeight, userDetails.PreferredUnits, exerciseLogs)
 	if err != nil {
 		return fmt.Errorf("failed to validate trophies: %w", err)
 	}
@@ -184,13 +188,22 @@ func checkAndUpdateUserTrophies(userID int32) error {
 		}
 	}
 
+	// Trophies are replaced and their unlocks recorded in one transaction
+	ctx := context.Background()
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save trophies: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
 	// Delete trophies that are no longer unlocked
 	for _, trophy := range userTrophies {
 		if !trophy.DisplayOrder.Valid {
 			continue
 		}
 		if _, found := unlockedTrophies[trophy.ID]; !found {
-			err = queries.DeleteUserTrophy(context.Background(), db.DeleteUserTrophyParams{
+			err = qtx.DeleteUserTrophy(ctx, db.DeleteUserTrophyParams{
 				UserID:   userID,
 				TrophyID: trophy.ID,
 			})
@@ -203,13 +216,13 @@ func checkAndUpdateUserTrophies(userID int32) error {
 	// Save unlocked trophies in the database
 	for _, trophy := range unlockedTrophies {
 		// Check if there is an existing trophy with the same display_order
-		existingTrophy, err := queries.GetTrophyByDisplayOrder(context.Background(), db.GetTrophyByDisplayOrderParams{
+		existingTrophy, err := qtx.GetTrophyByDisplayOrder(ctx, db.GetTrophyByDisplayOrderParams{
 			UserID:       userID,
 			DisplayOrder: pgtype.Int4{Int32: trophy.DisplayOrder, Valid: true},
 		})
 		if err == nil && existingTrophy.ID != 0 {
 			// If an existing trophy is found, delete it
-			err = queries.DeleteUserTrophy(context.Background(), db.DeleteUserTrophyParams{
+			err = qtx.DeleteUserTrophy(ctx, db.DeleteUserTrophyParams{
 				UserID:   userID,
 				TrophyID: existingTrophy.ID,
 			})
@@ -219,7 +232,7 @@ func checkAndUpdateUserTrophies(userID int32) error {
 		}
 
 		// Insert the new trophy
-		err = queries.InsertUserTrophy(context.Background(), db.InsertUserTrophyParams{
+		err = qtx.InsertUserTrophy(ctx, db.InsertUserTrophyParams{
 			UserID:       userID,
 			TrophyID:     trophy.TrophyID,
 			DisplayOrder: pgtype.Int4{Int32: trophy.DisplayOrder, Valid: true},
@@ -229,13 +242,65 @@ func checkAndUpdateUserTrophies(userID int32) error {
 		}
 	}
 
+	if err := emitUnlockedTrophies(ctx, qtx, userID, userTrophies, unlockedTrophies); err != nil {
+		return fmt.Errorf("failed to record unlocked trophies: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to save trophies: %w", err)
+	}
+
 	duration := time.Since(start)
 	fmt.Printf("checkAndUpdateUserTrophies took %v to complete for user %d\n", duration, userID)
 
 	return nil
 }
 
-func validateTrophies(req ValidateTrophiesRequest, userSex pgtype.Text, latestBodyWeight pgtype.Numeric, unit db.UnitSystem, exerciseLogs []map[string]interface{}) ([]map[string]interface{}, error) {
+// emitUnlockedTrophies emits a TrophyUnlocked event for each unlocked trophy the user did not
+// have before.
+func emitUnlockedTrophies(ctx context.Context, qtx *db.Queries, userID int32, previous []db.GetUserTrophiesRow, unlocked map[int32]TrophyRequest) error {
+	had := make(map[int32]bool, len(previous))
+	for _, trophy := range previous {
+		had[trophy.ID] = true
+	}
+	var newIDs []int32
+	for trophyID := range unlocked {
+		if !had[trophyID] {
+			newIDs = append(newIDs, trophyID)
+		}
+	}
+	if len(newIDs) == 0 {
+		return nil
+	}
+	sort.Slice(newIDs, func(i, j int) bool { return newIDs[i] < newIDs[j] })
+
+	trophies, err := qtx.ListTrophies(ctx)
+	if err != nil {
+		return err
+	}
+	names := make(map[int32]string, len(trophies))
+	for _, trophy := range trophies {
+		names[trophy.ID] = trophy.Name
+	}
+
+	for _, trophyID := range newIDs {
+		err := events.Emit(ctx, qtx, userID, events.TrophyUnlocked, events.TrophyUnlockedPayload{TrophyID: trophyID, Trophy: names[trophyID]})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTrophies(userID int32, req ValidateTrophiesRequest, userSex pgtype.Text, latestBodyWeight pgtype.Numeric, unit db.UnitSystem, exerciseLogs []map[string]interface{}) ([]map[string]interface{}, error) {
+	// Streak and challenge trophies are evaluated here, the frontend evaluates the lifting trophies
+	streakTrophies, liftingTrophies, err := evaluateStreakTrophies(userID, req.Trophies)
+	if err != nil {
+		return nil, err
+	}
+	if len(liftingTrophies) == 0 {
+		return streakTrophies, nil
+	}
+
 	payload := map[string]interface{}{
 		"user": map[string]interface{}{
 			"sex":        userSex.String,
@@ -243,7 +308,7 @@ func validateTrophies(req ValidateTrophiesRequest, userSex pgtype.Text, latestBo
 			"units":      unit,
 		},
 		"exerciseLogs": exerciseLogs,
-		"trophies":     req.Trophies,
+		"trophies":     liftingTrophies,
 	}
 
 	frontendFullURL := config.EnvVars["FRONTEND_FULL_URL"]
@@ -259,14 +324,67 @@ func validateTrophies(req ValidateTrophiesRequest, userSex pgtype.Text, latestBo
 		return nil, fmt.Errorf("invalid response format")
 	}
 
-	trophies := make([]map[string]interface{}, len(evaluatedTrophies))
-	for i, trophy := range evaluatedTrophies {
-		trophies[i] = trophy.(map[string]interface{})
+	trophies := streakTrophies
+	for _, trophy := range evaluatedTrophies {
+		trophies = append(trophies, trophy.(map[string]interface{}))
 	}
 
 	return trophies, nil
 }
 
+// evaluateStreakTrophies splits off the streak and challenge trophies of a request and evaluates
+// them against the user's longest streak and challenge wins, in the format of the frontend's
+// evaluation.
+func evaluateStreakTrophies(userID int32, requested []TrophyRequest) ([]map[string]interface{}, []TrophyRequest, error) {
+	evaluated := []map[string]interface{}{}
+	others := []TrophyRequest{}
+	if len(requested) == 0 {
+		return evaluated, others, nil
+	}
+
+	trophies, err := queries.ListTrophies(context.Background())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch trophies: %w", err)
+	}
+	names := make(map[int32]string, len(trophies))
+	for _, trophy := range trophies {
+		names[trophy.ID] = trophy.Name
+	}
+
+	var userStreak *streaks.Streaks
+	for _, trophy := range requested {
+		if names[trophy.TrophyID] == challenges.ChampionTrophy {
+			wins, err := queries.CountChallengeWins(context.Background(), userID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to count challenge wins: %w", err)
+			}
+			evaluated = append(evaluated, map[string]interface{}{
+				"trophy_id": float64(trophy.TrophyID),
+				"unlocked":  wins > 0,
+			})
+			continue
+		}
+
+		milestone, ok := streaks.MilestoneFor(names[trophy.TrophyID])
+		if !ok {
+			others = append(others, trophy)
+			continue
+		}
+		if userStreak == nil {
+			s, err := userStreaks(context.Background(), userID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to compute streaks: %w", err)
+			}
+			userStreak = &s
+		}
+		evaluated = append(evaluated, map[string]interface{}{
+			"trophy_id": float64(trophy.TrophyID),
+			"unlocked":  userStreak.Longest >= milestone.Weeks,
+		})
+	}
+	return evaluated, others, nil
+}
+
 type Trophy struct {
 	ID           int32  `json:"id"`
 	Name         string `json:"name"`
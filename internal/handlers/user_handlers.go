@@ -9,6 +9,7 @@ import (
 	"time"
 	"new-chainsaw/db"
 	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/config"
 	"new-chainsaw/internal/middleware"
 	"new-chainsaw/internal/response"
 	"new-chainsaw/internal/validation"
@@ -32,7 +33,13 @@ func GetUserProfileByUsernameHandler(c *gin.Context) {
 		return
 	}
 
-	response.JSONResponse(c, http.StatusOK, "", gin.H{"user": userProfile}, err)
+	strengthStandards, err := userStrengthStandards(context.Background(), userProfile.ID, userProfile.Sex, userProfile.PreferredUnits)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch strength standards", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"user": PublicUserProfile{userProfile, strengthStandards}}, err)
 }
 
 func GetUserProfileByIDHandler(c *gin.Context) {
@@ -48,7 +55,13 @@ func GetUserProfileByIDHandler(c *gin.Context) {
 		return
 	}
 
-	response.JSONResponse(c, http.StatusOK, "", gin.H{"user": userProfile}, err)
+	strengthStandards, err := userStrengthStandards(context.Background(), userProfile.ID, userProfile.Sex, userProfile.PreferredUnits)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch strength standards", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"user": UserProfile{userProfile, strengthStandards}}, err)
 }
 
 func DeleteAccountHandler(c *gin.Context) {
@@ -61,15 +74,49 @@ func DeleteAccountHandler(c *gin.Context) {
 		return
 	}
 
-	// Delete the user
-	err = queries.DeleteUser(context.Background(), int32(userID))
+	// Mark the user for deletion, the purge job removes the account once the grace period is over
+	purgeAfter := time.Now().Add(config.AccountDeletionGracePeriod())
+	err = queries.MarkUserForDeletion(context.Background(), db.MarkUserForDeletionParams{
+		ID:         int32(userID),
+		PurgeAfter: pgtype.Timestamptz{Time: purgeAfter, Valid: true},
+	})
 	if err != nil {
 		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete account", nil, err)
 		return
 	}
 
 	middleware.ClearCookies(c)
-	response.JSONResponse(c, http.StatusOK, "Account deleted successfully", nil, err)
+	response.JSONResponse(c, http.StatusOK, "Account scheduled for deletion", gin.H{"purge_after": purgeAfter}, err)
+}
+
+func RestoreAccountHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	restored, err := queries.RestoreUser(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to restore account", nil, err)
+		return
+	}
+	if restored == 0 {
+		response.JSONResponse(c, http.StatusBadRequest, "Account is not pending deletion", nil, nil)
+		return
+	}
+
+	// Reissue the tokens so the pending deletion flag is dropped from the session
+	token, err := middleware.GenerateJWT(userID, c.GetString("username"), c.GetString("name"), c.GetString("email"), c.GetString("avatar_url"), false, false)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to generate JWT token", nil, err)
+		return
+	}
+
+	refreshToken, err := generateRefreshToken(userID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to generate refresh token", nil, err)
+		return
+	}
+
+	middleware.SetCookies(c, token, refreshToken)
+	response.JSONResponse(c, http.StatusOK, "Account restored successfully", nil, nil)
 }
 
 func CheckUsernameAvailabilityHandler(c *gin.Context) {
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+	"new-chainsaw/internal/webhooks"
+)
+
+const (
+	defaultWebhookDeliveryPageSize = 20
+	maxWebhookDeliveryPageSize     = 100
+)
+
+type WebhookRequest struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description *string  `json:"description"`
+	Active      *bool    `json:"active"` // Unchanged when not given
+}
+
+type WebhookDetails struct {
+	ID             int32     `json:"id"`
+	OrganizationID *int32    `json:"organization_id"` // Set for the webhooks of an organization
+	URL            string    `json:"url"`
+	EventTypes     []string  `json:"event_types"`
+	Description    *string   `json:"description"`
+	Active         bool      `json:"active"`
+	Secret         string    `json:"secret,omitempty"` // Only shown when created or rotated
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryDetails struct {
+	ID             int64           `json:"id"`
+	EventID        *int64          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"` // The event payload, see internal/events
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"` // Set while pending
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus *int32          `json:"response_status"`
+	LastError      *string         `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// ListWebhooksHandler returns the user's webhooks, those of organizations are listed with the
+// organization.
+func ListWebhooksHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	respondWithWebhooks(c, db.ListWebhooksParams{UserID: pgtype.Int4{Int32: int32(userID), Valid: true}})
+}
+
+// CreateWebhookHandler subscribes a URL to the user's events.
+func CreateWebhookHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+	createWebhook(c, db.CreateWebhookParams{UserID: pgtype.Int4{Int32: int32(userID), Valid: true}})
+}
+
+// ListOrganizationWebhooksHandler returns the webhooks of an organization to its owner.
+func ListOrganizationWebhooksHandler(c *gin.Context) {
+	organization, ok := fetchOwnedOrganization(c)
+	if !ok {
+		return
+	}
+	respondWithWebhooks(c, db.ListWebhooksParams{OrganizationID: pgtype.Int4{Int32: organization.ID, Valid: true}})
+}
+
+// CreateOrganizationWebhookHandler subscribes a URL to the events of the organization's
+// athletes, within what each shares with the coaches.
+func CreateOrganizationWebhookHandler(c *gin.Context) {
+	organization, ok := fetchOwnedOrganization(c)
+	if !ok {
+		return
+	}
+	createWebhook(c, db.CreateWebhookParams{OrganizationID: pgtype.Int4{Int32: organization.ID, Valid: true}})
+}
+
+func GetWebhookHandler(c *gin.Context) {
+	webhook, ok := fetchWebhook(c)
+	if !ok {
+		return
+	}
+	response.JSONResponse(c, http.StatusOK, "", toWebhookDetails(webhook, false), nil)
+}
+
+func UpdateWebhookHandler(c *gin.Context) {
+	webhook, ok := fetchWebhook(c)
+	if !ok {
+		return
+	}
+	var req WebhookRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	req.URL = strings.TrimSpace(req.URL)
+	if err := validation.ValidateWebhook(req.URL, req.EventTypes, req.Description); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	active := webhook.Active
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	webhook, err := queries.UpdateWebhook(context.Background(), db.UpdateWebhookParams{
+		ID:          webhook.ID,
+		Url:         req.URL,
+		EventTypes:  req.EventTypes,
+		Description: trimmedText(req.Description),
+		Active:      active,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update webhook", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Webhook updated", toWebhookDetails(webhook, false), nil)
+}
+
+// RotateWebhookSecretHandler replaces the secret payloads are signed with. Deliveries are
+// signed with the new secret from their next attempt.
+func RotateWebhookSecretHandler(c *gin.Context) {
+	webhook, ok := fetchWebhook(c)
+	if !ok {
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to rotate webhook secret", nil, err)
+		return
+	}
+	webhook, err = queries.RotateWebhookSecret(context.Background(), db.RotateWebhookSecretParams{ID: webhook.ID, Secret: secret})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to rotate webhook secret", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Webhook secret rotated", toWebhookDetails(webhook, true), nil)
+}
+
+// DeleteWebhookHandler deletes a webhook with its delivery log, pending deliveries are dropped.
+func DeleteWebhookHandler(c *gin.Context) {
+	webhook, ok := fetchWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := queries.DeleteWebhook(context.Background(), webhook.ID); err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete webhook", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Webhook deleted", nil, nil)
+}
+
+// ListWebhookDeliveriesHandler returns the delivery log of a webhook newest first. Pages end at
+// the delivery before the cursor, next_cursor is null on the last page.
+func ListWebhookDeliveriesHandler(c *gin.Context) {
+	webhook, ok := fetchWebhook(c)
+	if !ok {
+		return
+	}
+	limit, before, ok := parseCursorPage(c, defaultWebhookDeliveryPageSize, maxWebhookDeliveryPageSize)
+	if !ok {
+		return
+	}
+
+	// One more than the page tells whether there is a next page
+	rows, err := queries.ListWebhookDeliveries(context.Background(), db.ListWebhookDeliveriesParams{WebhookID: webhook.ID, BeforeID: before, MaxResults: int32(limit + 1)})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch webhook deliveries", nil, err)
+		return
+	}
+
+	var nextCursor *string
+	if len(rows) > limit {
+		rows = rows[:limit]
+		cursor := strconv.FormatInt(rows[limit-1].ID, 10)
+		nextCursor = &cursor
+	}
+	details := make([]WebhookDeliveryDetails, len(rows))
+	for i, row := range rows {
+		details[i] = toWebhookDeliveryDetails(row)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"deliveries": details, "next_cursor": nextCursor}, nil)
+}
+
+// RedeliverWebhookDeliveryHandler queues a delivery again, as a new delivery of the same event
+// due now. It is retried like any other.
+func RedeliverWebhookDeliveryHandler(c *gin.Context) {
+	webhook, ok := fetchWebhook(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid delivery ID", nil, err)
+		return
+	}
+
+	delivery, err := queries.RedeliverWebhookDelivery(context.Background(), db.RedeliverWebhookDeliveryParams{ID: deliveryID, WebhookID: webhook.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Delivery not found", nil, err)
+			return
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to redeliver webhook delivery", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Delivery queued", toWebhookDeliveryDetails(delivery), nil)
+}
+
+func createWebhook(c *gin.Context, params db.CreateWebhookParams) {
+	var req WebhookRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	ctx := context.Background()
+
+	req.URL = strings.TrimSpace(req.URL)
+	if err := validation.ValidateWebhook(req.URL, req.EventTypes, req.Description); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+
+	count, err := queries.CountWebhooks(ctx, db.CountWebhooksParams{UserID: params.UserID, OrganizationID: params.OrganizationID})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create webhook", nil, err)
+		return
+	}
+	if count >= webhooks.MaxWebhooks {
+		response.JSONResponse(c, http.StatusConflict, "Webhook limit reached, delete one to add another", nil, nil)
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create webhook", nil, err)
+		return
+	}
+	params.Url = req.URL
+	params.Secret = secret
+	params.EventTypes = req.EventTypes
+	params.Description = trimmedText(req.Description)
+
+	webhook, err := queries.CreateWebhook(ctx, params)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to create webhook", nil, err)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusCreated, "Webhook created", toWebhookDetails(webhook, true), nil)
+}
+
+func respondWithWebhooks(c *gin.Context, params db.ListWebhooksParams) {
+	rows, err := queries.ListWebhooks(context.Background(), params)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch webhooks", nil, err)
+		return
+	}
+	details := make([]WebhookDetails, len(rows))
+	for i, row := range rows {
+		details[i] = toWebhookDetails(row, false)
+	}
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"webhooks": details, "event_types": webhooks.EventTypes}, nil)
+}
+
+// fetchOwnedOrganization returns the organization of the request when the user owns it, only
+// the owner manages its webhooks.
+func fetchOwnedOrganization(c *gin.Context) (db.Organization, bool) {
+	organization, member, ok := fetchOrganization(c)
+	if !ok {
+		return db.Organization{}, false
+	}
+	if member.Role != db.OrganizationRoleOwner {
+		response.JSONResponse(c, http.StatusForbidden, "Only the owner can manage the organization's webhooks", nil, nil)
+		return db.Organization{}, false
+	}
+	return organization, true
+}
+
+// fetchWebhook returns the webhook of the request when it is the user's or belongs to an
+// organization the user owns. Other webhooks are not found.
+func fetchWebhook(c *gin.Context) (db.Webhook, bool) {
+	userID := c.GetInt("userID")
+	ctx := context.Background()
+
+	webhookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid webhook ID", nil, err)
+		return db.Webhook{}, false
+	}
+
+	webhook, err := queries.GetWebhook(ctx, int32(webhookID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Webhook not found", nil, err)
+			return db.Webhook{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch webhook", nil, err)
+		return db.Webhook{}, false
+	}
+	if webhook.UserID.Valid {
+		if webhook.UserID.Int32 != int32(userID) {
+			response.JSONResponse(c, http.StatusNotFound, "Webhook not found", nil, nil)
+			return db.Webhook{}, false
+		}
+		return webhook, true
+	}
+
+	member, err := queries.GetOrganizationMember(ctx, db.GetOrganizationMemberParams{OrganizationID: webhook.OrganizationID.Int32, UserID: int32(userID)})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch webhook", nil, err)
+		return db.Webhook{}, false
+	}
+	if err != nil || member.Role != db.OrganizationRoleOwner {
+		response.JSONResponse(c, http.StatusNotFound, "Webhook not found", nil, nil)
+		return db.Webhook{}, false
+	}
+	return webhook, true
+}
+
+// parseCursorPage reads the limit and cursor query parameters of a page of rows listed by
+// descending ID.
+func parseCursorPage(c *gin.Context, defaultLimit int, maxLimit int) (int, pgtype.Int8, bool) {
+	limit := defaultLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxLimit {
+			response.JSONResponse(c, http.StatusBadRequest, "Limit must be between 1 and "+strconv.Itoa(maxLimit), nil, err)
+			return 0, pgtype.Int8{}, false
+		}
+		limit = parsed
+	}
+	var before pgtype.Int8
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			response.JSONResponse(c, http.StatusBadRequest, "Invalid cursor", nil, err)
+			return 0, pgtype.Int8{}, false
+		}
+		before = pgtype.Int8{Int64: cursor, Valid: true}
+	}
+	return limit, before, true
+}
+
+func toWebhookDetails(w db.Webhook, withSecret bool) WebhookDetails {
+	details := WebhookDetails{
+		ID:         w.ID,
+		URL:        w.Url,
+		EventTypes: w.EventTypes,
+		Active:     w.Active,
+		CreatedAt:  w.CreatedAt.Time,
+		UpdatedAt:  w.UpdatedAt.Time,
+	}
+	if w.OrganizationID.Valid {
+		details.OrganizationID = &w.OrganizationID.Int32
+	}
+	if w.Description.Valid {
+		details.Description = &w.Description.String
+	}
+	if withSecret {
+		details.Secret = w.Secret
+	}
+	return details
+}
+
+func toWebhookDeliveryDetails(d db.WebhookDelivery) WebhookDeliveryDetails {
+	details := WebhookDeliveryDetails{
+		ID:        d.ID,
+		EventType: d.EventType,
+		Payload:   d.Payload,
+		Status:    string(d.Status),
+		Attempts:  d.Attempts,
+		CreatedAt: d.CreatedAt.Time,
+	}
+	if d.EventID.Valid {
+		details.EventID = &d.EventID.Int64
+	}
+	if d.Status == db.WebhookDeliveryStatusPending {
+		details.NextAttemptAt = &d.NextAttemptAt.Time
+	}
+	if d.LastAttemptAt.Valid {
+		details.LastAttemptAt = &d.LastAttemptAt.Time
+	}
+	if d.ResponseStatus.Valid {
+		details.ResponseStatus = &d.ResponseStatus.Int32
+	}
+	if d.LastError.Valid {
+		details.LastError = &d.LastError.String
+	}
+	if d.DeliveredAt.Valid {
+		details.DeliveredAt = &d.DeliveredAt.Time
+	}
+	return details
+}
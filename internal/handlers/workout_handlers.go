@@ -0,0 +1,459 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/binding"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/response"
+	"new-chainsaw/internal/validation"
+)
+
+const (
+	defaultWorkoutListLimit = 20
+	maxWorkoutListLimit     = 100
+)
+
+type WorkoutSetDetails struct {
+	ID            int32    `json:"id"`
+	SetNumber     int32    `json:"set_number"`
+	TargetReps    *int32   `json:"target_reps"`
+	TargetWeight  *float64 `json:"target_weight"`
+	RestSeconds   *int32   `json:"rest_seconds"`
+	Reps          *int32   `json:"reps"`
+	Weight        *float64 `json:"weight"`
+	RPE           *float64 `json:"rpe"`
+	Completed     bool     `json:"completed"`
+	ExerciseLogID *int32   `json:"exercise_log_id,omitempty"`
+}
+
+type WorkoutExerciseDetails struct {
+	Position     int32               `json:"position"`
+	ExerciseID   int32               `json:"exercise_id"`
+	ExerciseName string              `json:"exercise_name"`
+	Sets         []WorkoutSetDetails `json:"sets"`
+}
+
+type WorkoutDetails struct {
+	ID          int32                    `json:"id"`
+	TemplateID  *int32                   `json:"template_id"`
+	Name        string                   `json:"name"`
+	Status      string                   `json:"status"`
+	StartedAt   time.Time                `json:"started_at"`
+	CompletedAt *time.Time               `json:"completed_at"`
+	Unit        string                   `json:"unit"`
+	Exercises   []WorkoutExerciseDetails `json:"exercises,omitempty"`
+}
+
+type WorkoutSetRequest struct {
+	Reps      *int32   `json:"reps"`   // Defaults to the target reps when the set is completed
+	Weight    *float64 `json:"weight"` // Defaults to the target weight when the set is completed
+	Unit      string   `json:"unit"`
+	RPE       *float64 `json:"rpe"` // Unchanged when omitted
+	Completed bool     `json:"completed"`
+}
+
+type CompleteWorkoutRequest struct {
+	BodyWeight *float64 `json:"body_weight"` // Logged when no bodyweight is logged on or before the workout
+	Unit       string   `json:"unit"`
+}
+
+// ListWorkoutsHandler returns the user's latest workout sessions without their sets.
+func ListWorkoutsHandler(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	limit := defaultWorkoutListLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxWorkoutListLimit {
+			response.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxWorkoutListLimit), nil, err)
+			return
+		}
+		limit = parsed
+	}
+
+	units, err := queries.GetUserPreferredUnit(context.Background(), int32(userID))
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+
+	sessions, err := queries.ListWorkoutSessions(context.Background(), db.ListWorkoutSessionsParams{
+		UserID: int32(userID),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch workouts", nil, err)
+		return
+	}
+
+	workouts := make([]WorkoutDetails, len(sessions))
+	for i, session := range sessions {
+		workouts[i] = toWorkoutDetails(session, units)
+	}
+
+	response.JSONResponse(c, http.StatusOK, "", gin.H{"workouts": workouts}, nil)
+}
+
+func GetWorkoutHandler(c *gin.Context) {
+	session, ok := fetchWorkoutSession(c)
+	if !ok {
+		return
+	}
+	respondWithWorkout(c, http.StatusOK, "", session)
+}
+
+// UpdateWorkoutSetHandler records what was lifted in a set of an in progress workout.
+func UpdateWorkoutSetHandler(c *gin.Context) {
+	session, ok := fetchWorkoutSession(c)
+	if !ok {
+		return
+	}
+	if session.Status != db.WorkoutSessionStatusInProgress {
+		response.JSONResponse(c, http.StatusConflict, "Workout is already completed", nil, nil)
+		return
+	}
+
+	setID, err := strconv.Atoi(c.Param("set_id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid set ID", nil, err)
+		return
+	}
+
+	var req WorkoutSetRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	if err := validation.ValidateRPE(req.RPE); err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+		return
+	}
+	if req.Reps != nil && *req.Reps < 1 {
+		response.JSONResponse(c, http.StatusBadRequest, "reps must be positive", nil, nil)
+		return
+	}
+	if req.Weight != nil && *req.Weight < 0 {
+		response.JSONResponse(c, http.StatusBadRequest, "weight must not be negative", nil, nil)
+		return
+	}
+
+	sets, err := queries.ListWorkoutSessionSets(context.Background(), session.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch workout sets", nil, err)
+		return
+	}
+	var set *db.ListWorkoutSessionSetsRow
+	for i := range sets {
+		if sets[i].ID == int32(setID) {
+			set = &sets[i]
+		}
+	}
+	if set == nil {
+		response.JSONResponse(c, http.StatusNotFound, "Set not found", nil, nil)
+		return
+	}
+
+	reps := optionalInt4(req.Reps)
+	weight := pgtype.Numeric{}
+	if req.Weight != nil {
+		weight = conversion.ToNumeric(calculateWeight(*req.Weight, req.Unit))
+	}
+	rpe := set.Rpe
+	if req.RPE != nil {
+		rpe = conversion.ToNumeric(*req.RPE)
+	}
+	completedAt := pgtype.Timestamptz{}
+	if req.Completed {
+		if !reps.Valid {
+			reps = set.TargetReps
+		}
+		if !weight.Valid {
+			weight = set.TargetWeight
+		}
+		if !reps.Valid {
+			response.JSONResponse(c, http.StatusBadRequest, "reps are required to complete a set without target reps", nil, nil)
+			return
+		}
+		if !weight.Valid {
+			weight = conversion.ToNumeric(0)
+		}
+		// Keep the time the set was first completed, it becomes the log date of the set
+		completedAt = set.CompletedAt
+		if !completedAt.Valid {
+			completedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+	}
+
+	_, err = queries.UpdateWorkoutSessionSet(context.Background(), db.UpdateWorkoutSessionSetParams{
+		ID:          set.ID,
+		SessionID:   session.ID,
+		Reps:        reps,
+		Weight:      weight,
+		Rpe:         rpe,
+		CompletedAt: completedAt,
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to update set", nil, err)
+		return
+	}
+
+	respondWithWorkout(c, http.StatusOK, "Set updated", session)
+}
+
+// CompleteWorkoutHandler logs the completed sets of a workout as exercise logs and closes the
+// workout. Sets that were not completed are not logged.
+func CompleteWorkoutHandler(c *gin.Context) {
+	session, ok := fetchWorkoutSession(c)
+	if !ok {
+		return
+	}
+	if session.Status != db.WorkoutSessionStatusInProgress {
+		response.JSONResponse(c, http.StatusConflict, "Workout is already completed", nil, nil)
+		return
+	}
+
+	var req CompleteWorkoutRequest
+	if err := binding.BindJSON(c, &req); err != nil {
+		return
+	}
+	var fallbackBodyweight *float64
+	if req.BodyWeight != nil {
+		bodyweightKg := calculateWeight(*req.BodyWeight, req.Unit)
+		fallbackBodyweight = &bodyweightKg
+	}
+
+	ctx := context.Background()
+	sets, err := queries.ListWorkoutSessionSets(ctx, session.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch workout sets", nil, err)
+		return
+	}
+
+	tx, err := dbPool.Begin(ctx)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to complete workout", nil, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	var bodyweightID int32
+	logged := 0
+	var loggedSets []loggedSet
+	var workoutSets []events.WorkoutLoggedSet
+	for _, set := range sets {
+		if !set.CompletedAt.Valid || !set.Reps.Valid {
+			continue
+		}
+		if logged == 0 {
+			bodyweightID, err = importBodyweightID(ctx, qtx, session.UserID, session.StartedAt.Time, fallbackBodyweight)
+			if err != nil {
+				response.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, err)
+				return
+			}
+		}
+
+		params := db.LogWorkoutSetParams{
+			UserID:       session.UserID,
+			ExerciseID:   set.ExerciseID,
+			Reps:         set.Reps.Int32,
+			Weight:       set.Weight,
+			ExerciseType: set.DefaultExerciseType,
+			BodyweightID: bodyweightID,
+			LogDate:      set.CompletedAt,
+			Rpe:          set.Rpe,
+			RestSeconds:  set.RestSeconds,
+		}
+		// The weight of a set of a bodyweight exercise is the weight added or assisted with
+		if set.DefaultExerciseType.Valid {
+			params.Weight = conversion.ToNumeric(0)
+			if set.DefaultExerciseType.ExerciseType != db.ExerciseTypeBodyweight {
+				params.AdditionalWeight = set.Weight
+			}
+		}
+
+		logID, err := qtx.LogWorkoutSet(ctx, params)
+		if err != nil {
+			if isUniqueViolation(err) {
+				response.JSONResponse(c, http.StatusConflict, fmt.Sprintf("A set of %s is already logged at this time", set.ExerciseName), nil, err)
+				return
+			}
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to log workout sets", nil, err)
+			return
+		}
+		if err := qtx.LinkWorkoutSessionSetLog(ctx, db.LinkWorkoutSessionSetLogParams{
+			ID:            set.ID,
+			ExerciseLogID: pgtype.Int4{Int32: logID, Valid: true},
+		}); err != nil {
+			response.JSONResponse(c, http.StatusInternalServerError, "Failed to log workout sets", nil, err)
+			return
+		}
+		logged++
+		loggedSets = append(loggedSets, loggedSet{ExerciseID: set.ExerciseID, LogDate: set.CompletedAt.Time})
+		workoutSet := events.WorkoutLoggedSet{ExerciseID: set.ExerciseID, LogDate: set.CompletedAt.Time, Reps: params.Reps, Weight: numericToFloat(params.Weight)}
+		if params.AdditionalWeight.Valid {
+			additionalWeight := numericToFloat(params.AdditionalWeight)
+			workoutSet.AdditionalWeight = &additionalWeight
+		}
+		workoutSets = append(workoutSets, workoutSet)
+	}
+	if logged == 0 {
+		response.JSONResponse(c, http.StatusBadRequest, "Complete at least one set before completing the workout", nil, nil)
+		return
+	}
+
+	completed, err := qtx.CompleteWorkoutSession(ctx, db.CompleteWorkoutSessionParams{
+		ID:     session.ID,
+		UserID: session.UserID,
+	})
+	if err == nil && completed == 0 {
+		err = errors.New("workout was completed concurrently")
+	}
+	if err == nil {
+		err = events.Emit(ctx, qtx, session.UserID, events.WorkoutLogged, events.WorkoutLoggedPayload{WorkoutID: &session.ID, Sets: workoutSets})
+	}
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to complete workout", nil, err)
+		return
+	}
+
+	if err := checkAndUpdateUserTrophies(session.UserID); err != nil {
+		log.Printf("Failed to update trophies for user %d: %v\n", session.UserID, err)
+	}
+	if err := checkStrengthGoals(session.UserID); err != nil {
+		log.Printf("Failed to check strength goals for user %d: %v\n", session.UserID, err)
+	}
+	if err := checkPersonalRecords(session.UserID, loggedSets); err != nil {
+		log.Printf("Failed to check personal records for user %d: %v\n", session.UserID, err)
+	}
+
+	session, err = queries.GetWorkoutSession(ctx, db.GetWorkoutSessionParams{ID: session.ID, UserID: session.UserID})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch workout", nil, err)
+		return
+	}
+	respondWithWorkout(c, http.StatusOK, fmt.Sprintf("Workout completed, %d sets logged", logged), session)
+}
+
+// DeleteWorkoutHandler discards an in progress workout. Completed workouts are kept, their
+// sets are part of the training log.
+func DeleteWorkoutHandler(c *gin.Context) {
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid workout ID", nil, err)
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	deleted, err := queries.DeleteWorkoutSession(context.Background(), db.DeleteWorkoutSessionParams{
+		ID:     int32(workoutID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to delete workout", nil, err)
+		return
+	}
+	if deleted == 0 {
+		response.JSONResponse(c, http.StatusNotFound, "No workout in progress with this ID", nil, nil)
+		return
+	}
+
+	response.JSONResponse(c, http.StatusOK, "Workout discarded", nil, nil)
+}
+
+func fetchWorkoutSession(c *gin.Context) (db.WorkoutSession, bool) {
+	userID := c.GetInt("userID")
+
+	workoutID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSONResponse(c, http.StatusBadRequest, "Invalid workout ID", nil, err)
+		return db.WorkoutSession{}, false
+	}
+
+	session, err := queries.GetWorkoutSession(context.Background(), db.GetWorkoutSessionParams{
+		ID:     int32(workoutID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			response.JSONResponse(c, http.StatusNotFound, "Workout not found", nil, err)
+			return db.WorkoutSession{}, false
+		}
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch workout", nil, err)
+		return db.WorkoutSession{}, false
+	}
+	return session, true
+}
+
+// respondWithWorkout responds with a workout and its sets grouped by exercise, with weights
+// in the user's units.
+func respondWithWorkout(c *gin.Context, status int, message string, session db.WorkoutSession) {
+	units, err := queries.GetUserPreferredUnit(context.Background(), session.UserID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch preferred units", nil, err)
+		return
+	}
+	sets, err := queries.ListWorkoutSessionSets(context.Background(), session.ID)
+	if err != nil {
+		response.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch workout sets", nil, err)
+		return
+	}
+
+	workout := toWorkoutDetails(session, units)
+	workout.Exercises = []WorkoutExerciseDetails{}
+	for _, set := range sets {
+		last := len(workout.Exercises) - 1
+		if last < 0 || workout.Exercises[last].Position != set.Position {
+			workout.Exercises = append(workout.Exercises, WorkoutExerciseDetails{
+				Position:     set.Position,
+				ExerciseID:   set.ExerciseID,
+				ExerciseName: set.ExerciseName,
+				Sets:         []WorkoutSetDetails{},
+			})
+			last++
+		}
+		workout.Exercises[last].Sets = append(workout.Exercises[last].Sets, WorkoutSetDetails{
+			ID:            set.ID,
+			SetNumber:     set.SetNumber,
+			TargetReps:    optionalInt32(set.TargetReps),
+			TargetWeight:  displayWeight(set.TargetWeight, units),
+			RestSeconds:   optionalInt32(set.RestSeconds),
+			Reps:          optionalInt32(set.Reps),
+			Weight:        displayWeight(set.Weight, units),
+			RPE:           optionalNumeric(set.Rpe),
+			Completed:     set.CompletedAt.Valid,
+			ExerciseLogID: optionalInt32(set.ExerciseLogID),
+		})
+	}
+
+	response.JSONResponse(c, status, message, gin.H{"workout": workout}, nil)
+}
+
+func toWorkoutDetails(session db.WorkoutSession, units db.UnitSystem) WorkoutDetails {
+	workout := WorkoutDetails{
+		ID:         session.ID,
+		TemplateID: optionalInt32(session.TemplateID),
+		Name:       session.Name,
+		Status:     string(session.Status),
+		StartedAt:  session.StartedAt.Time,
+		Unit:       string(units),
+	}
+	if session.CompletedAt.Valid {
+		workout.CompletedAt = &session.CompletedAt.Time
+	}
+	return workout
+}
@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dateFormats are the date formats a generic CSV import can declare.
+var dateFormats = map[string][]string{
+	"YYYY-MM-DD":          {"2006-01-02", "2006-01-02 15:04:05", "2006-01-02 15:04"},
+	"DD/MM/YYYY":          {"02/01/2006", "2/1/2006", "02/01/2006 15:04"},
+	"MM/DD/YYYY":          {"01/02/2006", "1/2/2006", "01/02/2006 15:04"},
+	"DD.MM.YYYY":          {"02.01.2006", "2.1.2006", "02.01.2006 15:04"},
+	"YYYY-MM-DDTHH:mm:ssZ": {"2006-01-02T15:04:05Z07:00"},
+}
+
+// ColumnMapping tells the generic parser which header holds which value.
+type ColumnMapping struct {
+	Date       string `json:"date"`
+	Exercise   string `json:"exercise"`
+	Reps       string `json:"reps"`
+	Weight     string `json:"weight"`
+	Unit       string `json:"unit"`        // Optional column with a per-row unit (kg or lbs)
+	DateFormat string `json:"date_format"` // One of the keys of dateFormats, defaults to YYYY-MM-DD
+}
+
+// GenericParser reads any CSV file whose columns are described by a ColumnMapping.
+// Unit is used for rows when the mapping has no unit column.
+type GenericParser struct {
+	Mapping ColumnMapping
+	Unit    string
+}
+
+func (p GenericParser) Parse(r io.Reader) ([]Row, []RowError, error) {
+	m := p.Mapping
+	if m.Date == "" || m.Exercise == "" || m.Reps == "" || m.Weight == "" {
+		return nil, nil, errors.New("column mapping must name the date, exercise, reps and weight columns")
+	}
+
+	dateFormat := m.DateFormat
+	if dateFormat == "" {
+		dateFormat = "YYYY-MM-DD"
+	}
+	layouts, ok := dateFormats[dateFormat]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported date format %q", m.DateFormat)
+	}
+
+	br := bufio.NewReader(r)
+	header, records, err := readAll(newCSVReader(br, sniffDelimiter(br)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := columnIndex(header)
+	dateColumn, exerciseColumn := strings.ToLower(m.Date), strings.ToLower(m.Exercise)
+	repsColumn, weightColumn, unitColumn := strings.ToLower(m.Reps), strings.ToLower(m.Weight), strings.ToLower(m.Unit)
+	if err := requireColumns(index, dateColumn, exerciseColumn, repsColumn, weightColumn); err != nil {
+		return nil, nil, err
+	}
+	if unitColumn != "" {
+		if err := requireColumns(index, unitColumn); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var rows []Row
+	var rowErrors []RowError
+	for i, record := range records {
+		line := i + 2 // Header is line 1
+
+		date, err := parseDate(field(record, index, dateColumn), layouts)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		reps, weight, err := parseRepsAndWeight(field(record, index, repsColumn), field(record, index, weightColumn))
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+		if reps == 0 {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: "sets without reps are not supported"})
+			continue
+		}
+
+		unit := p.Unit
+		if unitColumn != "" {
+			if unit, err = parseUnit(field(record, index, unitColumn)); err != nil {
+				rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+				continue
+			}
+		}
+
+		exercise := field(record, index, exerciseColumn)
+		if exercise == "" {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: "missing exercise name"})
+			continue
+		}
+
+		rows = append(rows, Row{
+			Line:     line,
+			Date:     date,
+			Exercise: exercise,
+			Reps:     reps,
+			Weight:   weight,
+			Unit:     unit,
+		})
+	}
+
+	return rows, rowErrors, nil
+}
@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"io"
+	"strings"
+)
+
+var hevyDateLayouts = []string{"2 Jan 2006, 15:04", "02 Jan 2006, 15:04", "2006-01-02T15:04:05Z07:00", "2006-01-02 15:04:05"}
+
+// HevyParser reads the CSV export of the Hevy app. Hevy names its weight column after
+// the unit it is recorded in (weight_kg or weight_lbs).
+type HevyParser struct{}
+
+func (p HevyParser) Parse(r io.Reader) ([]Row, []RowError, error) {
+	header, records, err := readAll(newCSVReader(r, ','))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := columnIndex(header)
+	if err := requireColumns(index, "start_time", "exercise_title", "reps"); err != nil {
+		return nil, nil, err
+	}
+
+	weightColumn, unit := "weight_kg", "metric"
+	if _, ok := index["weight_lbs"]; ok {
+		weightColumn, unit = "weight_lbs", "imperial"
+	} else if err := requireColumns(index, "weight_kg"); err != nil {
+		return nil, nil, err
+	}
+
+	var rows []Row
+	var rowErrors []RowError
+	for i, record := range records {
+		line := i + 2 // Header is line 1
+
+		if strings.EqualFold(field(record, index, "set_type"), "warmup") {
+			continue
+		}
+
+		date, err := parseDate(field(record, index, "start_time"), hevyDateLayouts)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		reps, weight, err := parseRepsAndWeight(field(record, index, "reps"), field(record, index, weightColumn))
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+		if reps == 0 {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: "sets without reps are not supported"})
+			continue
+		}
+
+		rows = append(rows, Row{
+			Line:     line,
+			Date:     date,
+			Exercise: field(record, index, "exercise_title"),
+			Reps:     reps,
+			Weight:   weight,
+			Unit:     unit,
+		})
+	}
+
+	return rows, rowErrors, nil
+}
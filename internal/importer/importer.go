@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Row is a single set parsed from a third-party export. Weight is in the unit of the source file.
+type Row struct {
+	Line     int       `json:"line"`
+	Date     time.Time `json:"date"`
+	Exercise string    `json:"exercise"`
+	Reps     int32     `json:"reps"`
+	Weight   float64   `json:"weight"`
+	Unit     string    `json:"unit"`
+}
+
+// RowError describes a line of the source file that could not be parsed or imported.
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Parser turns an export file into rows. Lines that cannot be parsed are reported
+// as row errors, only an unreadable file fails the whole parse.
+type Parser interface {
+	Parse(r io.Reader) ([]Row, []RowError, error)
+}
+
+var ErrUnknownColumn = errors.New("missing required column")
+
+// columnIndex maps header names to their position, ignoring case and surrounding whitespace.
+func columnIndex(header []string) map[string]int {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff") // Byte order mark written by some exporters
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return index
+}
+
+func requireColumns(index map[string]int, names ...string) error {
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+		}
+	}
+	return nil
+}
+
+func newCSVReader(r io.Reader, delimiter rune) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader
+}
+
+// readAll reads the header and all records, numbering records by their line in the file.
+func readAll(reader *csv.Reader) ([]string, [][]string, error) {
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		records = append(records, record)
+	}
+	return header, records, nil
+}
+
+func field(record []string, index map[string]int, name string) string {
+	i, ok := index[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// AssignTimestamps offsets sets that share a workout timestamp by one second each.
+// Exercise logs are unique per exercise and timestamp, while exports only record
+// when the workout started.
+func AssignTimestamps(rows []Row) {
+	seen := make(map[string]int)
+	for i := range rows {
+		key := rows[i].Date.UTC().Format(time.RFC3339) + "|" + strings.ToLower(rows[i].Exercise)
+		rows[i].Date = rows[i].Date.Add(time.Duration(seen[key]) * time.Second)
+		seen[key]++
+	}
+}
+
+// ExerciseNames returns the distinct exercise names in rows, sorted.
+func ExerciseNames(rows []Row) []string {
+	set := make(map[string]struct{})
+	for _, row := range rows {
+		set[row.Exercise] = struct{}{}
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,55 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CatalogExercise is an entry of the exercises table imported names can be mapped onto.
+type CatalogExercise struct {
+	ID      int32
+	Name    string
+	Aliases []string
+}
+
+var (
+	barbellSuffixRegex = regexp.MustCompile(`(?i)\s*\(barbell\)\s*$`)
+	nonAlphanumRegex   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// normalizeName lowercases a name and strips punctuation. Strong and Hevy suffix lifts with
+// their equipment, only the barbell variants are the same movement as our catalog entries.
+func normalizeName(name string) string {
+	name = barbellSuffixRegex.ReplaceAllString(name, "")
+	return nonAlphanumRegex.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// MatchExercises suggests a catalog exercise for every imported name, matching exercise names
+// first and aliases second. Names without a match, or with an alias shared by several exercises,
+// map to nil and have to be mapped manually before the import can be committed.
+func MatchExercises(names []string, catalog []CatalogExercise) map[string]*int32 {
+	byName := make(map[string]int32, len(catalog))
+	byAlias := make(map[string][]int32)
+	for _, exercise := range catalog {
+		byName[normalizeName(exercise.Name)] = exercise.ID
+		for _, alias := range exercise.Aliases {
+			byAlias[normalizeName(alias)] = append(byAlias[normalizeName(alias)], exercise.ID)
+		}
+	}
+	for alias, ids := range byAlias {
+		if _, ok := byName[alias]; !ok && len(ids) == 1 {
+			byName[alias] = ids[0]
+		}
+	}
+
+	matches := make(map[string]*int32, len(names))
+	for _, name := range names {
+		if id, ok := byName[normalizeName(name)]; ok {
+			id := id
+			matches[name] = &id
+		} else {
+			matches[name] = nil
+		}
+	}
+	return matches
+}
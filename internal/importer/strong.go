@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var strongDateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// StrongParser reads the CSV export of the Strong app. Strong only records weights in the
+// unit the user had selected, so that unit has to be supplied unless the file carries a
+// "Weight Unit" column (older app versions).
+type StrongParser struct {
+	Unit string
+}
+
+func (p StrongParser) Parse(r io.Reader) ([]Row, []RowError, error) {
+	br := bufio.NewReader(r)
+	header, records, err := readAll(newCSVReader(br, sniffDelimiter(br)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := columnIndex(header)
+	if err := requireColumns(index, "date", "exercise name", "weight", "reps"); err != nil {
+		return nil, nil, err
+	}
+
+	var rows []Row
+	var rowErrors []RowError
+	for i, record := range records {
+		line := i + 2 // Header is line 1
+
+		// Strong marks warm-up sets with "W" in the set order column
+		if strings.EqualFold(field(record, index, "set order"), "W") {
+			continue
+		}
+
+		date, err := parseDate(field(record, index, "date"), strongDateLayouts)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		reps, weight, err := parseRepsAndWeight(field(record, index, "reps"), field(record, index, "weight"))
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+		if reps == 0 {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: "sets without reps are not supported"})
+			continue
+		}
+
+		unit := p.Unit
+		if column := field(record, index, "weight unit"); column != "" {
+			if unit, err = parseUnit(column); err != nil {
+				rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+				continue
+			}
+		}
+
+		rows = append(rows, Row{
+			Line:     line,
+			Date:     date,
+			Exercise: field(record, index, "exercise name"),
+			Reps:     reps,
+			Weight:   weight,
+			Unit:     unit,
+		})
+	}
+
+	return rows, rowErrors, nil
+}
+
+// sniffDelimiter detects exports written with a semicolon separator, which Strong
+// uses in locales where the comma is the decimal separator.
+func sniffDelimiter(br *bufio.Reader) rune {
+	// Peek returns whatever is available when the file is shorter than requested
+	head, _ := br.Peek(4096)
+	first := string(head)
+	if i := strings.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		return ';'
+	}
+	return ','
+}
+
+func parseDate(value string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func parseRepsAndWeight(repsValue, weightValue string) (int32, float64, error) {
+	reps := 0.0
+	if repsValue != "" {
+		var err error
+		if reps, err = parseNumber(repsValue); err != nil || reps < 0 || reps != float64(int32(reps)) {
+			return 0, 0, fmt.Errorf("invalid reps %q", repsValue)
+		}
+	}
+
+	weight := 0.0
+	if weightValue != "" {
+		var err error
+		if weight, err = parseNumber(weightValue); err != nil || weight < 0 {
+			return 0, 0, fmt.Errorf("invalid weight %q", weightValue)
+		}
+	}
+	return int32(reps), weight, nil
+}
+
+// parseNumber accepts both decimal points and decimal commas.
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+func parseUnit(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "kg", "kgs", "metric":
+		return "metric", nil
+	case "lb", "lbs", "imperial":
+		return "imperial", nil
+	}
+	return "", fmt.Errorf("invalid unit %q", value)
+}
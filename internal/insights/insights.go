@@ -0,0 +1,231 @@
+package insights
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"new-chainsaw/internal/streaks"
+	"new-chainsaw/internal/strength"
+)
+
+// Kind is what an insight is about, matching the insight_kind enum.
+type Kind string
+
+const (
+	Plateau         Kind = "plateau"
+	Regression      Kind = "regression"
+	FastGain        Kind = "fast_gain"
+	SuspiciousEntry Kind = "suspicious_entry"
+)
+
+// LookbackWeeks is how many weeks of sets, including the current one, Detect needs.
+const LookbackWeeks = 18
+
+// Windows in weeks, counting back from and including the current week
+const (
+	plateauWeeks         = 6  // No new best for this long is a plateau
+	minPlateauWeeks      = 4  // Weeks trained in the plateau window
+	regressionWeeks      = 3  // Recent best compared to the peak before
+	minRegressionWeeks   = 2  // Weeks trained in the regression window
+	fastGainWeeks        = 4  // Recent best compared to the weeks before
+	fastGainBaselineWeek = 12 // Oldest week of the fast gain baseline
+)
+
+// Thresholds as ratios of estimated one rep maxes
+const (
+	plateauTolerance   = 1.01 // Improving by less than this is flat
+	regressionRatio    = 0.90
+	fastGainRatio      = 1.15
+	suspiciousJump     = 1.5 // A set this much above every earlier set is suspicious
+	minSetsForJump     = 3   // Earlier sets needed to judge a jump
+	maxPlausibleLoadKg = 600.0
+	maxBodyweightRatio = 5.0
+)
+
+// maxEstimateReps matches the records, longer sets do not estimate a one rep max.
+const maxEstimateReps = 12
+
+// Set is a reps and weight set of an exercise.
+type Set struct {
+	LogID        int32
+	ExerciseID   int32
+	Exercise     string
+	Date         time.Time
+	Reps         int32
+	LoadKg       float64
+	RPE          *float64
+	Bodyweight   bool    // A bodyweight exercise, the load includes the lifter's bodyweight
+	BodyweightKg float64 // The lifter's bodyweight when the set was logged
+}
+
+// Insight is something worth telling a lifter about their training. Weights are in kilograms.
+type Insight struct {
+	Kind           Kind      `json:"kind"`
+	Key            string    `json:"-"` // Identifies the insight across scans
+	ExerciseID     int32     `json:"exercise_id"`
+	Exercise       string    `json:"exercise"`
+	ExerciseLogID  *int32    `json:"exercise_log_id,omitempty"` // The suspicious set
+	Since          time.Time `json:"since"`                     // Date of the previous best, or of the suspicious set
+	Weeks          int       `json:"weeks,omitempty"`           // Weeks since the previous best
+	BestKg         float64   `json:"best_kg"`                   // Recent best estimated one rep max, or the load of the suspicious set
+	PreviousBestKg float64   `json:"previous_best_kg,omitempty"`
+	ChangePercent  float64   `json:"change_percent,omitempty"`
+	Reason         string    `json:"reason,omitempty"` // Why a set is suspicious
+}
+
+// estimate is a set's estimated one rep max.
+type estimate struct {
+	date time.Time
+	week time.Time
+	kg   float64
+}
+
+// Detect finds plateaus, regressions, fast gains and suspicious sets in sets ordered oldest
+// first. Suspicious sets are left out of the other insights.
+func Detect(sets []Set, now time.Time) []Insight {
+	current := streaks.WeekStart(now)
+	byExercise := make(map[int32][]Set)
+	order := []int32{}
+	for _, set := range sets {
+		if _, ok := byExercise[set.ExerciseID]; !ok {
+			order = append(order, set.ExerciseID)
+		}
+		byExercise[set.ExerciseID] = append(byExercise[set.ExerciseID], set)
+	}
+
+	found := []Insight{}
+	for _, exerciseID := range order {
+		exerciseSets := byExercise[exerciseID]
+		suspicious, estimates := screen(exerciseSets)
+		found = append(found, suspicious...)
+		if trend, ok := detectTrend(exerciseSets[0], estimates, current); ok {
+			found = append(found, trend)
+		}
+	}
+	return found
+}
+
+// screen flags suspicious sets and estimates the one rep max of the others.
+func screen(sets []Set) ([]Insight, []estimate) {
+	suspicious := []Insight{}
+	estimates := []estimate{}
+	top := 0.0
+	for _, set := range sets {
+		e1rm := 0.0
+		if reps := effectiveReps(set); reps <= maxEstimateReps && set.Reps > 0 {
+			if set.RPE != nil {
+				e1rm = strength.EstimatedOneRepMaxAtRPE(set.LoadKg, set.Reps, *set.RPE)
+			} else {
+				e1rm = strength.EstimatedOneRepMax(set.LoadKg, set.Reps)
+			}
+		}
+
+		reason := ""
+		switch {
+		case set.LoadKg > maxPlausibleLoadKg:
+			reason = fmt.Sprintf("more than %g kg", maxPlausibleLoadKg)
+		case !set.Bodyweight && set.BodyweightKg > 0 && set.LoadKg > maxBodyweightRatio*set.BodyweightKg:
+			reason = fmt.Sprintf("more than %g times bodyweight", maxBodyweightRatio)
+		case len(estimates) >= minSetsForJump && top > 0 && e1rm > suspiciousJump*top:
+			reason = fmt.Sprintf("estimated one rep max %d%% above the previous best", int(math.Round((e1rm/top-1)*100)))
+		}
+		if reason != "" {
+			logID := set.LogID
+			suspicious = append(suspicious, Insight{
+				Kind:           SuspiciousEntry,
+				Key:            fmt.Sprintf("%s:%d", SuspiciousEntry, set.LogID),
+				ExerciseID:     set.ExerciseID,
+				Exercise:       set.Exercise,
+				ExerciseLogID:  &logID,
+				Since:          set.Date,
+				BestKg:         round(set.LoadKg),
+				PreviousBestKg: round(top),
+				Reason:         reason,
+			})
+			continue
+		}
+
+		if e1rm > 0 {
+			estimates = append(estimates, estimate{date: set.Date, week: streaks.WeekStart(set.Date), kg: e1rm})
+			top = max(top, e1rm)
+		}
+	}
+	return suspicious, estimates
+}
+
+// detectTrend compares the recent estimated one rep maxes of an exercise with the weeks
+// before. A regression takes precedence over a plateau, and a plateau over a fast gain.
+func detectTrend(exercise Set, estimates []estimate, current time.Time) (Insight, bool) {
+	if len(estimates) == 0 {
+		return Insight{}, false
+	}
+	insight := Insight{ExerciseID: exercise.ExerciseID, Exercise: exercise.Exercise}
+
+	// Regression: the recent best dropped well below the peak before
+	recent, recentWeeks := best(estimates, current, 0, regressionWeeks-1)
+	peak, _ := best(estimates, current, regressionWeeks, LookbackWeeks-1)
+	if recentWeeks >= minRegressionWeeks && peak.kg > 0 && recent.kg < regressionRatio*peak.kg {
+		insight.Kind = Regression
+		insight.Key = fmt.Sprintf("%s:%d:%s", Regression, exercise.ExerciseID, peak.date.Format("2006-01-02"))
+		return withChange(insight, recent, peak, current), true
+	}
+
+	// Plateau: trained regularly without beating the best from before
+	recent, recentWeeks = best(estimates, current, 0, plateauWeeks-1)
+	peak, _ = best(estimates, current, plateauWeeks, LookbackWeeks-1)
+	if recentWeeks >= minPlateauWeeks && peak.kg > 0 && recent.kg < plateauTolerance*peak.kg && recent.kg >= regressionRatio*peak.kg {
+		insight.Kind = Plateau
+		insight.Key = fmt.Sprintf("%s:%d:%s", Plateau, exercise.ExerciseID, peak.date.Format("2006-01-02"))
+		return withChange(insight, recent, peak, current), true
+	}
+
+	// Fast gain: the recent best is far above the weeks before, at most once a month
+	recent, _ = best(estimates, current, 0, fastGainWeeks-1)
+	baseline, _ := best(estimates, current, fastGainWeeks, fastGainBaselineWeek-1)
+	if baseline.kg > 0 && recent.kg > fastGainRatio*baseline.kg {
+		insight.Kind = FastGain
+		insight.Key = fmt.Sprintf("%s:%d:%s", FastGain, exercise.ExerciseID, current.Format("2006-01"))
+		return withChange(insight, recent, baseline, current), true
+	}
+	return Insight{}, false
+}
+
+// best returns the best estimate from weeks newest to oldest weeks before the current one and
+// the number of weeks trained in between.
+func best(estimates []estimate, current time.Time, newest int, oldest int) (estimate, int) {
+	from := current.AddDate(0, 0, -7*oldest)
+	to := current.AddDate(0, 0, -7*newest)
+	top := estimate{}
+	weeks := make(map[time.Time]bool)
+	for _, e := range estimates {
+		if e.week.Before(from) || e.week.After(to) {
+			continue
+		}
+		weeks[e.week] = true
+		if e.kg > top.kg {
+			top = e
+		}
+	}
+	return top, len(weeks)
+}
+
+func withChange(insight Insight, recent estimate, previous estimate, current time.Time) Insight {
+	insight.Since = previous.date
+	insight.Weeks = int(current.Sub(previous.week).Hours() / 24 / 7)
+	insight.BestKg = round(recent.kg)
+	insight.PreviousBestKg = round(previous.kg)
+	insight.ChangePercent = math.Round((recent.kg/previous.kg-1)*1000) / 10
+	return insight
+}
+
+func effectiveReps(set Set) float64 {
+	if set.RPE != nil {
+		return float64(set.Reps) + 10 - *set.RPE
+	}
+	return float64(set.Reps)
+}
+
+func round(kg float64) float64 {
+	return math.Round(kg*100) / 100
+}
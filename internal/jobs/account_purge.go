@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/storage"
+)
+
+const purgeBatchSize = 100
+
+// PurgeDeletedAccounts hard deletes accounts whose deletion grace period has passed,
+// along with any files generated for them.
+func PurgeDeletedAccounts(queries *db.Queries, fileStorage storage.Storage) func(context.Context) error {
+	return func(ctx context.Context) error {
+		userIDs, err := queries.ListUsersDueForPurge(ctx, purgeBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list accounts due for purge: %w", err)
+		}
+
+		for _, userID := range userIDs {
+			keys, err := queries.GetDataExportStorageKeysByUserID(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("failed to list exports for user %d: %w", userID, err)
+			}
+			for _, key := range keys {
+				if err := fileStorage.Delete(ctx, key.String); err != nil {
+					return fmt.Errorf("failed to delete export %s for user %d: %w", key.String, userID, err)
+				}
+			}
+
+			// Everything owned by the user cascades from the users row
+			if err := queries.DeleteUser(ctx, userID); err != nil {
+				return fmt.Errorf("failed to purge user %d: %w", userID, err)
+			}
+			log.Printf("Purged account %d after deletion grace period\n", userID)
+		}
+
+		return nil
+	}
+}
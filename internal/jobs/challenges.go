@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/challenges"
+	"new-chainsaw/internal/events"
+)
+
+const challengeBatchSize = 100
+
+// FinalizeChallenges saves the final ranks of challenges that ended, which awards the challenge
+// trophy to the winners, and lets every participant know how they placed.
+func FinalizeChallenges(pool *pgxpool.Pool) func(context.Context) error {
+	queries := db.New(pool)
+	return func(ctx context.Context) error {
+		challengeIDs, err := queries.ListChallengesDueForFinalization(ctx, challengeBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list challenges due for finalization: %w", err)
+		}
+
+		for _, challengeID := range challengeIDs {
+			if err := finalizeChallenge(ctx, pool, challengeID); err != nil {
+				return fmt.Errorf("failed to finalize challenge %d: %w", challengeID, err)
+			}
+		}
+		return nil
+	}
+}
+
+func finalizeChallenge(ctx context.Context, pool *pgxpool.Pool, challengeID int32) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := db.New(tx)
+
+	// Marking first locks the challenge, so a concurrent run finds it finalized
+	marked, err := qtx.MarkChallengeFinalized(ctx, challengeID)
+	if err != nil || marked == 0 {
+		return err
+	}
+	challenge, err := qtx.GetChallenge(ctx, challengeID)
+	if err != nil {
+		return err
+	}
+	rows, err := qtx.GetChallengeStandings(ctx, challengeID)
+	if err != nil {
+		return err
+	}
+
+	ranked := challenges.Rank(challenges.Standings(challenge.Metric, rows))
+	for _, r := range ranked {
+		var value pgtype.Numeric
+		if err := value.Scan(fmt.Sprintf("%.2f", math.Round(r.Value*100)/100)); err != nil {
+			return err
+		}
+		err := qtx.SaveChallengeResult(ctx, db.SaveChallengeResultParams{
+			ChallengeID: challengeID,
+			UserID:      r.UserID,
+			FinalRank:   pgtype.Int4{Int32: int32(r.Rank), Valid: true},
+			FinalValue:  value,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = events.Emit(ctx, qtx, r.UserID, events.ChallengeFinished, events.ChallengeFinishedPayload{
+			ChallengeID:  challengeID,
+			Challenge:    challenge.Name,
+			Metric:       string(challenge.Metric),
+			Rank:         r.Rank,
+			Value:        r.Value,
+			Participants: len(ranked),
+			Won:          r.Won(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
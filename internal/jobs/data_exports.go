@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/storage"
+)
+
+const expiredExportBatchSize = 100
+
+// MaintainDataExports fails the exports a restart interrupted, so users can request new ones,
+// and deletes the archives of expired exports from storage.
+func MaintainDataExports(queries *db.Queries, fileStorage storage.Storage) func(context.Context) error {
+	return func(ctx context.Context) error {
+		failed, err := queries.FailStaleDataExports(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fail interrupted data exports: %w", err)
+		}
+		if failed > 0 {
+			log.Printf("Failed %d interrupted data exports\n", failed)
+		}
+
+		for {
+			expired, err := queries.ListExpiredDataExports(ctx, expiredExportBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to list expired data exports: %w", err)
+			}
+
+			for _, export := range expired {
+				if err := fileStorage.Delete(ctx, export.StorageKey.String); err != nil {
+					return fmt.Errorf("failed to delete archive of data export %d: %w", export.ID, err)
+				}
+				if err := queries.ClearDataExportArchive(ctx, export.ID); err != nil {
+					return fmt.Errorf("failed to clear archive of data export %d: %w", export.ID, err)
+				}
+			}
+			if len(expired) < expiredExportBatchSize {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/insights"
+	"new-chainsaw/internal/strength"
+)
+
+const (
+	insightBatchSize = 100
+	// insightRescanAfter is how often insights are refreshed for users whose logs did not change,
+	// since the windows they are detected in move on.
+	insightRescanAfter = 24 * time.Hour
+)
+
+// ScanInsights detects plateaus, regressions, fast gains and suspicious entries for users whose
+// exercise logs changed or whose insights are getting old.
+func ScanInsights(queries *db.Queries) func(context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		userIDs, err := queries.ListUsersDueForInsights(ctx, db.ListUsersDueForInsightsParams{
+			StaleBefore: pgtype.Timestamptz{Time: now.Add(-insightRescanAfter), Valid: true},
+			MaxUsers:    insightBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list users due for insights: %w", err)
+		}
+
+		// A user whose scan fails is retried with the rescans, recording the scan keeps them from
+		// holding up the users after them in every run
+		for _, userID := range userIDs {
+			if err := scanUserInsights(ctx, queries, userID, now); err != nil {
+				log.Printf("Failed to scan insights of user %d: %v\n", userID, err)
+				if err := queries.SaveInsightScan(ctx, userID); err != nil {
+					log.Printf("Failed to record failed insight scan of user %d: %v\n", userID, err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func scanUserInsights(ctx context.Context, queries *db.Queries, userID int32, now time.Time) error {
+	from := now.AddDate(0, 0, -7*insights.LookbackWeeks)
+	rows, err := queries.GetInsightSets(ctx, db.GetInsightSetsParams{
+		UserID:   userID,
+		FromDate: pgtype.Timestamptz{Time: from, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	sets := make([]insights.Set, len(rows))
+	for i, row := range rows {
+		bodyweight := numericToFloat(row.Bodyweight)
+		sets[i] = insights.Set{
+			LogID:        row.ID,
+			ExerciseID:   row.ExerciseID,
+			Exercise:     row.ExerciseName,
+			Date:         row.LogDate.Time,
+			Reps:         row.Reps,
+			LoadKg:       strength.Load(numericToFloat(row.Weight), numericToFloat(row.AdditionalWeight), row.ExerciseType, bodyweight),
+			Bodyweight:   row.ExerciseType.Valid,
+			BodyweightKg: bodyweight,
+		}
+		if row.Rpe.Valid {
+			rpe := numericToFloat(row.Rpe)
+			sets[i].RPE = &rpe
+		}
+	}
+
+	found := insights.Detect(sets, now)
+	keys := make([]string, len(found))
+	for i, insight := range found {
+		keys[i] = insight.Key
+		details, err := json.Marshal(insight)
+		if err != nil {
+			return err
+		}
+		var logID pgtype.Int4
+		if insight.ExerciseLogID != nil {
+			logID = pgtype.Int4{Int32: *insight.ExerciseLogID, Valid: true}
+		}
+		err = queries.UpsertInsight(ctx, db.UpsertInsightParams{
+			UserID:        userID,
+			Kind:          db.InsightKind(insight.Kind),
+			DedupeKey:     insight.Key,
+			ExerciseID:    insight.ExerciseID,
+			ExerciseLogID: logID,
+			Details:       details,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := queries.DeleteStaleInsights(ctx, db.DeleteStaleInsightsParams{UserID: userID, CurrentKeys: keys}); err != nil {
+		return err
+	}
+	return queries.SaveInsightScan(ctx, userID)
+}
+
+func numericToFloat(n pgtype.Numeric) float64 {
+	f, err := n.Float64Value()
+	if err != nil || !f.Valid {
+		return 0
+	}
+	return f.Float64
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/notifications"
+)
+
+const notificationBatchSize = 100
+
+// DeliverNotifications notifies users of the events emitted since the last run, on the
+// channels they enabled for each type. Events are claimed before they are delivered, so a
+// failed delivery is logged and not retried.
+func DeliverNotifications(queries *db.Queries, dispatcher *notifications.Dispatcher) func(context.Context) error {
+	return func(ctx context.Context) error {
+		for {
+			claimed, err := queries.ClaimUnnotifiedEvents(ctx, notificationBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to claim events: %w", err)
+			}
+			sort.Slice(claimed, func(i, j int) bool { return claimed[i].ID < claimed[j].ID })
+
+			for _, event := range claimed {
+				if err := notifyEvent(ctx, queries, dispatcher, event); err != nil {
+					log.Printf("Failed to notify event %d: %v\n", event.ID, err)
+				}
+			}
+			if len(claimed) < notificationBatchSize {
+				return nil
+			}
+		}
+	}
+}
+
+func notifyEvent(ctx context.Context, queries *db.Queries, dispatcher *notifications.Dispatcher, event db.Event) error {
+	eventType := events.Type(event.Type)
+	msg, ok, err := notifications.Render(eventType, event.Payload)
+	if err != nil || !ok {
+		return err
+	}
+
+	user, err := queries.GetUserAccount(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	if user.DeletedAt.Valid {
+		return nil
+	}
+
+	pref := notifications.DefaultPreference(eventType)
+	saved, err := queries.GetNotificationPreference(ctx, db.GetNotificationPreferenceParams{UserID: event.UserID, Type: event.Type})
+	if err == nil {
+		pref = notifications.Preference{InApp: saved.InApp, Email: saved.Email, Push: saved.Push}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	to := notifications.Recipient{UserID: user.ID, Email: user.Email, Name: user.Name.String}
+	return dispatcher.Dispatch(ctx, to, pref, msg)
+}
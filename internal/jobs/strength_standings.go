@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"new-chainsaw/db"
+)
+
+// RefreshStrengthStandings ranks every user's best sets against the population, so profiles
+// read the percentiles instead of ranking all exercise logs on every request.
+func RefreshStrengthStandings(pool *pgxpool.Pool) func(context.Context) error {
+	queries := db.New(pool)
+	return func(ctx context.Context) error {
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		qtx := queries.WithTx(tx)
+		if err := qtx.DeleteStrengthStandings(ctx); err != nil {
+			return fmt.Errorf("failed to delete strength standings: %w", err)
+		}
+		if _, err := qtx.RefreshStrengthStandings(ctx); err != nil {
+			return fmt.Errorf("failed to refresh strength standings: %w", err)
+		}
+		return tx.Commit(ctx)
+	}
+}
@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/webhooks"
+)
+
+const webhookBatchSize = 20
+
+// DeliverWebhooks posts the due deliveries of the outbox to their webhooks. Failed attempts are
+// retried with exponential backoff until webhooks.MaxAttempts, then the delivery fails.
+func DeliverWebhooks(queries *db.Queries, client *webhooks.Client) func(context.Context) error {
+	return func(ctx context.Context) error {
+		for {
+			claimed, err := queries.ClaimDueWebhookDeliveries(ctx, webhookBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+			}
+
+			for _, delivery := range claimed {
+				if err := deliverWebhook(ctx, queries, client, delivery); err != nil {
+					log.Printf("Failed to record webhook delivery %d: %v\n", delivery.ID, err)
+				}
+			}
+			if len(claimed) < webhookBatchSize {
+				return nil
+			}
+		}
+	}
+}
+
+func deliverWebhook(ctx context.Context, queries *db.Queries, client *webhooks.Client, delivery db.ClaimDueWebhookDeliveriesRow) error {
+	payload := webhooks.Payload{
+		DeliveryID: delivery.ID,
+		Type:       events.Type(delivery.EventType),
+		UserID:     delivery.UserID,
+		CreatedAt:  delivery.CreatedAt.Time,
+		Data:       delivery.Payload,
+	}
+	if delivery.EventID.Valid {
+		payload.EventID = &delivery.EventID.Int64
+	}
+
+	status, sendErr := client.Send(ctx, delivery.Url, delivery.Secret, payload)
+	attempt := db.RecordWebhookAttemptParams{
+		ID:             delivery.ID,
+		Status:         db.WebhookDeliveryStatusDelivered,
+		ResponseStatus: pgtype.Int4{Int32: int32(status), Valid: status != 0},
+	}
+	if sendErr != nil {
+		attempts := int(delivery.Attempts) + 1
+		attempt.LastError = pgtype.Text{String: sendErr.Error(), Valid: true}
+		attempt.Status = db.WebhookDeliveryStatusPending
+		attempt.NextAttemptAt = pgtype.Timestamptz{Time: time.Now().Add(webhooks.Backoff(attempts)), Valid: true}
+		if attempts >= webhooks.MaxAttempts {
+			attempt.Status = db.WebhookDeliveryStatusFailed
+			log.Printf("Webhook delivery %d to webhook %d failed after %d attempts: %v\n", delivery.ID, delivery.WebhookID, attempts, sendErr)
+		}
+	}
+	return queries.RecordWebhookAttempt(ctx, attempt)
+}
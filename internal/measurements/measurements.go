@@ -0,0 +1,102 @@
+package measurements
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kind is what a body measurement measures, matching the body_measurement_kind enum.
+type Kind string
+
+const (
+	Neck         Kind = "neck"
+	Shoulders    Kind = "shoulders"
+	Chest        Kind = "chest"
+	Waist        Kind = "waist"
+	Hips         Kind = "hips"
+	LeftArm      Kind = "left_arm"
+	RightArm     Kind = "right_arm"
+	LeftForearm  Kind = "left_forearm"
+	RightForearm Kind = "right_forearm"
+	LeftThigh    Kind = "left_thigh"
+	RightThigh   Kind = "right_thigh"
+	LeftCalf     Kind = "left_calf"
+	RightCalf    Kind = "right_calf"
+	BodyFat      Kind = "body_fat"
+)
+
+// Kinds lists every kind in the order measurements are shown.
+var Kinds = []Kind{
+	Neck, Shoulders, Chest, Waist, Hips, LeftArm, RightArm, LeftForearm, RightForearm,
+	LeftThigh, RightThigh, LeftCalf, RightCalf, BodyFat,
+}
+
+// Dimension is what the value of a measurement is.
+type Dimension int
+
+const (
+	Length     Dimension = iota // Circumference, stored in centimeters
+	Percentage                  // Share of bodyweight
+)
+
+// Limits of plausible values
+const (
+	minLength  = 5.0
+	maxLength  = 300.0
+	minBodyFat = 2.0
+	maxBodyFat = 70.0
+)
+
+var ErrInvalidMeasurement = errors.New("invalid measurement")
+
+// ParseKind returns the kind with the given name.
+func ParseKind(name string) (Kind, error) {
+	for _, kind := range Kinds {
+		if string(kind) == name {
+			return kind, nil
+		}
+	}
+	names := make([]string, len(Kinds))
+	for i, kind := range Kinds {
+		names[i] = string(kind)
+	}
+	return "", invalid(fmt.Sprintf("kind must be one of %s", strings.Join(names, ", ")))
+}
+
+func (k Kind) Dimension() Dimension {
+	if k == BodyFat {
+		return Percentage
+	}
+	return Length
+}
+
+// Validate checks that a value in centimeters or percent is plausible for the kind.
+func (k Kind) Validate(value float64) error {
+	if k.Dimension() == Percentage {
+		if value < minBodyFat || value > maxBodyFat {
+			return invalid(fmt.Sprintf("%s must be between %g and %g percent", k, minBodyFat, maxBodyFat))
+		}
+		return nil
+	}
+	if value < minLength || value > maxLength {
+		return invalid(fmt.Sprintf("%s must be between %g and %g cm", k, minLength, maxLength))
+	}
+	return nil
+}
+
+// Composition splits bodyweight into lean and fat mass.
+type Composition struct {
+	LeanMass float64
+	FatMass  float64
+}
+
+// Compose works out the body composition of a bodyweight at a body fat percentage.
+func Compose(bodyweight float64, bodyFatPercent float64) Composition {
+	fat := bodyweight * bodyFatPercent / 100
+	return Composition{LeanMass: bodyweight - fat, FatMass: fat}
+}
+
+func invalid(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidMeasurement, message)
+}
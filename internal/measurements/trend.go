@@ -0,0 +1,54 @@
+package measurements
+
+import (
+	"math"
+	"time"
+)
+
+// minTrendDays is the shortest span of readings a weekly rate is estimated from.
+const minTrendDays = 7
+
+// Reading is a measurement of one kind at a time.
+type Reading struct {
+	Date  time.Time
+	Value float64
+}
+
+// Trend summarizes readings of one kind.
+type Trend struct {
+	First        float64
+	Latest       float64
+	Change       float64  // Latest minus first
+	WeeklyChange *float64 // Slope of a line fitted through the readings, nil when they span less than a week
+}
+
+// Summarize fits a line through readings ordered oldest first. Measurements are taken every
+// week or two, so every reading counts instead of a smoothed daily series like bodyweight.
+func Summarize(readings []Reading) (Trend, bool) {
+	if len(readings) == 0 {
+		return Trend{}, false
+	}
+	first, latest := readings[0], readings[len(readings)-1]
+	trend := Trend{First: first.Value, Latest: latest.Value, Change: round(latest.Value - first.Value)}
+	if latest.Date.Sub(first.Date).Hours()/24 < minTrendDays {
+		return trend, true
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, reading := range readings {
+		x := reading.Date.Sub(first.Date).Hours() / 24
+		sumX += x
+		sumY += reading.Value
+		sumXY += x * reading.Value
+		sumXX += x * x
+	}
+	n := float64(len(readings))
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	weekly := round(slope * 7)
+	trend.WeeklyChange = &weekly
+	return trend, true
+}
+
+func round(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
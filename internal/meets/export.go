@@ -0,0 +1,88 @@
+package meets
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"new-chainsaw/db"
+)
+
+var csvColumns = []string{
+	"Place", "Name", "Sex", "Event", "Equipment", "Age", "Division", "BodyweightKg", "WeightClassKg",
+	"Squat1Kg", "Squat2Kg", "Squat3Kg", "Best3SquatKg",
+	"Bench1Kg", "Bench2Kg", "Bench3Kg", "Best3BenchKg",
+	"Deadlift1Kg", "Deadlift2Kg", "Deadlift3Kg", "Best3DeadliftKg",
+	"TotalKg", "Dots", "Goodlift", "Date", "MeetName",
+}
+
+// WriteCSV writes placings in the OpenPowerlifting format, one row per lifter. Missed attempts
+// are negative weights and lifters without a total are placed DQ.
+func WriteCSV(w io.Writer, meetName string, date string, placings []Placing, divisions map[int32]Division) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return err
+	}
+
+	for _, p := range placings {
+		division := divisions[p.DivisionID]
+		place := "DQ"
+		if p.TotalKg != nil {
+			place = strconv.Itoa(p.Place)
+		}
+		age := ""
+		if p.Age != nil {
+			age = strconv.Itoa(*p.Age)
+		}
+
+		record := []string{place, p.Name, sexCode(p.Sex), "SBD", "Raw", age, division.Name, optionalFloat(p.BodyweightKg), optionalFloat(division.WeightClassKg)}
+		for _, lift := range Lifts {
+			attempts := make([]string, Attempts)
+			for _, attempt := range p.Attempts {
+				if attempt.Lift != lift || attempt.Number < 1 || attempt.Number > Attempts {
+					continue
+				}
+				switch attempt.Result {
+				case db.AttemptResultGood:
+					attempts[attempt.Number-1] = formatFloat(attempt.WeightKg)
+				case db.AttemptResultNoLift:
+					attempts[attempt.Number-1] = formatFloat(-attempt.WeightKg)
+				}
+			}
+			best := ""
+			if weight, ok := p.BestKg[lift]; ok {
+				best = formatFloat(weight)
+			}
+			record = append(record, attempts...)
+			record = append(record, best)
+		}
+		record = append(record, optionalFloat(p.TotalKg), optionalFloat(p.Dots), optionalFloat(p.Goodlift), date, meetName)
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func sexCode(sex string) string {
+	switch sex {
+	case "male":
+		return "M"
+	case "female":
+		return "F"
+	}
+	return ""
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func optionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return formatFloat(*f)
+}
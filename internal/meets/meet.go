@@ -0,0 +1,206 @@
+package meets
+
+import (
+	"fmt"
+	"sort"
+
+	"new-chainsaw/db"
+)
+
+// Attempts is the number of attempts every lifter gets on each lift.
+const Attempts = 3
+
+// maxAttemptKg is the heaviest attempt that can be declared.
+const maxAttemptKg = 600.0
+
+// Lifts are the lifts of a meet in the order they are contested.
+var Lifts = []db.MeetLift{db.MeetLiftSquat, db.MeetLiftBench, db.MeetLiftDeadlift}
+
+// ExerciseNames are the catalog exercises good lifts are logged as when a meet closes.
+var ExerciseNames = map[db.MeetLift]string{
+	db.MeetLiftSquat:    "Back Squat",
+	db.MeetLiftBench:    "Bench Press",
+	db.MeetLiftDeadlift: "Deadlift",
+}
+
+// Division groups the lifters that compete against each other.
+type Division struct {
+	ID            int32
+	Name          string
+	Sex           string
+	WeightClassKg *float64 // Upper limit, nil for an open class
+	MinAge        *int
+	MaxAge        *int
+}
+
+// Attempt is a declared attempt on a lift.
+type Attempt struct {
+	ID       int32
+	Lift     db.MeetLift
+	Number   int
+	WeightKg float64
+	Result   db.AttemptResult
+}
+
+// Entry is a lifter in a division with their attempts.
+type Entry struct {
+	ID           int32
+	Name         string
+	Sex          string
+	Age          *int
+	DivisionID   int32
+	BodyweightKg *float64 // Weigh-in, nil before it
+	Attempts     []Attempt
+}
+
+// Placing is the result of an entry. Lifters without a good lift on every lift have no total
+// and are not placed.
+type Placing struct {
+	Entry
+	BestKg       map[db.MeetLift]float64 // Heaviest good attempt of each lift
+	TotalKg      *float64
+	Dots         *float64
+	Goodlift     *float64
+	Place        int // In the division
+	OverallPlace int // By the points the meet is scored by
+}
+
+// Admits checks that a lifter of a sex and age can enter a division.
+func (d Division) Admits(sex string, age *int) error {
+	if sex != d.Sex {
+		return fmt.Errorf("the %s division is for %s lifters", d.Name, d.Sex)
+	}
+	if d.MinAge == nil && d.MaxAge == nil {
+		return nil
+	}
+	switch {
+	case age == nil:
+		return fmt.Errorf("the %s division has an age limit, the lifter's age is required", d.Name)
+	case d.MinAge != nil && *age < *d.MinAge:
+		return fmt.Errorf("the %s division is for lifters aged %d and up", d.Name, *d.MinAge)
+	case d.MaxAge != nil && *age > *d.MaxAge:
+		return fmt.Errorf("the %s division is for lifters aged up to %d", d.Name, *d.MaxAge)
+	}
+	return nil
+}
+
+// CheckWeighIn checks that a weigh-in makes the weight class of a division.
+func (d Division) CheckWeighIn(bodyweightKg float64) error {
+	if d.WeightClassKg != nil && bodyweightKg > *d.WeightClassKg {
+		return fmt.Errorf("%g kg is over the %g kg limit of the %s division", bodyweightKg, *d.WeightClassKg, d.Name)
+	}
+	return nil
+}
+
+// CheckDeclaration checks an attempt against the attempts already declared on the lift. The
+// previous attempt must have been judged, and the weight may only go up after a good lift.
+func CheckDeclaration(attempts []Attempt, lift db.MeetLift, number int, weightKg float64) error {
+	if _, ok := ExerciseNames[lift]; !ok {
+		return fmt.Errorf("lift must be squat, bench or deadlift")
+	}
+	if number < 1 || number > Attempts {
+		return fmt.Errorf("attempt must be between 1 and %d", Attempts)
+	}
+	if weightKg <= 0 || weightKg > maxAttemptKg {
+		return fmt.Errorf("weight must be between 0 and %g kg", maxAttemptKg)
+	}
+
+	for _, attempt := range attempts {
+		if attempt.Lift != lift {
+			continue
+		}
+		switch attempt.Number {
+		case number:
+			if attempt.Result != db.AttemptResultPending {
+				return fmt.Errorf("attempt %d was already judged", number)
+			}
+		case number - 1:
+			switch attempt.Result {
+			case db.AttemptResultPending:
+				return fmt.Errorf("attempt %d has to be judged first", number-1)
+			case db.AttemptResultGood:
+				if weightKg <= attempt.WeightKg {
+					return fmt.Errorf("after a good lift the weight has to go up from %g kg", attempt.WeightKg)
+				}
+			case db.AttemptResultNoLift:
+				if weightKg < attempt.WeightKg {
+					return fmt.Errorf("after a missed lift the weight cannot go down from %g kg", attempt.WeightKg)
+				}
+			}
+			return nil
+		}
+	}
+	if number > 1 {
+		return fmt.Errorf("attempt %d has to be declared first", number-1)
+	}
+	return nil
+}
+
+// Rank works out the best lifts, totals and points of the entries and places them within their
+// divisions and overall. A tie on total goes to the lighter lifter, then to the earlier entry.
+// Placings are in the order of the entries.
+func Rank(entries []Entry, scoring db.MeetScoring) []Placing {
+	placings := make([]Placing, len(entries))
+	for i, entry := range entries {
+		placings[i] = place(entry)
+	}
+
+	ranked := make([]*Placing, 0, len(placings))
+	for i := range placings {
+		if placings[i].TotalKg != nil {
+			ranked = append(ranked, &placings[i])
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if *a.TotalKg != *b.TotalKg {
+			return *a.TotalKg > *b.TotalKg
+		}
+		if *a.BodyweightKg != *b.BodyweightKg {
+			return *a.BodyweightKg < *b.BodyweightKg
+		}
+		return a.ID < b.ID
+	})
+	places := make(map[int32]int)
+	for _, p := range ranked {
+		places[p.DivisionID]++
+		p.Place = places[p.DivisionID]
+	}
+
+	points := func(p *Placing) float64 {
+		if scoring == db.MeetScoringIpfGl {
+			return *p.Goodlift
+		}
+		return *p.Dots
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return points(ranked[i]) > points(ranked[j])
+	})
+	for i, p := range ranked {
+		p.OverallPlace = i + 1
+	}
+	return placings
+}
+
+func place(entry Entry) Placing {
+	placing := Placing{Entry: entry, BestKg: make(map[db.MeetLift]float64)}
+	for _, attempt := range entry.Attempts {
+		if attempt.Result == db.AttemptResultGood && attempt.WeightKg > placing.BestKg[attempt.Lift] {
+			placing.BestKg[attempt.Lift] = attempt.WeightKg
+		}
+	}
+	if len(placing.BestKg) < len(Lifts) || entry.BodyweightKg == nil {
+		return placing
+	}
+
+	total := 0.0
+	for _, lift := range Lifts {
+		total += placing.BestKg[lift]
+	}
+	dotsPoints := Dots(entry.Sex, *entry.BodyweightKg, total)
+	goodliftPoints := Goodlift(entry.Sex, *entry.BodyweightKg, total)
+	placing.TotalKg = &total
+	placing.Dots = &dotsPoints
+	placing.Goodlift = &goodliftPoints
+	return placing
+}
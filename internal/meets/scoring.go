@@ -0,0 +1,46 @@
+package meets
+
+import "math"
+
+// dotsCoefficients are the polynomial coefficients of the DOTS formula from the fourth power of
+// bodyweight down to the constant, with the bodyweights the formula is defined for.
+type dotsCoefficients struct {
+	a, b, c, d, e   float64
+	minBodyweightKg float64
+	maxBodyweightKg float64
+}
+
+var dots = map[string]dotsCoefficients{
+	"male":   {-0.0000010930, 0.0007391293, -0.1918759221, 24.0900756, -307.75076, 40, 210},
+	"female": {-0.0000010706, 0.0005158568, -0.1126655495, 13.6175032, -57.96288, 40, 150},
+}
+
+// goodliftCoefficients are the IPF GL coefficients for classic (raw) three lift totals.
+var goodliftCoefficients = map[string][3]float64{
+	"male":   {1199.72839, 1025.18162, 0.00921},
+	"female": {610.32796, 1045.59282, 0.03048},
+}
+
+// Dots returns the DOTS points of a total, zero for an unknown sex.
+func Dots(sex string, bodyweightKg float64, totalKg float64) float64 {
+	k, ok := dots[sex]
+	if !ok {
+		return 0
+	}
+	bw := math.Max(k.minBodyweightKg, math.Min(k.maxBodyweightKg, bodyweightKg))
+	denominator := k.a*math.Pow(bw, 4) + k.b*math.Pow(bw, 3) + k.c*bw*bw + k.d*bw + k.e
+	return round(totalKg * 500 / denominator)
+}
+
+// Goodlift returns the IPF GL points of a classic total, zero for an unknown sex.
+func Goodlift(sex string, bodyweightKg float64, totalKg float64) float64 {
+	k, ok := goodliftCoefficients[sex]
+	if !ok || bodyweightKg <= 0 {
+		return 0
+	}
+	return round(totalKg * 100 / (k[0] - k[1]*math.Exp(-k[2]*bodyweightKg)))
+}
+
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
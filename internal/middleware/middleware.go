@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"log"
 	"net/http"
@@ -23,6 +25,8 @@ type Claims struct {
 	AvatarURL string `json:"avatar_url"`
 	Name      string `json:"name"`
 	IsNewUser bool   `json:"is_new_user"`
+	// PendingDeletion is set when the user signed in during their account deletion grace period
+	PendingDeletion bool `json:"pending_deletion"`
 	jwt.RegisteredClaims
 }
 
@@ -54,6 +58,8 @@ func JWTMiddleware(dbPool *pgxpool.Pool) gin.HandlerFunc {
 		c.Set("avatar_url", claims.AvatarURL)
 		c.Set("name", claims.Name)
 		c.Set("is_new_user", claims.IsNewUser)
+		c.Set("pending_deletion", claims.PendingDeletion)
+		c.Set("token_expires_at", claims.ExpiresAt.Time)
 
 		c.Next()
 	}
@@ -77,7 +83,8 @@ func handleRefreshToken(c *gin.Context, dbPool *pgxpool.Pool) {
 	}
 
 	// Assume isNewUser is false for refresh tokens since we don't store it in the database
-	token, err := GenerateJWT(int(user.ID), user.Username, user.Name.String, user.Email, user.AvatarUrl.String, false)
+	expiresAt := time.Now().Add(config.JwtExpiration)
+	token, err := GenerateJWT(int(user.ID), user.Username, user.Name.String, user.Email, user.AvatarUrl.String, false, user.DeletedAt.Valid)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate JWT token"})
 		c.Abort()
@@ -97,19 +104,55 @@ func handleRefreshToken(c *gin.Context, dbPool *pgxpool.Pool) {
 	c.Set("email", user.Email)
 	c.Set("avatar_url", user.AvatarUrl.String)
 	c.Set("name", user.Name.String)
+	c.Set("pending_deletion", user.DeletedAt.Valid)
+	c.Set("token_expires_at", expiresAt)
 
 	c.Next()
 }
 
-func GenerateJWT(userID int, username, name, email, avatarURL string, isNewUser bool) (string, error) {
+// AccountStatusQuerier looks up whether an account is pending deletion, *db.Queries implements it.
+type AccountStatusQuerier interface {
+	GetUserDeletionStatus(ctx context.Context, id int32) (db.GetUserDeletionStatusRow, error)
+}
+
+// RequireActiveAccount rejects requests from accounts that are pending deletion.
+// Those users can only see their session, restore the account or sign out. The account is
+// checked in the database, access tokens issued before a deletion or restore are still valid.
+func RequireActiveAccount(queries AccountStatusQuerier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, err := queries.GetUserDeletionStatus(c.Request.Context(), int32(c.GetInt("userID")))
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Account no longer exists"})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to fetch account status: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch account status"})
+			c.Abort()
+			return
+		}
+		if status.DeletedAt.Valid {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is pending deletion"})
+			c.Abort()
+			return
+		}
+
+		c.Set("pending_deletion", false)
+		c.Next()
+	}
+}
+
+func GenerateJWT(userID int, username, name, email, avatarURL string, isNewUser, pendingDeletion bool) (string, error) {
 	expirationTime := time.Now().Add(config.JwtExpiration)
 	claims := &Claims{
-		UserID:    userID,
-		Username:  username,
-		Email:     email,
-		AvatarURL: avatarURL,
-		Name:      name,
-		IsNewUser: isNewUser,
+		UserID:          userID,
+		Username:        username,
+		Email:           email,
+		AvatarURL:       avatarURL,
+		Name:            name,
+		IsNewUser:       isNewUser,
+		PendingDeletion: pendingDeletion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
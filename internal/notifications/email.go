@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPChannel emails notifications through an SMTP server.
+type SMTPChannel struct {
+	addr string
+	auth smtp.Auth
+	from mail.Address
+}
+
+// NewSMTPChannel returns a channel sending from the given address. Servers that need no
+// authentication are used without a username.
+func NewSMTPChannel(host string, port string, username string, password string, from string) (*SMTPChannel, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPChannel{addr: net.JoinHostPort(host, port), auth: auth, from: *address}, nil
+}
+
+func (c *SMTPChannel) Name() string {
+	return ChannelEmail
+}
+
+func (c *SMTPChannel) Deliver(ctx context.Context, to Recipient, msg Message) error {
+	if to.Email == "" {
+		return nil
+	}
+	recipient := mail.Address{Name: to.Name, Address: to.Email}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", c.from.String())
+	fmt.Fprintf(&body, "To: %s\r\n", recipient.String())
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	body.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	body.WriteString(msg.Body)
+	body.WriteString("\r\n")
+
+	return smtp.SendMail(c.addr, c.auth, c.from.Address, []string{to.Email}, body.Bytes())
+}
@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+)
+
+// Delivery is a notification delivered by a FakeChannel.
+type Delivery struct {
+	To      Recipient
+	Message Message
+}
+
+// FakeChannel records the notifications it delivers instead of sending them, for tests.
+type FakeChannel struct {
+	name string
+	err  error
+
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+// NewFakeChannel returns a channel with the given name that fails every delivery with err
+// when err is not nil.
+func NewFakeChannel(name string, err error) *FakeChannel {
+	return &FakeChannel{name: name, err: err}
+}
+
+func (c *FakeChannel) Name() string {
+	return c.name
+}
+
+func (c *FakeChannel) Deliver(ctx context.Context, to Recipient, msg Message) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deliveries = append(c.deliveries, Delivery{To: to, Message: msg})
+	return nil
+}
+
+// Deliveries returns the notifications delivered so far.
+func (c *FakeChannel) Deliveries() []Delivery {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Delivery(nil), c.deliveries...)
+}
@@ -0,0 +1,31 @@
+package notifications
+
+import (
+	"context"
+
+	"new-chainsaw/db"
+)
+
+// InAppChannel stores notifications for the notification center.
+type InAppChannel struct {
+	queries *db.Queries
+}
+
+func NewInAppChannel(queries *db.Queries) *InAppChannel {
+	return &InAppChannel{queries: queries}
+}
+
+func (c *InAppChannel) Name() string {
+	return ChannelInApp
+}
+
+func (c *InAppChannel) Deliver(ctx context.Context, to Recipient, msg Message) error {
+	_, err := c.queries.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID: to.UserID,
+		Type:   string(msg.Type),
+		Title:  msg.Title,
+		Body:   msg.Body,
+		Data:   msg.Data,
+	})
+	return err
+}
@@ -0,0 +1,199 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"new-chainsaw/internal/events"
+)
+
+// Channels a notification is delivered on.
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+	ChannelPush  = "push"
+)
+
+// maxBodyLength is how much of a comment is quoted in a notification.
+const maxBodyLength = 140
+
+// Types lists the events users are notified of, in the order their preferences are shown.
+var Types = []events.Type{
+	events.TrophyUnlocked,
+	events.GoalReached,
+	events.CommentAdded,
+	events.CommentMentioned,
+	events.ChallengeInvited,
+	events.ChallengeFinished,
+	events.CoachAssigned,
+}
+
+// Preference is the channels a user is notified on for a type of notification.
+type Preference struct {
+	InApp bool `json:"in_app"`
+	Email bool `json:"email"`
+	Push  bool `json:"push"`
+}
+
+// Allows reports whether the preference enables a channel.
+func (p Preference) Allows(channel string) bool {
+	switch channel {
+	case ChannelInApp:
+		return p.InApp
+	case ChannelEmail:
+		return p.Email
+	case ChannelPush:
+		return p.Push
+	}
+	return false
+}
+
+// DefaultPreference is the preference of users who did not choose one for a type. Invites and
+// assignments wait on the user, so they are also emailed.
+func DefaultPreference(t events.Type) Preference {
+	switch t {
+	case events.ChallengeInvited, events.CoachAssigned:
+		return Preference{InApp: true, Email: true, Push: true}
+	}
+	return Preference{InApp: true, Push: true}
+}
+
+// IsType reports whether users are notified of an event type.
+func IsType(t events.Type) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Message is a notification as delivered on every channel.
+type Message struct {
+	Type  events.Type     `json:"type"`
+	Title string          `json:"title"`
+	Body  string          `json:"body"`
+	Data  json.RawMessage `json:"data"` // The event payload
+}
+
+// Render writes the notification of an event. It returns false for events users are not
+// notified of.
+func Render(eventType events.Type, payload []byte) (Message, bool, error) {
+	msg := Message{Type: eventType, Data: payload}
+	var err error
+	switch eventType {
+	case events.TrophyUnlocked:
+		var p events.TrophyUnlockedPayload
+		if err = json.Unmarshal(payload, &p); err == nil {
+			msg.Title = "Trophy unlocked"
+			msg.Body = fmt.Sprintf("You unlocked the %s trophy.", p.Trophy)
+		}
+	case events.GoalReached:
+		var p events.GoalReachedPayload
+		if err = json.Unmarshal(payload, &p); err == nil {
+			msg.Title = "Goal reached"
+			msg.Body = fmt.Sprintf("You reached your %s goal.", p.Exercise)
+		}
+	case events.CommentAdded:
+		var p events.CommentAddedPayload
+		if err = json.Unmarshal(payload, &p); err == nil {
+			msg.Title = fmt.Sprintf("%s commented on your %s", p.Author, subjectName(p.Subject))
+			if p.Reply {
+				msg.Title = fmt.Sprintf("%s replied to your comment", p.Author)
+			}
+			msg.Body = quote(p.Body)
+		}
+	case events.CommentMentioned:
+		var p events.CommentMentionedPayload
+		if err = json.Unmarshal(payload, &p); err == nil {
+			msg.Title = fmt.Sprintf("%s mentioned you", p.Author)
+			msg.Body = quote(p.Body)
+		}
+	case events.ChallengeInvited:
+		var p events.ChallengeInvitedPayload
+		if err = json.Unmarshal(payload, &p); err == nil {
+			msg.Title = "Challenge invite"
+			msg.Body = fmt.Sprintf("%s invited you to %s.", p.InvitedBy, p.Challenge)
+		}
+	case events.ChallengeFinished:
+		var p events.ChallengeFinishedPayload
+		if err = json.Unmarshal(payload, &p); err == nil {
+			msg.Title = fmt.Sprintf("%s finished", p.Challenge)
+			msg.Body = fmt.Sprintf("You finished #%d of %d.", p.Rank, p.Participants)
+			if p.Won {
+				msg.Body = fmt.Sprintf("You won, finishing first of %d!", p.Participants)
+			}
+		}
+	case events.CoachAssigned:
+		var p events.CoachAssignedPayload
+		if err = json.Unmarshal(payload, &p); err == nil {
+			msg.Title = "New assignment"
+			msg.Body = fmt.Sprintf("%s assigned you %s in %s.", p.Coach, p.Name, p.Organization)
+		}
+	default:
+		return Message{}, false, nil
+	}
+	if err != nil {
+		return Message{}, false, fmt.Errorf("invalid %s payload: %w", eventType, err)
+	}
+	return msg, true, nil
+}
+
+func subjectName(subject string) string {
+	if subject == "personal_record" {
+		return "personal record"
+	}
+	return subject
+}
+
+// quote shortens a comment to the length shown in notifications.
+func quote(body string) string {
+	if utf8.RuneCountInString(body) <= maxBodyLength {
+		return body
+	}
+	return string([]rune(body)[:maxBodyLength-1]) + "…"
+}
+
+// Recipient is the user a notification is delivered to.
+type Recipient struct {
+	UserID int32
+	Email  string
+	Name   string
+}
+
+// Channel delivers notifications.
+type Channel interface {
+	// Name is the channel preferences refer to, ChannelInApp, ChannelEmail or ChannelPush.
+	Name() string
+
+	// Deliver sends a notification to a recipient. Recipients the channel cannot reach, such
+	// as users without a push subscription, are not an error.
+	Deliver(ctx context.Context, to Recipient, msg Message) error
+}
+
+// Dispatcher delivers notifications on the channels recipients enabled.
+type Dispatcher struct {
+	channels []Channel
+}
+
+func NewDispatcher(channels ...Channel) *Dispatcher {
+	return &Dispatcher{channels: channels}
+}
+
+// Dispatch delivers a notification on every channel the preference allows. A failing channel
+// does not stop the others, the errors are joined.
+func (d *Dispatcher) Dispatch(ctx context.Context, to Recipient, pref Preference, msg Message) error {
+	var errs []error
+	for _, channel := range d.channels {
+		if !pref.Allows(channel.Name()) {
+			continue
+		}
+		if err := channel.Deliver(ctx, to, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
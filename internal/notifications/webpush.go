@@ -0,0 +1,231 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/httpclient"
+)
+
+const (
+	// pushTTL is how long push services keep a notification for a browser that is offline.
+	pushTTL = 24 * time.Hour
+	// pushRecordSize is the size of the single encrypted record a push carries.
+	pushRecordSize = 4096
+	// vapidExpiration is how long the VAPID token of a push is valid, at most 24 hours.
+	vapidExpiration = 12 * time.Hour
+)
+
+// WebPushChannel pushes notifications to the browsers users subscribed, encrypted as RFC 8291
+// requires and identified by the server's VAPID key (RFC 8292). Endpoints are submitted by
+// users, so pushes only go to public addresses.
+type WebPushChannel struct {
+	queries    *db.Queries
+	privateKey *ecdsa.PrivateKey
+	publicKey  string // Uncompressed point, base64url
+	subject    string // mailto: or https: URL push services can contact
+	client     *http.Client
+}
+
+// NewWebPushChannel returns a channel pushing with a VAPID key pair, base64url encoded as
+// browsers expect the public key.
+func NewWebPushChannel(queries *db.Queries, publicKey string, privateKey string, subject string) (*WebPushChannel, error) {
+	d, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(privateKey, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	point := key.PublicKey().Bytes()
+	if strings.TrimRight(publicKey, "=") != base64.RawURLEncoding.EncodeToString(point) {
+		return nil, errors.New("VAPID public key does not match the private key")
+	}
+
+	return &WebPushChannel{
+		queries: queries,
+		privateKey: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(point[1:33]),
+				Y:     new(big.Int).SetBytes(point[33:]),
+			},
+			D: new(big.Int).SetBytes(d),
+		},
+		publicKey: base64.RawURLEncoding.EncodeToString(point),
+		subject:   subject,
+		client:    httpclient.NewPublicClient(10*time.Second, false),
+	}, nil
+}
+
+func (c *WebPushChannel) Name() string {
+	return ChannelPush
+}
+
+// Deliver pushes to every browser of the recipient. The browser shows the title and body, the
+// data stays in the notification center as pushes are small.
+func (c *WebPushChannel) Deliver(ctx context.Context, to Recipient, msg Message) error {
+	subscriptions, err := c.queries.ListPushSubscriptions(ctx, to.UserID)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+	payload, err := json.Marshal(Message{Type: msg.Type, Title: msg.Title, Body: msg.Body})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, subscription := range subscriptions {
+		if err := c.push(ctx, subscription, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *WebPushChannel) push(ctx context.Context, subscription db.PushSubscription, payload []byte) error {
+	body, err := EncryptPushPayload(subscription.P256dh, subscription.Auth, payload)
+	if err != nil {
+		return err
+	}
+	authorization, err := c.vapidAuthorization(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(pushTTL.Seconds())))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		// The browser unsubscribed
+		_, err := c.queries.DeletePushSubscription(ctx, db.DeletePushSubscriptionParams{ID: subscription.ID, UserID: subscription.UserID})
+		return err
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("push service responded %s", resp.Status)
+	}
+	return nil
+}
+
+// vapidAuthorization returns the Authorization header identifying the server to the push
+// service of an endpoint.
+func (c *WebPushChannel) vapidAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidExpiration).Unix(),
+		"sub": c.subject,
+	})
+	signed, err := token.SignedString(c.privateKey)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("vapid t=%s, k=%s", signed, c.publicKey), nil
+}
+
+// EncryptPushPayload encrypts a push for a browser's subscription keys, base64url encoded as
+// the browser reports them, in a single aes128gcm record (RFC 8188, RFC 8291).
+func EncryptPushPayload(p256dh string, auth string, payload []byte) ([]byte, error) {
+	browserPublic, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(p256dh, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	browserKey, err := ecdh.P256().NewPublicKey(browserPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	authSecret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(auth, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription secret: %w", err)
+	}
+	if len(payload)+1+aes.BlockSize > pushRecordSize {
+		return nil, errors.New("push payload is too large")
+	}
+
+	// A new key pair for every push, its public key goes in the record header
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := serverKey.ECDH(browserKey)
+	if err != nil {
+		return nil, err
+	}
+	serverPublic := serverKey.PublicKey().Bytes()
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), browserPublic...), serverPublic...)
+	ikm := hkdf(authSecret, sharedSecret, keyInfo, 32)
+	contentKey := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(salt)+5+len(serverPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, pushRecordSize)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+
+	// The padding delimiter 0x02 marks the last record
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// hkdf derives at most 32 bytes with HKDF-SHA-256 (RFC 5869).
+func hkdf(salt []byte, ikm []byte, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm)
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write(info)
+	expand.Write([]byte{1})
+	return expand.Sum(nil)[:length]
+}
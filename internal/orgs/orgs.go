@@ -0,0 +1,72 @@
+package orgs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"new-chainsaw/db"
+)
+
+const (
+	DefaultInviteDays = 7
+	MaxInviteDays     = 30
+)
+
+var (
+	ErrInviteExpired = errors.New("the invite has expired")
+	ErrInviteUsedUp  = errors.New("the invite has been used up")
+)
+
+// InviteCode returns a random code for an invite link.
+func InviteCode() (string, error) {
+	code := make([]byte, 16)
+	if _, err := rand.Read(code); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(code), nil
+}
+
+// CheckInvite returns why an invite can no longer be accepted, nil while it can.
+func CheckInvite(expiresAt time.Time, maxUses *int32, uses int32, now time.Time) error {
+	switch {
+	case !now.Before(expiresAt):
+		return ErrInviteExpired
+	case maxUses != nil && uses >= *maxUses:
+		return ErrInviteUsedUp
+	}
+	return nil
+}
+
+// CanCoach reports whether a member sees the dashboards of the athletes and assigns them
+// templates and programs.
+func CanCoach(role db.OrganizationRole) bool {
+	return role == db.OrganizationRoleOwner || role == db.OrganizationRoleCoach
+}
+
+// CanInvite reports whether a member can create invites for a role. Coaches bring in athletes,
+// only the owner brings in coaches.
+func CanInvite(inviter db.OrganizationRole, role db.OrganizationRole) bool {
+	switch role {
+	case db.OrganizationRoleCoach:
+		return inviter == db.OrganizationRoleOwner
+	case db.OrganizationRoleAthlete:
+		return CanCoach(inviter)
+	}
+	return false
+}
+
+// CanRemove reports whether a member can remove another from the organization. Everybody but
+// the owner can leave, the owner removes anybody else and coaches remove athletes.
+func CanRemove(remover db.OrganizationMember, member db.OrganizationMember) bool {
+	switch {
+	case member.Role == db.OrganizationRoleOwner:
+		return false
+	case remover.UserID == member.UserID:
+		return true
+	case remover.Role == db.OrganizationRoleOwner:
+		return true
+	}
+	return remover.Role == db.OrganizationRoleCoach && member.Role == db.OrganizationRoleAthlete
+}
@@ -0,0 +1,188 @@
+package plates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"new-chainsaw/internal/conversion"
+)
+
+// Limits of a plate inventory
+const (
+	maxPlateSizes  = 15
+	maxPairs       = 20
+	maxPlateWeight = 100.0
+	maxBarWeight   = 100.0
+	maxCollar      = 10.0
+)
+
+// scale turns weights into whole hundredths, the precision plates are solved at.
+const scale = 100
+
+var ErrInvalidInventory = errors.New("invalid plate inventory")
+
+// Plate is a plate size of an inventory with the number of pairs available.
+type Plate struct {
+	Weight float64 `json:"weight"`
+	Pairs  int     `json:"pairs"`
+}
+
+// Inventory is the equipment a bar is loaded from. Weights are in the inventory's own unit,
+// kilograms or pounds, and change plates are simply small plates.
+type Inventory struct {
+	Bar    float64 `json:"bar"`
+	Collar float64 `json:"collar"` // Weight of one collar, one goes on each side once plates are loaded
+	Plates []Plate `json:"plates"`
+}
+
+// Loading is a loaded bar.
+type Loading struct {
+	Total   float64 `json:"total"`
+	Bar     float64 `json:"bar"`
+	Collars float64 `json:"collars"`  // Both collars together
+	PerSide []Plate `json:"per_side"` // Plates on each side, heaviest first. Pairs is the count per side
+}
+
+// DefaultInventory is a typical commercial gym: a 20 kg bar with 25 to 1.25 kg plates, or a
+// 45 lb bar with 45 to 2.5 lb plates.
+func DefaultInventory(imperial bool) Inventory {
+	if imperial {
+		return Inventory{Bar: 45, Plates: []Plate{{45, 4}, {35, 1}, {25, 2}, {10, 2}, {5, 2}, {2.5, 2}}}
+	}
+	return Inventory{Bar: 20, Plates: []Plate{{25, 4}, {20, 2}, {15, 1}, {10, 2}, {5, 2}, {2.5, 2}, {1.25, 2}}}
+}
+
+// LoadableWeight rounds a weight in kilograms to the nearest 2.5 kg, or 5 lbs for imperial users,
+// the smallest jump of the default inventories.
+func LoadableWeight(kg float64, imperial bool) float64 {
+	if imperial {
+		return round(conversion.LbsToKg(math.Round(conversion.KgToLbs(kg)/5) * 5))
+	}
+	return math.Round(kg/2.5) * 2.5
+}
+
+// Validate checks that the inventory can be loaded with and keeps to the limits of the solver.
+func (inv Inventory) Validate() error {
+	switch {
+	case inv.Bar <= 0 || inv.Bar > maxBarWeight:
+		return invalid(fmt.Sprintf("bar must weigh more than 0 and at most %g", maxBarWeight))
+	case inv.Collar < 0 || inv.Collar > maxCollar:
+		return invalid(fmt.Sprintf("collars must weigh between 0 and %g", maxCollar))
+	case len(inv.Plates) == 0 || len(inv.Plates) > maxPlateSizes:
+		return invalid(fmt.Sprintf("an inventory needs between 1 and %d plate sizes", maxPlateSizes))
+	}
+
+	seen := make(map[int]bool, len(inv.Plates))
+	for _, plate := range inv.Plates {
+		weight := hundredths(plate.Weight)
+		switch {
+		case plate.Weight <= 0 || plate.Weight > maxPlateWeight:
+			return invalid(fmt.Sprintf("plates must weigh more than 0 and at most %g", maxPlateWeight))
+		case plate.Pairs < 1 || plate.Pairs > maxPairs:
+			return invalid(fmt.Sprintf("plate %g needs between 1 and %d pairs", plate.Weight, maxPairs))
+		case seen[weight]:
+			return invalid(fmt.Sprintf("plate %g is listed twice", plate.Weight))
+		}
+		seen[weight] = true
+	}
+	return nil
+}
+
+// Load finds the loading closest to the target, preferring the lighter one on a tie. Equal
+// loadings use the fewest plates, and the heaviest plates among those. A target below the bar
+// gives the empty bar.
+func (inv Inventory) Load(target float64) Loading {
+	empty := Loading{Total: inv.Bar, Bar: inv.Bar, PerSide: []Plate{}}
+	perSide := (target - inv.Bar - 2*inv.Collar) / 2
+	if perSide <= 0 {
+		return empty
+	}
+
+	plates := append([]Plate(nil), inv.Plates...)
+	sort.Slice(plates, func(i, j int) bool { return plates[i].Weight < plates[j].Weight })
+
+	// Solve in units of the greatest common divisor of the plates to keep the table small
+	unit := 0
+	for _, plate := range plates {
+		unit = gcd(unit, hundredths(plate.Weight))
+	}
+	want := int(math.Round(perSide * scale / float64(unit)))
+	available := 0
+	for _, plate := range plates {
+		available += plate.Pairs * hundredths(plate.Weight) / unit
+	}
+	capacity := min(want+hundredths(plates[len(plates)-1].Weight)/unit, available)
+
+	// fewest[w] is the fewest plates that make w per side, counts[i][w] how many of plate i
+	// that solution uses given plates 0..i. Plates go from light to heavy and ties take the
+	// larger count, so the heaviest plates are used as much as possible.
+	const unreachable = math.MaxInt32
+	fewest := make([]int, capacity+1)
+	for w := 1; w <= capacity; w++ {
+		fewest[w] = unreachable
+	}
+	counts := make([][]int8, len(plates))
+	for i, plate := range plates {
+		weight := hundredths(plate.Weight) / unit
+		counts[i] = make([]int8, capacity+1)
+		next := append([]int(nil), fewest...)
+		for w := 0; w <= capacity; w++ {
+			for n := 1; n <= plate.Pairs && n*weight <= w; n++ {
+				if fewest[w-n*weight] != unreachable && fewest[w-n*weight]+n <= next[w] {
+					next[w] = fewest[w-n*weight] + n
+					counts[i][w] = int8(n)
+				}
+			}
+		}
+		fewest = next
+	}
+
+	best := 0
+	for w := 1; w <= capacity; w++ {
+		if fewest[w] != unreachable && abs(w-want) < abs(best-want) {
+			best = w
+		}
+	}
+	if best == 0 {
+		return empty
+	}
+
+	loading := Loading{Bar: inv.Bar, Collars: 2 * inv.Collar, PerSide: []Plate{}}
+	for i, w := len(plates)-1, best; i >= 0; i-- {
+		n := int(counts[i][w])
+		if n > 0 {
+			loading.PerSide = append(loading.PerSide, Plate{Weight: plates[i].Weight, Pairs: n})
+			w -= n * hundredths(plates[i].Weight) / unit
+		}
+	}
+	loading.Total = round(inv.Bar + loading.Collars + 2*float64(best*unit)/scale)
+	return loading
+}
+
+func hundredths(weight float64) int {
+	return int(math.Round(weight * scale))
+}
+
+func round(weight float64) float64 {
+	return math.Round(weight*scale) / scale
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func invalid(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidInventory, message)
+}
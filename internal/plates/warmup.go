@@ -0,0 +1,40 @@
+package plates
+
+// WarmupSet is a warm-up set leading up to a working weight.
+type WarmupSet struct {
+	Reps    int32   `json:"reps"`
+	Percent float64 `json:"percent"` // Of the working weight, 0 for the empty bar
+	Loading
+}
+
+// warmupRamp lists the warm-up sets after the empty bar as percentages of the working weight.
+var warmupRamp = []struct {
+	percent float64
+	reps    int32
+}{
+	{40, 5},
+	{60, 3},
+	{80, 2},
+	{90, 1},
+}
+
+// Warmup ramps up from the empty bar to the working weight. Every set is loaded from the
+// inventory, and sets that would not be heavier than the one before, or not lighter than the
+// working weight, are left out.
+func (inv Inventory) Warmup(target float64) []WarmupSet {
+	work := inv.Load(target)
+	sets := []WarmupSet{}
+	if work.Total <= inv.Bar {
+		return sets
+	}
+
+	sets = append(sets, WarmupSet{Reps: 10, Loading: Loading{Total: inv.Bar, Bar: inv.Bar, PerSide: []Plate{}}})
+	for _, step := range warmupRamp {
+		loading := inv.Load(target * step.percent / 100)
+		if loading.Total <= sets[len(sets)-1].Total || loading.Total >= work.Total {
+			continue
+		}
+		sets = append(sets, WarmupSet{Reps: step.reps, Percent: step.percent, Loading: loading})
+	}
+	return sets
+}
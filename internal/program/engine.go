@@ -0,0 +1,192 @@
+package program
+
+// weightTolerance accepts logged weights slightly below the prescription, so weights rounded
+// to the user's units still count.
+const weightTolerance = 0.99
+
+// Workout is the prescribed workout of one program day.
+type Workout struct {
+	Cycle     int                  `json:"cycle"`
+	Week      int                  `json:"week"`
+	Day       int                  `json:"day"`
+	Name      string               `json:"name"`
+	Exercises []PrescribedExercise `json:"exercises"`
+}
+
+type PrescribedExercise struct {
+	Lift          string          `json:"lift"`
+	ExerciseID    int32           `json:"exercise_id"`
+	TrainingMaxKg float64         `json:"training_max_kg"`
+	Sets          []PrescribedSet `json:"sets"`
+}
+
+type PrescribedSet struct {
+	Reps     int32   `json:"reps"`
+	WeightKg float64 `json:"weight_kg"`
+	AMRAP    bool    `json:"amrap,omitempty"`
+}
+
+// LoggedSet is a set the user logged, used to judge a prescribed workout.
+type LoggedSet struct {
+	ExerciseID int32
+	Reps       int32
+	WeightKg   float64
+}
+
+// Prescribe returns the workout of the state's current day. round turns a weight in
+// kilograms into a loadable weight.
+func Prescribe(d Definition, s State, round func(float64) float64) Workout {
+	day := d.Weeks[s.Week].Days[s.Day]
+	workout := Workout{
+		Cycle:     s.Cycle,
+		Week:      s.Week,
+		Day:       s.Day,
+		Name:      day.Name,
+		Exercises: make([]PrescribedExercise, len(day.Exercises)),
+	}
+	for i, prescription := range day.Exercises {
+		lift := s.Lifts[prescription.Lift]
+		exercise := PrescribedExercise{
+			Lift:          prescription.Lift,
+			ExerciseID:    lift.ExerciseID,
+			TrainingMaxKg: lift.TrainingMax,
+			Sets:          make([]PrescribedSet, len(prescription.Sets)),
+		}
+		for j, set := range prescription.Sets {
+			exercise.Sets[j] = PrescribedSet{
+				Reps:     set.Reps,
+				WeightKg: round(lift.TrainingMax * set.Percent / 100),
+				AMRAP:    set.AMRAP,
+			}
+		}
+		workout.Exercises[i] = exercise
+	}
+	return workout
+}
+
+// Evaluate judges a workout against the sets logged for it. A lift succeeds when every
+// prescribed set is matched by a different logged set with at least the prescribed reps and
+// weight. done is false when none of the workout's lifts were logged.
+func Evaluate(w Workout, logged []LoggedSet) (results map[string]bool, done bool) {
+	results = make(map[string]bool, len(w.Exercises))
+	for _, exercise := range w.Exercises {
+		var sets []LoggedSet
+		for _, set := range logged {
+			if set.ExerciseID == exercise.ExerciseID {
+				sets = append(sets, set)
+			}
+		}
+		if len(sets) > 0 {
+			done = true
+		}
+		success, ok := results[exercise.Lift]
+		if !ok {
+			success = true
+		}
+		results[exercise.Lift] = success && matchSets(exercise.Sets, sets)
+	}
+	return results, done
+}
+
+// matchSets reports whether every prescribed set is matched by a different logged set. It finds
+// a maximum bipartite matching with augmenting paths, a greedy choice can use up the only logged
+// set another prescribed set could match.
+func matchSets(prescribed []PrescribedSet, logged []LoggedSet) bool {
+	if len(logged) < len(prescribed) {
+		return false
+	}
+	matchedTo := make([]int, len(logged)) // The prescribed set each logged set is matched to, or -1
+	for i := range matchedTo {
+		matchedTo[i] = -1
+	}
+
+	var augment func(target int, visited []bool) bool
+	augment = func(target int, visited []bool) bool {
+		for i, set := range logged {
+			if visited[i] || !satisfies(set, prescribed[target]) {
+				continue
+			}
+			visited[i] = true
+			if matchedTo[i] < 0 || augment(matchedTo[i], visited) {
+				matchedTo[i] = target
+				return true
+			}
+		}
+		return false
+	}
+
+	for target := range prescribed {
+		if !augment(target, make([]bool, len(logged))) {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfies reports whether a logged set has at least the prescribed reps and weight.
+func satisfies(set LoggedSet, target PrescribedSet) bool {
+	return set.Reps >= target.Reps && set.WeightKg >= target.WeightKg*weightTolerance
+}
+
+// Advance applies the results of the current day and moves the state to the next day.
+// Lifts progressing per cycle go up when a cycle ends without a failure.
+func Advance(d Definition, s State, results map[string]bool) (State, []Adjustment) {
+	next := s.clone()
+	adjustments := []Adjustment{}
+
+	for _, lift := range d.Lifts {
+		success, ok := results[lift.Key]
+		if !ok {
+			continue
+		}
+		state := next.Lifts[lift.Key]
+		if success {
+			state.Failures = 0
+			if lift.ProgressOn == ProgressPerSession && lift.Increment > 0 {
+				adjustments = append(adjustments, adjust(lift.Key, AdjustmentProgress, state, state.TrainingMax+lift.Increment))
+			}
+			continue
+		}
+
+		state.Failures++
+		state.CycleFailed = true
+		if lift.FailureLimit > 0 && state.Failures >= lift.FailureLimit {
+			state.Failures = 0
+			adjustments = append(adjustments, adjust(lift.Key, AdjustmentDeload, state, state.TrainingMax*(1-lift.DeloadPercent/100)))
+		}
+	}
+
+	next.Day++
+	if next.Day >= len(d.Weeks[next.Week].Days) {
+		next.Day = 0
+		next.Week++
+	}
+	if next.Week >= len(d.Weeks) {
+		next.Week = 0
+		next.Cycle++
+		for _, lift := range d.Lifts {
+			state := next.Lifts[lift.Key]
+			if lift.ProgressOn == ProgressPerCycle && !state.CycleFailed && lift.Increment > 0 {
+				adjustments = append(adjustments, adjust(lift.Key, AdjustmentProgress, state, state.TrainingMax+lift.Increment))
+			}
+			state.CycleFailed = false
+		}
+	}
+	return next, adjustments
+}
+
+func adjust(lift string, kind string, state *LiftState, trainingMax float64) Adjustment {
+	adjustment := Adjustment{Lift: lift, Kind: kind, FromKg: state.TrainingMax, ToKg: trainingMax}
+	state.TrainingMax = trainingMax
+	return adjustment
+}
+
+func (s State) clone() State {
+	next := s
+	next.Lifts = make(map[string]*LiftState, len(s.Lifts))
+	for key, lift := range s.Lifts {
+		copied := *lift
+		next.Lifts[key] = &copied
+	}
+	return next
+}
@@ -0,0 +1,181 @@
+package program
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Progression modes of a lift
+const (
+	ProgressPerSession = "session" // The training max goes up after every successful session
+	ProgressPerCycle   = "cycle"   // The training max goes up after a cycle without failures
+)
+
+// Adjustment kinds
+const (
+	AdjustmentProgress = "progress"
+	AdjustmentDeload   = "deload"
+)
+
+// Limits that keep definitions to a size the API can return in one response
+const (
+	maxWeeks       = 52
+	maxDaysPerWeek = 7
+	maxExercises   = 20
+	maxSetsPerLift = 20
+	maxLifts       = 20
+)
+
+var ErrInvalidDefinition = errors.New("invalid program definition")
+
+// Definition describes a program as a cycle of weeks of training days. Weights are prescribed
+// as percentages of a per lift training max, which the progression rules move up after
+// successful sessions or cycles and down after repeated failures.
+type Definition struct {
+	TrainingMaxPercent float64 `json:"training_max_percent"` // Percent of the estimated one rep max a training max starts at
+	Lifts              []Lift  `json:"lifts"`
+	Weeks              []Week  `json:"weeks"`
+}
+
+// Lift is a lift of the program with its progression and deload rules.
+type Lift struct {
+	Key           string  `json:"key"`
+	Exercise      string  `json:"exercise"`       // Exercise name or alias, resolved when a user enrolls
+	Increment     float64 `json:"increment"`      // Kilograms added to the training max on progress
+	ProgressOn    string  `json:"progress_on"`    // session or cycle
+	FailureLimit  int     `json:"failure_limit"`  // Consecutive failures before a deload, 0 never deloads
+	DeloadPercent float64 `json:"deload_percent"` // Percent taken off the training max on a deload
+}
+
+type Week struct {
+	Days []Day `json:"days"`
+}
+
+type Day struct {
+	Name      string         `json:"name"`
+	Exercises []Prescription `json:"exercises"`
+}
+
+type Prescription struct {
+	Lift string `json:"lift"`
+	Sets []Set  `json:"sets"`
+}
+
+// Set is a prescribed set. AMRAP sets ask for as many reps as possible, Reps is the minimum.
+type Set struct {
+	Reps    int32   `json:"reps"`
+	Percent float64 `json:"percent"`
+	AMRAP   bool    `json:"amrap,omitempty"`
+}
+
+// LiftState is the progress of an enrolled user on one lift.
+type LiftState struct {
+	ExerciseID  int32   `json:"exercise_id"`
+	TrainingMax float64 `json:"training_max"` // Kilograms
+	Failures    int     `json:"failures"`     // Consecutive failed sessions
+	CycleFailed bool    `json:"cycle_failed"` // Whether the lift failed in the current cycle
+}
+
+// State is the position of an enrolled user in the program. Week and Day index the
+// definition's weeks and days.
+type State struct {
+	Cycle int                   `json:"cycle"`
+	Week  int                   `json:"week"`
+	Day   int                   `json:"day"`
+	Lifts map[string]*LiftState `json:"lifts"`
+}
+
+// Adjustment is a change of a training max made when the state advances.
+type Adjustment struct {
+	Lift   string  `json:"lift"`
+	Kind   string  `json:"kind"`
+	FromKg float64 `json:"from_kg"`
+	ToKg   float64 `json:"to_kg"`
+}
+
+// Validate checks that the definition is complete and that every prescription refers to a lift.
+func (d Definition) Validate() error {
+	if d.TrainingMaxPercent <= 0 || d.TrainingMaxPercent > 100 {
+		return invalid("training max percent must be above 0 and at most 100")
+	}
+	if len(d.Lifts) == 0 || len(d.Lifts) > maxLifts {
+		return invalid(fmt.Sprintf("a program needs between 1 and %d lifts", maxLifts))
+	}
+
+	lifts := make(map[string]bool, len(d.Lifts))
+	for _, lift := range d.Lifts {
+		switch {
+		case strings.TrimSpace(lift.Key) == "":
+			return invalid("every lift needs a key")
+		case lifts[lift.Key]:
+			return invalid(fmt.Sprintf("lift %q is defined twice", lift.Key))
+		case strings.TrimSpace(lift.Exercise) == "":
+			return invalid(fmt.Sprintf("lift %q needs an exercise", lift.Key))
+		case lift.ProgressOn != ProgressPerSession && lift.ProgressOn != ProgressPerCycle:
+			return invalid(fmt.Sprintf("lift %q must progress per %s or %s", lift.Key, ProgressPerSession, ProgressPerCycle))
+		case lift.Increment < 0:
+			return invalid(fmt.Sprintf("lift %q must not have a negative increment", lift.Key))
+		case lift.FailureLimit < 0:
+			return invalid(fmt.Sprintf("lift %q must not have a negative failure limit", lift.Key))
+		case lift.DeloadPercent < 0 || lift.DeloadPercent > 50:
+			return invalid(fmt.Sprintf("lift %q must deload by 0 to 50 percent", lift.Key))
+		}
+		lifts[lift.Key] = true
+	}
+
+	if len(d.Weeks) == 0 || len(d.Weeks) > maxWeeks {
+		return invalid(fmt.Sprintf("a program needs between 1 and %d weeks", maxWeeks))
+	}
+	for w, week := range d.Weeks {
+		if len(week.Days) == 0 || len(week.Days) > maxDaysPerWeek {
+			return invalid(fmt.Sprintf("week %d needs between 1 and %d days", w+1, maxDaysPerWeek))
+		}
+		for i, day := range week.Days {
+			if len(day.Exercises) == 0 || len(day.Exercises) > maxExercises {
+				return invalid(fmt.Sprintf("day %d of week %d needs between 1 and %d exercises", i+1, w+1, maxExercises))
+			}
+			for _, prescription := range day.Exercises {
+				if !lifts[prescription.Lift] {
+					return invalid(fmt.Sprintf("day %d of week %d uses undefined lift %q", i+1, w+1, prescription.Lift))
+				}
+				if len(prescription.Sets) == 0 || len(prescription.Sets) > maxSetsPerLift {
+					return invalid(fmt.Sprintf("%s on day %d of week %d needs between 1 and %d sets", prescription.Lift, i+1, w+1, maxSetsPerLift))
+				}
+				for _, set := range prescription.Sets {
+					if set.Reps < 1 || set.Percent <= 0 || set.Percent > 120 {
+						return invalid(fmt.Sprintf("%s on day %d of week %d needs positive reps and a percent above 0 and at most 120", prescription.Lift, i+1, w+1))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// NewState starts a program at its first day with the given lift states. Every lift of the
+// definition needs a positive training max.
+func NewState(d Definition, lifts map[string]LiftState) (State, error) {
+	state := State{Lifts: make(map[string]*LiftState, len(d.Lifts))}
+	for _, lift := range d.Lifts {
+		liftState, ok := lifts[lift.Key]
+		if !ok || liftState.TrainingMax <= 0 {
+			return State{}, invalid(fmt.Sprintf("lift %q needs a training max", lift.Key))
+		}
+		state.Lifts[lift.Key] = &liftState
+	}
+	return state, nil
+}
+
+func (d Definition) lift(key string) (Lift, bool) {
+	for _, lift := range d.Lifts {
+		if lift.Key == key {
+			return lift, true
+		}
+	}
+	return Lift{}, false
+}
+
+func invalid(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidDefinition, message)
+}
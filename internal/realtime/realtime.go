@@ -0,0 +1,143 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Channel is the Postgres channel the notify_realtime trigger announces new rows on.
+	Channel = "realtime"
+	// streamBuffer is how many messages a stream holds before it misses newer ones.
+	streamBuffer = 16
+	// reconnectDelay is how long the listener waits before listening again after an error.
+	reconnectDelay = 5 * time.Second
+)
+
+// Announcement is the payload of a notification on Channel, a row of events or notifications
+// inserted for a user.
+type Announcement struct {
+	Table  string `json:"table"`
+	ID     int64  `json:"id"`
+	UserID int32  `json:"user_id"`
+}
+
+// Message is an update streamed to a user.
+type Message struct {
+	Event string // The event name of the stream
+	Data  any    // Encoded as JSON
+}
+
+// Hub fans messages out to the streams users opened on this server.
+type Hub struct {
+	mu      sync.Mutex
+	streams map[int32]map[chan Message]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{streams: make(map[int32]map[chan Message]struct{})}
+}
+
+// Subscribe opens a stream of the user's messages. The stream is closed by calling cancel.
+func (h *Hub) Subscribe(userID int32) (<-chan Message, func()) {
+	stream := make(chan Message, streamBuffer)
+
+	h.mu.Lock()
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[chan Message]struct{})
+	}
+	h.streams[userID][stream] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.streams[userID], stream)
+			if len(h.streams[userID]) == 0 {
+				delete(h.streams, userID)
+			}
+			close(stream)
+		})
+	}
+	return stream, cancel
+}
+
+// HasSubscribers reports whether the user has a stream open on this server.
+func (h *Hub) HasSubscribers(userID int32) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.streams[userID]) > 0
+}
+
+// Publish sends a message to every stream of a user. Streams too slow to keep up miss it
+// rather than holding up the others. It reports how many streams received it.
+func (h *Hub) Publish(userID int32, msg Message) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sent := 0
+	for stream := range h.streams[userID] {
+		select {
+		case stream <- msg:
+			sent++
+		default:
+		}
+	}
+	return sent
+}
+
+// Listen passes the announcements on Channel to handle until ctx is cancelled, listening again
+// after connection errors. Every server listens, so each reaches the streams opened on it.
+func Listen(ctx context.Context, pool *pgxpool.Pool, handle func(context.Context, Announcement)) {
+	for {
+		err := listen(ctx, pool, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Realtime listener stopped: %v\n", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, handle func(context.Context, Announcement)) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection keeps listening, so it is taken out of the pool and closed when done
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var announcement Announcement
+		if err := json.Unmarshal([]byte(notification.Payload), &announcement); err != nil {
+			log.Printf("Skipping invalid realtime announcement %q: %v\n", notification.Payload, err)
+			continue
+		}
+		handle(ctx, announcement)
+	}
+}
+
+// String describes an announcement for logs.
+func (a Announcement) String() string {
+	return fmt.Sprintf("%s %d of user %d", a.Table, a.ID, a.UserID)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"new-chainsaw/db"
 	"new-chainsaw/internal/config"
 	"new-chainsaw/internal/handlers"
 	"new-chainsaw/internal/middleware"
@@ -42,25 +43,189 @@ func (s *Server) RegisterRoutes() http.Handler {
 
 	r.GET("/user/:username", handlers.GetUserProfileByUsernameHandler)
 
+	r.GET("/exports/:id/download", handlers.DownloadDataExportHandler)
+
+	// Routes that stay available while an account is pending deletion
+	account := r.Group("/")
+	account.Use(middleware.JWTMiddleware(s.dbPool))
+	{
+		account.GET("/session", handlers.SessionHandler)
+		account.POST("/sign-out", handlers.SignOutHandler)
+		account.POST("/restore-account", handlers.RestoreAccountHandler)
+	}
+
 	protected := r.Group("/")
-	protected.Use(middleware.JWTMiddleware(s.dbPool))
+	protected.Use(middleware.JWTMiddleware(s.dbPool), middleware.RequireActiveAccount(db.New(s.dbPool)))
 	{
 		protected.GET("/search", handlers.SearchUsers)
 
 		protected.GET("/protected-endpoint", protectedEndpointHandler)
 
-		protected.GET("/session", handlers.SessionHandler)
-		protected.POST("/sign-out", handlers.SignOutHandler)
 		protected.DELETE("/delete-account", handlers.DeleteAccountHandler)
 		protected.GET("/check-username", handlers.CheckUsernameAvailabilityHandler)
 		protected.PATCH("/update-user", handlers.UpdateUserHandler)
 
+		protected.POST("/me/export", handlers.RequestDataExportHandler)
+		protected.GET("/me/export/:id", handlers.GetDataExportHandler)
+		protected.GET("/me/exports/logs", handlers.ExportLogsHandler)
+		protected.GET("/me/calendar", handlers.GetTrainingCalendarHandler)
+		protected.GET("/me/streaks", handlers.GetStreaksHandler)
+		protected.PUT("/me/streaks/target", handlers.UpdateStreakTargetHandler)
+		protected.GET("/me/insights", handlers.ListInsightsHandler)
+		protected.POST("/me/insights/:id/dismiss", handlers.DismissInsightHandler)
+
 		/* */
 		protected.GET("/user/profile", handlers.GetUserProfileByIDHandler)
 		/* */
 
 		protected.POST("/log-exercises", handlers.LogExerciseHandler)
 		protected.GET("/exercises/latest", handlers.GetLatestExercises)
+		protected.GET("/exercises", handlers.ListExercisesHandler)
+		protected.GET("/exercises/search", handlers.SearchExercisesHandler)
+		protected.POST("/exercises", handlers.CreateCustomExerciseHandler)
+		protected.GET("/exercises/:id/stats", handlers.GetExerciseStatsHandler)
+		protected.PUT("/exercises/:id", handlers.UpdateCustomExerciseHandler)
+		protected.DELETE("/exercises/:id", handlers.DeleteCustomExerciseHandler)
+
+		protected.POST("/imports", handlers.CreateImportHandler)
+		protected.GET("/imports/:id", handlers.GetImportHandler)
+		protected.PUT("/imports/:id/mappings", handlers.UpdateImportMappingsHandler)
+		protected.POST("/imports/:id/commit", handlers.CommitImportHandler)
+
+		protected.GET("/templates", handlers.ListWorkoutTemplatesHandler)
+		protected.POST("/templates", handlers.CreateWorkoutTemplateHandler)
+		protected.GET("/templates/shared/:code", handlers.GetSharedWorkoutTemplateHandler)
+		protected.POST("/templates/shared/:code/clone", handlers.CloneSharedWorkoutTemplateHandler)
+		protected.GET("/templates/:id", handlers.GetWorkoutTemplateHandler)
+		protected.PUT("/templates/:id", handlers.UpdateWorkoutTemplateHandler)
+		protected.DELETE("/templates/:id", handlers.DeleteWorkoutTemplateHandler)
+		protected.POST("/templates/:id/clone", handlers.CloneWorkoutTemplateHandler)
+		protected.POST("/templates/:id/share", handlers.ShareWorkoutTemplateHandler)
+		protected.DELETE("/templates/:id/share", handlers.UnshareWorkoutTemplateHandler)
+		protected.POST("/templates/:id/start", handlers.StartWorkoutTemplateHandler)
+
+		protected.GET("/workouts", handlers.ListWorkoutsHandler)
+		protected.GET("/workouts/:id", handlers.GetWorkoutHandler)
+		protected.PUT("/workouts/:id/sets/:set_id", handlers.UpdateWorkoutSetHandler)
+		protected.POST("/workouts/:id/complete", handlers.CompleteWorkoutHandler)
+		protected.DELETE("/workouts/:id", handlers.DeleteWorkoutHandler)
+
+		protected.GET("/programs", handlers.ListTrainingProgramsHandler)
+		protected.POST("/programs", handlers.CreateTrainingProgramHandler)
+		protected.GET("/programs/:id", handlers.GetTrainingProgramHandler)
+		protected.DELETE("/programs/:id", handlers.DeleteTrainingProgramHandler)
+		protected.POST("/programs/:id/enroll", handlers.EnrollInProgramHandler)
+
+		protected.GET("/program-enrollments", handlers.ListProgramEnrollmentsHandler)
+		protected.GET("/program-enrollments/:id", handlers.GetProgramEnrollmentHandler)
+		protected.GET("/program-enrollments/:id/today", handlers.GetProgramWorkoutHandler)
+		protected.POST("/program-enrollments/:id/advance", handlers.AdvanceProgramEnrollmentHandler)
+		protected.POST("/program-enrollments/:id/start", handlers.StartProgramWorkoutHandler)
+		protected.DELETE("/program-enrollments/:id", handlers.StopProgramEnrollmentHandler)
+
+		protected.GET("/analytics", handlers.GetTrainingAnalyticsHandler)
+
+		protected.GET("/bodyweight/trend", handlers.GetBodyweightTrendHandler)
+		protected.GET("/bodyweight/goal", handlers.GetBodyweightGoalHandler)
+		protected.PUT("/bodyweight/goal", handlers.SetBodyweightGoalHandler)
+		protected.DELETE("/bodyweight/goal", handlers.DeleteBodyweightGoalHandler)
+
+		protected.GET("/goals", handlers.ListStrengthGoalsHandler)
+		protected.POST("/goals", handlers.CreateStrengthGoalHandler)
+		protected.GET("/goals/:id", handlers.GetStrengthGoalHandler)
+		protected.PUT("/goals/:id", handlers.UpdateStrengthGoalHandler)
+		protected.DELETE("/goals/:id", handlers.DeleteStrengthGoalHandler)
+
+		protected.GET("/body-measurements", handlers.ListBodyMeasurementsHandler)
+		protected.POST("/body-measurements", handlers.LogBodyMeasurementsHandler)
+		protected.GET("/body-measurements/latest", handlers.GetLatestBodyMeasurementsHandler)
+		protected.GET("/body-measurements/trend", handlers.GetBodyMeasurementTrendHandler)
+		protected.DELETE("/body-measurements/:id", handlers.DeleteBodyMeasurementHandler)
+
+		protected.GET("/meets", handlers.ListMeetsHandler)
+		protected.POST("/meets", handlers.CreateMeetHandler)
+		protected.GET("/meets/:id", handlers.GetMeetHandler)
+		protected.DELETE("/meets/:id", handlers.DeleteMeetHandler)
+		protected.POST("/meets/:id/divisions", handlers.CreateMeetDivisionHandler)
+		protected.DELETE("/meets/:id/divisions/:division_id", handlers.DeleteMeetDivisionHandler)
+		protected.POST("/meets/:id/entries", handlers.CreateMeetEntryHandler)
+		protected.DELETE("/meets/:id/entries/:entry_id", handlers.DeleteMeetEntryHandler)
+		protected.POST("/meets/:id/entries/:entry_id/accept", handlers.AcceptMeetEntryHandler)
+		protected.POST("/meets/:id/entries/:entry_id/decline", handlers.DeclineMeetEntryHandler)
+		protected.PUT("/meets/:id/entries/:entry_id/weigh-in", handlers.WeighInHandler)
+		protected.PUT("/meets/:id/entries/:entry_id/attempts", handlers.DeclareAttemptHandler)
+		protected.PUT("/meets/:id/attempts/:attempt_id/decision", handlers.JudgeAttemptHandler)
+		protected.POST("/meets/:id/close", handlers.CloseMeetHandler)
+		protected.GET("/meets/:id/results", handlers.MeetResultsHandler)
+
+		protected.GET("/challenges", handlers.ListChallengesHandler)
+		protected.POST("/challenges", handlers.CreateChallengeHandler)
+		protected.GET("/challenges/open", handlers.ListOpenChallengesHandler)
+		protected.GET("/challenges/:id", handlers.GetChallengeHandler)
+		protected.DELETE("/challenges/:id", handlers.DeleteChallengeHandler)
+		protected.POST("/challenges/:id/invites", handlers.InviteToChallengeHandler)
+		protected.POST("/challenges/:id/join", handlers.JoinChallengeHandler)
+		protected.POST("/challenges/:id/leave", handlers.LeaveChallengeHandler)
+
+		protected.GET("/me/privacy", handlers.GetPrivacySettingsHandler)
+		protected.PUT("/me/privacy", handlers.UpdatePrivacySettingsHandler)
+
+		protected.GET("/organizations", handlers.ListOrganizationsHandler)
+		protected.POST("/organizations", handlers.CreateOrganizationHandler)
+		protected.GET("/organizations/invites/:code", handlers.GetOrganizationInviteHandler)
+		protected.POST("/organizations/invites/:code/accept", handlers.AcceptOrganizationInviteHandler)
+		protected.GET("/organizations/:id", handlers.GetOrganizationHandler)
+		protected.PUT("/organizations/:id", handlers.UpdateOrganizationHandler)
+		protected.DELETE("/organizations/:id", handlers.DeleteOrganizationHandler)
+		protected.GET("/organizations/:id/invites", handlers.ListOrganizationInvitesHandler)
+		protected.POST("/organizations/:id/invites", handlers.CreateOrganizationInviteHandler)
+		protected.DELETE("/organizations/:id/invites/:invite_id", handlers.DeleteOrganizationInviteHandler)
+		protected.PUT("/organizations/:id/members/:user_id", handlers.UpdateOrganizationMemberHandler)
+		protected.DELETE("/organizations/:id/members/:user_id", handlers.RemoveOrganizationMemberHandler)
+		protected.GET("/organizations/:id/assignments", handlers.ListCoachAssignmentsHandler)
+		protected.GET("/organizations/:id/athletes/:user_id/dashboard", handlers.GetAthleteDashboardHandler)
+		protected.POST("/organizations/:id/athletes/:user_id/templates", handlers.AssignTemplateHandler)
+		protected.POST("/organizations/:id/athletes/:user_id/programs", handlers.AssignProgramHandler)
+		protected.GET("/organizations/:id/webhooks", handlers.ListOrganizationWebhooksHandler)
+		protected.POST("/organizations/:id/webhooks", handlers.CreateOrganizationWebhookHandler)
+
+		protected.GET("/workouts/:id/comments", handlers.ListWorkoutCommentsHandler)
+		protected.POST("/workouts/:id/comments", handlers.CreateWorkoutCommentHandler)
+		protected.POST("/workouts/:id/reactions", handlers.AddWorkoutReactionHandler)
+		protected.DELETE("/workouts/:id/reactions/:kind", handlers.RemoveWorkoutReactionHandler)
+		protected.GET("/personal-records/:id/comments", handlers.ListPersonalRecordCommentsHandler)
+		protected.POST("/personal-records/:id/comments", handlers.CreatePersonalRecordCommentHandler)
+		protected.POST("/personal-records/:id/reactions", handlers.AddPersonalRecordReactionHandler)
+		protected.DELETE("/personal-records/:id/reactions/:kind", handlers.RemovePersonalRecordReactionHandler)
+		protected.PUT("/comments/:id", handlers.UpdateCommentHandler)
+		protected.DELETE("/comments/:id", handlers.DeleteCommentHandler)
+
+		protected.GET("/events", handlers.StreamEventsHandler)
+
+		protected.GET("/notifications", handlers.ListNotificationsHandler)
+		protected.GET("/notifications/unread-count", handlers.GetUnreadNotificationCountHandler)
+		protected.POST("/notifications/read-all", handlers.MarkAllNotificationsReadHandler)
+		protected.POST("/notifications/:id/read", handlers.MarkNotificationReadHandler)
+		protected.GET("/notifications/preferences", handlers.GetNotificationPreferencesHandler)
+		protected.PUT("/notifications/preferences", handlers.UpdateNotificationPreferencesHandler)
+		protected.GET("/notifications/push/key", handlers.GetPushKeyHandler)
+		protected.POST("/notifications/push/subscriptions", handlers.CreatePushSubscriptionHandler)
+		protected.DELETE("/notifications/push/subscriptions/:id", handlers.DeletePushSubscriptionHandler)
+
+		protected.GET("/webhooks", handlers.ListWebhooksHandler)
+		protected.POST("/webhooks", handlers.CreateWebhookHandler)
+		protected.GET("/webhooks/:id", handlers.GetWebhookHandler)
+		protected.PUT("/webhooks/:id", handlers.UpdateWebhookHandler)
+		protected.DELETE("/webhooks/:id", handlers.DeleteWebhookHandler)
+		protected.POST("/webhooks/:id/secret", handlers.RotateWebhookSecretHandler)
+		protected.GET("/webhooks/:id/deliveries", handlers.ListWebhookDeliveriesHandler)
+		protected.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", handlers.RedeliverWebhookDeliveryHandler)
+
+		protected.GET("/tools/plates", handlers.PlateLoadingHandler)
+		protected.GET("/tools/warmup", handlers.WarmupHandler)
+		protected.GET("/tools/plates/inventory", handlers.GetPlateInventoryHandler)
+		protected.PUT("/tools/plates/inventory", handlers.UpdatePlateInventoryHandler)
+		protected.DELETE("/tools/plates/inventory", handlers.ResetPlateInventoryHandler)
 
 		protected.POST("/validate-save-trophies", handlers.ValidateAndSaveTrophiesHandler)
 		protected.GET("/trophies", handlers.GetTrophiesHandler)
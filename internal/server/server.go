@@ -1,16 +1,24 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/joho/godotenv/autoload"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
+	"new-chainsaw/db"
 	"new-chainsaw/internal/auth"
 	"new-chainsaw/internal/config"
 	"new-chainsaw/internal/handlers"
+	"new-chainsaw/internal/jobs"
+	"new-chainsaw/internal/notifications"
+	"new-chainsaw/internal/realtime"
+	"new-chainsaw/internal/storage"
+	"new-chainsaw/internal/webhooks"
 
 	"new-chainsaw/internal/database"
 )
@@ -39,6 +47,35 @@ func NewServer() *http.Server {
 	// Initialize the handlers with db pool
 	handlers.InitializeQueries(dbPool)
 
+	// Initialize file storage for generated archives
+	storageDir := config.EnvVars["STORAGE_DIR"]
+	if storageDir == "" {
+		storageDir = "./storage"
+	}
+	fileStorage, err := storage.NewLocalStorage(storageDir)
+	if err != nil {
+		log.Fatalf("cannot initialize storage: %v", err)
+	}
+	handlers.InitializeStorage(fileStorage)
+
+	// Stream new events and notifications, announced by Postgres to every server
+	streams := realtime.NewHub()
+	handlers.InitializeRealtime(streams)
+	go realtime.Listen(context.Background(), dbPool, handlers.PublishAnnouncement)
+
+	// Initialize the channels notifications are delivered on
+	dispatcher := notifications.NewDispatcher(notificationChannels(db.New(dbPool))...)
+
+	// Start background jobs
+	go jobs.Every(context.Background(), "purge-deleted-accounts", config.AccountPurgeInterval, jobs.PurgeDeletedAccounts(db.New(dbPool), fileStorage))
+	go jobs.Every(context.Background(), "maintain-data-exports", config.DataExportMaintenanceInterval, jobs.MaintainDataExports(db.New(dbPool), fileStorage))
+	go jobs.Every(context.Background(), "scan-insights", config.InsightScanInterval, jobs.ScanInsights(db.New(dbPool)))
+	go jobs.Every(context.Background(), "refresh-strength-standings", config.StrengthStandingsRefreshInterval, jobs.RefreshStrengthStandings(dbPool))
+	go jobs.Every(context.Background(), "finalize-challenges", config.ChallengeFinalizeInterval, jobs.FinalizeChallenges(dbPool))
+	go jobs.Every(context.Background(), "deliver-notifications", config.NotificationDeliveryInterval, jobs.DeliverNotifications(db.New(dbPool), dispatcher))
+	webhookClient := webhooks.NewClient(config.EnvVars["WEBHOOKS_ALLOW_PRIVATE_NETWORKS"] == "true")
+	go jobs.Every(context.Background(), "deliver-webhooks", config.WebhookDeliveryInterval, jobs.DeliverWebhooks(db.New(dbPool), webhookClient))
+
 	// Declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", NewServer.port),
@@ -57,3 +94,30 @@ func NewServer() *http.Server {
 
 	return server
 }
+
+// notificationChannels returns the in-app channel, with email when SMTP_HOST is set and Web
+// Push when a VAPID key pair is set.
+func notificationChannels(queries *db.Queries) []notifications.Channel {
+	channels := []notifications.Channel{notifications.NewInAppChannel(queries)}
+
+	if host := config.EnvVars["SMTP_HOST"]; host != "" {
+		port := config.EnvVars["SMTP_PORT"]
+		if port == "" {
+			port = "587"
+		}
+		email, err := notifications.NewSMTPChannel(host, port, config.EnvVars["SMTP_USERNAME"], config.EnvVars["SMTP_PASSWORD"], config.EnvVars["SMTP_FROM"])
+		if err != nil {
+			log.Fatalf("cannot initialize email notifications: %v", err)
+		}
+		channels = append(channels, email)
+	}
+
+	if publicKey := config.EnvVars["VAPID_PUBLIC_KEY"]; publicKey != "" {
+		push, err := notifications.NewWebPushChannel(queries, publicKey, config.EnvVars["VAPID_PRIVATE_KEY"], config.EnvVars["VAPID_SUBJECT"])
+		if err != nil {
+			log.Fatalf("cannot initialize push notifications: %v", err)
+		}
+		channels = append(channels, push)
+	}
+	return channels
+}
@@ -0,0 +1,50 @@
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpired          = errors.New("link has expired")
+)
+
+func signature(secret, path string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%s:%d", path, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns path with expires and signature query parameters appended.
+func Sign(secret, path string, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("signature", signature(secret, path, expires))
+	return path + "?" + q.Encode()
+}
+
+// Verify checks the expires and signature query parameters produced by Sign.
+func Verify(secret, path, expiresParam, signatureParam string, now time.Time) error {
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := signature(secret, path, expires)
+	if !hmac.Equal([]byte(expected), []byte(signatureParam)) {
+		return ErrInvalidSignature
+	}
+
+	if now.Unix() > expires {
+		return ErrExpired
+	}
+	return nil
+}
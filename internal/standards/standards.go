@@ -0,0 +1,78 @@
+package standards
+
+// Level is how strong a lift is for the lifter's sex and bodyweight.
+type Level string
+
+const (
+	Untrained    Level = "untrained" // Below the beginner standard
+	Beginner     Level = "beginner"
+	Novice       Level = "novice"
+	Intermediate Level = "intermediate"
+	Advanced     Level = "advanced"
+	Elite        Level = "elite"
+)
+
+// Levels are the levels with a standard, from the weakest.
+var Levels = []Level{Beginner, Novice, Intermediate, Advanced, Elite}
+
+// MinPopulation is how many lifters of the same sex need a ranked lift before a percentile is
+// shown, fewer would make it meaningless.
+const MinPopulation = 10
+
+// Standard is the one rep max in kilograms for each level, in the order of Levels, of lifters
+// from a bodyweight class up to the next class.
+type Standard struct {
+	BodyweightClassKg float64
+	ThresholdsKg      [5]float64
+}
+
+// Classification is the level of a one rep max and what it takes to reach the next one.
+type Classification struct {
+	Level             Level
+	BodyweightClassKg float64
+	NextLevel         *Level
+	NextKg            *float64 // One rep max of the next level
+	Thresholds        map[Level]float64
+}
+
+// ClassFor returns the standard of the class a bodyweight falls in, standards ordered by class.
+// A bodyweight below the lightest class uses that class.
+func ClassFor(standards []Standard, bodyweightKg float64) (Standard, bool) {
+	if len(standards) == 0 {
+		return Standard{}, false
+	}
+	class := standards[0]
+	for _, standard := range standards[1:] {
+		if bodyweightKg < standard.BodyweightClassKg {
+			break
+		}
+		class = standard
+	}
+	return class, true
+}
+
+// Classify finds the level of a one rep max for a lifter of a bodyweight.
+func Classify(standards []Standard, bodyweightKg float64, oneRepMaxKg float64) (Classification, bool) {
+	class, ok := ClassFor(standards, bodyweightKg)
+	if !ok {
+		return Classification{}, false
+	}
+
+	classification := Classification{
+		Level:             Untrained,
+		BodyweightClassKg: class.BodyweightClassKg,
+		Thresholds:        make(map[Level]float64, len(Levels)),
+	}
+	for i, level := range Levels {
+		threshold := class.ThresholdsKg[i]
+		classification.Thresholds[level] = threshold
+		if oneRepMaxKg >= threshold {
+			classification.Level = level
+		} else if classification.NextLevel == nil {
+			next := level
+			classification.NextLevel = &next
+			classification.NextKg = &threshold
+		}
+	}
+	return classification, true
+}
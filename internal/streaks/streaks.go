@@ -0,0 +1,102 @@
+package streaks
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	DefaultTarget = 3
+	MinTarget     = 1
+	MaxTarget     = 7
+	// consistencyWeeks is how many complete weeks the consistency score looks back on.
+	consistencyWeeks = 12
+)
+
+// Milestone is a streak length that unlocks a trophy.
+type Milestone struct {
+	Weeks  int
+	Trophy string // Name in the trophies table
+}
+
+// Milestones are ordered from the shortest streak.
+var Milestones = []Milestone{
+	{4, "streak-4-weeks"},
+	{12, "streak-12-weeks"},
+	{26, "streak-26-weeks"},
+	{52, "streak-52-weeks"},
+}
+
+// Week is a week starting on Monday with the number of days trained in it.
+type Week struct {
+	Start time.Time
+	Days  int
+}
+
+// Streaks is how consistently a user reaches their target days per week.
+type Streaks struct {
+	Target          int
+	Current         int     // Weeks in a row reaching the target, up to the current week
+	Longest         int     // Longest run of weeks reaching the target
+	CurrentWeekDays int     // Days trained in the current week so far
+	Consistency     float64 // 0 to 100, the share of the target reached over the last complete weeks
+}
+
+// WeekStart returns the Monday starting the week of t in UTC.
+func WeekStart(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// Compute works out the streaks of weeks ordered oldest first, as of now. Weeks without
+// training may be missing. The current week only breaks the streak once it is over, so a
+// streak is not lost on a Monday.
+func Compute(weeks []Week, target int, now time.Time) Streaks {
+	current := WeekStart(now)
+	days := make(map[time.Time]int, len(weeks))
+	for _, week := range weeks {
+		days[WeekStart(week.Start)] += week.Days
+	}
+	streaks := Streaks{Target: target, CurrentWeekDays: days[current]}
+
+	run := 0
+	for i, week := range weeks {
+		start := WeekStart(week.Start)
+		if i > 0 && start.Sub(WeekStart(weeks[i-1].Start)) > 7*24*time.Hour {
+			run = 0
+		}
+		if week.Days >= target {
+			run++
+			streaks.Longest = max(streaks.Longest, run)
+		} else if !start.Equal(current) {
+			run = 0
+		}
+	}
+
+	// Count back from the current week, or the week before while the current one is short
+	week := current
+	if days[week] < target {
+		week = week.AddDate(0, 0, -7)
+	}
+	for days[week] >= target {
+		streaks.Current++
+		week = week.AddDate(0, 0, -7)
+	}
+
+	var reached float64
+	for i := 1; i <= consistencyWeeks; i++ {
+		reached += math.Min(float64(days[current.AddDate(0, 0, -7*i)])/float64(target), 1)
+	}
+	streaks.Consistency = math.Round(reached/consistencyWeeks*1000) / 10
+	return streaks
+}
+
+// MilestoneFor returns the milestone unlocking the named trophy.
+func MilestoneFor(trophy string) (Milestone, bool) {
+	for _, milestone := range Milestones {
+		if milestone.Trophy == trophy {
+			return milestone, true
+		}
+	}
+	return Milestone{}, false
+}
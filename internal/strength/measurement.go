@@ -0,0 +1,71 @@
+package strength
+
+import (
+	"errors"
+	"fmt"
+	"new-chainsaw/db"
+)
+
+var ErrInvalidMeasurement = errors.New("invalid measurement")
+
+// Measurement holds the fields of one logged set. Weights are in kilograms, distances in meters.
+type Measurement struct {
+	Reps            int32
+	WeightKg        float64
+	DurationSeconds *int32
+	DistanceMeters  *float64
+}
+
+// Validate checks that a set records exactly the fields its exercise's measurement kind uses.
+// Time and distance based sets count as a single repetition.
+func Validate(kind db.MeasurementKind, m Measurement) error {
+	if m.WeightKg < 0 {
+		return invalid("weight must not be negative")
+	}
+	if m.DurationSeconds != nil && *m.DurationSeconds <= 0 {
+		return invalid("duration must be positive")
+	}
+	if m.DistanceMeters != nil && *m.DistanceMeters <= 0 {
+		return invalid("distance must be positive")
+	}
+
+	switch kind {
+	case db.MeasurementKindRepsWeight:
+		if m.Reps <= 0 {
+			return invalid("reps must be positive")
+		}
+		if m.DurationSeconds != nil || m.DistanceMeters != nil {
+			return invalid("duration and distance are not recorded for this exercise")
+		}
+		return nil
+	case db.MeasurementKindTime, db.MeasurementKindTimeWeight:
+		if m.DurationSeconds == nil {
+			return invalid("duration is required for this exercise")
+		}
+		if m.DistanceMeters != nil {
+			return invalid("distance is not recorded for this exercise")
+		}
+	case db.MeasurementKindDistance, db.MeasurementKindDistanceWeight:
+		if m.DistanceMeters == nil {
+			return invalid("distance is required for this exercise")
+		}
+	default:
+		return invalid(fmt.Sprintf("unknown measurement kind %q", kind))
+	}
+
+	if m.Reps > 1 {
+		return invalid("time and distance based sets are logged one at a time")
+	}
+	weighted := kind == db.MeasurementKindTimeWeight || kind == db.MeasurementKindDistanceWeight
+	if weighted && m.WeightKg == 0 {
+		return invalid("weight is required for this exercise")
+	}
+	if !weighted && m.WeightKg != 0 {
+		return invalid("weight is not recorded for this exercise")
+	}
+	return nil
+}
+
+func invalid(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidMeasurement, message)
+}
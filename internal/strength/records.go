@@ -0,0 +1,212 @@
+package strength
+
+import (
+	"time"
+
+	"new-chainsaw/db"
+)
+
+// maxEstimateReps is the highest rep count, including reps in reserve, a one rep max is
+// estimated from, estimates from longer sets are too inaccurate to count as records.
+const maxEstimateReps = 12
+
+// Record types
+const (
+	RecordHeaviestWeight  = "heaviest_weight"
+	RecordEstimated1RM    = "estimated_1rm"
+	RecordBestSetVolume   = "best_set_volume"
+	RecordMostReps        = "most_reps"
+	RecordLongestDuration = "longest_duration"
+	RecordLongestDistance = "longest_distance"
+	RecordFastestPace     = "fastest_pace"
+)
+
+// Set is a logged set prepared for record and summary calculations.
+type Set struct {
+	Date            time.Time
+	Reps            int32
+	LoadKg          float64 // Weight moved, including bodyweight for bodyweight exercises
+	DurationSeconds *int32
+	DistanceMeters  *float64
+	RPE             *float64
+}
+
+// Record is the best value of one record type. Value is in kilograms, repetitions, seconds,
+// meters or seconds per kilometer depending on the type.
+type Record struct {
+	Type  string    `json:"type"`
+	Value float64   `json:"value"`
+	Date  time.Time `json:"date"`
+}
+
+// Summary adds up all sets of an exercise.
+type Summary struct {
+	Sets            int     `json:"sets"`
+	TotalReps       int64   `json:"total_reps"`
+	VolumeKg        float64 `json:"volume_kg"`
+	DurationSeconds int64   `json:"duration_seconds"`
+	DistanceMeters  float64 `json:"distance_meters"`
+}
+
+// Load returns the weight moved in a set. For bodyweight exercises this is the lifter's
+// bodyweight plus or minus the additional weight, otherwise the logged weight.
+func Load(weightKg float64, additionalWeightKg float64, exerciseType db.NullExerciseType, bodyweightKg float64) float64 {
+	if !exerciseType.Valid {
+		return weightKg
+	}
+	switch exerciseType.ExerciseType {
+	case db.ExerciseTypeWeighted:
+		return bodyweightKg + additionalWeightKg
+	case db.ExerciseTypeAssisted:
+		return bodyweightKg - additionalWeightKg
+	}
+	return bodyweightKg
+}
+
+// EstimatedOneRepMax estimates a one rep max with the Epley formula.
+func EstimatedOneRepMax(loadKg float64, reps int32) float64 {
+	return epley(loadKg, float64(reps))
+}
+
+// EstimatedOneRepMaxAtRPE estimates a one rep max from a set that stopped short of failure.
+// The reps left in reserve (10 - RPE) count as performed reps, so an RPE 8 triple is
+// estimated like a set of five to failure.
+func EstimatedOneRepMaxAtRPE(loadKg float64, reps int32, rpe float64) float64 {
+	return epley(loadKg, float64(reps)+10-rpe)
+}
+
+func epley(loadKg float64, reps float64) float64 {
+	if reps <= 1 {
+		return loadKg
+	}
+	return loadKg * (1 + reps/30)
+}
+
+// PersonalRecords returns the records that apply to kind, in a fixed order per kind.
+// Record types without a qualifying set are left out.
+func PersonalRecords(kind db.MeasurementKind, sets []Set) []Record {
+	var types []string
+	switch kind {
+	case db.MeasurementKindRepsWeight:
+		types = []string{RecordHeaviestWeight, RecordEstimated1RM, RecordBestSetVolume, RecordMostReps}
+	case db.MeasurementKindTime:
+		types = []string{RecordLongestDuration}
+	case db.MeasurementKindTimeWeight:
+		types = []string{RecordHeaviestWeight, RecordLongestDuration}
+	case db.MeasurementKindDistance:
+		types = []string{RecordLongestDistance, RecordFastestPace}
+	case db.MeasurementKindDistanceWeight:
+		types = []string{RecordHeaviestWeight, RecordLongestDistance}
+	}
+
+	records := []Record{}
+	for _, recordType := range types {
+		if record, ok := bestRecord(recordType, sets); ok {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Improvement is a record set by beating the best value of the earlier sets.
+type Improvement struct {
+	Record
+	Previous float64 `json:"previous"`
+}
+
+// ImprovedRecords returns the records the set logged at date holds by beating the sets before
+// it. The first set of a record type beats nothing, so it improves no record.
+func ImprovedRecords(kind db.MeasurementKind, sets []Set, date time.Time) []Improvement {
+	var earlier []Set
+	for _, set := range sets {
+		if set.Date.Before(date) {
+			earlier = append(earlier, set)
+		}
+	}
+	previous := make(map[string]float64)
+	for _, record := range PersonalRecords(kind, earlier) {
+		previous[record.Type] = record.Value
+	}
+
+	improved := []Improvement{}
+	for _, record := range PersonalRecords(kind, sets) {
+		if value, ok := previous[record.Type]; ok && record.Date.Equal(date) {
+			improved = append(improved, Improvement{Record: record, Previous: value})
+		}
+	}
+	return improved
+}
+
+// bestRecord finds the best set for a record type. Earlier sets win ties, so the record
+// date is when the value was first reached.
+func bestRecord(recordType string, sets []Set) (Record, bool) {
+	var best Record
+	found := false
+	for _, set := range sets {
+		value, ok := recordValue(recordType, set)
+		if !ok {
+			continue
+		}
+		better := value > best.Value
+		if recordType == RecordFastestPace {
+			better = value < best.Value
+		}
+		if !found || better {
+			best = Record{Type: recordType, Value: value, Date: set.Date}
+			found = true
+		}
+	}
+	return best, found
+}
+
+func recordValue(recordType string, set Set) (float64, bool) {
+	switch recordType {
+	case RecordHeaviestWeight:
+		return set.LoadKg, set.LoadKg > 0
+	case RecordEstimated1RM:
+		if set.RPE != nil {
+			reps := float64(set.Reps) + 10 - *set.RPE
+			return EstimatedOneRepMaxAtRPE(set.LoadKg, set.Reps, *set.RPE), set.LoadKg > 0 && reps <= maxEstimateReps
+		}
+		return EstimatedOneRepMax(set.LoadKg, set.Reps), set.LoadKg > 0 && set.Reps <= maxEstimateReps
+	case RecordBestSetVolume:
+		return set.LoadKg * float64(set.Reps), set.LoadKg > 0
+	case RecordMostReps:
+		return float64(set.Reps), true
+	case RecordLongestDuration:
+		if set.DurationSeconds == nil {
+			return 0, false
+		}
+		return float64(*set.DurationSeconds), true
+	case RecordLongestDistance:
+		if set.DistanceMeters == nil {
+			return 0, false
+		}
+		return *set.DistanceMeters, true
+	case RecordFastestPace:
+		if set.DistanceMeters == nil || set.DurationSeconds == nil {
+			return 0, false
+		}
+		return float64(*set.DurationSeconds) / (*set.DistanceMeters / 1000), true
+	}
+	return 0, false
+}
+
+// Summarize adds up the sets of an exercise. Reps and volume only apply to reps and weight
+// based exercises.
+func Summarize(kind db.MeasurementKind, sets []Set) Summary {
+	summary := Summary{Sets: len(sets)}
+	for _, set := range sets {
+		if kind == db.MeasurementKindRepsWeight {
+			summary.TotalReps += int64(set.Reps)
+			summary.VolumeKg += set.LoadKg * float64(set.Reps)
+		}
+		if set.DurationSeconds != nil {
+			summary.DurationSeconds += int64(*set.DurationSeconds)
+		}
+		if set.DistanceMeters != nil {
+			summary.DistanceMeters += *set.DistanceMeters
+		}
+	}
+	return summary
+}
@@ -0,0 +1,48 @@
+package templates
+
+import (
+	"new-chainsaw/internal/strength"
+)
+
+// Target is how a template exercise prescribes its sets. Weights are in kilograms and the
+// percentage is of the estimated one rep max, a template sets a weight or a percentage.
+type Target struct {
+	Reps       *int32
+	WeightKg   *float64
+	Percentage *float64
+}
+
+// LatestSet is the latest set the user logged of the exercise.
+type LatestSet struct {
+	Reps     int32
+	WeightKg float64
+	RPE      *float64
+}
+
+// Prescribe returns the target reps and weight in kilograms of a template exercise's sets. Open
+// targets repeat the latest set, and percentages of the one rep max estimated from it are
+// rounded to a loadable weight with round. nil leaves the target to the user.
+func Prescribe(target Target, latest *LatestSet, round func(float64) float64) (reps *int32, weightKg *float64) {
+	reps = target.Reps
+	if reps == nil && latest != nil {
+		reps = &latest.Reps
+	}
+
+	switch {
+	case target.WeightKg != nil:
+		return reps, target.WeightKg
+	case latest == nil:
+		return reps, nil
+	case target.Percentage != nil:
+		oneRepMax := strength.EstimatedOneRepMax(latest.WeightKg, latest.Reps)
+		if latest.RPE != nil {
+			oneRepMax = strength.EstimatedOneRepMaxAtRPE(latest.WeightKg, latest.Reps, *latest.RPE)
+		}
+		if oneRepMax <= 0 {
+			return reps, nil
+		}
+		weight := round(oneRepMax * *target.Percentage / 100)
+		return reps, &weight
+	}
+	return reps, &latest.WeightKg
+}
@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	minBodyweight      = 20.0
+	maxBodyweight      = 400.0
+	maxGoalHorizonDays = 5 * 365
+)
+
+// ValidateBodyweightGoal ensures a goal weight in kilograms is plausible and its date is in
+// the coming years
+func ValidateBodyweightGoal(kg float64, date time.Time, today time.Time) error {
+	switch {
+	case kg < minBodyweight || kg > maxBodyweight:
+		return fmt.Errorf("target weight must be between %g and %g kg", minBodyweight, maxBodyweight)
+	case !date.After(today):
+		return fmt.Errorf("target date must be in the future")
+	case date.After(today.AddDate(0, 0, maxGoalHorizonDays)):
+		return fmt.Errorf("target date must be within %d days", maxGoalHorizonDays)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"new-chainsaw/db"
+)
+
+var (
+	maxChallengeNameLength = 100
+	maxChallengeDays       = 366
+)
+
+// ValidateChallenge ensures a challenge has a name, a known metric and a window of up to a
+// year that has not ended. A best one rep max only compares within a single exercise.
+func ValidateChallenge(name string, metric db.ChallengeMetric, exerciseID *int32, startsOn time.Time, endsOn time.Time, today time.Time) error {
+	switch metric {
+	case db.ChallengeMetricTotalReps, db.ChallengeMetricTonnage, db.ChallengeMetricSessions:
+	case db.ChallengeMetricBestE1rm:
+		if exerciseID == nil {
+			return fmt.Errorf("an exercise is required for best_e1rm challenges")
+		}
+	default:
+		return fmt.Errorf("metric must be one of total_reps, tonnage, sessions or best_e1rm")
+	}
+	switch {
+	case strings.TrimSpace(name) == "" || len(name) > maxChallengeNameLength:
+		return fmt.Errorf("name must be between 1 and %d characters", maxChallengeNameLength)
+	case endsOn.Before(startsOn):
+		return fmt.Errorf("end date must not be before the start date")
+	case endsOn.Before(today):
+		return fmt.Errorf("end date must not be in the past")
+	case endsOn.Sub(startsOn) >= time.Duration(maxChallengeDays)*24*time.Hour:
+		return fmt.Errorf("challenges can last up to %d days", maxChallengeDays)
+	}
+	return nil
+}
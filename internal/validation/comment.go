@@ -0,0 +1,16 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+var maxCommentLength = 2000
+
+// ValidateComment ensures a comment is not blank or too long.
+func ValidateComment(body string) error {
+	if strings.TrimSpace(body) == "" || len(body) > maxCommentLength {
+		return fmt.Errorf("comment must be between 1 and %d characters", maxCommentLength)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MuscleGroups are the muscle groups exercises can target.
+var MuscleGroups = []string{
+	"chest", "shoulders", "triceps", "biceps", "forearms", "lats", "upper_back", "traps",
+	"lower_back", "core", "glutes", "quads", "hamstrings", "adductors", "abductors", "calves",
+}
+
+var (
+	equipmentTypes    = []string{"barbell", "dumbbell", "kettlebell", "machine", "cable", "bodyweight", "band", "other"}
+	movementPatterns  = []string{"squat", "hinge", "lunge", "horizontal_push", "vertical_push", "horizontal_pull", "vertical_pull", "carry", "core", "isolation", "olympic"}
+	exerciseTypes     = []string{"Bodyweight", "Weighted", "Assisted"}
+	measurementKinds  = []string{"reps_weight", "time", "distance", "distance_weight", "time_weight"}
+	maxMuscleGroups   = 6
+	maxExerciseLength = 100
+)
+
+// ValidateExerciseName ensures a custom exercise name fits the exercises table
+func ValidateExerciseName(name string) error {
+	trimmed := strings.TrimSpace(name)
+	switch {
+	case trimmed == "":
+		return fmt.Errorf("exercise name must not be empty")
+	case len(trimmed) > maxExerciseLength:
+		return fmt.Errorf("exercise name must be at most %d characters", maxExerciseLength)
+	case trimmed != name:
+		return fmt.Errorf("exercise name must not start or end with spaces")
+	}
+	return nil
+}
+
+// ValidateMuscleGroups ensures every muscle group is known and listed once
+func ValidateMuscleGroups(groups []string) error {
+	if len(groups) > maxMuscleGroups {
+		return fmt.Errorf("at most %d muscle groups are allowed", maxMuscleGroups)
+	}
+	seen := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if !contains(MuscleGroups, group) {
+			return fmt.Errorf("unknown muscle group %q", group)
+		}
+		if seen[group] {
+			return fmt.Errorf("muscle group %q is listed twice", group)
+		}
+		seen[group] = true
+	}
+	return nil
+}
+
+// ValidateMuscleGroupOverlap ensures no muscle group is both primary and secondary
+func ValidateMuscleGroupOverlap(primary []string, secondary []string) error {
+	for _, group := range secondary {
+		if contains(primary, group) {
+			return fmt.Errorf("a muscle group cannot be both primary and secondary")
+		}
+	}
+	return nil
+}
+
+// ValidateEquipment accepts an empty value or one of the equipment_type enum values
+func ValidateEquipment(equipment string) error {
+	if equipment != "" && !contains(equipmentTypes, equipment) {
+		return fmt.Errorf("equipment must be one of %s", strings.Join(equipmentTypes, ", "))
+	}
+	return nil
+}
+
+// ValidateMovementPattern accepts an empty value or one of the movement_pattern enum values
+func ValidateMovementPattern(pattern string) error {
+	if pattern != "" && !contains(movementPatterns, pattern) {
+		return fmt.Errorf("movement pattern must be one of %s", strings.Join(movementPatterns, ", "))
+	}
+	return nil
+}
+
+// ValidateExerciseType accepts an empty value or one of the exercise_type enum values
+func ValidateExerciseType(exerciseType string) error {
+	if exerciseType != "" && !contains(exerciseTypes, exerciseType) {
+		return fmt.Errorf("exercise type must be one of %s", strings.Join(exerciseTypes, ", "))
+	}
+	return nil
+}
+
+// ValidateMeasurementKind accepts one of the measurement_kind enum values
+func ValidateMeasurementKind(kind string) error {
+	if !contains(measurementKinds, kind) {
+		return fmt.Errorf("measurement kind must be one of %s", strings.Join(measurementKinds, ", "))
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"new-chainsaw/db"
+)
+
+var (
+	maxGoalWeight = 1000.0
+	maxGoalReps   = 1000.0
+)
+
+// ValidateStrengthGoal ensures a goal has a known metric, a plausible target in kilograms or
+// repetitions and a deadline in the coming years
+func ValidateStrengthGoal(metric db.GoalMetric, target float64, deadline time.Time, today time.Time) error {
+	switch metric {
+	case db.GoalMetricEstimated1rm, db.GoalMetricHeaviestWeight:
+		if target <= 0 || target > maxGoalWeight {
+			return fmt.Errorf("target must be between 0 and %g kg", maxGoalWeight)
+		}
+	case db.GoalMetricMostReps:
+		if target < 1 || target > maxGoalReps || target != float64(int(target)) {
+			return fmt.Errorf("target must be a whole number of repetitions up to %g", maxGoalReps)
+		}
+	default:
+		return fmt.Errorf("metric must be one of estimated_1rm, heaviest_weight or most_reps")
+	}
+	switch {
+	case !deadline.After(today):
+		return fmt.Errorf("deadline must be in the future")
+	case deadline.After(today.AddDate(0, 0, maxGoalHorizonDays)):
+		return fmt.Errorf("deadline must be within %d days", maxGoalHorizonDays)
+	}
+	return nil
+}
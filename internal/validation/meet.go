@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"new-chainsaw/db"
+)
+
+var (
+	maxMeetNameLength     = 100
+	maxDivisionNameLength = 50
+	maxWeightClass        = 300.0
+	maxLifterAge          = 120
+)
+
+// ValidateMeet ensures a meet has a name and a known scoring
+func ValidateMeet(name string, scoring db.MeetScoring) error {
+	switch {
+	case strings.TrimSpace(name) == "" || len(name) > maxMeetNameLength:
+		return fmt.Errorf("name must be between 1 and %d characters", maxMeetNameLength)
+	case scoring != db.MeetScoringDots && scoring != db.MeetScoringIpfGl:
+		return fmt.Errorf("scoring must be dots or ipf_gl")
+	}
+	return nil
+}
+
+// ValidateMeetDivision ensures a division has a name, a sex, a plausible weight class limit in
+// kilograms and an age range that is not empty
+func ValidateMeetDivision(name string, sex string, weightClass *float64, minAge *int, maxAge *int) error {
+	switch {
+	case strings.TrimSpace(name) == "" || len(name) > maxDivisionNameLength:
+		return fmt.Errorf("name must be between 1 and %d characters", maxDivisionNameLength)
+	case sex != "male" && sex != "female":
+		return fmt.Errorf("sex must be male or female")
+	case weightClass != nil && (*weightClass < minBodyweight || *weightClass > maxWeightClass):
+		return fmt.Errorf("weight class must be between %g and %g kg", minBodyweight, maxWeightClass)
+	case minAge != nil && (*minAge < 1 || *minAge > maxLifterAge):
+		return fmt.Errorf("minimum age must be between 1 and %d", maxLifterAge)
+	case maxAge != nil && (*maxAge < 1 || *maxAge > maxLifterAge):
+		return fmt.Errorf("maximum age must be between 1 and %d", maxLifterAge)
+	case minAge != nil && maxAge != nil && *maxAge < *minAge:
+		return fmt.Errorf("maximum age must not be below the minimum age")
+	}
+	return nil
+}
+
+// ValidateWeighIn ensures a weigh-in in kilograms is plausible
+func ValidateWeighIn(kg float64) error {
+	if kg < minBodyweight || kg > maxBodyweight {
+		return fmt.Errorf("bodyweight must be between %g and %g kg", minBodyweight, maxBodyweight)
+	}
+	return nil
+}
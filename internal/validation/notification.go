@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/notifications"
+)
+
+// ValidateNotificationType ensures users can be notified of a type.
+func ValidateNotificationType(t string) error {
+	if !notifications.IsType(events.Type(t)) {
+		return fmt.Errorf("unknown notification type %q", t)
+	}
+	return nil
+}
+
+// maxPushEndpointLength is well above the endpoints of the browsers' push services.
+const maxPushEndpointLength = 1000
+
+// ValidatePushSubscription ensures a browser's push subscription has an HTTPS endpoint and keys
+// of the right size, base64url encoded.
+func ValidatePushSubscription(endpoint string, p256dh string, auth string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil || len(endpoint) > maxPushEndpointLength {
+		return fmt.Errorf("endpoint must be an HTTPS URL of at most %d characters", maxPushEndpointLength)
+	}
+	key, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(p256dh, "="))
+	if err != nil || len(key) != 65 || key[0] != 4 {
+		return fmt.Errorf("p256dh must be an uncompressed P-256 public key")
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(auth, "="))
+	if err != nil || len(secret) != 16 {
+		return fmt.Errorf("auth must be a 16 byte secret")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"new-chainsaw/db"
+	"new-chainsaw/internal/orgs"
+)
+
+var maxOrganizationNameLength = 100
+
+// ValidateOrganization ensures an organization has a name.
+func ValidateOrganization(name string) error {
+	if strings.TrimSpace(name) == "" || len(name) > maxOrganizationNameLength {
+		return fmt.Errorf("name must be between 1 and %d characters", maxOrganizationNameLength)
+	}
+	return nil
+}
+
+// ValidateOrganizationInvite ensures an invite is for a coach or an athlete and expires within
+// the allowed days. Invites without a maximum can be used until they expire.
+func ValidateOrganizationInvite(role db.OrganizationRole, expiresInDays int, maxUses *int32) error {
+	switch {
+	case role != db.OrganizationRoleCoach && role != db.OrganizationRoleAthlete:
+		return fmt.Errorf("role must be coach or athlete")
+	case expiresInDays < 1 || expiresInDays > orgs.MaxInviteDays:
+		return fmt.Errorf("invites must expire in 1 to %d days", orgs.MaxInviteDays)
+	case maxUses != nil && *maxUses < 1:
+		return fmt.Errorf("max uses must be at least 1")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	maxProgramNameLength = 100
+	maxTrainingMax       = 1000.0
+)
+
+// ValidateProgramName ensures a training program name fits the training_programs table
+func ValidateProgramName(name string) error {
+	trimmed := strings.TrimSpace(name)
+	switch {
+	case trimmed == "":
+		return fmt.Errorf("program name must not be empty")
+	case len(trimmed) > maxProgramNameLength:
+		return fmt.Errorf("program name must be at most %d characters", maxProgramNameLength)
+	}
+	return nil
+}
+
+// ValidateTrainingMax ensures a training max in kilograms is positive and plausible
+func ValidateTrainingMax(lift string, kg float64) error {
+	if kg <= 0 || kg > maxTrainingMax {
+		return fmt.Errorf("training max of %s must be above 0 and at most %g kg", lift, maxTrainingMax)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	// Eccentric, bottom pause, concentric and top pause in seconds, X for an explosive phase
+	tempoRegex      = regexp.MustCompile(`^([0-9]|[1-9][0-9]|X)-([0-9]|[1-9][0-9]|X)-([0-9]|[1-9][0-9]|X)-([0-9]|[1-9][0-9]|X)$`)
+	minRPE          = 6.0
+	maxRPE          = 10.0
+	maxRIR          = 10
+	maxRestSeconds  = 3600
+	maxSetNotesSize = 500
+)
+
+// ValidateRPE accepts an empty value or an RPE from 6 to 10 in half steps
+func ValidateRPE(rpe *float64) error {
+	if rpe == nil {
+		return nil
+	}
+	if *rpe < minRPE || *rpe > maxRPE || math.Mod(*rpe*2, 1) != 0 {
+		return fmt.Errorf("rpe must be between %g and %g in steps of 0.5", minRPE, maxRPE)
+	}
+	return nil
+}
+
+// ValidateRIR accepts an empty value or a number of reps in reserve from 0 to 10
+func ValidateRIR(rir *int32) error {
+	if rir != nil && (*rir < 0 || *rir > int32(maxRIR)) {
+		return fmt.Errorf("rir must be between 0 and %d", maxRIR)
+	}
+	return nil
+}
+
+// ValidateTempo accepts an empty value or four tempo phases such as 3-1-1-0 or 2-0-X-1
+func ValidateTempo(tempo string) error {
+	if tempo != "" && !tempoRegex.MatchString(tempo) {
+		return fmt.Errorf("tempo must be four phases in seconds or X separated by dashes, e.g. 3-1-1-0")
+	}
+	return nil
+}
+
+// ValidateRestSeconds accepts an empty value or a rest of at most an hour
+func ValidateRestSeconds(rest *int32) error {
+	if rest != nil && (*rest < 0 || *rest > int32(maxRestSeconds)) {
+		return fmt.Errorf("rest must be between 0 and %d seconds", maxRestSeconds)
+	}
+	return nil
+}
+
+// ValidateSetNotes ensures the notes of a set are not too long
+func ValidateSetNotes(notes string) error {
+	if utf8.RuneCountInString(notes) > maxSetNotesSize {
+		return fmt.Errorf("notes must be at most %d characters", maxSetNotesSize)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	maxTemplateNameLength = 100
+	maxTemplateExercises  = 30
+	maxTargetSets         = 20
+	maxTargetPercentage   = 120.0
+)
+
+// ValidateTemplateName ensures a workout template name fits the workout_templates table
+func ValidateTemplateName(name string) error {
+	trimmed := strings.TrimSpace(name)
+	switch {
+	case trimmed == "":
+		return fmt.Errorf("template name must not be empty")
+	case len(trimmed) > maxTemplateNameLength:
+		return fmt.Errorf("template name must be at most %d characters", maxTemplateNameLength)
+	}
+	return nil
+}
+
+// ValidateTemplateExerciseCount ensures a template has at least one and not too many exercises
+func ValidateTemplateExerciseCount(count int) error {
+	if count == 0 || count > maxTemplateExercises {
+		return fmt.Errorf("a template must have between 1 and %d exercises", maxTemplateExercises)
+	}
+	return nil
+}
+
+// ValidateTargetSets ensures the number of sets of a template exercise is within range
+func ValidateTargetSets(sets int32) error {
+	if sets < 1 || sets > int32(maxTargetSets) {
+		return fmt.Errorf("target sets must be between 1 and %d", maxTargetSets)
+	}
+	return nil
+}
+
+// ValidateTargetReps accepts an empty value or a positive number of reps
+func ValidateTargetReps(reps *int32) error {
+	if reps != nil && *reps < 1 {
+		return fmt.Errorf("target reps must be positive")
+	}
+	return nil
+}
+
+// ValidateTargetLoad accepts either a target weight or a percentage of the one rep max, not both
+func ValidateTargetLoad(weight *float64, percentage *float64) error {
+	switch {
+	case weight != nil && percentage != nil:
+		return fmt.Errorf("set either a target weight or a target percentage, not both")
+	case weight != nil && *weight < 0:
+		return fmt.Errorf("target weight must not be negative")
+	case percentage != nil && (*percentage <= 0 || *percentage > maxTargetPercentage):
+		return fmt.Errorf("target percentage must be above 0 and at most %g", maxTargetPercentage)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+
+	"new-chainsaw/internal/events"
+	"new-chainsaw/internal/webhooks"
+)
+
+const (
+	maxWebhookURLLength         = 2000
+	maxWebhookDescriptionLength = 200
+)
+
+// ValidateWebhook ensures a webhook posts to an HTTPS URL and subscribes to at least one event
+// webhooks can subscribe to.
+func ValidateWebhook(rawURL string, eventTypes []string, description *string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || len(rawURL) > maxWebhookURLLength {
+		return fmt.Errorf("url must be an HTTPS URL of at most %d characters", maxWebhookURLLength)
+	}
+	if u.User != nil {
+		return fmt.Errorf("url cannot contain credentials")
+	}
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("subscribe to at least one event type")
+	}
+	for _, t := range eventTypes {
+		if !webhooks.IsEventType(events.Type(t)) {
+			return fmt.Errorf("unknown webhook event type %q", t)
+		}
+	}
+	if description != nil && len(*description) > maxWebhookDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", maxWebhookDescriptionLength)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"new-chainsaw/internal/httpclient"
+)
+
+const (
+	// requestTimeout is how long an endpoint has to respond to a delivery.
+	requestTimeout = 10 * time.Second
+	// maxResponseBody is how much of a response is read so the connection can be reused.
+	maxResponseBody = 64 << 10
+)
+
+// Client posts signed payloads to webhooks.
+type Client struct {
+	http *http.Client
+}
+
+// NewClient returns a client that only posts to public addresses, unless allowPrivate is set
+// for development, see httpclient.NewPublicClient.
+func NewClient(allowPrivate bool) *Client {
+	return &Client{http: httpclient.NewPublicClient(requestTimeout, allowPrivate)}
+}
+
+// Send posts a payload to a webhook. It returns the status the endpoint responded with, 0
+// without a response, and an error unless the status is 2xx.
+func (c *Client) Send(ctx context.Context, url string, secret string, payload Payload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), body))
+	req.Header.Set(EventHeader, string(payload.Type))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(payload.DeliveryID, 10))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,131 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"new-chainsaw/internal/events"
+)
+
+// Headers of a delivery. The signature header is "t=<unix seconds>,v1=<hex HMAC-SHA256>" of
+// the timestamp, a dot and the body, keyed with the webhook's secret.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	// MaxWebhooks is how many webhooks a user or an organization can have.
+	MaxWebhooks = 10
+	// MaxAttempts is how many times a delivery is attempted before it fails.
+	MaxAttempts = 10
+	// firstRetryDelay doubles after every failed attempt, up to maxRetryDelay.
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = 6 * time.Hour
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrSignatureExpired = errors.New("webhook signature timestamp is outside the tolerance")
+)
+
+// EventTypes lists the events webhooks can subscribe to.
+var EventTypes = []events.Type{
+	events.WorkoutLogged,
+	events.PRAchieved,
+	events.TrophyUnlocked,
+	events.BodyweightLogged,
+}
+
+// IsEventType reports whether webhooks can subscribe to an event type.
+func IsEventType(t events.Type) bool {
+	for _, known := range EventTypes {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the body posted to a webhook.
+type Payload struct {
+	DeliveryID int64           `json:"delivery_id"` // Redeliveries get a new one
+	EventID    *int64          `json:"event_id"`    // The same for redeliveries
+	Type       events.Type     `json:"type"`
+	UserID     int32           `json:"user_id"` // Whose event it is
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"` // The event payload, see internal/events
+}
+
+// NewSecret returns a random secret to sign a webhook's payloads with.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// Backoff returns how long to wait after a delivery failed its attempts-th attempt.
+func Backoff(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// Sign returns the signature header of a body posted at a time.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(signature(secret, t, body))
+}
+
+// Verify checks the signature header of a received body, as receivers should, and that it was
+// signed within the tolerance of now to prevent replays.
+func Verify(secret string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var t string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	expected := signature(secret, t, body)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+				return ErrSignatureExpired
+			}
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func signature(secret string, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s.", timestamp)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
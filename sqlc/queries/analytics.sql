@@ -0,0 +1,152 @@
+-- Training analytics queries
+
+-- name: GetAnalyticsCache :one
+SELECT payload
+FROM analytics_cache
+WHERE user_id = $1 AND cache_key = $2;
+
+-- The version of the user's logs, read before computing analytics to save with SaveAnalyticsCache.
+-- Waits for logs being written to commit.
+-- name: GetAnalyticsVersion :one
+INSERT INTO analytics_versions (user_id)
+VALUES ($1)
+ON CONFLICT (user_id) DO UPDATE
+SET version = analytics_versions.version
+RETURNING version;
+
+-- Saves analytics computed at a version of the user's logs unless logs changed since. Locking the
+-- version waits for logs being written to commit, they clear the cache before the save otherwise.
+-- name: SaveAnalyticsCache :execrows
+INSERT INTO analytics_cache (user_id, cache_key, payload)
+SELECT v.user_id, @cache_key, @payload
+FROM (
+    SELECT user_id
+    FROM analytics_versions
+    WHERE user_id = @user_id AND version = @version
+    FOR SHARE
+) v
+ON CONFLICT (user_id, cache_key) DO UPDATE
+SET payload = EXCLUDED.payload, computed_at = NOW();
+
+-- Tonnage, sets and training days per week or month since from_date, including empty periods.
+-- Bodyweight exercises are loaded with the bodyweight logged with the set.
+-- name: GetTrainingVolume :many
+WITH periods AS (
+    SELECT generate_series(
+        date_trunc(@period::text, @from_date::timestamptz AT TIME ZONE 'UTC'),
+        date_trunc(@period::text, NOW() AT TIME ZONE 'UTC'),
+        ('1 ' || @period::text)::interval
+    ) AS period_start
+),
+volume AS (
+    SELECT
+        date_trunc(@period::text, el.log_date AT TIME ZONE 'UTC') AS period_start,
+        SUM(el.reps * CASE el.exercise_type
+            WHEN 'Weighted' THEN bw.bodyweight + COALESCE(el.additional_weight, 0)
+            WHEN 'Assisted' THEN bw.bodyweight - COALESCE(el.additional_weight, 0)
+            WHEN 'Bodyweight' THEN bw.bodyweight
+            ELSE el.weight
+        END) FILTER (WHERE e.measurement_kind = 'reps_weight') AS tonnage,
+        COUNT(*) AS sets,
+        SUM(el.reps) FILTER (WHERE e.measurement_kind = 'reps_weight') AS reps,
+        COUNT(DISTINCT (el.log_date AT TIME ZONE 'UTC')::date) AS training_days
+    FROM exercise_logs el
+    JOIN exercises e ON el.exercise_id = e.id
+    JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+    WHERE el.user_id = @user_id AND el.log_date >= @from_date
+    GROUP BY 1
+)
+SELECT
+    p.period_start::timestamp AS period_start,
+    COALESCE(v.tonnage, 0)::numeric AS tonnage,
+    COALESCE(v.sets, 0)::bigint AS sets,
+    COALESCE(v.reps, 0)::bigint AS reps,
+    COALESCE(v.training_days, 0)::bigint AS training_days,
+    (LAG(COALESCE(v.tonnage, 0)) OVER (ORDER BY p.period_start))::numeric AS previous_tonnage
+FROM periods p
+LEFT JOIN volume v ON v.period_start = p.period_start
+ORDER BY p.period_start;
+
+-- Sets per muscle group and week or month since from_date, counted once for every primary and
+-- secondary muscle group of the exercise.
+-- name: GetMuscleGroupSets :many
+WITH sets AS (
+    SELECT
+        date_trunc(@period::text, el.log_date AT TIME ZONE 'UTC') AS period_start,
+        e.primary_muscle_groups,
+        e.secondary_muscle_groups
+    FROM exercise_logs el
+    JOIN exercises e ON el.exercise_id = e.id
+    WHERE el.user_id = @user_id AND el.log_date >= @from_date
+),
+muscle_sets AS (
+    SELECT period_start, UNNEST(primary_muscle_groups) AS muscle_group, 1 AS primary_set, 0 AS secondary_set
+    FROM sets
+    UNION ALL
+    SELECT period_start, UNNEST(secondary_muscle_groups) AS muscle_group, 0 AS primary_set, 1 AS secondary_set
+    FROM sets
+)
+SELECT
+    period_start::timestamp AS period_start,
+    muscle_group::text AS muscle_group,
+    SUM(primary_set)::bigint AS primary_sets,
+    SUM(secondary_set)::bigint AS secondary_sets
+FROM muscle_sets
+GROUP BY period_start, muscle_group
+ORDER BY period_start, muscle_group;
+
+-- name: GetExerciseFrequency :many
+SELECT
+    e.id AS exercise_id,
+    e.name AS exercise_name,
+    COUNT(DISTINCT (el.log_date AT TIME ZONE 'UTC')::date)::bigint AS training_days,
+    COUNT(*)::bigint AS sets,
+    MAX(el.log_date)::timestamptz AS last_performed
+FROM exercise_logs el
+JOIN exercises e ON el.exercise_id = e.id
+WHERE el.user_id = @user_id AND el.log_date >= @from_date
+GROUP BY e.id, e.name
+ORDER BY training_days DESC, sets DESC, e.name;
+
+-- Sets since from_date bucketed by their load as a percentage of the best estimated one rep max
+-- of the exercise up to that set. Sets with an RPE count their reps in reserve like
+-- strength.EstimatedOneRepMaxAtRPE.
+-- name: GetIntensityDistribution :many
+WITH loads AS (
+    SELECT
+        el.exercise_id,
+        el.log_date,
+        CASE el.exercise_type
+            WHEN 'Weighted' THEN bw.bodyweight + COALESCE(el.additional_weight, 0)
+            WHEN 'Assisted' THEN bw.bodyweight - COALESCE(el.additional_weight, 0)
+            WHEN 'Bodyweight' THEN bw.bodyweight
+            ELSE el.weight
+        END AS load,
+        CASE WHEN el.rpe IS NULL THEN el.reps ELSE el.reps + 10 - el.rpe END AS effective_reps
+    FROM exercise_logs el
+    JOIN exercises e ON el.exercise_id = e.id
+    JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+    WHERE el.user_id = @user_id AND e.measurement_kind = 'reps_weight'
+),
+estimates AS (
+    SELECT
+        log_date,
+        load,
+        MAX(CASE WHEN effective_reps <= 1 THEN load ELSE load * (1 + effective_reps / 30.0) END) OVER (
+            PARTITION BY exercise_id ORDER BY log_date ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+        ) AS best_e1rm
+    FROM loads
+)
+SELECT
+    (CASE
+        WHEN load < best_e1rm * 0.6 THEN 0
+        WHEN load < best_e1rm * 0.7 THEN 60
+        WHEN load < best_e1rm * 0.8 THEN 70
+        WHEN load < best_e1rm * 0.9 THEN 80
+        ELSE 90
+    END)::integer AS percent_from,
+    COUNT(*)::bigint AS sets
+FROM estimates
+WHERE log_date >= @from_date AND best_e1rm > 0
+GROUP BY 1
+ORDER BY 1;
@@ -0,0 +1,46 @@
+-- Body measurement queries
+
+-- name: UpsertBodyMeasurement :one
+INSERT INTO body_measurements (user_id, kind, value, source, measured_at)
+VALUES (@user_id, @kind, @value, @source, @measured_at)
+ON CONFLICT (user_id, kind, measured_at) DO UPDATE
+SET value = EXCLUDED.value,
+    source = EXCLUDED.source,
+    updated_at = NOW()
+RETURNING id, user_id, kind, value, source, measured_at, created_at, updated_at;
+
+-- Body fat readings come with the weigh-in closest to them within a week for lean mass
+-- name: ListBodyMeasurements :many
+SELECT bm.id, bm.kind, bm.value, bm.source, bm.measured_at, bw.bodyweight
+FROM body_measurements bm
+LEFT JOIN LATERAL (
+    SELECT bl.bodyweight
+    FROM bodyweight_logs bl
+    WHERE bl.user_id = bm.user_id
+      AND bl.log_date BETWEEN bm.measured_at - INTERVAL '7 days' AND bm.measured_at + INTERVAL '7 days'
+    ORDER BY ABS(EXTRACT(EPOCH FROM bl.log_date - bm.measured_at))
+    LIMIT 1
+) bw ON bm.kind = 'body_fat'
+WHERE bm.user_id = @user_id
+  AND (sqlc.narg(kind)::body_measurement_kind IS NULL OR bm.kind = sqlc.narg(kind))
+  AND (sqlc.narg(from_date)::timestamptz IS NULL OR bm.measured_at >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::timestamptz IS NULL OR bm.measured_at < sqlc.narg(to_date))
+ORDER BY bm.measured_at, bm.kind;
+
+-- name: GetLatestBodyMeasurements :many
+SELECT DISTINCT ON (bm.kind) bm.id, bm.kind, bm.value, bm.source, bm.measured_at, bw.bodyweight
+FROM body_measurements bm
+LEFT JOIN LATERAL (
+    SELECT bl.bodyweight
+    FROM bodyweight_logs bl
+    WHERE bl.user_id = bm.user_id
+      AND bl.log_date BETWEEN bm.measured_at - INTERVAL '7 days' AND bm.measured_at + INTERVAL '7 days'
+    ORDER BY ABS(EXTRACT(EPOCH FROM bl.log_date - bm.measured_at))
+    LIMIT 1
+) bw ON bm.kind = 'body_fat'
+WHERE bm.user_id = $1
+ORDER BY bm.kind, bm.measured_at DESC;
+
+-- name: DeleteBodyMeasurement :execrows
+DELETE FROM body_measurements
+WHERE id = $1 AND user_id = $2;
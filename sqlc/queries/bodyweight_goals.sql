@@ -0,0 +1,21 @@
+-- Bodyweight goal queries
+
+-- name: GetBodyweightGoal :one
+SELECT user_id, target_weight, target_date, start_weight, created_at, updated_at
+FROM bodyweight_goals
+WHERE user_id = $1;
+
+-- name: UpsertBodyweightGoal :one
+INSERT INTO bodyweight_goals (user_id, target_weight, target_date, start_weight)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET target_weight = EXCLUDED.target_weight,
+    target_date = EXCLUDED.target_date,
+    start_weight = EXCLUDED.start_weight,
+    created_at = NOW(),
+    updated_at = NOW()
+RETURNING user_id, target_weight, target_date, start_weight, created_at, updated_at;
+
+-- name: DeleteBodyweightGoal :execrows
+DELETE FROM bodyweight_goals
+WHERE user_id = $1;
@@ -27,3 +27,17 @@ LIMIT 1;
 SELECT id
 FROM bodyweight_logs
 WHERE user_id = $1 AND log_date = $2;
+
+-- name: GetBodyweightLogOnOrBefore :one
+SELECT id
+FROM bodyweight_logs
+WHERE user_id = $1 AND log_date <= $2
+ORDER BY log_date DESC
+LIMIT 1;
+
+-- Weigh-ins from a date until another, oldest first
+-- name: GetBodyWeightLogsBetween :many
+SELECT bodyweight, log_date
+FROM bodyweight_logs
+WHERE user_id = @user_id AND log_date >= @from_date AND log_date < @to_date
+ORDER BY log_date ASC;
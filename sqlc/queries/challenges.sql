@@ -0,0 +1,145 @@
+-- Challenge queries
+
+-- name: GetChallenge :one
+SELECT id, owner_user_id, name, description, metric, exercise_id, starts_on, ends_on, open_join, finalized_at, created_at, updated_at
+FROM challenges
+WHERE id = $1;
+
+-- Challenges the user organizes, joined or is invited to, latest first
+-- name: ListUserChallenges :many
+SELECT c.id, c.owner_user_id, c.name, c.description, c.metric, c.exercise_id, c.starts_on, c.ends_on, c.open_join, c.finalized_at, c.created_at, c.updated_at, p.status AS participant_status
+FROM challenges c
+LEFT JOIN challenge_participants p ON p.challenge_id = c.id AND p.user_id = @user_id
+WHERE c.owner_user_id = @user_id
+   OR p.user_id IS NOT NULL
+ORDER BY c.ends_on DESC, c.id DESC;
+
+-- Challenges anyone can join that have not ended, starting soonest first
+-- name: ListOpenChallenges :many
+SELECT id, owner_user_id, name, description, metric, exercise_id, starts_on, ends_on, open_join, finalized_at, created_at, updated_at
+FROM challenges
+WHERE open_join
+  AND ends_on >= (NOW() AT TIME ZONE 'UTC')::date
+ORDER BY starts_on, id
+LIMIT @max_challenges;
+
+-- name: CreateChallenge :one
+INSERT INTO challenges (owner_user_id, name, description, metric, exercise_id, starts_on, ends_on, open_join)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, owner_user_id, name, description, metric, exercise_id, starts_on, ends_on, open_join, finalized_at, created_at, updated_at;
+
+-- Finalized challenges are kept, their winners hold the trophy
+-- name: DeleteChallenge :execrows
+DELETE FROM challenges
+WHERE id = $1
+  AND owner_user_id = $2
+  AND finalized_at IS NULL;
+
+-- name: GetChallengeParticipant :one
+SELECT challenge_id, user_id, status, invited_by, joined_at, final_rank, final_value, created_at, updated_at
+FROM challenge_participants
+WHERE challenge_id = $1 AND user_id = $2;
+
+-- Participants with their final results once the challenge is finalized, joined first
+-- name: ListChallengeParticipants :many
+SELECT p.user_id, u.username, u.name, p.status, p.joined_at, p.final_rank, p.final_value
+FROM challenge_participants p
+JOIN users u ON u.id = p.user_id
+WHERE p.challenge_id = $1
+  AND u.deleted_at IS NULL
+ORDER BY p.joined_at NULLS LAST, p.user_id;
+
+-- name: InviteChallengeParticipant :execrows
+INSERT INTO challenge_participants (challenge_id, user_id, invited_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (challenge_id, user_id) DO NOTHING;
+
+-- Joins a challenge, accepting an invite when there is one
+-- name: JoinChallenge :exec
+INSERT INTO challenge_participants (challenge_id, user_id, status, joined_at)
+VALUES ($1, $2, 'joined', NOW())
+ON CONFLICT (challenge_id, user_id) DO UPDATE
+SET status = 'joined',
+    joined_at = COALESCE(challenge_participants.joined_at, NOW()),
+    updated_at = NOW();
+
+-- Leaves a challenge or declines an invite
+-- name: LeaveChallenge :execrows
+DELETE FROM challenge_participants
+WHERE challenge_id = $1 AND user_id = $2;
+
+-- Totals of the sets each participant logged in the window of the challenge. Reps, tonnage and
+-- one rep maxes only count sets of reps and weight exercises, bodyweight exercises loaded with
+-- the bodyweight logged with the set. One rep maxes are estimated from sets of up to 12 reps.
+-- name: GetChallengeStandings :many
+WITH sets AS (
+    SELECT
+        el.user_id,
+        (el.log_date AT TIME ZONE 'UTC')::date AS log_day,
+        el.reps,
+        el.rpe,
+        e.measurement_kind,
+        CASE el.exercise_type
+            WHEN 'Weighted' THEN bw.bodyweight + COALESCE(el.additional_weight, 0)
+            WHEN 'Assisted' THEN bw.bodyweight - COALESCE(el.additional_weight, 0)
+            WHEN 'Bodyweight' THEN bw.bodyweight
+            ELSE el.weight
+        END AS load
+    FROM challenges c
+    JOIN challenge_participants p ON p.challenge_id = c.id AND p.status = 'joined'
+    JOIN exercise_logs el ON el.user_id = p.user_id
+    JOIN exercises e ON e.id = el.exercise_id
+    JOIN bodyweight_logs bw ON bw.id = el.bodyweight_id
+    WHERE c.id = @challenge_id
+      AND (c.exercise_id IS NULL OR el.exercise_id = c.exercise_id)
+      AND (el.log_date AT TIME ZONE 'UTC')::date BETWEEN c.starts_on AND c.ends_on
+)
+SELECT
+    p.user_id,
+    p.joined_at,
+    COALESCE(SUM(s.reps) FILTER (WHERE s.measurement_kind = 'reps_weight'), 0)::bigint AS total_reps,
+    COALESCE(SUM(s.reps * s.load) FILTER (WHERE s.measurement_kind = 'reps_weight'), 0)::numeric AS tonnage,
+    COUNT(DISTINCT s.log_day)::bigint AS sessions,
+    COALESCE(MAX(CASE
+        WHEN s.reps + COALESCE(10 - s.rpe, 0) <= 1 THEN s.load
+        ELSE s.load * (1 + (s.reps + COALESCE(10 - s.rpe, 0)) / 30.0)
+    END) FILTER (WHERE s.measurement_kind = 'reps_weight' AND s.reps > 0 AND s.reps + COALESCE(10 - s.rpe, 0) <= 12), 0)::numeric AS best_e1rm
+FROM challenge_participants p
+JOIN users u ON u.id = p.user_id
+LEFT JOIN sets s ON s.user_id = p.user_id
+WHERE p.challenge_id = @challenge_id
+  AND p.status = 'joined'
+  AND u.deleted_at IS NULL
+GROUP BY p.user_id, p.joined_at
+ORDER BY p.joined_at, p.user_id;
+
+-- Ended challenges whose results were not saved yet
+-- name: ListChallengesDueForFinalization :many
+SELECT id
+FROM challenges
+WHERE finalized_at IS NULL
+  AND ends_on < (NOW() AT TIME ZONE 'UTC')::date
+ORDER BY ends_on, id
+LIMIT @max_challenges;
+
+-- Only the first run to finalize a challenge marks it
+-- name: MarkChallengeFinalized :execrows
+UPDATE challenges
+SET finalized_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1
+  AND finalized_at IS NULL;
+
+-- name: SaveChallengeResult :exec
+UPDATE challenge_participants
+SET final_rank = $3,
+    final_value = $4,
+    updated_at = NOW()
+WHERE challenge_id = $1 AND user_id = $2;
+
+-- name: CountChallengeWins :one
+SELECT COUNT(*)
+FROM challenge_participants
+WHERE user_id = $1
+  AND final_rank = 1
+  AND final_value > 0;
@@ -0,0 +1,58 @@
+-- Comment and reaction queries
+
+-- name: CreateComment :one
+INSERT INTO comments (user_id, workout_id, exercise_log_id, parent_id, body)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, workout_id, exercise_log_id, parent_id, body, edited_at, created_at, updated_at;
+
+-- name: GetComment :one
+SELECT id, user_id, workout_id, exercise_log_id, parent_id, body, edited_at, created_at, updated_at
+FROM comments
+WHERE id = $1;
+
+-- Comments on a workout or a logged set with their authors, oldest first
+-- name: ListComments :many
+SELECT c.id, c.parent_id, c.user_id, u.username, u.name, u.avatar_url, c.body, c.edited_at, c.created_at
+FROM comments c
+JOIN users u ON c.user_id = u.id
+WHERE (sqlc.narg(workout_id)::integer IS NULL OR c.workout_id = sqlc.narg(workout_id))
+    AND (sqlc.narg(exercise_log_id)::integer IS NULL OR c.exercise_log_id = sqlc.narg(exercise_log_id))
+ORDER BY c.created_at, c.id;
+
+-- name: UpdateComment :one
+UPDATE comments
+SET body = $2, edited_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING id, user_id, workout_id, exercise_log_id, parent_id, body, edited_at, created_at, updated_at;
+
+-- Deletes a comment with its replies
+-- name: DeleteComment :exec
+DELETE FROM comments
+WHERE id = $1;
+
+-- Records a mention, reporting whether the user was not mentioned in the comment before
+-- name: AddCommentMention :execrows
+INSERT INTO comment_mentions (comment_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: AddReaction :execrows
+INSERT INTO reactions (user_id, workout_id, exercise_log_id, kind)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveReaction :execrows
+DELETE FROM reactions
+WHERE user_id = @user_id
+    AND kind = @kind
+    AND (sqlc.narg(workout_id)::integer IS NULL OR workout_id = sqlc.narg(workout_id))
+    AND (sqlc.narg(exercise_log_id)::integer IS NULL OR exercise_log_id = sqlc.narg(exercise_log_id));
+
+-- Reactions on a workout or a logged set by kind, with whether the user reacted with the kind
+-- name: CountReactions :many
+SELECT kind, COUNT(*) AS count, BOOL_OR(user_id = @user_id)::boolean AS reacted
+FROM reactions
+WHERE (sqlc.narg(workout_id)::integer IS NULL OR workout_id = sqlc.narg(workout_id))
+    AND (sqlc.narg(exercise_log_id)::integer IS NULL OR exercise_log_id = sqlc.narg(exercise_log_id))
+GROUP BY kind
+ORDER BY kind;
@@ -0,0 +1,70 @@
+-- Data export queries
+
+-- name: CreateDataExport :one
+INSERT INTO data_exports (user_id)
+VALUES ($1)
+RETURNING id, user_id, status, storage_key, error, expires_at, completed_at, created_at, updated_at;
+
+-- name: GetDataExport :one
+SELECT id, user_id, status, storage_key, error, expires_at, completed_at, created_at, updated_at
+FROM data_exports
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetDataExportByID :one
+SELECT id, user_id, status, storage_key, error, expires_at, completed_at, created_at, updated_at
+FROM data_exports
+WHERE id = $1;
+
+-- Exports not updated within the 30 minute lease were interrupted, see FailStaleDataExports
+-- name: GetActiveDataExport :one
+SELECT id, user_id, status, storage_key, error, expires_at, completed_at, created_at, updated_at
+FROM data_exports
+WHERE user_id = $1
+  AND status IN ('pending', 'processing')
+  AND updated_at > NOW() - INTERVAL '30 minutes'
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: MarkDataExportProcessing :exec
+UPDATE data_exports
+SET status = 'processing', updated_at = NOW()
+WHERE id = $1;
+
+-- name: CompleteDataExport :exec
+UPDATE data_exports
+SET status = 'completed',
+    storage_key = $2,
+    expires_at = $3,
+    completed_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: FailDataExport :exec
+UPDATE data_exports
+SET status = 'failed', error = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetDataExportStorageKeysByUserID :many
+SELECT storage_key
+FROM data_exports
+WHERE user_id = $1 AND storage_key IS NOT NULL;
+
+-- Fails the exports a restart interrupted, they hold the lease of GetActiveDataExport no longer
+-- name: FailStaleDataExports :execrows
+UPDATE data_exports
+SET status = 'failed', error = 'Export was interrupted', updated_at = NOW()
+WHERE status IN ('pending', 'processing')
+  AND updated_at <= NOW() - INTERVAL '30 minutes';
+
+-- name: ListExpiredDataExports :many
+SELECT id, storage_key
+FROM data_exports
+WHERE storage_key IS NOT NULL AND expires_at <= NOW()
+ORDER BY expires_at
+LIMIT $1;
+
+-- Forgets the archive of an expired export once it was deleted from storage
+-- name: ClearDataExportArchive :exec
+UPDATE data_exports
+SET storage_key = NULL, updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,11 @@
+-- Event queries
+
+-- name: InsertEvent :one
+INSERT INTO events (user_id, type, payload)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: GetEvent :one
+SELECT id, user_id, type, payload, created_at, notified_at
+FROM events
+WHERE id = $1;
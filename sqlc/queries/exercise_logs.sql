@@ -1,8 +1,13 @@
 -- Exercise log queries
 
 -- name: LogExercise :exec
-INSERT INTO exercise_logs (user_id, exercise_id, reps, weight, additional_weight, exercise_type, bodyweight_id, log_date)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+INSERT INTO exercise_logs (user_id, exercise_id, reps, weight, additional_weight, exercise_type, bodyweight_id, log_date, duration_seconds, distance_meters, rpe, rir, tempo, rest_seconds, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
+
+-- name: LogWorkoutSet :one
+INSERT INTO exercise_logs (user_id, exercise_id, reps, weight, additional_weight, exercise_type, bodyweight_id, log_date, rpe, rest_seconds)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id;
 
 -- name: UpdateExerciseLog :exec
 UPDATE exercise_logs
@@ -12,12 +17,19 @@ SET
     additional_weight = $5,
     exercise_type = $6,
     bodyweight_id = $7,
+    duration_seconds = $9,
+    distance_meters = $10,
+    rpe = $11,
+    rir = $12,
+    tempo = $13,
+    rest_seconds = $14,
+    notes = $15,
     updated_at = CURRENT_TIMESTAMP
 WHERE
     user_id = $1 AND exercise_id = $2 AND log_date = $8;
 
 -- name: GetExerciseLogs :many
-SELECT el.id, el.exercise_id, e.name AS exercise_name, el.reps, el.weight, el.log_date
+SELECT el.id, el.exercise_id, e.name AS exercise_name, el.reps, el.weight, el.rpe, el.rir, el.tempo, el.rest_seconds, el.notes, el.log_date
 FROM exercise_logs el
 JOIN exercises e ON el.exercise_id = e.id
 WHERE el.user_id = $1
@@ -32,6 +44,12 @@ WHERE user_id = $1
   AND weight = $4
   AND log_date = $5;
 
+-- Exercise logs are unique per exercise and time
+-- name: GetExerciseLogID :one
+SELECT id
+FROM exercise_logs
+WHERE user_id = $1 AND exercise_id = $2 AND log_date = $3;
+
 -- name: GetExercisesWithLatestLogDate :many
 WITH latest_logs AS (
     SELECT
@@ -50,6 +68,11 @@ SELECT
     el.log_date,
     el.reps,
     el.weight,
+    el.rpe,
+    el.rir,
+    el.tempo,
+    el.rest_seconds,
+    el.notes,
     el.bodyweight_id
 FROM
     exercises e
@@ -64,4 +87,68 @@ ON
 WHERE
     el.user_id = $1;
 
+-- name: GetExerciseLogsForExport :many
+SELECT
+    el.id,
+    el.exercise_id,
+    e.name AS exercise_name,
+    el.reps,
+    el.weight,
+    el.additional_weight,
+    el.exercise_type,
+    el.duration_seconds,
+    el.distance_meters,
+    el.rpe,
+    el.rir,
+    el.tempo,
+    el.rest_seconds,
+    el.notes,
+    bw.bodyweight,
+    el.log_date,
+    el.created_at,
+    el.updated_at
+FROM exercise_logs el
+JOIN exercises e ON el.exercise_id = e.id
+JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+WHERE el.user_id = $1
+ORDER BY el.log_date;
+
+-- name: CountExerciseLogsByExercise :one
+SELECT COUNT(*)
+FROM exercise_logs
+WHERE exercise_id = $1;
 
+-- name: GetExerciseLogsForStats :many
+SELECT
+    el.reps,
+    el.weight,
+    el.additional_weight,
+    el.exercise_type,
+    el.duration_seconds,
+    el.distance_meters,
+    el.rpe,
+    bw.bodyweight,
+    el.log_date
+FROM exercise_logs el
+JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+WHERE el.user_id = @user_id
+  AND el.exercise_id = @exercise_id
+  AND (sqlc.narg(from_date)::timestamptz IS NULL OR el.log_date >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::timestamptz IS NULL OR el.log_date < sqlc.narg(to_date))
+ORDER BY el.log_date;
+
+-- name: GetExerciseLogsForProgram :many
+SELECT el.exercise_id, el.reps, el.weight, el.log_date
+FROM exercise_logs el
+WHERE el.user_id = @user_id
+  AND el.exercise_id = ANY(@exercise_ids::integer[])
+  AND el.log_date >= @from_date
+  AND el.log_date < @to_date
+ORDER BY el.log_date;
+
+-- A logged set with its exercise, to check whether it was a personal record
+-- name: GetExerciseLogWithExercise :one
+SELECT el.id, el.user_id, el.exercise_id, e.name AS exercise_name, e.measurement_kind, el.log_date
+FROM exercise_logs el
+JOIN exercises e ON el.exercise_id = e.id
+WHERE el.id = $1;
@@ -0,0 +1,98 @@
+-- Exercise catalog queries
+
+-- name: ListExercises :many
+SELECT id, name
+FROM exercises
+WHERE owner_user_id IS NULL OR owner_user_id = @user_id::integer
+ORDER BY id;
+
+-- name: SearchExercises :many
+SELECT id, name, owner_user_id, primary_muscle_groups, secondary_muscle_groups, equipment, movement_pattern, is_unilateral, default_exercise_type, measurement_kind, created_at, updated_at
+FROM exercises
+WHERE (owner_user_id IS NULL OR owner_user_id = @user_id::integer)
+  AND (sqlc.narg(search)::text IS NULL OR name ILIKE '%' || sqlc.narg(search) || '%')
+  AND (sqlc.narg(muscle_group)::text IS NULL OR sqlc.narg(muscle_group) = ANY(primary_muscle_groups) OR sqlc.narg(muscle_group) = ANY(secondary_muscle_groups))
+  AND (sqlc.narg(equipment)::equipment_type IS NULL OR equipment = sqlc.narg(equipment))
+  AND (sqlc.narg(movement_pattern)::movement_pattern IS NULL OR movement_pattern = sqlc.narg(movement_pattern))
+  AND (NOT @custom_only::boolean OR owner_user_id IS NOT NULL)
+ORDER BY owner_user_id NULLS FIRST, name;
+
+-- name: GetAccessibleExercise :one
+SELECT id, name, owner_user_id, primary_muscle_groups, secondary_muscle_groups, equipment, movement_pattern, is_unilateral, default_exercise_type, measurement_kind, created_at, updated_at
+FROM exercises
+WHERE id = @id AND (owner_user_id IS NULL OR owner_user_id = @user_id::integer);
+
+-- name: CreateCustomExercise :one
+INSERT INTO exercises (name, owner_user_id, primary_muscle_groups, secondary_muscle_groups, equipment, movement_pattern, is_unilateral, default_exercise_type, measurement_kind)
+VALUES (@name, @owner_user_id::integer, @primary_muscle_groups, @secondary_muscle_groups, @equipment, @movement_pattern, @is_unilateral, @default_exercise_type, @measurement_kind)
+RETURNING id, name, owner_user_id, primary_muscle_groups, secondary_muscle_groups, equipment, movement_pattern, is_unilateral, default_exercise_type, measurement_kind, created_at, updated_at;
+
+-- name: UpdateCustomExercise :one
+UPDATE exercises
+SET
+    name = @name,
+    primary_muscle_groups = @primary_muscle_groups,
+    secondary_muscle_groups = @secondary_muscle_groups,
+    equipment = @equipment,
+    movement_pattern = @movement_pattern,
+    is_unilateral = @is_unilateral,
+    default_exercise_type = @default_exercise_type,
+    measurement_kind = @measurement_kind,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND owner_user_id = @owner_user_id::integer
+RETURNING id, name, owner_user_id, primary_muscle_groups, secondary_muscle_groups, equipment, movement_pattern, is_unilateral, default_exercise_type, measurement_kind, created_at, updated_at;
+
+-- name: DeleteCustomExercise :execrows
+DELETE FROM exercises
+WHERE id = @id AND owner_user_id = @owner_user_id::integer;
+
+-- name: ListExerciseAliases :many
+SELECT a.exercise_id, a.alias
+FROM exercise_aliases a
+JOIN exercises e ON e.id = a.exercise_id
+WHERE e.owner_user_id IS NULL OR e.owner_user_id = @user_id::integer
+ORDER BY a.exercise_id, a.alias;
+
+-- name: ResolveExerciseName :many
+SELECT DISTINCT e.id, e.name
+FROM exercises e
+LEFT JOIN exercise_aliases a ON a.exercise_id = e.id
+WHERE (e.owner_user_id IS NULL OR e.owner_user_id = @user_id::integer)
+  AND (LOWER(e.name) = LOWER(@name::text) OR LOWER(a.alias) = LOWER(@name::text))
+ORDER BY e.id;
+
+-- The user's exercises performed as one of the named catalog lifts: every exercise whose name or
+-- alias is the lift's name or one of its aliases.
+-- name: ListLiftExercises :many
+SELECT DISTINCT e.id, l.name AS lift
+FROM exercises l
+LEFT JOIN exercise_aliases la ON la.exercise_id = l.id
+JOIN exercises e ON e.owner_user_id IS NULL OR e.owner_user_id = @user_id::integer
+LEFT JOIN exercise_aliases ea ON ea.exercise_id = e.id
+WHERE l.owner_user_id IS NULL
+  AND l.name = ANY(@names::text[])
+  AND (e.id = l.id
+       OR LOWER(e.name) IN (LOWER(l.name), LOWER(la.alias))
+       OR LOWER(ea.alias) IN (LOWER(l.name), LOWER(la.alias)))
+ORDER BY e.id;
+
+-- name: FuzzySearchExercises :many
+WITH matches AS (
+    SELECT e.id, e.name, e.name AS matched_name, similarity(e.name, @query::text) AS score
+    FROM exercises e
+    WHERE e.owner_user_id IS NULL OR e.owner_user_id = @user_id::integer
+    UNION ALL
+    SELECT e.id, e.name, a.alias AS matched_name, similarity(a.alias, @query::text) AS score
+    FROM exercise_aliases a
+    JOIN exercises e ON e.id = a.exercise_id
+    WHERE e.owner_user_id IS NULL OR e.owner_user_id = @user_id::integer
+), best_matches AS (
+    SELECT DISTINCT ON (id) id, name, matched_name, score
+    FROM matches
+    WHERE score >= @min_score::real
+    ORDER BY id, score DESC
+)
+SELECT id, name, matched_name, score
+FROM best_matches
+ORDER BY score DESC, name
+LIMIT @max_results::integer;
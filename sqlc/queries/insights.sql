@@ -0,0 +1,66 @@
+-- Insight queries
+
+-- name: ListInsights :many
+SELECT id, kind, exercise_id, exercise_log_id, details, detected_at, dismissed_at
+FROM insights
+WHERE user_id = @user_id AND (@include_dismissed::boolean OR dismissed_at IS NULL)
+ORDER BY detected_at DESC, id DESC;
+
+-- name: DismissInsight :execrows
+UPDATE insights
+SET dismissed_at = COALESCE(dismissed_at, NOW()), updated_at = NOW()
+WHERE id = $1 AND user_id = $2;
+
+-- Users with exercise logs that were never scanned, were last scanned before stale_before or
+-- changed their logs since, least recently scanned first
+-- name: ListUsersDueForInsights :many
+SELECT u.id
+FROM users u
+LEFT JOIN insight_scans s ON s.user_id = u.id
+WHERE u.deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM exercise_logs el WHERE el.user_id = u.id)
+  AND (
+      s.scanned_at IS NULL
+      OR s.scanned_at < @stale_before
+      OR EXISTS (SELECT 1 FROM exercise_logs el WHERE el.user_id = u.id AND el.updated_at > s.scanned_at)
+  )
+ORDER BY s.scanned_at NULLS FIRST, u.id
+LIMIT @max_users;
+
+-- Reps and weight sets since from_date, oldest first
+-- name: GetInsightSets :many
+SELECT
+    el.id,
+    el.exercise_id,
+    e.name AS exercise_name,
+    el.reps,
+    el.weight,
+    el.additional_weight,
+    el.exercise_type,
+    el.rpe,
+    bw.bodyweight,
+    el.log_date
+FROM exercise_logs el
+JOIN exercises e ON el.exercise_id = e.id
+JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+WHERE el.user_id = @user_id AND el.log_date >= @from_date AND e.measurement_kind = 'reps_weight'
+ORDER BY el.log_date, el.id;
+
+-- Insights found again keep their detection time and whether they were dismissed
+-- name: UpsertInsight :exec
+INSERT INTO insights (user_id, kind, dedupe_key, exercise_id, exercise_log_id, details)
+VALUES (@user_id, @kind, @dedupe_key, @exercise_id, @exercise_log_id, @details)
+ON CONFLICT (user_id, dedupe_key) DO UPDATE
+SET details = EXCLUDED.details,
+    updated_at = NOW();
+
+-- Removes the insights that were not dismissed and no longer apply
+-- name: DeleteStaleInsights :execrows
+DELETE FROM insights
+WHERE user_id = @user_id AND dismissed_at IS NULL AND dedupe_key <> ALL(@current_keys::text[]);
+
+-- name: SaveInsightScan :exec
+INSERT INTO insight_scans (user_id, scanned_at)
+VALUES ($1, NOW())
+ON CONFLICT (user_id) DO UPDATE
+SET scanned_at = EXCLUDED.scanned_at;
@@ -0,0 +1,145 @@
+-- Meet queries
+
+-- name: GetMeet :one
+SELECT id, owner_user_id, name, meet_date, scoring, status, closed_at, created_at, updated_at
+FROM meets
+WHERE id = $1;
+
+-- Meets the user organizes or is entered in
+-- name: ListMeets :many
+SELECT id, owner_user_id, name, meet_date, scoring, status, closed_at, created_at, updated_at
+FROM meets
+WHERE owner_user_id = @user_id
+   OR id IN (SELECT meet_id FROM meet_entries WHERE user_id = @user_id)
+ORDER BY meet_date DESC, id DESC;
+
+-- name: CreateMeet :one
+INSERT INTO meets (owner_user_id, name, meet_date, scoring)
+VALUES ($1, $2, $3, $4)
+RETURNING id, owner_user_id, name, meet_date, scoring, status, closed_at, created_at, updated_at;
+
+-- Closed meets are kept, their attempts are in the lifters' logs
+-- name: DeleteMeet :execrows
+DELETE FROM meets
+WHERE id = $1
+  AND owner_user_id = $2
+  AND status = 'open';
+
+-- name: CloseMeet :execrows
+UPDATE meets
+SET status = 'closed',
+    closed_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1
+  AND status = 'open';
+
+-- name: ListMeetDivisions :many
+SELECT id, meet_id, name, sex, weight_class, min_age, max_age
+FROM meet_divisions
+WHERE meet_id = $1
+ORDER BY sex, weight_class NULLS LAST, min_age NULLS FIRST, name;
+
+-- name: GetMeetDivision :one
+SELECT id, meet_id, name, sex, weight_class, min_age, max_age
+FROM meet_divisions
+WHERE id = $1
+  AND meet_id = $2;
+
+-- name: CreateMeetDivision :one
+INSERT INTO meet_divisions (meet_id, name, sex, weight_class, min_age, max_age)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, meet_id, name, sex, weight_class, min_age, max_age;
+
+-- Divisions with entries are kept
+-- name: DeleteMeetDivision :execrows
+DELETE FROM meet_divisions d
+WHERE d.id = $1
+  AND d.meet_id = $2
+  AND NOT EXISTS (SELECT 1 FROM meet_entries e WHERE e.division_id = d.id);
+
+-- name: ListMeetEntries :many
+SELECT e.id, e.meet_id, e.division_id, e.user_id, e.age, e.bodyweight, e.confirmed_at, u.username, u.name, u.sex
+FROM meet_entries e
+JOIN users u ON u.id = e.user_id
+WHERE e.meet_id = $1
+ORDER BY e.id;
+
+-- name: GetMeetEntry :one
+SELECT id, meet_id, division_id, user_id, age, bodyweight, confirmed_at, created_at, updated_at
+FROM meet_entries
+WHERE id = $1
+  AND meet_id = $2;
+
+-- Lifters entering themselves are confirmed, the others are invited until they accept
+-- name: CreateMeetEntry :one
+INSERT INTO meet_entries (meet_id, division_id, user_id, age, confirmed_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, meet_id, division_id, user_id, age, bodyweight, confirmed_at, created_at, updated_at;
+
+-- name: ConfirmMeetEntry :execrows
+UPDATE meet_entries
+SET confirmed_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND confirmed_at IS NULL;
+
+-- name: UpdateMeetEntryWeighIn :exec
+UPDATE meet_entries
+SET bodyweight = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteMeetEntry :execrows
+DELETE FROM meet_entries
+WHERE id = $1
+  AND meet_id = $2;
+
+-- name: ListMeetAttempts :many
+SELECT a.id, a.entry_id, a.lift, a.attempt, a.weight, a.result, a.exercise_log_id, a.judged_at, a.created_at, a.updated_at
+FROM meet_attempts a
+JOIN meet_entries e ON e.id = a.entry_id
+WHERE e.meet_id = $1
+ORDER BY a.entry_id, a.lift, a.attempt;
+
+-- name: GetMeetAttempt :one
+SELECT a.id, a.entry_id, a.lift, a.attempt, a.weight, a.result, a.exercise_log_id, a.judged_at, a.created_at, a.updated_at
+FROM meet_attempts a
+JOIN meet_entries e ON e.id = a.entry_id
+WHERE a.id = $1
+  AND e.meet_id = $2;
+
+-- Declares an attempt or changes its weight, no row is returned once it was judged
+-- name: DeclareMeetAttempt :one
+INSERT INTO meet_attempts (entry_id, lift, attempt, weight)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (entry_id, lift, attempt) DO UPDATE
+SET weight = EXCLUDED.weight,
+    updated_at = NOW()
+WHERE meet_attempts.result = 'pending'
+RETURNING id, entry_id, lift, attempt, weight, result, exercise_log_id, judged_at, created_at, updated_at;
+
+-- name: JudgeMeetAttempt :one
+UPDATE meet_attempts
+SET result = $2,
+    judged_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, entry_id, lift, attempt, weight, result, exercise_log_id, judged_at, created_at, updated_at;
+
+-- name: GetMeetLiftExercises :many
+SELECT id, name
+FROM exercises
+WHERE owner_user_id IS NULL
+  AND name = ANY(@names::text[]);
+
+-- name: LogMeetAttempt :one
+INSERT INTO exercise_logs (user_id, exercise_id, reps, weight, bodyweight_id, log_date, notes)
+VALUES ($1, $2, 1, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: LinkMeetAttemptLog :exec
+UPDATE meet_attempts
+SET exercise_log_id = $2,
+    updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,91 @@
+-- Notification queries
+
+-- Marks the oldest events not notified yet as notified and returns them, skipping events
+-- claimed by a concurrent run
+-- name: ClaimUnnotifiedEvents :many
+UPDATE events
+SET notified_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM events
+    WHERE notified_at IS NULL
+    ORDER BY id
+    LIMIT @max_events
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, type, payload, created_at, notified_at;
+
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, type, title, body, data)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, type, title, body, data, read_at, created_at;
+
+-- name: GetNotification :one
+SELECT id, user_id, type, title, body, data, read_at, created_at
+FROM notifications
+WHERE id = $1;
+
+-- Notifications of a user newest first, older than the cursor when one is given
+-- name: ListNotifications :many
+SELECT id, user_id, type, title, body, data, read_at, created_at
+FROM notifications
+WHERE user_id = @user_id
+    AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT @max_results;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- Marks a notification read, keeping the time it was first read
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: ListNotificationPreferences :many
+SELECT user_id, type, in_app, email, push, updated_at
+FROM notification_preferences
+WHERE user_id = $1
+ORDER BY type;
+
+-- name: GetNotificationPreference :one
+SELECT user_id, type, in_app, email, push, updated_at
+FROM notification_preferences
+WHERE user_id = $1 AND type = $2;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, type, in_app, email, push)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, type) DO UPDATE
+SET in_app = EXCLUDED.in_app,
+    email = EXCLUDED.email,
+    push = EXCLUDED.push,
+    updated_at = NOW();
+
+-- A browser subscribing again replaces its subscription, even when another user subscribed it
+-- name: UpsertPushSubscription :one
+INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (endpoint) DO UPDATE
+SET user_id = EXCLUDED.user_id,
+    p256dh = EXCLUDED.p256dh,
+    auth = EXCLUDED.auth
+RETURNING id, user_id, endpoint, p256dh, auth, created_at;
+
+-- name: ListPushSubscriptions :many
+SELECT id, user_id, endpoint, p256dh, auth, created_at
+FROM push_subscriptions
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: DeletePushSubscription :execrows
+DELETE FROM push_subscriptions
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,147 @@
+-- Organization queries
+
+-- name: GetPrivacySettings :one
+SELECT share_training_with_coaches, share_body_with_coaches
+FROM privacy_settings
+WHERE user_id = $1;
+
+-- name: UpsertPrivacySettings :exec
+INSERT INTO privacy_settings (user_id, share_training_with_coaches, share_body_with_coaches)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET share_training_with_coaches = EXCLUDED.share_training_with_coaches,
+    share_body_with_coaches = EXCLUDED.share_body_with_coaches,
+    updated_at = NOW();
+
+-- name: CreateOrganization :one
+INSERT INTO organizations (name, description)
+VALUES ($1, $2)
+RETURNING id, name, description, created_at, updated_at;
+
+-- name: GetOrganization :one
+SELECT id, name, description, created_at, updated_at
+FROM organizations
+WHERE id = $1;
+
+-- name: UpdateOrganization :one
+UPDATE organizations
+SET name = $2, description = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, description, created_at, updated_at;
+
+-- name: DeleteOrganization :exec
+DELETE FROM organizations
+WHERE id = $1;
+
+-- Organizations the user is a member of with their role and the number of members
+-- name: ListUserOrganizations :many
+SELECT
+    o.id,
+    o.name,
+    o.description,
+    om.role,
+    (SELECT COUNT(*) FROM organization_members m WHERE m.organization_id = o.id) AS member_count,
+    o.created_at
+FROM organization_members om
+JOIN organizations o ON om.organization_id = o.id
+WHERE om.user_id = $1
+ORDER BY LOWER(o.name), o.id;
+
+-- Adds a member unless they already are one
+-- name: AddOrganizationMember :execrows
+INSERT INTO organization_members (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, user_id) DO NOTHING;
+
+-- name: GetOrganizationMember :one
+SELECT organization_id, user_id, role, created_at, updated_at
+FROM organization_members
+WHERE organization_id = $1 AND user_id = $2;
+
+-- Members with the owners first, then the coaches and the athletes
+-- name: ListOrganizationMembers :many
+SELECT om.user_id, u.username, u.name, u.avatar_url, om.role, om.created_at AS joined_at
+FROM organization_members om
+JOIN users u ON om.user_id = u.id
+WHERE om.organization_id = $1 AND u.deleted_at IS NULL
+ORDER BY om.role, LOWER(u.username);
+
+-- The owner's role cannot be changed
+-- name: UpdateOrganizationMemberRole :execrows
+UPDATE organization_members
+SET role = $3, updated_at = NOW()
+WHERE organization_id = $1 AND user_id = $2 AND role <> 'owner';
+
+-- The owner cannot leave, they delete the organization instead
+-- name: RemoveOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE organization_id = $1 AND user_id = $2 AND role <> 'owner';
+
+-- name: CreateOrganizationInvite :one
+INSERT INTO organization_invites (organization_id, code, role, created_by, expires_at, max_uses)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, organization_id, code, role, created_by, expires_at, max_uses, uses, created_at;
+
+-- name: GetOrganizationInviteByCode :one
+SELECT i.id, i.organization_id, o.name AS organization_name, i.code, i.role, i.expires_at, i.max_uses, i.uses
+FROM organization_invites i
+JOIN organizations o ON i.organization_id = o.id
+WHERE i.code = $1;
+
+-- Invites that have not expired, latest first
+-- name: ListOrganizationInvites :many
+SELECT id, organization_id, code, role, created_by, expires_at, max_uses, uses, created_at
+FROM organization_invites
+WHERE organization_id = $1 AND expires_at > NOW()
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteOrganizationInvite :execrows
+DELETE FROM organization_invites
+WHERE id = $1 AND organization_id = $2;
+
+-- Counts a use of an invite unless it expired or was used up in the meantime
+-- name: UseOrganizationInvite :execrows
+UPDATE organization_invites
+SET uses = uses + 1
+WHERE id = $1 AND expires_at > NOW() AND (max_uses IS NULL OR uses < max_uses);
+
+-- name: CreateCoachAssignment :one
+INSERT INTO coach_assignments (organization_id, coach_user_id, athlete_user_id, template_id, program_id, enrollment_id, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, organization_id, coach_user_id, athlete_user_id, template_id, program_id, enrollment_id, note, created_at;
+
+-- Assignments in an organization, only those of one athlete when athlete_user_id is set, latest first
+-- name: ListCoachAssignments :many
+SELECT
+    ca.id,
+    ca.athlete_user_id,
+    athlete.username AS athlete_username,
+    ca.coach_user_id,
+    coach.username AS coach_username,
+    ca.template_id,
+    wt.name AS template_name,
+    ca.program_id,
+    tp.name AS program_name,
+    ca.enrollment_id,
+    ca.note,
+    ca.created_at
+FROM coach_assignments ca
+JOIN users athlete ON ca.athlete_user_id = athlete.id
+LEFT JOIN users coach ON ca.coach_user_id = coach.id
+LEFT JOIN workout_templates wt ON ca.template_id = wt.id
+LEFT JOIN training_programs tp ON ca.program_id = tp.id
+WHERE ca.organization_id = @organization_id
+    AND (sqlc.narg(athlete_user_id)::integer IS NULL OR ca.athlete_user_id = sqlc.narg(athlete_user_id))
+ORDER BY ca.created_at DESC, ca.id DESC;
+
+-- Whether a user is the owner or a coach of an organization the athlete is an athlete in
+-- name: IsCoachOf :one
+SELECT EXISTS (
+    SELECT 1
+    FROM organization_members coach
+    JOIN organization_members athlete ON coach.organization_id = athlete.organization_id
+    WHERE coach.user_id = @coach_user_id
+        AND coach.role IN ('owner', 'coach')
+        AND athlete.user_id = @athlete_user_id
+        AND athlete.role = 'athlete'
+)::boolean AS coaches;
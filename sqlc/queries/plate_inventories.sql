@@ -0,0 +1,21 @@
+-- Plate inventory queries
+
+-- name: GetPlateInventory :one
+SELECT user_id, unit, bar_weight, collar_weight, plates, created_at, updated_at
+FROM plate_inventories
+WHERE user_id = $1;
+
+-- name: UpsertPlateInventory :one
+INSERT INTO plate_inventories (user_id, unit, bar_weight, collar_weight, plates)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET unit = EXCLUDED.unit,
+    bar_weight = EXCLUDED.bar_weight,
+    collar_weight = EXCLUDED.collar_weight,
+    plates = EXCLUDED.plates,
+    updated_at = NOW()
+RETURNING user_id, unit, bar_weight, collar_weight, plates, created_at, updated_at;
+
+-- name: DeletePlateInventory :execrows
+DELETE FROM plate_inventories
+WHERE user_id = $1;
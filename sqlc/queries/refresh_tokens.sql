@@ -19,7 +19,13 @@ ON CONFLICT (user_id) DO UPDATE
 SET token = EXCLUDED.token, expires_at = EXCLUDED.expires_at, created_at = CURRENT_TIMESTAMP;
 
 -- name: ValidateRefreshToken :one
-SELECT u.id, u.username, u.email, u.avatar_url, u.name
+SELECT u.id, u.username, u.email, u.avatar_url, u.name, u.deleted_at
 FROM users u
 JOIN refresh_tokens rt ON u.id = rt.user_id
 WHERE rt.token = $1 AND rt.expires_at > CURRENT_TIMESTAMP;
+
+-- name: GetRefreshTokensByUserID :many
+SELECT id, expires_at, created_at
+FROM refresh_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC;
@@ -0,0 +1,63 @@
+-- Training calendar and streak queries
+
+-- name: GetStreakTarget :one
+SELECT target_days_per_week
+FROM streak_settings
+WHERE user_id = $1;
+
+-- name: UpsertStreakTarget :exec
+INSERT INTO streak_settings (user_id, target_days_per_week)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET target_days_per_week = EXCLUDED.target_days_per_week,
+    updated_at = NOW();
+
+-- Activity of every day with completed workouts or logged sets between from_date and to_date.
+-- Tonnage counts reps and weight sets like GetTrainingVolume.
+-- name: GetTrainingCalendar :many
+WITH sets AS (
+    SELECT
+        (el.log_date AT TIME ZONE 'UTC')::date AS day,
+        COUNT(*) AS sets,
+        SUM(el.reps * CASE el.exercise_type
+            WHEN 'Weighted' THEN bw.bodyweight + COALESCE(el.additional_weight, 0)
+            WHEN 'Assisted' THEN bw.bodyweight - COALESCE(el.additional_weight, 0)
+            WHEN 'Bodyweight' THEN bw.bodyweight
+            ELSE el.weight
+        END) FILTER (WHERE e.measurement_kind = 'reps_weight') AS tonnage
+    FROM exercise_logs el
+    JOIN exercises e ON el.exercise_id = e.id
+    JOIN bodyweight_logs bw ON el.bodyweight_id = bw.id
+    WHERE el.user_id = @user_id AND el.log_date >= @from_date AND el.log_date < @to_date
+    GROUP BY 1
+),
+sessions AS (
+    SELECT (started_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS sessions
+    FROM workout_sessions
+    WHERE user_id = @user_id AND status = 'completed' AND started_at >= @from_date AND started_at < @to_date
+    GROUP BY 1
+)
+SELECT
+    COALESCE(s.day, ws.day)::date AS day,
+    COALESCE(ws.sessions, 0)::bigint AS sessions,
+    COALESCE(s.sets, 0)::bigint AS sets,
+    COALESCE(s.tonnage, 0)::numeric AS tonnage
+FROM sets s
+FULL JOIN sessions ws ON ws.day = s.day
+ORDER BY 1;
+
+-- Days trained in every week with training, weeks starting on Monday
+-- name: GetWeeklyTrainingDays :many
+WITH days AS (
+    SELECT (log_date AT TIME ZONE 'UTC')::date AS day
+    FROM exercise_logs
+    WHERE user_id = @user_id
+    UNION
+    SELECT (started_at AT TIME ZONE 'UTC')::date AS day
+    FROM workout_sessions
+    WHERE user_id = @user_id AND status = 'completed'
+)
+SELECT date_trunc('week', day::timestamp)::timestamp AS week_start, COUNT(*)::bigint AS training_days
+FROM days
+GROUP BY 1
+ORDER BY 1;
@@ -0,0 +1,50 @@
+-- Strength goal queries
+
+-- name: ListStrengthGoals :many
+SELECT id, user_id, exercise_id, metric, target, deadline, start_value, achieved_at, created_at, updated_at
+FROM strength_goals
+WHERE user_id = $1
+ORDER BY achieved_at IS NOT NULL, deadline, id;
+
+-- Goals not reached yet
+-- name: ListOpenStrengthGoals :many
+SELECT id, user_id, exercise_id, metric, target, deadline, start_value, achieved_at, created_at, updated_at
+FROM strength_goals
+WHERE user_id = $1
+  AND achieved_at IS NULL
+ORDER BY id;
+
+-- name: GetStrengthGoal :one
+SELECT id, user_id, exercise_id, metric, target, deadline, start_value, achieved_at, created_at, updated_at
+FROM strength_goals
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: CreateStrengthGoal :one
+INSERT INTO strength_goals (user_id, exercise_id, metric, target, deadline, start_value)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, exercise_id, metric, target, deadline, start_value, achieved_at, created_at, updated_at;
+
+-- Changing the target of a reached goal opens it again
+-- name: UpdateStrengthGoal :one
+UPDATE strength_goals
+SET target = @target,
+    deadline = @deadline,
+    achieved_at = CASE WHEN target = @target THEN achieved_at END,
+    updated_at = NOW()
+WHERE id = @id
+  AND user_id = @user_id
+RETURNING id, user_id, exercise_id, metric, target, deadline, start_value, achieved_at, created_at, updated_at;
+
+-- name: DeleteStrengthGoal :execrows
+DELETE FROM strength_goals
+WHERE id = $1
+  AND user_id = $2;
+
+-- Only the first check to see a goal reached marks it
+-- name: MarkStrengthGoalAchieved :execrows
+UPDATE strength_goals
+SET achieved_at = $2,
+    updated_at = NOW()
+WHERE id = $1
+  AND achieved_at IS NULL;
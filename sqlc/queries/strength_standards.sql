@@ -0,0 +1,93 @@
+-- Strength standard queries
+
+-- name: ListCurrentStrengthStandards :many
+SELECT sv.name AS version, ss.exercise_id, ss.bodyweight_class, ss.beginner, ss.novice, ss.intermediate, ss.advanced, ss.elite
+FROM strength_standards ss
+JOIN strength_standard_versions sv ON sv.id = ss.version_id
+WHERE ss.version_id = (
+    SELECT id
+    FROM strength_standard_versions
+    WHERE published_at <= CURRENT_DATE
+    ORDER BY published_at DESC, id DESC
+    LIMIT 1
+)
+  AND ss.sex = @sex
+ORDER BY ss.exercise_id, ss.bodyweight_class;
+
+-- Replaced by RefreshStrengthStandings in the same transaction
+-- name: DeleteStrengthStandings :exec
+DELETE FROM strength_standings;
+
+-- Each user's best set of every exercise with current standards, the one with the highest estimated
+-- one rep max relative to the bodyweight logged with it, ranked against the users of the same sex.
+-- The estimate matches strength.EstimatedOneRepMaxAtRPE and only counts sets of up to 12 reps.
+-- Sets flagged as suspicious entries are left out, dismissed or not.
+-- name: RefreshStrengthStandings :execrows
+INSERT INTO strength_standings (user_id, exercise_id, one_rep_max, bodyweight, log_date, percentile, population)
+WITH lifts AS (
+    SELECT DISTINCT exercise_id
+    FROM strength_standards
+    WHERE version_id = (
+        SELECT id
+        FROM strength_standard_versions
+        WHERE published_at <= CURRENT_DATE
+        ORDER BY published_at DESC, id DESC
+        LIMIT 1
+    )
+),
+estimates AS (
+    SELECT
+        el.user_id,
+        el.exercise_id,
+        u.sex,
+        el.log_date,
+        bw.bodyweight,
+        CASE
+            WHEN el.reps + COALESCE(10 - el.rpe, 0) <= 1 THEN el.weight
+            ELSE el.weight * (1 + (el.reps + COALESCE(10 - el.rpe, 0)) / 30.0)
+        END AS one_rep_max
+    FROM exercise_logs el
+    JOIN users u ON u.id = el.user_id
+    JOIN bodyweight_logs bw ON bw.id = el.bodyweight_id
+    WHERE el.exercise_id IN (SELECT exercise_id FROM lifts)
+      AND el.exercise_type IS NULL
+      AND el.reps > 0
+      AND el.reps + COALESCE(10 - el.rpe, 0) <= 12
+      AND bw.bodyweight > 0
+      AND u.sex IS NOT NULL
+      AND u.deleted_at IS NULL
+      AND NOT EXISTS (
+          SELECT 1
+          FROM insights i
+          WHERE i.exercise_log_id = el.id AND i.kind = 'suspicious_entry'
+      )
+),
+best AS (
+    SELECT DISTINCT ON (user_id, exercise_id) user_id, exercise_id, sex, log_date, bodyweight, one_rep_max
+    FROM estimates
+    ORDER BY user_id, exercise_id, one_rep_max / bodyweight DESC
+),
+ranked AS (
+    SELECT
+        best.*,
+        (PERCENT_RANK() OVER (PARTITION BY exercise_id, sex ORDER BY one_rep_max / bodyweight) * 100)::float8 AS percentile,
+        COUNT(*) OVER (PARTITION BY exercise_id, sex) AS population
+    FROM best
+)
+SELECT user_id, exercise_id, one_rep_max::DECIMAL(10, 2), bodyweight, log_date, percentile, population
+FROM ranked;
+
+-- The user's standings as of the last refresh, see RefreshStrengthStandings
+-- name: GetStrengthStandings :many
+SELECT
+    s.exercise_id,
+    e.name AS exercise_name,
+    s.one_rep_max,
+    s.bodyweight,
+    s.log_date,
+    s.percentile,
+    s.population
+FROM strength_standings s
+JOIN exercises e ON e.id = s.exercise_id
+WHERE s.user_id = $1
+ORDER BY e.name;
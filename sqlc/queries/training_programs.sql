@@ -0,0 +1,48 @@
+-- Training program queries
+
+-- name: ListTrainingPrograms :many
+SELECT id, owner_user_id, name, description, definition, created_at, updated_at
+FROM training_programs
+WHERE owner_user_id IS NULL OR owner_user_id = @user_id::integer
+ORDER BY owner_user_id NULLS FIRST, LOWER(name);
+
+-- name: GetAccessibleTrainingProgram :one
+SELECT id, owner_user_id, name, description, definition, created_at, updated_at
+FROM training_programs
+WHERE id = @id AND (owner_user_id IS NULL OR owner_user_id = @user_id::integer);
+
+-- name: CreateTrainingProgram :one
+INSERT INTO training_programs (owner_user_id, name, description, definition)
+VALUES (@owner_user_id::integer, @name, @description, @definition)
+RETURNING id, owner_user_id, name, description, definition, created_at, updated_at;
+
+-- name: DeleteTrainingProgram :execrows
+DELETE FROM training_programs
+WHERE id = @id AND owner_user_id = @owner_user_id::integer;
+
+-- name: CreateProgramEnrollment :one
+INSERT INTO program_enrollments (user_id, program_id, state)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, program_id, status, state, day_started_at, created_at, updated_at;
+
+-- name: GetProgramEnrollment :one
+SELECT id, user_id, program_id, status, state, day_started_at, created_at, updated_at
+FROM program_enrollments
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListProgramEnrollments :many
+SELECT pe.id, pe.program_id, tp.name AS program_name, pe.status, pe.state, pe.day_started_at, pe.created_at
+FROM program_enrollments pe
+JOIN training_programs tp ON pe.program_id = tp.id
+WHERE pe.user_id = $1
+ORDER BY pe.created_at DESC;
+
+-- name: UpdateProgramEnrollmentState :exec
+UPDATE program_enrollments
+SET state = $2, day_started_at = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: StopProgramEnrollment :execrows
+UPDATE program_enrollments
+SET status = 'stopped', updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND status = 'active';
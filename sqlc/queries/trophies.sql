@@ -26,3 +26,8 @@ WHERE user_id = $1 AND trophy_id = $2;
 -- name: DeleteUserTrophyByOrder :exec
 DELETE FROM user_trophies
 WHERE user_id = $1 AND display_order = $2;
+
+-- name: ListTrophies :many
+SELECT id, name, description
+FROM trophies
+ORDER BY id;
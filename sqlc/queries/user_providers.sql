@@ -12,3 +12,9 @@ INSERT INTO user_providers (
     avatar_url = EXCLUDED.avatar_url,
     location = EXCLUDED.location,
     updated_at = EXCLUDED.updated_at;
+
+-- name: GetUserProvidersByUserID :many
+SELECT id, user_id, provider, provider_user_id, first_name, last_name, nickname, avatar_url, location, created_at, updated_at
+FROM user_providers
+WHERE user_id = $1
+ORDER BY created_at;
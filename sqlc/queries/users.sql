@@ -8,6 +8,12 @@ SELECT id, username, name, email, avatar_url, sex, preferred_units, country_code
 FROM users
 WHERE username = $1;
 
+-- Accounts pending deletion are left out
+-- name: GetActiveUserByUsername :one
+SELECT id, username
+FROM users
+WHERE username = $1 AND deleted_at IS NULL;
+
 -- name: UpdateUsername :exec
 UPDATE users SET username = $1, updated_at = NOW() WHERE id = $2;
 
@@ -23,7 +29,7 @@ SET username = $2,
 WHERE id = $1;
 
 -- name: GetUserByEmail :one
-SELECT id, username, name, email, avatar_url, sex, preferred_units, country_code, created_at, updated_at
+SELECT id, username, name, email, avatar_url, sex, preferred_units, country_code, created_at, updated_at, deleted_at, purge_after
 FROM users
 WHERE email = $1;
 
@@ -61,6 +67,13 @@ SELECT
         'exercise_name', e.name,
         'reps', el.reps,
         'weight', el.weight,
+        'rpe', el.rpe,
+        'rir', el.rir,
+        'tempo', el.tempo,
+        'rest_seconds', el.rest_seconds,
+        'notes', el.notes,
+        'duration_seconds', el.duration_seconds,
+        'distance_meters', el.distance_meters,
         'log_date', el.log_date
     )) FILTER (WHERE el.id IS NOT NULL), '[]') as exercise_logs
 FROM
@@ -100,6 +113,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -147,6 +167,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -184,7 +211,7 @@ SELECT
         ) bw
     ) AS latest_bodyweight
 FROM users u
-WHERE u.username = $1;
+WHERE u.username = $1 AND u.deleted_at IS NULL;
 
 -- name: GetUserProfileByID :one
 SELECT
@@ -207,6 +234,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -254,6 +288,13 @@ SELECT
                      el.weight,
                      el.additional_weight,
                      el.exercise_type,
+                     el.rpe,
+                     el.rir,
+                     el.tempo,
+                     el.rest_seconds,
+                     el.notes,
+                     el.duration_seconds,
+                     el.distance_meters,
                      el.log_date,
                      bw.bodyweight
                  FROM exercise_logs el
@@ -302,7 +343,35 @@ WHERE id = $1;
 SELECT username, name, avatar_url, sex, country_code
 FROM users
 WHERE
-    username ILIKE '%' || $1 || '%'
-   OR name ILIKE '%' || $1 || '%'
+    deleted_at IS NULL
+    AND (username ILIKE '%' || $1 || '%'
+        OR name ILIKE '%' || $1 || '%')
 ORDER BY username
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+LIMIT $2 OFFSET $3;
+
+-- name: GetUserAccount :one
+SELECT id, username, email, name, sex, preferred_units, country_code, avatar_url, bio, created_at, updated_at, deleted_at, purge_after
+FROM users
+WHERE id = $1;
+
+-- name: MarkUserForDeletion :exec
+UPDATE users
+SET deleted_at = NOW(), purge_after = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = NULL, purge_after = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: GetUserDeletionStatus :one
+SELECT deleted_at, purge_after
+FROM users
+WHERE id = $1;
+
+-- name: ListUsersDueForPurge :many
+SELECT id
+FROM users
+WHERE deleted_at IS NOT NULL AND purge_after <= NOW()
+ORDER BY purge_after
+LIMIT $1;
\ No newline at end of file
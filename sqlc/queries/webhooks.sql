@@ -0,0 +1,105 @@
+-- Webhook queries
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (user_id, organization_id, url, secret, event_types, description)
+VALUES (sqlc.narg(user_id), sqlc.narg(organization_id), @url, @secret, @event_types, @description)
+RETURNING id, user_id, organization_id, url, secret, event_types, description, active, created_at, updated_at;
+
+-- name: GetWebhook :one
+SELECT id, user_id, organization_id, url, secret, event_types, description, active, created_at, updated_at
+FROM webhooks
+WHERE id = $1;
+
+-- Webhooks of a user, or of an organization
+-- name: ListWebhooks :many
+SELECT id, user_id, organization_id, url, secret, event_types, description, active, created_at, updated_at
+FROM webhooks
+WHERE user_id = sqlc.narg(user_id) OR organization_id = sqlc.narg(organization_id)
+ORDER BY id;
+
+-- name: CountWebhooks :one
+SELECT COUNT(*)
+FROM webhooks
+WHERE user_id = sqlc.narg(user_id) OR organization_id = sqlc.narg(organization_id);
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = @url, event_types = @event_types, description = @description, active = @active, updated_at = NOW()
+WHERE id = @id
+RETURNING id, user_id, organization_id, url, secret, event_types, description, active, created_at, updated_at;
+
+-- name: RotateWebhookSecret :one
+UPDATE webhooks
+SET secret = @secret, updated_at = NOW()
+WHERE id = @id
+RETURNING id, user_id, organization_id, url, secret, event_types, description, active, created_at, updated_at;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- Queues an event for the active webhooks subscribed to its type: the user's, and those of the
+-- organizations the user is an athlete in and shares the data of the event with, as on the
+-- coach dashboards
+-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_id, user_id, event_type, payload)
+SELECT w.id, e.id, e.user_id, e.type, e.payload
+FROM events e
+JOIN webhooks w ON w.active AND e.type = ANY (w.event_types)
+WHERE e.id = $1
+    AND (w.user_id = e.user_id OR w.organization_id IN (
+        SELECT m.organization_id
+        FROM organization_members m
+        LEFT JOIN privacy_settings p ON p.user_id = m.user_id
+        WHERE m.user_id = e.user_id AND m.role = 'athlete'
+            AND CASE WHEN e.type = 'bodyweight.logged' THEN COALESCE(p.share_body_with_coaches, FALSE)
+                ELSE COALESCE(p.share_training_with_coaches, TRUE) END
+    ));
+
+-- Returns the oldest due deliveries of active webhooks and postpones them by the lease, so
+-- deliveries claimed by a run that dies are retried once it runs out. Deliveries claimed by a
+-- concurrent run are skipped.
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET next_attempt_at = NOW() + INTERVAL '10 minutes'
+FROM webhooks w
+WHERE w.id = d.webhook_id
+    AND d.id IN (
+        SELECT due.id
+        FROM webhook_deliveries due
+        JOIN webhooks active ON active.id = due.webhook_id AND active.active
+        WHERE due.status = 'pending' AND due.next_attempt_at <= NOW()
+        ORDER BY due.next_attempt_at
+        LIMIT @max_deliveries
+        FOR UPDATE OF due SKIP LOCKED
+    )
+RETURNING d.id, d.webhook_id, d.event_id, d.user_id, d.event_type, d.payload, d.attempts, d.created_at, w.url, w.secret;
+
+-- Records an attempt at a delivery, next_attempt_at is kept when not given
+-- name: RecordWebhookAttempt :exec
+UPDATE webhook_deliveries
+SET status = @status,
+    attempts = attempts + 1,
+    last_attempt_at = NOW(),
+    response_status = sqlc.narg(response_status),
+    last_error = sqlc.narg(last_error),
+    next_attempt_at = COALESCE(sqlc.narg(next_attempt_at), next_attempt_at),
+    delivered_at = CASE WHEN @status = 'delivered' THEN NOW() END
+WHERE id = @id;
+
+-- Deliveries of a webhook newest first, older than the cursor when one is given
+-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event_id, user_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, response_status, last_error, delivered_at, created_at
+FROM webhook_deliveries
+WHERE webhook_id = @webhook_id
+    AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT @max_results;
+
+-- Queues a delivery again as a new delivery of the same event, due now
+-- name: RedeliverWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event_id, user_id, event_type, payload)
+SELECT webhook_id, event_id, user_id, event_type, payload
+FROM webhook_deliveries
+WHERE id = @id AND webhook_id = @webhook_id
+RETURNING id, webhook_id, event_id, user_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, response_status, last_error, delivered_at, created_at;
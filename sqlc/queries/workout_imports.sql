@@ -0,0 +1,21 @@
+-- Workout import queries
+
+-- name: CreateWorkoutImport :one
+INSERT INTO workout_imports (user_id, source, parsed_rows, parse_errors, exercise_mappings)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, source, status, parsed_rows, parse_errors, exercise_mappings, report, committed_at, created_at, updated_at;
+
+-- name: GetWorkoutImport :one
+SELECT id, user_id, source, status, parsed_rows, parse_errors, exercise_mappings, report, committed_at, created_at, updated_at
+FROM workout_imports
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateWorkoutImportMappings :exec
+UPDATE workout_imports
+SET exercise_mappings = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND status = 'preview';
+
+-- name: CommitWorkoutImport :execrows
+UPDATE workout_imports
+SET status = 'committed', report = $3, committed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND status = 'preview';
@@ -0,0 +1,68 @@
+-- Workout session queries
+
+-- name: CreateWorkoutSession :one
+INSERT INTO workout_sessions (user_id, template_id, name, started_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, template_id, name, status, started_at, completed_at, created_at, updated_at;
+
+-- name: GetWorkoutSession :one
+SELECT id, user_id, template_id, name, status, started_at, completed_at, created_at, updated_at
+FROM workout_sessions
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListWorkoutSessions :many
+SELECT id, user_id, template_id, name, status, started_at, completed_at, created_at, updated_at
+FROM workout_sessions
+WHERE user_id = $1
+ORDER BY started_at DESC
+LIMIT $2;
+
+-- name: CompleteWorkoutSession :execrows
+UPDATE workout_sessions
+SET status = 'completed', completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND status = 'in_progress';
+
+-- name: DeleteWorkoutSession :execrows
+DELETE FROM workout_sessions
+WHERE id = $1 AND user_id = $2 AND status = 'in_progress';
+
+-- name: AddWorkoutSessionSet :exec
+INSERT INTO workout_session_sets (session_id, exercise_id, position, set_number, target_reps, target_weight, rest_seconds)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListWorkoutSessionSets :many
+SELECT
+    ss.id,
+    ss.exercise_id,
+    e.name AS exercise_name,
+    e.default_exercise_type,
+    ss.position,
+    ss.set_number,
+    ss.target_reps,
+    ss.target_weight,
+    ss.rest_seconds,
+    ss.reps,
+    ss.weight,
+    ss.rpe,
+    ss.completed_at,
+    ss.exercise_log_id
+FROM workout_session_sets ss
+JOIN exercises e ON ss.exercise_id = e.id
+WHERE ss.session_id = $1
+ORDER BY ss.position, ss.set_number;
+
+-- name: UpdateWorkoutSessionSet :execrows
+UPDATE workout_session_sets
+SET reps = $3, weight = $4, rpe = $5, completed_at = $6
+WHERE id = $1 AND session_id = $2;
+
+-- name: LinkWorkoutSessionSetLog :exec
+UPDATE workout_session_sets
+SET exercise_log_id = $2
+WHERE id = $1;
+
+-- Owner of a workout, for comments and reactions
+-- name: GetWorkoutSessionOwner :one
+SELECT user_id
+FROM workout_sessions
+WHERE id = $1;
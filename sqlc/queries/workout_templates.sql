@@ -0,0 +1,63 @@
+-- Workout template queries
+
+-- name: CreateWorkoutTemplate :one
+INSERT INTO workout_templates (user_id, name, description, cloned_from_id)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, name, description, share_code, cloned_from_id, created_at, updated_at;
+
+-- name: UpdateWorkoutTemplate :one
+UPDATE workout_templates
+SET name = $3, description = $4, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, name, description, share_code, cloned_from_id, created_at, updated_at;
+
+-- name: GetWorkoutTemplate :one
+SELECT id, user_id, name, description, share_code, cloned_from_id, created_at, updated_at
+FROM workout_templates
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetSharedWorkoutTemplate :one
+SELECT id, user_id, name, description, share_code, cloned_from_id, created_at, updated_at
+FROM workout_templates
+WHERE share_code = $1;
+
+-- name: ListWorkoutTemplates :many
+SELECT id, user_id, name, description, share_code, cloned_from_id, created_at, updated_at
+FROM workout_templates
+WHERE user_id = $1
+ORDER BY LOWER(name), id;
+
+-- name: DeleteWorkoutTemplate :execrows
+DELETE FROM workout_templates
+WHERE id = $1 AND user_id = $2;
+
+-- name: SetWorkoutTemplateShareCode :one
+UPDATE workout_templates
+SET share_code = sqlc.narg(share_code), updated_at = NOW()
+WHERE id = @id AND user_id = @user_id
+RETURNING id, user_id, name, description, share_code, cloned_from_id, created_at, updated_at;
+
+-- name: AddWorkoutTemplateExercise :exec
+INSERT INTO workout_template_exercises (template_id, position, exercise_id, target_sets, target_reps, target_weight, target_percentage, rest_seconds, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: DeleteWorkoutTemplateExercises :exec
+DELETE FROM workout_template_exercises
+WHERE template_id = $1;
+
+-- name: ListWorkoutTemplateExercises :many
+SELECT
+    te.id,
+    te.position,
+    te.exercise_id,
+    e.name AS exercise_name,
+    te.target_sets,
+    te.target_reps,
+    te.target_weight,
+    te.target_percentage,
+    te.rest_seconds,
+    te.notes
+FROM workout_template_exercises te
+JOIN exercises e ON te.exercise_id = e.id
+WHERE te.template_id = $1
+ORDER BY te.position;
@@ -1,10 +1,49 @@
 -- sqlc/schema/schema.sql
 CREATE EXTENSION IF NOT EXISTS citext;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
 
 CREATE TYPE unit_system AS ENUM ('metric', 'imperial');
 
 CREATE TYPE exercise_type AS ENUM ('Bodyweight', 'Weighted', 'Assisted');
 
+CREATE TYPE data_export_status AS ENUM ('pending', 'processing', 'completed', 'failed');
+
+CREATE TYPE import_source AS ENUM ('strong', 'hevy', 'generic');
+
+CREATE TYPE import_status AS ENUM ('preview', 'committed');
+
+CREATE TYPE equipment_type AS ENUM ('barbell', 'dumbbell', 'kettlebell', 'machine', 'cable', 'bodyweight', 'band', 'other');
+
+CREATE TYPE movement_pattern AS ENUM ('squat', 'hinge', 'lunge', 'horizontal_push', 'vertical_push', 'horizontal_pull', 'vertical_pull', 'carry', 'core', 'isolation', 'olympic');
+
+CREATE TYPE measurement_kind AS ENUM ('reps_weight', 'time', 'distance', 'distance_weight', 'time_weight');
+
+CREATE TYPE workout_session_status AS ENUM ('in_progress', 'completed');
+
+CREATE TYPE program_enrollment_status AS ENUM ('active', 'stopped');
+
+CREATE TYPE body_measurement_kind AS ENUM ('neck', 'shoulders', 'chest', 'waist', 'hips', 'left_arm', 'right_arm', 'left_forearm', 'right_forearm', 'left_thigh', 'right_thigh', 'left_calf', 'right_calf', 'body_fat');
+
+CREATE TYPE measurement_source AS ENUM ('tape', 'calipers', 'smart_scale', 'dexa', 'other');
+
+CREATE TYPE insight_kind AS ENUM ('plateau', 'regression', 'fast_gain', 'suspicious_entry');
+
+CREATE TYPE goal_metric AS ENUM ('estimated_1rm', 'heaviest_weight', 'most_reps');
+
+CREATE TYPE meet_scoring AS ENUM ('dots', 'ipf_gl');
+CREATE TYPE meet_status AS ENUM ('open', 'closed');
+CREATE TYPE meet_lift AS ENUM ('squat', 'bench', 'deadlift');
+CREATE TYPE attempt_result AS ENUM ('pending', 'good', 'no_lift');
+
+CREATE TYPE challenge_metric AS ENUM ('total_reps', 'tonnage', 'sessions', 'best_e1rm');
+CREATE TYPE challenge_participant_status AS ENUM ('invited', 'joined');
+
+CREATE TYPE organization_role AS ENUM ('owner', 'coach', 'athlete');
+
+CREATE TYPE reaction_kind AS ENUM ('like', 'fire', 'strong', 'clap');
+
+CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'delivered', 'failed');
+
 CREATE TABLE users (
     id SERIAL PRIMARY KEY,
     username CITEXT UNIQUE NOT NULL CHECK (
@@ -21,7 +60,9 @@ CREATE TABLE users (
     avatar_url VARCHAR(255),
     bio TEXT CHECK (LENGTH(bio) <= 160), -- Limit bio to 160 characters
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ, -- Set while the account is pending deletion
+    purge_after TIMESTAMPTZ -- The account is hard deleted once this has passed
 );
 
 CREATE TABLE user_providers (
@@ -65,9 +106,36 @@ CREATE TABLE refresh_tokens (
 
 CREATE TABLE exercises (
     id SERIAL PRIMARY KEY,
-    name VARCHAR(100) NOT NULL
+    name VARCHAR(100) NOT NULL,
+    owner_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- NULL for the global catalog, set for private custom exercises
+    primary_muscle_groups TEXT[] NOT NULL DEFAULT '{}',
+    secondary_muscle_groups TEXT[] NOT NULL DEFAULT '{}',
+    equipment equipment_type,
+    movement_pattern movement_pattern,
+    is_unilateral BOOLEAN NOT NULL DEFAULT FALSE,
+    default_exercise_type exercise_type, -- Used for logs that do not specify an exercise type
+    measurement_kind measurement_kind NOT NULL DEFAULT 'reps_weight', -- Which fields a log of this exercise records
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Catalog names are unique, custom exercise names are unique per user
+CREATE UNIQUE INDEX exercises_catalog_name_idx ON exercises (LOWER(name)) WHERE owner_user_id IS NULL;
+CREATE UNIQUE INDEX exercises_custom_name_idx ON exercises (owner_user_id, LOWER(name)) WHERE owner_user_id IS NOT NULL;
+
+CREATE TABLE exercise_aliases (
+    id SERIAL PRIMARY KEY,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    alias VARCHAR(100) NOT NULL, -- Alternative name or abbreviation, e.g. BP for Bench Press
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE UNIQUE INDEX exercise_aliases_exercise_alias_idx ON exercise_aliases (exercise_id, LOWER(alias));
+
+-- Trigram indexes for fuzzy exercise name lookups
+CREATE INDEX exercises_name_trgm_idx ON exercises USING GIN (name gin_trgm_ops);
+CREATE INDEX exercise_aliases_alias_trgm_idx ON exercise_aliases USING GIN (alias gin_trgm_ops);
+
 CREATE TABLE bodyweight_logs (
     id SERIAL PRIMARY KEY,
     user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
@@ -82,10 +150,17 @@ CREATE TABLE exercise_logs (
     id SERIAL PRIMARY KEY,
     user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
-    reps INTEGER NOT NULL,
-    weight DECIMAL(10, 2) NOT NULL, -- Store in kilograms
+    reps INTEGER NOT NULL, -- 1 for time and distance based sets
+    weight DECIMAL(10, 2) NOT NULL, -- Store in kilograms, 0 for unweighted time and distance based sets
     additional_weight DECIMAL(10, 2), -- Store in kilograms, only for bodyweight exercises
     exercise_type exercise_type, -- Only for bodyweight exercises
+    duration_seconds INTEGER CHECK (duration_seconds > 0), -- Only for time based exercises, optional for distance based ones
+    distance_meters DECIMAL(10, 2) CHECK (distance_meters > 0), -- Store in meters, only for distance based exercises
+    rpe DECIMAL(3, 1) CHECK (rpe BETWEEN 6 AND 10 AND rpe * 2 = TRUNC(rpe * 2)), -- Rate of perceived exertion in half steps
+    rir INTEGER CHECK (rir BETWEEN 0 AND 10), -- Reps in reserve
+    tempo VARCHAR(11), -- Eccentric-pause-concentric-pause, e.g. 3-1-1-0
+    rest_seconds INTEGER CHECK (rest_seconds >= 0), -- Rest taken after the set
+    notes TEXT,
     bodyweight_id INTEGER NOT NULL REFERENCES bodyweight_logs(id),
     log_date TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
@@ -110,3 +185,530 @@ CREATE TABLE user_trophies (
     updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     UNIQUE (user_id, trophy_id)
 );
+
+CREATE TABLE data_exports (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status data_export_status NOT NULL DEFAULT 'pending',
+    storage_key VARCHAR(255), -- Set once the archive has been written to storage
+    error TEXT,
+    expires_at TIMESTAMPTZ, -- Download links stop working after this point
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE workout_imports (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source import_source NOT NULL,
+    status import_status NOT NULL DEFAULT 'preview',
+    parsed_rows JSONB NOT NULL, -- Rows parsed from the uploaded file, weights in the source unit
+    parse_errors JSONB NOT NULL DEFAULT '[]', -- Lines of the file that could not be parsed
+    exercise_mappings JSONB NOT NULL DEFAULT '{}', -- Source exercise name -> exercises.id, 0 skips the exercise
+    report JSONB, -- Per-row outcome, set once the import is committed
+    committed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE workout_templates (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    share_code VARCHAR(32) UNIQUE, -- Set while the template is shared by link
+    cloned_from_id INTEGER REFERENCES workout_templates(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE workout_template_exercises (
+    id SERIAL PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES workout_templates(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL, -- Order of the exercise in the template, starting at 1
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    target_sets INTEGER NOT NULL CHECK (target_sets BETWEEN 1 AND 20),
+    target_reps INTEGER CHECK (target_reps > 0),
+    target_weight DECIMAL(10, 2) CHECK (target_weight >= 0), -- Store in kilograms
+    target_percentage DECIMAL(5, 2) CHECK (target_percentage > 0 AND target_percentage <= 120), -- Percent of the estimated one rep max
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    notes TEXT,
+    UNIQUE (template_id, position),
+    CHECK (target_weight IS NULL OR target_percentage IS NULL)
+);
+
+CREATE TABLE workout_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    template_id INTEGER REFERENCES workout_templates(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    status workout_session_status NOT NULL DEFAULT 'in_progress',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX workout_sessions_user_started_idx ON workout_sessions (user_id, started_at DESC);
+
+CREATE TABLE workout_session_sets (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL, -- Order of the exercise in the session, starting at 1
+    set_number INTEGER NOT NULL,
+    target_reps INTEGER,
+    target_weight DECIMAL(10, 2), -- Store in kilograms, pre-filled from the template and the latest logs
+    rest_seconds INTEGER,
+    reps INTEGER CHECK (reps > 0), -- Performed reps, set once the set is done
+    weight DECIMAL(10, 2) CHECK (weight >= 0), -- Store in kilograms
+    rpe DECIMAL(3, 1) CHECK (rpe BETWEEN 6 AND 10 AND rpe * 2 = TRUNC(rpe * 2)),
+    completed_at TIMESTAMPTZ,
+    exercise_log_id INTEGER REFERENCES exercise_logs(id) ON DELETE SET NULL, -- Set when the session is completed
+    UNIQUE (session_id, position, set_number)
+);
+
+CREATE TABLE training_programs (
+    id SERIAL PRIMARY KEY,
+    owner_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- NULL for built-in programs
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    definition JSONB NOT NULL, -- Weeks, days, prescriptions and progression rules, see internal/program
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX training_programs_builtin_name_idx ON training_programs (LOWER(name)) WHERE owner_user_id IS NULL;
+CREATE UNIQUE INDEX training_programs_custom_name_idx ON training_programs (owner_user_id, LOWER(name)) WHERE owner_user_id IS NOT NULL;
+
+CREATE TABLE program_enrollments (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    program_id INTEGER NOT NULL REFERENCES training_programs(id) ON DELETE CASCADE,
+    status program_enrollment_status NOT NULL DEFAULT 'active',
+    state JSONB NOT NULL, -- Current cycle, week and day and the training max of every lift
+    day_started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, -- Sets logged since count towards the current day
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user follows one program at a time
+CREATE UNIQUE INDEX program_enrollments_active_user_idx ON program_enrollments (user_id) WHERE status = 'active';
+
+CREATE TABLE plate_inventories (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    unit unit_system NOT NULL, -- Plates and bar are in kg for metric, lbs for imperial
+    bar_weight DECIMAL(5,2) NOT NULL CHECK (bar_weight > 0),
+    collar_weight DECIMAL(4,2) NOT NULL DEFAULT 0 CHECK (collar_weight >= 0), -- Per collar
+    plates JSONB NOT NULL, -- [{"weight": 20, "pairs": 2}, ...]
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Computed training analytics, cleared whenever the user's exercise logs change
+CREATE TABLE analytics_cache (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    cache_key VARCHAR(50) NOT NULL, -- Period, number of periods and start of the window
+    payload JSONB NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, cache_key)
+);
+
+-- Bumped whenever the user's analytics cache is cleared, so analytics computed from older logs are not cached
+CREATE TABLE analytics_versions (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    version BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE FUNCTION clear_analytics_cache() RETURNS TRIGGER AS $$
+DECLARE
+    changed_user_id INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_user_id := OLD.user_id;
+    ELSE
+        changed_user_id := NEW.user_id;
+    END IF;
+    DELETE FROM analytics_cache WHERE user_id = changed_user_id;
+    INSERT INTO analytics_versions (user_id, version) VALUES (changed_user_id, 1)
+    ON CONFLICT (user_id) DO UPDATE SET version = analytics_versions.version + 1;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_clear_analytics_cache
+AFTER INSERT OR UPDATE OR DELETE ON exercise_logs
+FOR EACH ROW EXECUTE FUNCTION clear_analytics_cache();
+
+-- Bodyweight exercises are loaded with the logged bodyweight
+CREATE TRIGGER bodyweight_logs_clear_analytics_cache
+AFTER UPDATE OR DELETE ON bodyweight_logs
+FOR EACH ROW EXECUTE FUNCTION clear_analytics_cache();
+
+CREATE TABLE bodyweight_goals (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    target_weight DECIMAL(10, 2) NOT NULL CHECK (target_weight > 0), -- Store in kilograms
+    target_date DATE NOT NULL,
+    start_weight DECIMAL(10, 2) NOT NULL, -- Latest bodyweight when the goal was set, in kilograms
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE body_measurements (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind body_measurement_kind NOT NULL,
+    value DECIMAL(5, 2) NOT NULL CHECK (value > 0), -- Store in centimeters, or percent for body_fat
+    source measurement_source,
+    measured_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, kind, measured_at)
+);
+
+CREATE TABLE streak_settings (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    target_days_per_week SMALLINT NOT NULL CHECK (target_days_per_week BETWEEN 1 AND 7),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE insights (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind insight_kind NOT NULL,
+    dedupe_key VARCHAR(100) NOT NULL, -- Identifies the insight across scans, see internal/insights
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    exercise_log_id INTEGER REFERENCES exercise_logs(id) ON DELETE CASCADE, -- Only for suspicious entries
+    details JSONB NOT NULL, -- Weights in kilograms
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dismissed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, dedupe_key)
+);
+
+CREATE TABLE insight_scans (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    scanned_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE strength_goals (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    metric goal_metric NOT NULL,
+    target DECIMAL(10, 2) NOT NULL CHECK (target > 0), -- Store in kilograms, or repetitions for most_reps
+    deadline DATE NOT NULL,
+    start_value DECIMAL(10, 2) NOT NULL, -- Best value in the weeks before the goal was set
+    achieved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL, -- See internal/events
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    notified_at TIMESTAMPTZ -- When the notifications for the event were sent
+);
+CREATE INDEX events_user_id_idx ON events (user_id, id);
+CREATE INDEX events_unnotified_idx ON events (id) WHERE notified_at IS NULL;
+
+CREATE TABLE strength_standard_versions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(20) UNIQUE NOT NULL,
+    published_at DATE NOT NULL, -- The latest published version is the current one
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE strength_standards (
+    id SERIAL PRIMARY KEY,
+    version_id INTEGER NOT NULL REFERENCES strength_standard_versions(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    sex VARCHAR(6) NOT NULL CHECK (sex IN ('male', 'female')),
+    bodyweight_class DECIMAL(5, 2) NOT NULL, -- Lower bound of the class in kilograms, up to the next class
+    beginner DECIMAL(6, 2) NOT NULL, -- One rep maxes in kilograms for each level
+    novice DECIMAL(6, 2) NOT NULL,
+    intermediate DECIMAL(6, 2) NOT NULL,
+    advanced DECIMAL(6, 2) NOT NULL,
+    elite DECIMAL(6, 2) NOT NULL,
+    UNIQUE (version_id, exercise_id, sex, bodyweight_class),
+    CHECK (beginner < novice AND novice < intermediate AND intermediate < advanced AND advanced < elite)
+);
+
+CREATE TABLE strength_standings (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    one_rep_max DECIMAL(10, 2) NOT NULL, -- Estimated from the user's best set, in kilograms
+    bodyweight DECIMAL(10, 2) NOT NULL,
+    log_date TIMESTAMPTZ NOT NULL,
+    percentile DOUBLE PRECISION NOT NULL, -- Among the users of the same sex
+    population BIGINT NOT NULL,
+    PRIMARY KEY (user_id, exercise_id)
+);
+
+CREATE TABLE meets (
+    id SERIAL PRIMARY KEY,
+    owner_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- The organizer
+    name VARCHAR(100) NOT NULL,
+    meet_date DATE NOT NULL,
+    scoring meet_scoring NOT NULL DEFAULT 'dots', -- Points the overall ranking is by
+    status meet_status NOT NULL DEFAULT 'open',
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE meet_divisions (
+    id SERIAL PRIMARY KEY,
+    meet_id INTEGER NOT NULL REFERENCES meets(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    sex VARCHAR(6) NOT NULL CHECK (sex IN ('male', 'female')),
+    weight_class DECIMAL(5, 2) CHECK (weight_class > 0), -- Upper limit in kilograms, NULL for an open class
+    min_age SMALLINT CHECK (min_age > 0),
+    max_age SMALLINT CHECK (max_age >= min_age),
+    UNIQUE (meet_id, name)
+);
+
+CREATE TABLE meet_entries (
+    id SERIAL PRIMARY KEY,
+    meet_id INTEGER NOT NULL REFERENCES meets(id) ON DELETE CASCADE,
+    division_id INTEGER NOT NULL REFERENCES meet_divisions(id),
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    age SMALLINT CHECK (age > 0), -- On the meet date
+    bodyweight DECIMAL(5, 2) CHECK (bodyweight > 0), -- Weigh-in in kilograms
+    confirmed_at TIMESTAMPTZ, -- Set once the lifter accepts the entry, nothing is logged for lifters who did not
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (meet_id, user_id)
+);
+
+CREATE TABLE meet_attempts (
+    id SERIAL PRIMARY KEY,
+    entry_id INTEGER NOT NULL REFERENCES meet_entries(id) ON DELETE CASCADE,
+    lift meet_lift NOT NULL,
+    attempt SMALLINT NOT NULL CHECK (attempt BETWEEN 1 AND 3),
+    weight DECIMAL(6, 2) NOT NULL CHECK (weight > 0), -- Store in kilograms
+    result attempt_result NOT NULL DEFAULT 'pending',
+    exercise_log_id INTEGER REFERENCES exercise_logs(id) ON DELETE SET NULL, -- Logged when the meet closes, good lifts only
+    judged_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entry_id, lift, attempt)
+);
+
+CREATE TABLE challenges (
+    id SERIAL PRIMARY KEY,
+    owner_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- The organizer
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    metric challenge_metric NOT NULL,
+    exercise_id INTEGER REFERENCES exercises(id) ON DELETE CASCADE, -- Only sets of this exercise count, NULL for every exercise
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL, -- Inclusive
+    open_join BOOLEAN NOT NULL DEFAULT FALSE, -- Anyone can join, otherwise only invited users
+    finalized_at TIMESTAMPTZ, -- When the final ranks were saved and the winners awarded
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE TABLE challenge_participants (
+    challenge_id INTEGER NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status challenge_participant_status NOT NULL DEFAULT 'invited',
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    joined_at TIMESTAMPTZ,
+    final_rank INTEGER, -- Saved when the challenge is finalized, 1 for the winners
+    final_value DECIMAL(12, 2), -- In kilograms for tonnage and best_e1rm
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (challenge_id, user_id)
+);
+CREATE INDEX challenge_participants_user_id_idx ON challenge_participants (user_id);
+
+CREATE TABLE privacy_settings (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    share_training_with_coaches BOOLEAN NOT NULL DEFAULT TRUE, -- Workouts, logged sets, streaks, programs and analytics
+    share_body_with_coaches BOOLEAN NOT NULL DEFAULT FALSE, -- Weigh-ins and body measurements
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organizations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organization_members (
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role organization_role NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id)
+);
+CREATE INDEX organization_members_user_id_idx ON organization_members (user_id);
+
+-- Links anyone can join an organization with until they expire or are used up
+CREATE TABLE organization_invites (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    role organization_role NOT NULL CHECK (role <> 'owner'),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    max_uses INTEGER CHECK (max_uses > 0), -- NULL for unlimited
+    uses INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX organization_invites_organization_id_idx ON organization_invites (organization_id);
+
+-- Templates and programs coaches copied into the accounts of their athletes
+CREATE TABLE coach_assignments (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    coach_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    athlete_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    template_id INTEGER REFERENCES workout_templates(id) ON DELETE SET NULL, -- The athlete's copy
+    program_id INTEGER REFERENCES training_programs(id) ON DELETE SET NULL, -- The athlete's copy
+    enrollment_id INTEGER REFERENCES program_enrollments(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX coach_assignments_athlete_idx ON coach_assignments (organization_id, athlete_user_id);
+
+-- Comments on a workout or on a set that was a personal record, replies one level deep
+CREATE TABLE comments (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- The author
+    workout_id INTEGER REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    exercise_log_id INTEGER REFERENCES exercise_logs(id) ON DELETE CASCADE,
+    parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE, -- The comment replied to, NULL for top level comments
+    body TEXT NOT NULL,
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((workout_id IS NULL) <> (exercise_log_id IS NULL))
+);
+CREATE INDEX comments_workout_id_idx ON comments (workout_id);
+CREATE INDEX comments_exercise_log_id_idx ON comments (exercise_log_id);
+
+-- Users mentioned in a comment, each notified once
+CREATE TABLE comment_mentions (
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE TABLE reactions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workout_id INTEGER REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    exercise_log_id INTEGER REFERENCES exercise_logs(id) ON DELETE CASCADE,
+    kind reaction_kind NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((workout_id IS NULL) <> (exercise_log_id IS NULL))
+);
+CREATE UNIQUE INDEX reactions_workout_user_kind_idx ON reactions (workout_id, user_id, kind) WHERE workout_id IS NOT NULL;
+CREATE UNIQUE INDEX reactions_exercise_log_user_kind_idx ON reactions (exercise_log_id, user_id, kind) WHERE exercise_log_id IS NOT NULL;
+
+-- Notifications shown in the notification center, created from events
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL, -- The event type, see internal/events
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL, -- The event payload
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX notifications_user_id_idx ON notifications (user_id, id);
+CREATE INDEX notifications_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Channels a user is notified on per notification type, defaults in internal/notifications
+CREATE TABLE notification_preferences (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    in_app BOOLEAN NOT NULL,
+    email BOOLEAN NOT NULL,
+    push BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, type)
+);
+
+-- Web Push subscriptions of the browsers of a user
+CREATE TABLE push_subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint TEXT UNIQUE NOT NULL,
+    p256dh VARCHAR(100) NOT NULL, -- Public key of the browser, base64url
+    auth VARCHAR(50) NOT NULL, -- Authentication secret, base64url
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Announces new events and notifications to the servers streaming them, see internal/realtime
+CREATE FUNCTION notify_realtime() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('realtime', json_build_object('table', TG_TABLE_NAME, 'id', NEW.id, 'user_id', NEW.user_id)::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER events_notify_realtime
+AFTER INSERT ON events
+FOR EACH ROW EXECUTE FUNCTION notify_realtime();
+
+CREATE TRIGGER notifications_notify_realtime
+AFTER INSERT ON notifications
+FOR EACH ROW EXECUTE FUNCTION notify_realtime();
+
+-- Endpoints of third-party integrations events are posted to, owned by a user or by an
+-- organization for the events of its athletes
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    organization_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL, -- Signs the payloads, see internal/webhooks
+    event_types TEXT[] NOT NULL,
+    description VARCHAR(200),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((user_id IS NULL) <> (organization_id IS NULL))
+);
+CREATE INDEX webhooks_user_id_idx ON webhooks (user_id);
+CREATE INDEX webhooks_organization_id_idx ON webhooks (organization_id);
+
+-- Outbox of the events posted to webhooks, queued with the event and retried with exponential
+-- backoff until delivered or out of attempts. Delivered and failed rows are the delivery log.
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id BIGINT REFERENCES events(id) ON DELETE SET NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- Whose event it is
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL, -- The event payload
+    status webhook_delivery_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER, -- HTTP status of the last attempt
+    last_error TEXT,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id);
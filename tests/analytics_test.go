@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"new-chainsaw/internal/handlers"
+)
+
+func TestTrainingAnalyticsRejectsInvalidPeriods(t *testing.T) {
+	r := gin.New()
+	r.GET("/analytics", handlers.GetTrainingAnalyticsHandler)
+
+	for _, query := range []string{"period=year", "period=week&periods=0", "period=month&periods=25", "periods=abc"} {
+		req, err := http.NewRequest("GET", "/analytics?"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
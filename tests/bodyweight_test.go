@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+	"new-chainsaw/internal/bodyweight"
+)
+
+func bodyweightDay(n int) time.Time {
+	return time.Date(2024, 1, 1, 7, 30, 0, 0, time.UTC).AddDate(0, 0, n)
+}
+
+func TestBodyweightTrendSmoothsAndAveragesDays(t *testing.T) {
+	trend := bodyweight.Smooth([]bodyweight.Point{
+		{Date: bodyweightDay(0), WeightKg: 80},
+		{Date: bodyweightDay(1), WeightKg: 82},
+		{Date: bodyweightDay(1).Add(10 * time.Hour), WeightKg: 84},
+	})
+
+	if len(trend.Days) != 2 {
+		t.Fatalf("expected weigh-ins on the same day to be averaged, got %+v", trend.Days)
+	}
+	if trend.Days[1].WeightKg != 83 || math.Abs(trend.Days[1].TrendKg-80.3) > 1e-9 {
+		t.Errorf("unexpected second day %+v", trend.Days[1])
+	}
+	if current, ok := trend.Current(); !ok || math.Abs(current-80.3) > 1e-9 {
+		t.Errorf("unexpected current trend %v", current)
+	}
+	if trend.WeeklyChangeKg != nil {
+		t.Errorf("expected no weekly change from two days, got %v", *trend.WeeklyChangeKg)
+	}
+}
+
+func TestBodyweightTrendInterpolatesShortGaps(t *testing.T) {
+	trend := bodyweight.Smooth([]bodyweight.Point{
+		{Date: bodyweightDay(0), WeightKg: 80},
+		{Date: bodyweightDay(4), WeightKg: 78},
+	})
+
+	if len(trend.Days) != 5 {
+		t.Fatalf("expected 5 days, got %d", len(trend.Days))
+	}
+	for i, d := range trend.Days[1:4] {
+		if !d.Interpolated || d.WeightKg != 80-0.5*float64(i+1) {
+			t.Errorf("unexpected interpolated day %+v", d)
+		}
+	}
+	if trend.Days[4].Interpolated {
+		t.Errorf("expected the weigh-in not to be interpolated")
+	}
+}
+
+func TestBodyweightTrendRestartsAfterLongGaps(t *testing.T) {
+	points := []bodyweight.Point{{Date: bodyweightDay(0), WeightKg: 90}}
+	for i := 0; i < 10; i++ {
+		points = append(points, bodyweight.Point{Date: bodyweightDay(30 + i), WeightKg: 80 - 0.1*float64(i)})
+	}
+	trend := bodyweight.Smooth(points)
+
+	if len(trend.Days) != 11 {
+		t.Fatalf("expected no interpolation across a long gap, got %d days", len(trend.Days))
+	}
+	if !trend.Days[1].Restarted || trend.Days[1].TrendKg != 80 {
+		t.Errorf("expected the trend to restart at the weigh-in after the gap, got %+v", trend.Days[1])
+	}
+	if trend.WeeklyChangeKg == nil || *trend.WeeklyChangeKg >= 0 || *trend.WeeklyChangeKg < -0.7 {
+		t.Errorf("expected a small weekly loss since the restart, got %v", trend.WeeklyChangeKg)
+	}
+}
+
+func TestBodyweightGoalProjection(t *testing.T) {
+	today := bodyweightDay(0)
+	goal := bodyweight.Goal{TargetKg: 75, TargetDate: today.AddDate(0, 0, 70), StartKg: 85}
+
+	loss := -1.0
+	projection := bodyweight.Project(goal, 80, &loss, today)
+	if projection.RemainingKg != -5 || projection.Progress != 50 || projection.Achieved {
+		t.Errorf("unexpected projection %+v", projection)
+	}
+	if projection.ProjectedDate == nil || !projection.ProjectedDate.Equal(time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)) || !projection.OnTrack {
+		t.Errorf("expected to reach the goal in 5 weeks, got %+v", projection.ProjectedDate)
+	}
+	if projection.RequiredWeeklyKg == nil || *projection.RequiredWeeklyKg != -0.5 {
+		t.Errorf("unexpected required weekly change %v", projection.RequiredWeeklyKg)
+	}
+
+	gain := 0.2
+	if projection := bodyweight.Project(goal, 80, &gain, today); projection.ProjectedDate != nil || projection.OnTrack {
+		t.Errorf("expected no projection when moving away from the goal, got %+v", projection)
+	}
+	if projection := bodyweight.Project(goal, 74.5, &gain, today); !projection.Achieved || projection.Progress != 100 {
+		t.Errorf("expected the goal to be achieved below the target, got %+v", projection)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"github.com/jackc/pgx/v5/pgtype"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/challenges"
+	"new-chainsaw/internal/validation"
+)
+
+var challengeToday = time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+
+func joinedAt(day int) time.Time {
+	return time.Date(2024, 9, day, 12, 0, 0, 0, time.UTC)
+}
+
+func TestRankChallengeSharesTies(t *testing.T) {
+	ranked := challenges.Rank([]challenges.Standing{
+		{UserID: 1, Value: 120, JoinedAt: joinedAt(3)},
+		{UserID: 2, Value: 150, JoinedAt: joinedAt(5)},
+		{UserID: 3, Value: 150, JoinedAt: joinedAt(1)},
+		{UserID: 4, Value: 0, JoinedAt: joinedAt(2)},
+	})
+
+	want := []struct {
+		userID int32
+		rank   int
+		won    bool
+	}{
+		{3, 1, true}, // Joined before user 2
+		{2, 1, true},
+		{1, 3, false},
+		{4, 4, false},
+	}
+	for i, w := range want {
+		if ranked[i].UserID != w.userID || ranked[i].Rank != w.rank || ranked[i].Won() != w.won {
+			t.Errorf("place %d: got %+v won %v, want %+v", i, ranked[i], ranked[i].Won(), w)
+		}
+	}
+}
+
+func TestNobodyWinsWithoutLogs(t *testing.T) {
+	ranked := challenges.Rank([]challenges.Standing{{UserID: 1}, {UserID: 2}})
+	for _, r := range ranked {
+		if r.Rank != 1 || r.Won() {
+			t.Errorf("unexpected %+v won %v", r, r.Won())
+		}
+	}
+}
+
+func TestChallengeStandingsPickMetric(t *testing.T) {
+	var tonnage, e1rm pgtype.Numeric
+	if err := tonnage.Scan("12500.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e1rm.Scan("142.333"); err != nil {
+		t.Fatal(err)
+	}
+	rows := []db.GetChallengeStandingsRow{{UserID: 7, TotalReps: 310, Tonnage: tonnage, Sessions: 9, BestE1rm: e1rm}}
+
+	tests := []struct {
+		metric db.ChallengeMetric
+		want   float64
+	}{
+		{db.ChallengeMetricTotalReps, 310},
+		{db.ChallengeMetricTonnage, 12500.5},
+		{db.ChallengeMetricSessions, 9},
+		{db.ChallengeMetricBestE1rm, 142.33},
+	}
+	for _, tt := range tests {
+		if got := challenges.Standings(tt.metric, rows)[0].Value; got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.metric, got, tt.want)
+		}
+	}
+}
+
+func TestChallengeStatus(t *testing.T) {
+	startsOn := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	endsOn := time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		today     time.Time
+		finalized bool
+		want      challenges.Status
+	}{
+		{startsOn.AddDate(0, 0, -1), false, challenges.StatusUpcoming},
+		{startsOn, false, challenges.StatusActive},
+		{endsOn, false, challenges.StatusActive},
+		{endsOn.AddDate(0, 0, 1), false, challenges.StatusEnded},
+		{endsOn.AddDate(0, 0, 1), true, challenges.StatusFinalized},
+	}
+	for _, tt := range tests {
+		if got := challenges.StatusOn(startsOn, endsOn, tt.finalized, tt.today); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.today.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestValidateChallenge(t *testing.T) {
+	exerciseID := int32(3)
+	october := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	endOfOctober := time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		metric     db.ChallengeMetric
+		exerciseID *int32
+		startsOn   time.Time
+		endsOn     time.Time
+		valid      bool
+	}{
+		{"most pull-ups in October", db.ChallengeMetricTotalReps, &exerciseID, october, endOfOctober, true},
+		{"sessions on every exercise", db.ChallengeMetricSessions, nil, october, endOfOctober, true},
+		{"best e1rm without exercise", db.ChallengeMetricBestE1rm, nil, october, endOfOctober, false},
+		{"unknown metric", db.ChallengeMetric("calories"), nil, october, endOfOctober, false},
+		{"ends before it starts", db.ChallengeMetricTonnage, nil, endOfOctober, october, false},
+		{"already ended", db.ChallengeMetricTonnage, nil, october.AddDate(0, -1, 0), october, false},
+		{"longer than a year", db.ChallengeMetricTonnage, nil, october, october.AddDate(1, 1, 0), false},
+		{"", db.ChallengeMetricTonnage, nil, october, endOfOctober, false},
+	}
+	for _, tt := range tests {
+		err := validation.ValidateChallenge(tt.name, tt.metric, tt.exerciseID, tt.startsOn, tt.endsOn, challengeToday)
+		if (err == nil) != tt.valid {
+			t.Errorf("%q: got error %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"new-chainsaw/internal/comments"
+	"new-chainsaw/internal/validation"
+)
+
+func TestMentions(t *testing.T) {
+	many := []string{}
+	for i := 0; i < comments.MaxMentions+2; i++ {
+		many = append(many, "@user"+string(rune('a'+i)))
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"none", "Great session!", []string{}},
+		{"single", "@coach_anna look at this", []string{"coach_anna"}},
+		{"trailing punctuation", "Nice one @mike. And @sara_k!", []string{"mike", "sara_k"}},
+		{"duplicates", "@mike @sara @mike", []string{"mike", "sara"}},
+		{"adjacent", "(@mike,@sara)", []string{"mike", "sara"}},
+		{"email", "mail me at anna@example.com", []string{}},
+		{"in word", "fire@will", []string{}},
+		{"bare at", "@ the gym", []string{}},
+		{"capped", strings.Join(many, " "), []string{"usera", "userb", "userc", "userd", "usere", "userf", "userg", "userh", "useri", "userj"}},
+	}
+	for _, tt := range tests {
+		if got := comments.Mentions(tt.body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanDeleteComment(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int32
+		want   bool
+	}{
+		{"author", 2, true},
+		{"owner", 1, true},
+		{"someone else", 3, false},
+	}
+	for _, tt := range tests {
+		if got := comments.CanDelete(tt.userID, 2, 1); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"valid", "Solid depth on the last set", false},
+		{"blank", "  \n ", true},
+		{"longest", strings.Repeat("a", 2000), false},
+		{"too long", strings.Repeat("a", 2001), true},
+	}
+	for _, tt := range tests {
+		if err := validation.ValidateComment(tt.body); (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
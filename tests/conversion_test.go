@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"testing"
+	"new-chainsaw/internal/conversion"
+)
+
+func TestToNumericKeepsDecimals(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+	}{
+		{"half step rpe", 8.5},
+		{"whole number", 100},
+		{"collar", 2.5},
+		{"mile", 1609.344},
+		{"negative", -0.25},
+	}
+	for _, tt := range tests {
+		num := conversion.ToNumeric(tt.value)
+		got, err := num.Float64Value()
+		if err != nil || !got.Valid || got.Float64 != tt.value {
+			t.Errorf("%s: got %v (%v), want %v", tt.name, got.Float64, err, tt.value)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"new-chainsaw/internal/dataexport"
+)
+
+func TestOpenPowerliftingWriterSummarizesTrainingDays(t *testing.T) {
+	squat, bench, deadlift, compSquat, rdl := "Back Squat", "Bench Press", "Deadlift", "Comp Squat", "Romanian Deadlift"
+	squatID, benchID, deadliftID, compSquatID, rdlID := int32(1), int32(2), int32(3), int32(40), int32(5)
+	weight := func(w float64) *float64 { return &w }
+	reps := func(r int32) *int32 { return &r }
+	day := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	lifter := dataexport.Lifter{
+		Name:  "Jane",
+		Sex:   "female",
+		Lifts: map[int32]string{squatID: "S", benchID: "B", deadliftID: "D", compSquatID: "S"}, // Comp Squat has the alias Back Squat
+	}
+	writer, err := dataexport.NewLogWriter(dataexport.FormatOpenPowerlifting, &buf, lifter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []dataexport.LogRow{
+		{Type: "exercise", Date: day, ExerciseID: &squatID, Exercise: &squat, Reps: reps(1), Weight: weight(100), Bodyweight: 60},
+		{Type: "exercise", Date: day.Add(time.Minute), ExerciseID: &compSquatID, Exercise: &compSquat, Reps: reps(1), Weight: weight(110), Bodyweight: 60},
+		{Type: "exercise", Date: day.Add(2 * time.Minute), ExerciseID: &squatID, Exercise: &squat, Reps: reps(5), Weight: weight(115), Bodyweight: 60},
+		{Type: "exercise", Date: day.Add(3 * time.Minute), ExerciseID: &benchID, Exercise: &bench, Reps: reps(1), Weight: weight(60), Bodyweight: 60},
+		{Type: "exercise", Date: day.Add(4 * time.Minute), ExerciseID: &deadliftID, Exercise: &deadlift, Reps: reps(1), Weight: weight(130), Bodyweight: 60},
+		{Type: "exercise", Date: day.Add(5 * time.Minute), ExerciseID: &rdlID, Exercise: &rdl, Reps: reps(1), Weight: weight(150), Bodyweight: 60}, // Not a competition lift
+		{Type: "bodyweight", Date: day.AddDate(0, 0, 1), Bodyweight: 61},
+		{Type: "exercise", Date: day.AddDate(0, 0, 2), ExerciseID: &benchID, Exercise: &bench, Reps: reps(1), Weight: weight(62.5), Bodyweight: 61},
+		{Type: "exercise", Date: day.AddDate(0, 0, 4), ExerciseID: &squatID, Exercise: &squat, Reps: reps(3), Weight: weight(105), Bodyweight: 61},
+	}
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two days with singles, got %q", buf.String())
+	}
+	if lines[1] != "Jane,F,SBD,Raw,60,110,60,130,300,2024-05-01" {
+		t.Errorf("unexpected full power day %q", lines[1])
+	}
+	if lines[2] != "Jane,F,B,Raw,61,,62.5,,,2024-05-03" {
+		t.Errorf("unexpected bench only day %q", lines[2])
+	}
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"github.com/jackc/pgx/v5/pgtype"
+	"new-chainsaw/internal/catalog"
+	"new-chainsaw/internal/validation"
+)
+
+func TestValidateCustomExercise(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{"name", validation.ValidateExerciseName("Safety bar squat"), false},
+		{"empty name", validation.ValidateExerciseName("  "), true},
+		{"long name", validation.ValidateExerciseName(strings.Repeat("a", 101)), true},
+		{"padded name", validation.ValidateExerciseName(" Safety bar squat"), true},
+		{"no muscle groups", validation.ValidateMuscleGroups(nil), false},
+		{"muscle groups", validation.ValidateMuscleGroups([]string{"quads", "glutes"}), false},
+		{"unknown muscle group", validation.ValidateMuscleGroups([]string{"quadriceps"}), true},
+		{"muscle group listed twice", validation.ValidateMuscleGroups([]string{"quads", "quads"}), true},
+		{"too many muscle groups", validation.ValidateMuscleGroups([]string{"chest", "shoulders", "triceps", "biceps", "forearms", "lats", "traps"}), true},
+		{"distinct primary and secondary", validation.ValidateMuscleGroupOverlap([]string{"quads"}, []string{"glutes"}), false},
+		{"primary and secondary", validation.ValidateMuscleGroupOverlap([]string{"quads", "glutes"}, []string{"glutes"}), true},
+		{"no equipment", validation.ValidateEquipment(""), false},
+		{"equipment", validation.ValidateEquipment("kettlebell"), false},
+		{"unknown equipment", validation.ValidateEquipment("sandbag"), true},
+		{"movement pattern", validation.ValidateMovementPattern("hinge"), false},
+		{"unknown movement pattern", validation.ValidateMovementPattern("twist"), true},
+		{"exercise type", validation.ValidateExerciseType("Assisted"), false},
+		{"lowercase exercise type", validation.ValidateExerciseType("weighted"), true},
+		{"measurement kind", validation.ValidateMeasurementKind("distance_weight"), false},
+		{"no measurement kind", validation.ValidateMeasurementKind(""), true},
+	}
+	for _, tt := range tests {
+		if (tt.err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tt.name, tt.err, tt.wantErr)
+		}
+	}
+}
+
+func TestCustomExerciseEditable(t *testing.T) {
+	tests := []struct {
+		name  string
+		owner pgtype.Int4
+		want  bool
+	}{
+		{"catalog", pgtype.Int4{}, false},
+		{"own", pgtype.Int4{Int32: 2, Valid: true}, true},
+		{"someone else's", pgtype.Int4{Int32: 3, Valid: true}, false},
+	}
+	for _, tt := range tests {
+		if got := catalog.Editable(2, tt.owner); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/goals"
+	"new-chainsaw/internal/strength"
+	"new-chainsaw/internal/validation"
+)
+
+// goalToday is a Wednesday, the current week starts on 2024-06-10.
+var goalToday = time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC)
+
+// goalSet returns a single of loadKg on the Monday n weeks before the current week.
+func goalSet(weeksAgo int, loadKg float64) strength.Set {
+	return strength.Set{Date: time.Date(2024, 6, 10, 18, 0, 0, 0, time.UTC).AddDate(0, 0, -7*weeksAgo), Reps: 1, LoadKg: loadKg}
+}
+
+func squatGoal(target float64, deadline time.Time) goals.Goal {
+	return goals.Goal{
+		Metric:     db.GoalMetricEstimated1rm,
+		Target:     target,
+		Deadline:   deadline,
+		StartValue: 100,
+		CreatedAt:  time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestTrackGoalProjectsCompletion(t *testing.T) {
+	sets := []strength.Set{}
+	for week := 7; week >= 0; week-- {
+		sets = append(sets, goalSet(week, 134-3.5*float64(week)))
+	}
+
+	progress := goals.Track(squatGoal(140, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)), sets, goalToday)
+	if progress.Status != goals.StatusOnTrack || progress.Current != 134 || progress.Percent != 85 || progress.Remaining != 6 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	if progress.WeeklyChange == nil || *progress.WeeklyChange != 3.5 {
+		t.Errorf("expected a weekly change of 3.5 kg, got %v", progress.WeeklyChange)
+	}
+	// 135 kg on the trend today, half a kilogram a day to go
+	if progress.ProjectedDate == nil || progress.ProjectedDate.Before(goalToday.AddDate(0, 0, 10)) || progress.ProjectedDate.After(goalToday.AddDate(0, 0, 11)) {
+		t.Errorf("unexpected projected date %v", progress.ProjectedDate)
+	}
+
+	if progress := goals.Track(squatGoal(140, goalToday.AddDate(0, 0, 5)), sets, goalToday); progress.Status != goals.StatusBehind {
+		t.Errorf("expected a close deadline to be behind, got %+v", progress)
+	}
+	if progress := goals.Track(squatGoal(140, goalToday.AddDate(0, 0, -1)), sets, goalToday); progress.Status != goals.StatusMissed {
+		t.Errorf("expected a past deadline to be missed, got %+v", progress)
+	}
+}
+
+func TestTrackGoalReached(t *testing.T) {
+	sets := []strength.Set{
+		goalSet(12, 145), // Before the goal was set
+		goalSet(3, 130),
+		goalSet(2, 141),
+		goalSet(1, 142),
+	}
+
+	progress := goals.Track(squatGoal(140, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)), sets, goalToday)
+	if progress.Status != goals.StatusAchieved || progress.AchievedAt == nil || !progress.AchievedAt.Equal(sets[2].Date) {
+		t.Fatalf("expected the goal to be reached by the first set above the target, got %+v", progress)
+	}
+	if progress.Current != 142 || progress.Percent != 100 || progress.Remaining != 0 {
+		t.Errorf("unexpected progress %+v", progress)
+	}
+}
+
+func TestTrackGoalWithoutProgress(t *testing.T) {
+	flat := []strength.Set{goalSet(3, 120), goalSet(2, 120), goalSet(1, 120)}
+	progress := goals.Track(squatGoal(140, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)), flat, goalToday)
+	if progress.Status != goals.StatusBehind || progress.ProjectedDate != nil {
+		t.Errorf("expected a flat trend to be behind without a projection, got %+v", progress)
+	}
+
+	progress = goals.Track(squatGoal(140, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)), flat[1:], goalToday)
+	if progress.Status != goals.StatusInsufficient || progress.WeeklyChange != nil {
+		t.Errorf("expected two weeks not to be enough to project, got %+v", progress)
+	}
+}
+
+func TestGoalValue(t *testing.T) {
+	rpe := 8.0
+	if value, ok := goals.Value(db.GoalMetricEstimated1rm, strength.Set{Reps: 3, LoadKg: 100, RPE: &rpe}); !ok || value != strength.EstimatedOneRepMax(100, 5) {
+		t.Errorf("expected reps in reserve to count towards the estimate, got %v", value)
+	}
+	if _, ok := goals.Value(db.GoalMetricEstimated1rm, strength.Set{Reps: 15, LoadKg: 60}); ok {
+		t.Errorf("expected a set of 15 not to estimate a one rep max")
+	}
+	if value, ok := goals.Value(db.GoalMetricMostReps, strength.Set{Reps: 15, LoadKg: 60}); !ok || value != 15 {
+		t.Errorf("unexpected most reps value %v", value)
+	}
+}
+
+func TestValidateStrengthGoal(t *testing.T) {
+	deadline := goalToday.AddDate(0, 3, 0)
+	if err := validation.ValidateStrengthGoal(db.GoalMetricEstimated1rm, 140, deadline, goalToday); err != nil {
+		t.Errorf("expected a valid goal, got %v", err)
+	}
+	if err := validation.ValidateStrengthGoal(db.GoalMetricMostReps, 12.5, deadline, goalToday); err == nil {
+		t.Errorf("expected a fractional rep target to be rejected")
+	}
+	if err := validation.ValidateStrengthGoal("max_volume", 140, deadline, goalToday); err == nil {
+		t.Errorf("expected an unknown metric to be rejected")
+	}
+	if err := validation.ValidateStrengthGoal(db.GoalMetricHeaviestWeight, 140, goalToday, goalToday); err == nil {
+		t.Errorf("expected a deadline of today to be rejected")
+	}
+}
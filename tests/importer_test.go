@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"new-chainsaw/internal/importer"
+)
+
+func TestStrongParserSkipsWarmupsAndReadsDecimalCommas(t *testing.T) {
+	csv := "Date;Workout Name;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes\n" +
+		"2024-03-01 18:30:00;Push;Bench Press (Barbell);W;40;10;0;0;;\n" +
+		"2024-03-01 18:30:00;Push;Bench Press (Barbell);1;82,5;5;0;0;;\n" +
+		"2024-03-01 18:30:00;Push;Bench Press (Barbell);2;82,5;5;0;0;;\n" +
+		"not a date;Push;Bench Press (Barbell);3;82,5;5;0;0;;\n"
+
+	rows, rowErrors, err := importer.StrongParser{Unit: "metric"}.Parse(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 working sets, got %d", len(rows))
+	}
+	if rows[0].Weight != 82.5 || rows[0].Reps != 5 {
+		t.Errorf("unexpected set %+v", rows[0])
+	}
+	if len(rowErrors) != 1 || rowErrors[0].Line != 5 {
+		t.Errorf("expected an error for line 5, got %+v", rowErrors)
+	}
+
+	importer.AssignTimestamps(rows)
+	if rows[1].Date.Sub(rows[0].Date) != time.Second {
+		t.Errorf("expected sets of the same workout to be one second apart, got %v and %v", rows[0].Date, rows[1].Date)
+	}
+}
+
+func TestMatchExercises(t *testing.T) {
+	catalog := []importer.CatalogExercise{
+		{ID: 1, Name: "Back Squat", Aliases: []string{"Squat"}},
+		{ID: 2, Name: "Bench Press"},
+		{ID: 3, Name: "Barbell Curl", Aliases: []string{"Curl"}},
+		{ID: 4, Name: "Dumbbell Curl", Aliases: []string{"Curl"}},
+	}
+	matches := importer.MatchExercises([]string{"Squat (Barbell)", "Bench Press (Barbell)", "Lat Pulldown", "Curl"}, catalog)
+
+	if id := matches["Squat (Barbell)"]; id == nil || *id != 1 {
+		t.Errorf("expected Squat (Barbell) to match Back Squat, got %v", id)
+	}
+	if id := matches["Bench Press (Barbell)"]; id == nil || *id != 2 {
+		t.Errorf("expected Bench Press (Barbell) to match Bench Press, got %v", id)
+	}
+	if id, ok := matches["Lat Pulldown"]; !ok || id != nil {
+		t.Errorf("expected Lat Pulldown to be unmapped")
+	}
+	if id := matches["Curl"]; id != nil {
+		t.Errorf("expected an alias shared by two exercises to stay unmapped, got %v", *id)
+	}
+}
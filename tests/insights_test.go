@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"new-chainsaw/internal/insights"
+)
+
+// insightNow is a Wednesday, the current week starts on 2024-06-10.
+var insightNow = time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC)
+
+// insightSet returns a single of loadKg on the Tuesday n weeks before the current week.
+func insightSet(logID int32, weeksAgo int, loadKg float64) insights.Set {
+	return insights.Set{
+		LogID:        logID,
+		ExerciseID:   1,
+		Exercise:     "Squat",
+		Date:         time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -7*weeksAgo),
+		Reps:         1,
+		LoadKg:       loadKg,
+		BodyweightKg: 80,
+	}
+}
+
+func TestDetectPlateau(t *testing.T) {
+	sets := []insights.Set{insightSet(1, 9, 95), insightSet(2, 8, 100)}
+	for week := 5; week >= 0; week-- {
+		sets = append(sets, insightSet(int32(10-week), week, 99))
+	}
+
+	found := insights.Detect(sets, insightNow)
+	if len(found) != 1 || found[0].Kind != insights.Plateau {
+		t.Fatalf("expected a plateau, got %+v", found)
+	}
+	if found[0].Weeks != 8 || found[0].BestKg != 99 || found[0].PreviousBestKg != 100 || !found[0].Since.Equal(sets[1].Date) {
+		t.Errorf("unexpected plateau %+v", found[0])
+	}
+	if found[0].Key != "plateau:1:2024-04-16" {
+		t.Errorf("unexpected key %q", found[0].Key)
+	}
+}
+
+func TestDetectRegression(t *testing.T) {
+	sets := []insights.Set{
+		insightSet(1, 6, 95), insightSet(2, 5, 100), insightSet(3, 4, 100), insightSet(4, 3, 98),
+		insightSet(5, 1, 85), insightSet(6, 0, 84),
+	}
+
+	found := insights.Detect(sets, insightNow)
+	if len(found) != 1 || found[0].Kind != insights.Regression {
+		t.Fatalf("expected a regression, got %+v", found)
+	}
+	if found[0].ChangePercent != -15 || found[0].Weeks != 5 {
+		t.Errorf("unexpected regression %+v", found[0])
+	}
+}
+
+func TestDetectFastGain(t *testing.T) {
+	sets := []insights.Set{}
+	for week := 11; week >= 4; week-- {
+		sets = append(sets, insightSet(int32(20-week), week, 100))
+	}
+	sets = append(sets, insightSet(30, 2, 110), insightSet(31, 0, 120))
+
+	found := insights.Detect(sets, insightNow)
+	if len(found) != 1 || found[0].Kind != insights.FastGain {
+		t.Fatalf("expected a fast gain, got %+v", found)
+	}
+	if found[0].ChangePercent != 20 || found[0].Key != "fast_gain:1:2024-06" {
+		t.Errorf("unexpected fast gain %+v", found[0])
+	}
+}
+
+func TestDetectSuspiciousEntries(t *testing.T) {
+	sets := []insights.Set{
+		insightSet(1, 3, 100), insightSet(2, 3, 100), insightSet(3, 2, 102),
+		insightSet(4, 2, 200), // Double every earlier set
+		insightSet(5, 1, 450), // More than five times bodyweight
+		insightSet(6, 1, 700), // Implausible
+		insightSet(7, 0, 103),
+	}
+
+	found := insights.Detect(sets, insightNow)
+	if len(found) != 3 {
+		t.Fatalf("expected three suspicious sets and no trend, got %+v", found)
+	}
+	for i, logID := range []int32{4, 5, 6} {
+		if found[i].Kind != insights.SuspiciousEntry || found[i].ExerciseLogID == nil || *found[i].ExerciseLogID != logID {
+			t.Errorf("expected set %d to be suspicious, got %+v", logID, found[i])
+		}
+	}
+
+	// Bodyweight exercises include the lifter's bodyweight in the load
+	weighted := insightSet(8, 0, 450)
+	weighted.Bodyweight = true
+	if found := insights.Detect([]insights.Set{weighted}, insightNow); len(found) != 0 {
+		t.Errorf("expected a heavy bodyweight exercise not to be suspicious, got %+v", found)
+	}
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+	"new-chainsaw/internal/conversion"
+	"new-chainsaw/internal/measurements"
+)
+
+func TestMeasurementKinds(t *testing.T) {
+	kind, err := measurements.ParseKind("waist")
+	if err != nil || kind != measurements.Waist || kind.Dimension() != measurements.Length {
+		t.Errorf("unexpected kind %q, %v", kind, err)
+	}
+	if _, err := measurements.ParseKind("belly"); !errors.Is(err, measurements.ErrInvalidMeasurement) {
+		t.Errorf("expected an invalid measurement error, got %v", err)
+	}
+
+	if err := measurements.Waist.Validate(81.5); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := measurements.Waist.Validate(500); err == nil {
+		t.Errorf("expected an implausible waist to be rejected")
+	}
+	if err := measurements.BodyFat.Validate(80); err == nil {
+		t.Errorf("expected an implausible body fat percentage to be rejected")
+	}
+}
+
+func TestLengthConversion(t *testing.T) {
+	if inches := conversion.CmToInches(2.54); inches != 1 {
+		t.Errorf("expected 1 inch, got %v", inches)
+	}
+	if cm := conversion.InchesToCm(conversion.CmToInches(80)); math.Abs(cm-80) > 1e-9 {
+		t.Errorf("expected a round trip, got %v", cm)
+	}
+}
+
+func TestBodyComposition(t *testing.T) {
+	composition := measurements.Compose(80, 15)
+	if composition.FatMass != 12 || composition.LeanMass != 68 {
+		t.Errorf("unexpected composition %+v", composition)
+	}
+}
+
+func TestMeasurementTrend(t *testing.T) {
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	readings := []measurements.Reading{
+		{Date: start, Value: 90},
+		{Date: start.AddDate(0, 0, 7), Value: 89},
+		{Date: start.AddDate(0, 0, 14), Value: 88},
+	}
+
+	trend, ok := measurements.Summarize(readings)
+	if !ok || trend.First != 90 || trend.Latest != 88 || trend.Change != -2 {
+		t.Errorf("unexpected trend %+v", trend)
+	}
+	if trend.WeeklyChange == nil || *trend.WeeklyChange != -1 {
+		t.Errorf("expected a weekly change of -1, got %v", trend.WeeklyChange)
+	}
+
+	if trend, _ := measurements.Summarize(readings[:1]); trend.WeeklyChange != nil {
+		t.Errorf("expected no weekly change from a single reading")
+	}
+	if _, ok := measurements.Summarize(nil); ok {
+		t.Errorf("expected no trend without readings")
+	}
+}
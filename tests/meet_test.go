@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/meets"
+	"new-chainsaw/internal/validation"
+)
+
+// meetLifter returns an entry with a good squat, bench and deadlift, the deadlift missed when
+// bombed so there is no total.
+func meetLifter(id int32, divisionID int32, sex string, bodyweightKg float64, squat, bench, deadlift float64, bombed bool) meets.Entry {
+	deadliftResult := db.AttemptResultGood
+	if bombed {
+		deadliftResult = db.AttemptResultNoLift
+	}
+	return meets.Entry{
+		ID:           id,
+		Name:         "Lifter " + string(rune('A'+id-1)),
+		Sex:          sex,
+		DivisionID:   divisionID,
+		BodyweightKg: &bodyweightKg,
+		Attempts: []meets.Attempt{
+			{Lift: db.MeetLiftSquat, Number: 1, WeightKg: squat - 10, Result: db.AttemptResultGood},
+			{Lift: db.MeetLiftSquat, Number: 2, WeightKg: squat, Result: db.AttemptResultGood},
+			{Lift: db.MeetLiftSquat, Number: 3, WeightKg: squat + 5, Result: db.AttemptResultNoLift},
+			{Lift: db.MeetLiftBench, Number: 1, WeightKg: bench, Result: db.AttemptResultGood},
+			{Lift: db.MeetLiftDeadlift, Number: 1, WeightKg: deadlift, Result: deadliftResult},
+		},
+	}
+}
+
+func TestMeetScoring(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"dots male", meets.Dots("male", 90, 700), 452.62},
+		{"dots female", meets.Dots("female", 60, 400), 443.42},
+		{"dots clamps bodyweight", meets.Dots("male", 250, 900), meets.Dots("male", 210, 900)},
+		{"goodlift male", meets.Goodlift("male", 90, 700), 93.06},
+		{"goodlift female", meets.Goodlift("female", 60, 400), 90.42},
+		{"unknown sex", meets.Dots("", 90, 700), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestRankMeet(t *testing.T) {
+	entries := []meets.Entry{
+		meetLifter(1, 1, "male", 90, 250, 170, 280, false),
+		meetLifter(2, 1, "male", 88, 250, 170, 280, false),
+		meetLifter(3, 1, "male", 80, 300, 200, 320, true),
+		meetLifter(4, 2, "female", 60, 150, 90, 160, false),
+	}
+
+	placings := meets.Rank(entries, db.MeetScoringDots)
+	want := []struct {
+		total        float64
+		place        int
+		overallPlace int
+	}{
+		{700, 2, 2},
+		{700, 1, 1}, // Same total, lighter
+		{0, 0, 0},
+		{400, 1, 3},
+	}
+	for i, w := range want {
+		p := placings[i]
+		if w.total == 0 {
+			if p.TotalKg != nil || p.Place != 0 || p.OverallPlace != 0 {
+				t.Errorf("entry %d bombed out but placed %+v", p.ID, p)
+			}
+			continue
+		}
+		if p.TotalKg == nil || *p.TotalKg != w.total || p.Place != w.place || p.OverallPlace != w.overallPlace {
+			t.Errorf("entry %d: got total %v place %d overall %d, want %+v", p.ID, p.TotalKg, p.Place, p.OverallPlace, w)
+		}
+	}
+	if placings[0].BestKg[db.MeetLiftSquat] != 250 || *placings[0].Dots != 452.62 {
+		t.Errorf("unexpected best squat or points %+v", placings[0])
+	}
+	if _, ok := placings[2].BestKg[db.MeetLiftDeadlift]; ok {
+		t.Errorf("missed deadlift counted as best %+v", placings[2].BestKg)
+	}
+}
+
+func TestCheckAttemptDeclaration(t *testing.T) {
+	declared := []meets.Attempt{
+		{Lift: db.MeetLiftSquat, Number: 1, WeightKg: 200, Result: db.AttemptResultGood},
+		{Lift: db.MeetLiftSquat, Number: 2, WeightKg: 210, Result: db.AttemptResultNoLift},
+		{Lift: db.MeetLiftBench, Number: 1, WeightKg: 140, Result: db.AttemptResultPending},
+	}
+	tests := []struct {
+		name     string
+		lift     db.MeetLift
+		number   int
+		weightKg float64
+		valid    bool
+	}{
+		{"repeat after a miss", db.MeetLiftSquat, 3, 210, true},
+		{"lower after a miss", db.MeetLiftSquat, 3, 205, false},
+		{"same after a good lift", db.MeetLiftSquat, 2, 200, false},
+		{"already judged", db.MeetLiftSquat, 1, 205, false},
+		{"change a pending attempt", db.MeetLiftBench, 1, 142.5, true},
+		{"before the previous is judged", db.MeetLiftBench, 2, 150, false},
+		{"skip an attempt", db.MeetLiftDeadlift, 2, 250, false},
+		{"opener", db.MeetLiftDeadlift, 1, 240, true},
+		{"fourth attempt", db.MeetLiftDeadlift, 4, 250, false},
+		{"unknown lift", db.MeetLift("clean"), 1, 100, false},
+		{"too heavy", db.MeetLiftDeadlift, 1, 650, false},
+	}
+	for _, tt := range tests {
+		err := meets.CheckDeclaration(declared, tt.lift, tt.number, tt.weightKg)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: got error %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestMeetDivisionEligibility(t *testing.T) {
+	weightClass, minAge, maxAge := 83.0, 14, 18
+	division := meets.Division{Name: "Sub-Junior 83", Sex: "male", WeightClassKg: &weightClass, MinAge: &minAge, MaxAge: &maxAge}
+
+	age, tooOld := 16, 19
+	if err := division.Admits("male", &age); err != nil {
+		t.Errorf("eligible lifter rejected: %v", err)
+	}
+	if division.Admits("female", &age) == nil || division.Admits("male", &tooOld) == nil || division.Admits("male", nil) == nil {
+		t.Error("ineligible lifter admitted")
+	}
+	if division.CheckWeighIn(83) != nil || division.CheckWeighIn(83.1) == nil {
+		t.Error("weight class limit not applied")
+	}
+
+	if err := validation.ValidateMeetDivision("Open", "male", nil, nil, nil); err != nil {
+		t.Errorf("open division rejected: %v", err)
+	}
+	if validation.ValidateMeetDivision("Masters", "male", nil, &maxAge, &minAge) == nil {
+		t.Error("empty age range accepted")
+	}
+}
+
+func TestWriteMeetCSV(t *testing.T) {
+	weightClass := 93.0
+	divisions := map[int32]meets.Division{1: {ID: 1, Name: "Open", Sex: "male", WeightClassKg: &weightClass}}
+	placings := meets.Rank([]meets.Entry{
+		meetLifter(1, 1, "male", 90, 250, 170, 280, false),
+		meetLifter(2, 1, "male", 92, 260, 180, 300, true),
+	}, db.MeetScoringDots)
+
+	var buf bytes.Buffer
+	if err := meets.WriteCSV(&buf, "Club Classic", "2024-06-15", placings, divisions); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0][0] != "Place" {
+		t.Fatalf("unexpected records %v", records)
+	}
+
+	placed := strings.Join(records[1], ",")
+	if want := "1,Lifter A,M,SBD,Raw,,Open,90,93,240,250,-255,250,170,,,170,280,,,280,700,452.62,93.06,2024-06-15,Club Classic"; placed != want {
+		t.Errorf("got %s, want %s", placed, want)
+	}
+	if records[2][0] != "DQ" || records[2][17] != "-300" || records[2][21] != "" {
+		t.Errorf("bombed out lifter not disqualified %v", records[2])
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/middleware"
+)
+
+type fakeAccountStatus struct {
+	status db.GetUserDeletionStatusRow
+	err    error
+}
+
+func (f fakeAccountStatus) GetUserDeletionStatus(ctx context.Context, id int32) (db.GetUserDeletionStatusRow, error) {
+	return f.status, f.err
+}
+
+func TestRequireActiveAccount(t *testing.T) {
+	deleted := db.GetUserDeletionStatusRow{
+		DeletedAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		PurgeAfter: pgtype.Timestamptz{Time: time.Now().Add(30 * 24 * time.Hour), Valid: true},
+	}
+
+	tests := []struct {
+		name            string
+		pendingDeletion bool // The claim of the access token
+		account         fakeAccountStatus
+		want            int
+	}{
+		{"active", false, fakeAccountStatus{}, http.StatusOK},
+		{"deleted after the token was issued", false, fakeAccountStatus{status: deleted}, http.StatusForbidden},
+		{"pending deletion", true, fakeAccountStatus{status: deleted}, http.StatusForbidden},
+		{"restored after the token was issued", true, fakeAccountStatus{}, http.StatusOK},
+		{"purged", false, fakeAccountStatus{err: pgx.ErrNoRows}, http.StatusUnauthorized},
+		{"database error", false, fakeAccountStatus{err: errors.New("connection refused")}, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", 1)
+			c.Set("pending_deletion", tt.pendingDeletion)
+		})
+		r.GET("/me", middleware.RequireActiveAccount(tt.account), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/me", nil))
+		if rr.Code != tt.want {
+			t.Errorf("%s: got status %d, want %d", tt.name, rr.Code, tt.want)
+		}
+	}
+}
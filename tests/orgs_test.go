@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/orgs"
+	"new-chainsaw/internal/validation"
+)
+
+func TestCanInvite(t *testing.T) {
+	tests := []struct {
+		inviter db.OrganizationRole
+		role    db.OrganizationRole
+		want    bool
+	}{
+		{db.OrganizationRoleOwner, db.OrganizationRoleCoach, true},
+		{db.OrganizationRoleOwner, db.OrganizationRoleAthlete, true},
+		{db.OrganizationRoleOwner, db.OrganizationRoleOwner, false},
+		{db.OrganizationRoleCoach, db.OrganizationRoleAthlete, true},
+		{db.OrganizationRoleCoach, db.OrganizationRoleCoach, false},
+		{db.OrganizationRoleAthlete, db.OrganizationRoleAthlete, false},
+	}
+	for _, tt := range tests {
+		if got := orgs.CanInvite(tt.inviter, tt.role); got != tt.want {
+			t.Errorf("%s inviting %s: got %v, want %v", tt.inviter, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestCanRemoveMember(t *testing.T) {
+	owner := db.OrganizationMember{UserID: 1, Role: db.OrganizationRoleOwner}
+	coach := db.OrganizationMember{UserID: 2, Role: db.OrganizationRoleCoach}
+	otherCoach := db.OrganizationMember{UserID: 3, Role: db.OrganizationRoleCoach}
+	athlete := db.OrganizationMember{UserID: 4, Role: db.OrganizationRoleAthlete}
+	otherAthlete := db.OrganizationMember{UserID: 5, Role: db.OrganizationRoleAthlete}
+
+	tests := []struct {
+		name    string
+		remover db.OrganizationMember
+		member  db.OrganizationMember
+		want    bool
+	}{
+		{"owner removes coach", owner, coach, true},
+		{"owner leaves", owner, owner, false},
+		{"coach removes athlete", coach, athlete, true},
+		{"coach removes coach", coach, otherCoach, false},
+		{"coach removes owner", coach, owner, false},
+		{"coach leaves", coach, coach, true},
+		{"athlete leaves", athlete, athlete, true},
+		{"athlete removes athlete", athlete, otherAthlete, false},
+	}
+	for _, tt := range tests {
+		if got := orgs.CanRemove(tt.remover, tt.member); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckInvite(t *testing.T) {
+	now := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	maxUses := int32(2)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		maxUses   *int32
+		uses      int32
+		want      error
+	}{
+		{"unlimited", now.Add(time.Hour), nil, 40, nil},
+		{"uses left", now.Add(time.Hour), &maxUses, 1, nil},
+		{"used up", now.Add(time.Hour), &maxUses, 2, orgs.ErrInviteUsedUp},
+		{"expired", now, nil, 0, orgs.ErrInviteExpired},
+	}
+	for _, tt := range tests {
+		if got := orgs.CheckInvite(tt.expiresAt, tt.maxUses, tt.uses, now); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInviteCodesAreUnique(t *testing.T) {
+	a, err := orgs.InviteCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := orgs.InviteCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 32 || a == b {
+		t.Errorf("unexpected codes %q and %q", a, b)
+	}
+}
+
+func TestValidateOrganizationInvite(t *testing.T) {
+	zero := int32(0)
+	tests := []struct {
+		name    string
+		role    db.OrganizationRole
+		days    int
+		maxUses *int32
+		valid   bool
+	}{
+		{"athlete link for a week", db.OrganizationRoleAthlete, 7, nil, true},
+		{"coach link", db.OrganizationRoleCoach, 30, nil, true},
+		{"owner link", db.OrganizationRoleOwner, 7, nil, false},
+		{"never expires", db.OrganizationRoleAthlete, 365, nil, false},
+		{"no uses", db.OrganizationRoleAthlete, 7, &zero, false},
+	}
+	for _, tt := range tests {
+		err := validation.ValidateOrganizationInvite(tt.role, tt.days, tt.maxUses)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: got error %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+	if validation.ValidateOrganization(" ") == nil {
+		t.Error("blank organization name accepted")
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"new-chainsaw/internal/plates"
+)
+
+func TestPlateLoadingUsesFewestPlates(t *testing.T) {
+	inventory := plates.DefaultInventory(false)
+
+	loading := inventory.Load(142.5)
+	expected := []plates.Plate{{Weight: 25, Pairs: 2}, {Weight: 10, Pairs: 1}, {Weight: 1.25, Pairs: 1}}
+	if loading.Total != 142.5 || !reflect.DeepEqual(loading.PerSide, expected) {
+		t.Errorf("unexpected loading %+v", loading)
+	}
+
+	if loading := inventory.Load(101); loading.Total != 100 {
+		t.Errorf("expected the closest lighter loadable weight, got %+v", loading)
+	}
+	if loading := inventory.Load(15); loading.Total != 20 || len(loading.PerSide) != 0 {
+		t.Errorf("expected the empty bar below the bar weight, got %+v", loading)
+	}
+}
+
+func TestPlateLoadingRespectsInventory(t *testing.T) {
+	inventory := plates.Inventory{Bar: 45, Collar: 2.5, Plates: []plates.Plate{{Weight: 45, Pairs: 1}, {Weight: 10, Pairs: 2}, {Weight: 2.5, Pairs: 1}}}
+	if err := inventory.Validate(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	loading := inventory.Load(185)
+	expected := []plates.Plate{{Weight: 45, Pairs: 1}, {Weight: 10, Pairs: 2}, {Weight: 2.5, Pairs: 1}}
+	if loading.Total != 185 || loading.Collars != 5 || !reflect.DeepEqual(loading.PerSide, expected) {
+		t.Errorf("unexpected loading %+v", loading)
+	}
+	if loading := inventory.Load(500); loading.Total != 185 {
+		t.Errorf("expected the heaviest loading the inventory allows, got %+v", loading)
+	}
+
+	inventory.Plates = append(inventory.Plates, plates.Plate{Weight: 10, Pairs: 1})
+	if err := inventory.Validate(); !errors.Is(err, plates.ErrInvalidInventory) {
+		t.Errorf("expected a duplicate plate to be rejected, got %v", err)
+	}
+}
+
+func TestWarmupRampsToWorkingWeight(t *testing.T) {
+	warmup := plates.DefaultInventory(false).Warmup(140)
+
+	totals := make([]float64, len(warmup))
+	for i, set := range warmup {
+		totals[i] = set.Total
+	}
+	if !reflect.DeepEqual(totals, []float64{20, 55, 85, 112.5, 125}) {
+		t.Errorf("unexpected warm-up weights %v", totals)
+	}
+	if warmup[0].Reps != 10 || warmup[len(warmup)-1].Reps != 1 {
+		t.Errorf("unexpected warm-up reps %+v", warmup)
+	}
+
+	if warmup := plates.DefaultInventory(false).Warmup(20); len(warmup) != 0 {
+		t.Errorf("expected no warm-up for the empty bar, got %+v", warmup)
+	}
+}
+
+func TestLoadableWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		kg       float64
+		imperial bool
+		want     float64
+	}{
+		{"metric", 93.3, false, 92.5},
+		{"metric rounds up", 98.9, false, 100},
+		{"imperial", 100, true, 99.79},          // 220 lbs
+		{"imperial rounds up", 45, true, 45.36}, // 100 lbs
+	}
+	for _, tt := range tests {
+		if got := plates.LoadableWeight(tt.kg, tt.imperial); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
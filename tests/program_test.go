@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"new-chainsaw/internal/program"
+)
+
+func testProgram() program.Definition {
+	return program.Definition{
+		TrainingMaxPercent: 90,
+		Lifts: []program.Lift{
+			{Key: "squat", Exercise: "Squat", Increment: 2.5, ProgressOn: program.ProgressPerSession, FailureLimit: 3, DeloadPercent: 10},
+			{Key: "bench", Exercise: "Bench Press", Increment: 5, ProgressOn: program.ProgressPerCycle},
+		},
+		Weeks: []program.Week{
+			{Days: []program.Day{
+				{Name: "A", Exercises: []program.Prescription{
+					{Lift: "squat", Sets: []program.Set{{Reps: 5, Percent: 100}, {Reps: 5, Percent: 100}}},
+					{Lift: "bench", Sets: []program.Set{{Reps: 5, Percent: 85, AMRAP: true}}},
+				}},
+			}},
+			{Days: []program.Day{
+				{Name: "B", Exercises: []program.Prescription{
+					{Lift: "bench", Sets: []program.Set{{Reps: 3, Percent: 90}}},
+				}},
+			}},
+		},
+	}
+}
+
+func testProgramState(t *testing.T, d program.Definition) program.State {
+	t.Helper()
+	state, err := program.NewState(d, map[string]program.LiftState{
+		"squat": {ExerciseID: 1, TrainingMax: 100},
+		"bench": {ExerciseID: 2, TrainingMax: 80},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return state
+}
+
+func roundToHalf(kg float64) float64 {
+	return math.Round(kg*2) / 2
+}
+
+func TestProgramDefinitionValidation(t *testing.T) {
+	if err := testProgram().Validate(); err != nil {
+		t.Fatalf("expected a valid definition, got %v", err)
+	}
+
+	undefinedLift := testProgram()
+	undefinedLift.Weeks[1].Days[0].Exercises[0].Lift = "deadlift"
+	badProgression := testProgram()
+	badProgression.Lifts[0].ProgressOn = "weekly"
+	noWeeks := testProgram()
+	noWeeks.Weeks = nil
+
+	for name, d := range map[string]program.Definition{"undefined lift": undefinedLift, "bad progression": badProgression, "no weeks": noWeeks} {
+		if err := d.Validate(); !errors.Is(err, program.ErrInvalidDefinition) {
+			t.Errorf("%s: expected an invalid definition, got %v", name, err)
+		}
+	}
+
+	if _, err := program.NewState(testProgram(), map[string]program.LiftState{"squat": {TrainingMax: 100}}); !errors.Is(err, program.ErrInvalidDefinition) {
+		t.Errorf("expected a missing training max to be rejected, got %v", err)
+	}
+}
+
+func TestProgramPrescribeAndEvaluate(t *testing.T) {
+	d := testProgram()
+	workout := program.Prescribe(d, testProgramState(t, d), roundToHalf)
+	if len(workout.Exercises) != 2 || workout.Exercises[0].Sets[0].WeightKg != 100 || workout.Exercises[1].Sets[0].WeightKg != 68 {
+		t.Fatalf("unexpected workout %+v", workout)
+	}
+
+	results, done := program.Evaluate(workout, []program.LoggedSet{
+		{ExerciseID: 1, Reps: 5, WeightKg: 100},
+		{ExerciseID: 1, Reps: 4, WeightKg: 100},
+		{ExerciseID: 2, Reps: 8, WeightKg: 67.5},
+	})
+	if !done || results["squat"] || !results["bench"] {
+		t.Errorf("expected a failed squat and a successful bench, got %v done=%v", results, done)
+	}
+
+	if _, done := program.Evaluate(workout, []program.LoggedSet{{ExerciseID: 3, Reps: 5, WeightKg: 100}}); done {
+		t.Error("expected a workout without sets of its lifts not to be done")
+	}
+}
+
+func TestProgramEvaluateMatchesEverySet(t *testing.T) {
+	workout := program.Workout{Exercises: []program.PrescribedExercise{
+		{Lift: "squat", ExerciseID: 1, Sets: []program.PrescribedSet{{Reps: 5, WeightKg: 100}, {Reps: 8, WeightKg: 90}}},
+	}}
+
+	tests := []struct {
+		name   string
+		logged []program.LoggedSet
+		want   bool
+	}{
+		// Matching 5@100 with 8@100 first would leave nothing for 8@90
+		{"heavier sets logged first", []program.LoggedSet{{ExerciseID: 1, Reps: 8, WeightKg: 100}, {ExerciseID: 1, Reps: 5, WeightKg: 100}}, true},
+		{"heavier sets logged last", []program.LoggedSet{{ExerciseID: 1, Reps: 5, WeightKg: 100}, {ExerciseID: 1, Reps: 8, WeightKg: 100}}, true},
+		{"as prescribed", []program.LoggedSet{{ExerciseID: 1, Reps: 8, WeightKg: 90}, {ExerciseID: 1, Reps: 5, WeightKg: 100}}, true},
+		{"one set for two", []program.LoggedSet{{ExerciseID: 1, Reps: 8, WeightKg: 100}}, false},
+		{"too few reps", []program.LoggedSet{{ExerciseID: 1, Reps: 5, WeightKg: 100}, {ExerciseID: 1, Reps: 7, WeightKg: 100}}, false},
+		{"too light", []program.LoggedSet{{ExerciseID: 1, Reps: 8, WeightKg: 90}, {ExerciseID: 1, Reps: 5, WeightKg: 95}}, false},
+	}
+	for _, tt := range tests {
+		results, _ := program.Evaluate(workout, tt.logged)
+		if results["squat"] != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, results["squat"], tt.want)
+		}
+	}
+}
+
+func TestProgramAdvanceDeloadsAfterRepeatedFailures(t *testing.T) {
+	d := testProgram()
+	state := testProgramState(t, d)
+
+	state, adjustments := program.Advance(d, state, map[string]bool{"squat": true, "bench": true})
+	if len(adjustments) != 1 || adjustments[0].Kind != program.AdjustmentProgress || state.Lifts["squat"].TrainingMax != 102.5 {
+		t.Fatalf("expected the squat to progress per session, got %+v", adjustments)
+	}
+	if state.Week != 1 || state.Day != 0 {
+		t.Fatalf("expected to move on to the second week, got %+v", state)
+	}
+
+	for i := 0; i < 2; i++ {
+		state, _ = program.Advance(d, state, map[string]bool{"squat": false})
+		if state.Lifts["squat"].TrainingMax != 102.5 {
+			t.Fatalf("expected no deload before the failure limit, got %+v", state.Lifts["squat"])
+		}
+	}
+	state, adjustments = program.Advance(d, state, map[string]bool{"squat": false})
+	if len(adjustments) == 0 || adjustments[0].Kind != program.AdjustmentDeload || state.Lifts["squat"].TrainingMax != 92.25 {
+		t.Fatalf("expected a 10%% deload after three failures, got %+v", adjustments)
+	}
+	if state.Lifts["squat"].Failures != 0 {
+		t.Errorf("expected the failures to reset after a deload, got %d", state.Lifts["squat"].Failures)
+	}
+}
+
+func TestProgramAdvanceProgressesPerCycle(t *testing.T) {
+	d := testProgram()
+	state := testProgramState(t, d)
+
+	state, _ = program.Advance(d, state, map[string]bool{"bench": true})
+	next, adjustments := program.Advance(d, state, map[string]bool{"bench": true})
+	if next.Cycle != 1 || next.Week != 0 || len(adjustments) != 1 || next.Lifts["bench"].TrainingMax != 85 {
+		t.Fatalf("expected the bench to progress at the end of the cycle, got %+v %+v", next, adjustments)
+	}
+	if state.Lifts["bench"].TrainingMax != 80 {
+		t.Error("expected advancing not to modify the previous state")
+	}
+
+	next, _ = program.Advance(d, next, map[string]bool{"bench": false})
+	next, adjustments = program.Advance(d, next, map[string]bool{"bench": true})
+	if len(adjustments) != 0 || next.Lifts["bench"].TrainingMax != 85 {
+		t.Errorf("expected no progress after a cycle with a failure, got %+v", adjustments)
+	}
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+	"new-chainsaw/internal/realtime"
+)
+
+func TestHubPublishesToTheUsersStreams(t *testing.T) {
+	hub := realtime.NewHub()
+	first, cancelFirst := hub.Subscribe(1)
+	second, cancelSecond := hub.Subscribe(1)
+	other, cancelOther := hub.Subscribe(2)
+	defer cancelSecond()
+	defer cancelOther()
+
+	if sent := hub.Publish(1, realtime.Message{Event: "trophy.unlocked"}); sent != 2 {
+		t.Fatalf("published to %d streams, want 2", sent)
+	}
+	for _, stream := range []<-chan realtime.Message{first, second} {
+		if msg := <-stream; msg.Event != "trophy.unlocked" {
+			t.Errorf("got %+v", msg)
+		}
+	}
+	select {
+	case msg := <-other:
+		t.Errorf("another user received %+v", msg)
+	default:
+	}
+
+	cancelFirst()
+	cancelFirst()
+	if _, open := <-first; open {
+		t.Error("cancelled stream is still open")
+	}
+	if sent := hub.Publish(1, realtime.Message{Event: "pr.achieved"}); sent != 1 {
+		t.Errorf("published to %d streams after cancelling one, want 1", sent)
+	}
+}
+
+func TestHubSubscribers(t *testing.T) {
+	hub := realtime.NewHub()
+	if hub.HasSubscribers(1) {
+		t.Fatal("expected no subscribers")
+	}
+	_, cancel := hub.Subscribe(1)
+	if !hub.HasSubscribers(1) {
+		t.Fatal("expected a subscriber")
+	}
+	cancel()
+	if hub.HasSubscribers(1) {
+		t.Error("expected no subscribers after cancelling")
+	}
+}
+
+func TestHubSkipsSlowStreams(t *testing.T) {
+	hub := realtime.NewHub()
+	slow, cancel := hub.Subscribe(1)
+	defer cancel()
+
+	sent := 0
+	for i := 0; i < 100; i++ {
+		sent += hub.Publish(1, realtime.Message{Event: "notification"})
+	}
+	if sent == 0 || sent == 100 {
+		t.Fatalf("delivered %d of 100 messages to a stream not reading", sent)
+	}
+	if len(slow) != sent {
+		t.Errorf("stream holds %d messages, %d were sent", len(slow), sent)
+	}
+}
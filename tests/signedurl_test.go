@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"new-chainsaw/internal/signedurl"
+)
+
+func TestSignedURLRoundTrip(t *testing.T) {
+	now := time.Now()
+	link := signedurl.Sign("secret", "/exports/1/download", now.Add(time.Hour))
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := parsed.Query()
+
+	if err := signedurl.Verify("secret", "/exports/1/download", q.Get("expires"), q.Get("signature"), now); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := signedurl.Verify("secret", "/exports/2/download", q.Get("expires"), q.Get("signature"), now); err != signedurl.ErrInvalidSignature {
+		t.Errorf("expected invalid signature for a different path, got %v", err)
+	}
+	if err := signedurl.Verify("secret", "/exports/1/download", q.Get("expires"), q.Get("signature"), now.Add(2*time.Hour)); err != signedurl.ErrExpired {
+		t.Errorf("expected expired link, got %v", err)
+	}
+	if !strings.HasPrefix(link, "/exports/1/download?") {
+		t.Errorf("unexpected link format: %s", link)
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"testing"
+	"new-chainsaw/internal/standards"
+)
+
+var benchStandards = []standards.Standard{
+	{BodyweightClassKg: 0, ThresholdsKg: [5]float64{30, 47.5, 62.5, 92.5, 125}},
+	{BodyweightClassKg: 60, ThresholdsKg: [5]float64{35, 52.5, 70, 105, 140}},
+	{BodyweightClassKg: 70, ThresholdsKg: [5]float64{37.5, 57.5, 77.5, 115, 152.5}},
+	{BodyweightClassKg: 80, ThresholdsKg: [5]float64{42.5, 62.5, 82.5, 125, 167.5}},
+}
+
+func TestStandardClasses(t *testing.T) {
+	for bodyweight, class := range map[float64]float64{45: 0, 60: 60, 79.9: 70, 80: 80, 140: 80} {
+		standard, ok := standards.ClassFor(benchStandards, bodyweight)
+		if !ok || standard.BodyweightClassKg != class {
+			t.Errorf("expected %g kg to be in the %g kg class, got %+v", bodyweight, class, standard)
+		}
+	}
+	if _, ok := standards.ClassFor(nil, 80); ok {
+		t.Errorf("expected no class without standards")
+	}
+}
+
+func TestClassifyStrength(t *testing.T) {
+	classification, ok := standards.Classify(benchStandards, 82, 100)
+	if !ok || classification.Level != standards.Intermediate || classification.BodyweightClassKg != 80 {
+		t.Fatalf("unexpected classification %+v", classification)
+	}
+	if classification.NextLevel == nil || *classification.NextLevel != standards.Advanced || *classification.NextKg != 125 {
+		t.Errorf("expected advanced at 125 kg next, got %v %v", classification.NextLevel, classification.NextKg)
+	}
+	if classification.Thresholds[standards.Elite] != 167.5 {
+		t.Errorf("unexpected thresholds %v", classification.Thresholds)
+	}
+
+	// A lighter lifter is advanced with the same bench
+	if classification, _ := standards.Classify(benchStandards, 58, 100); classification.Level != standards.Advanced {
+		t.Errorf("expected advanced in the lightest class, got %+v", classification)
+	}
+	if classification, _ := standards.Classify(benchStandards, 82, 40); classification.Level != standards.Untrained || *classification.NextLevel != standards.Beginner {
+		t.Errorf("expected untrained below the beginner standard, got %+v", classification)
+	}
+	if classification, _ := standards.Classify(benchStandards, 82, 170); classification.Level != standards.Elite || classification.NextLevel != nil {
+		t.Errorf("expected elite without a next level, got %+v", classification)
+	}
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"new-chainsaw/internal/streaks"
+)
+
+// streakWeek returns the Monday n weeks before the week of 2024-06-12, a Wednesday.
+func streakWeek(n int) time.Time {
+	return time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -7*n)
+}
+
+func TestWeekStart(t *testing.T) {
+	if start := streaks.WeekStart(time.Date(2024, 6, 16, 23, 0, 0, 0, time.UTC)); !start.Equal(streakWeek(0)) {
+		t.Errorf("expected Sunday to belong to the week starting Monday, got %v", start)
+	}
+}
+
+func TestStreaksCountConsecutiveWeeks(t *testing.T) {
+	now := time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC)
+	weeks := []streaks.Week{
+		{Start: streakWeek(8), Days: 3},
+		{Start: streakWeek(7), Days: 4},
+		{Start: streakWeek(6), Days: 3},
+		{Start: streakWeek(5), Days: 1}, // Short week breaks the streak
+		{Start: streakWeek(4), Days: 3},
+		// No training three weeks ago
+		{Start: streakWeek(2), Days: 3},
+		{Start: streakWeek(1), Days: 5},
+		{Start: streakWeek(0), Days: 1}, // Current week still in progress
+	}
+
+	s := streaks.Compute(weeks, 3, now)
+	if s.Current != 2 || s.Longest != 3 || s.CurrentWeekDays != 1 {
+		t.Errorf("unexpected streaks %+v", s)
+	}
+	// The last 12 complete weeks reach 1+1+0+1+1/3+1+1+1 of the target and then nothing
+	if s.Consistency != 52.8 {
+		t.Errorf("unexpected consistency %v", s.Consistency)
+	}
+
+	weeks[len(weeks)-1].Days = 3
+	if s := streaks.Compute(weeks, 3, now); s.Current != 3 || s.Longest != 3 {
+		t.Errorf("expected the current week to extend the streak once reached, got %+v", s)
+	}
+	if s := streaks.Compute(weeks, 5, now); s.Current != 1 || s.Longest != 1 {
+		t.Errorf("expected a higher target to shorten the streaks, got %+v", s)
+	}
+}
+
+func TestStreakMilestones(t *testing.T) {
+	milestone, ok := streaks.MilestoneFor("streak-12-weeks")
+	if !ok || milestone.Weeks != 12 {
+		t.Errorf("unexpected milestone %+v", milestone)
+	}
+	if _, ok := streaks.MilestoneFor("squat-sovereign"); ok {
+		t.Errorf("expected lifting trophies not to be streak milestones")
+	}
+}
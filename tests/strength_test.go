@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+	"new-chainsaw/db"
+	"new-chainsaw/internal/strength"
+)
+
+func TestPersonalRecordsForDistanceExercises(t *testing.T) {
+	day := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	seconds := func(s int32) *int32 { return &s }
+	meters := func(m float64) *float64 { return &m }
+
+	sets := []strength.Set{
+		{Date: day, Reps: 1, DurationSeconds: seconds(1500), DistanceMeters: meters(5000)},
+		{Date: day.AddDate(0, 0, 2), Reps: 1, DurationSeconds: seconds(2700), DistanceMeters: meters(10000)},
+		{Date: day.AddDate(0, 0, 4), Reps: 1, DistanceMeters: meters(3000)},
+	}
+
+	records := strength.PersonalRecords(db.MeasurementKindDistance, sets)
+	if len(records) != 2 {
+		t.Fatalf("expected distance and pace records, got %+v", records)
+	}
+	if records[0].Type != strength.RecordLongestDistance || records[0].Value != 10000 || !records[0].Date.Equal(day.AddDate(0, 0, 2)) {
+		t.Errorf("unexpected longest distance record %+v", records[0])
+	}
+	if records[1].Type != strength.RecordFastestPace || records[1].Value != 270 || !records[1].Date.Equal(day.AddDate(0, 0, 2)) {
+		t.Errorf("unexpected fastest pace record %+v", records[1])
+	}
+
+	summary := strength.Summarize(db.MeasurementKindDistance, sets)
+	if summary.Sets != 3 || summary.DistanceMeters != 18000 || summary.DurationSeconds != 4200 || summary.TotalReps != 0 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestValidateMeasurementRequiresKindFields(t *testing.T) {
+	duration := int32(60)
+	if err := strength.Validate(db.MeasurementKindTime, strength.Measurement{DurationSeconds: &duration}); err != nil {
+		t.Errorf("expected a timed set to be valid, got %v", err)
+	}
+	if err := strength.Validate(db.MeasurementKindDistance, strength.Measurement{DurationSeconds: &duration}); err == nil {
+		t.Error("expected a distance set without a distance to be invalid")
+	}
+}
+
+func TestEstimatedOneRepMaxCountsRepsInReserve(t *testing.T) {
+	rpe := func(r float64) *float64 { return &r }
+	day := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	sets := []strength.Set{
+		{Date: day, Reps: 5, LoadKg: 100},
+		{Date: day.AddDate(0, 0, 3), Reps: 3, LoadKg: 100, RPE: rpe(8)},
+		{Date: day.AddDate(0, 0, 7), Reps: 3, LoadKg: 102.5, RPE: rpe(6)},
+	}
+
+	records := strength.PersonalRecords(db.MeasurementKindRepsWeight, sets)
+	if records[1].Type != strength.RecordEstimated1RM {
+		t.Fatalf("expected the estimated 1RM record second, got %+v", records)
+	}
+	// 102.5 kg for three reps with four in reserve estimates like a set of seven
+	want := 102.5 * (1 + 7.0/30)
+	if math.Abs(records[1].Value-want) > 1e-9 || !records[1].Date.Equal(day.AddDate(0, 0, 7)) {
+		t.Errorf("expected an estimated 1RM of %.2f on the RPE 6 set, got %+v", want, records[1])
+	}
+	if strength.EstimatedOneRepMaxAtRPE(100, 3, 8) != strength.EstimatedOneRepMax(100, 5) {
+		t.Error("expected an RPE 8 triple to estimate like a set of five")
+	}
+}
+
+func TestImprovedRecords(t *testing.T) {
+	day := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	sets := []strength.Set{
+		{Date: day, Reps: 5, LoadKg: 100},
+		{Date: day.AddDate(0, 0, 2), Reps: 5, LoadKg: 100},
+		{Date: day.AddDate(0, 0, 4), Reps: 8, LoadKg: 95},
+		{Date: day.AddDate(0, 0, 6), Reps: 3, LoadKg: 90},
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want map[string]float64 // Previous value by record type
+	}{
+		{"first set", day, map[string]float64{}},
+		{"tie", day.AddDate(0, 0, 2), map[string]float64{}},
+		{"more reps at a lighter load", day.AddDate(0, 0, 4), map[string]float64{
+			strength.RecordEstimated1RM:  strength.EstimatedOneRepMax(100, 5),
+			strength.RecordBestSetVolume: 500,
+			strength.RecordMostReps:      5,
+		}},
+		{"no record", day.AddDate(0, 0, 6), map[string]float64{}},
+	}
+	for _, tt := range tests {
+		improved := strength.ImprovedRecords(db.MeasurementKindRepsWeight, sets, tt.date)
+		if len(improved) != len(tt.want) {
+			t.Errorf("%s: got %+v, want records %v", tt.name, improved, tt.want)
+			continue
+		}
+		for _, record := range improved {
+			previous, ok := tt.want[record.Type]
+			if !ok || math.Abs(record.Previous-previous) > 1e-9 || !record.Date.Equal(tt.date) {
+				t.Errorf("%s: got %+v, want previous %v", tt.name, record, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"testing"
+	"new-chainsaw/internal/plates"
+	"new-chainsaw/internal/templates"
+	"new-chainsaw/internal/validation"
+)
+
+func TestPrescribeTemplateExercise(t *testing.T) {
+	round := func(kg float64) float64 { return plates.LoadableWeight(kg, false) }
+
+	latest := &templates.LatestSet{Reps: 5, WeightKg: 100}
+	latestAtRPE := &templates.LatestSet{Reps: 5, WeightKg: 100, RPE: float64p(8)}
+
+	tests := []struct {
+		name       string
+		target     templates.Target
+		latest     *templates.LatestSet
+		wantReps   *int32
+		wantWeight *float64
+	}{
+		{"target weight", templates.Target{Reps: int32p(3), WeightKg: float64p(120)}, latest, int32p(3), float64p(120)},
+		{"open target repeats the latest set", templates.Target{}, latest, int32p(5), float64p(100)},
+		{"open target without a latest set", templates.Target{Reps: int32p(8)}, nil, int32p(8), nil},
+		{"percentage", templates.Target{Reps: int32p(3), Percentage: float64p(80)}, latest, int32p(3), float64p(92.5)},        // 80% of 116.7 kg
+		{"percentage at rpe", templates.Target{Reps: int32p(3), Percentage: float64p(80)}, latestAtRPE, int32p(3), float64p(97.5)}, // 80% of 123.3 kg
+		{"percentage without a latest set", templates.Target{Percentage: float64p(80)}, nil, nil, nil},
+		{"percentage of a bodyweight set", templates.Target{Percentage: float64p(80)}, &templates.LatestSet{Reps: 10}, int32p(10), nil},
+	}
+	for _, tt := range tests {
+		reps, weight := templates.Prescribe(tt.target, tt.latest, round)
+		if !equalPtr(reps, tt.wantReps) || !equalPtr(weight, tt.wantWeight) {
+			t.Errorf("%s: got %v reps at %v kg, want %v reps at %v kg", tt.name, deref(reps), deref(weight), deref(tt.wantReps), deref(tt.wantWeight))
+		}
+	}
+}
+
+func TestValidateTemplateTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{"sets", validation.ValidateTargetSets(5), false},
+		{"no sets", validation.ValidateTargetSets(0), true},
+		{"too many sets", validation.ValidateTargetSets(21), true},
+		{"open reps", validation.ValidateTargetReps(nil), false},
+		{"zero reps", validation.ValidateTargetReps(int32p(0)), true},
+		{"open load", validation.ValidateTargetLoad(nil, nil), false},
+		{"weight", validation.ValidateTargetLoad(float64p(100), nil), false},
+		{"percentage", validation.ValidateTargetLoad(nil, float64p(75)), false},
+		{"weight and percentage", validation.ValidateTargetLoad(float64p(100), float64p(75)), true},
+		{"negative weight", validation.ValidateTargetLoad(float64p(-5), nil), true},
+		{"zero percentage", validation.ValidateTargetLoad(nil, float64p(0)), true},
+		{"percentage above the limit", validation.ValidateTargetLoad(nil, float64p(125)), true},
+	}
+	for _, tt := range tests {
+		if (tt.err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tt.name, tt.err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateSetDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{"no rpe", validation.ValidateRPE(nil), false},
+		{"rpe", validation.ValidateRPE(float64p(8.5)), false},
+		{"rpe below 6", validation.ValidateRPE(float64p(5.5)), true},
+		{"rpe above 10", validation.ValidateRPE(float64p(10.5)), true},
+		{"rpe between half steps", validation.ValidateRPE(float64p(7.3)), true},
+		{"rir", validation.ValidateRIR(int32p(2)), false},
+		{"negative rir", validation.ValidateRIR(int32p(-1)), true},
+		{"no tempo", validation.ValidateTempo(""), false},
+		{"tempo", validation.ValidateTempo("3-1-1-0"), false},
+		{"explosive tempo", validation.ValidateTempo("2-0-X-1"), false},
+		{"three phase tempo", validation.ValidateTempo("3-1-1"), true},
+		{"rest", validation.ValidateRestSeconds(int32p(180)), false},
+		{"rest above an hour", validation.ValidateRestSeconds(int32p(3601)), true},
+	}
+	for _, tt := range tests {
+		if (tt.err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tt.name, tt.err, tt.wantErr)
+		}
+	}
+}
+
+func int32p(v int32) *int32 { return &v }
+
+func float64p(v float64) *float64 { return &v }
+
+func equalPtr[T comparable](a, b *T) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}